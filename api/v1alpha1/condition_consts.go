@@ -37,6 +37,34 @@ const (
 	// VMCreateFailed (Severity=Error) documents a KubevirtMachine that is unable to create the
 	// corresponding VM object.
 	VMCreateFailedReason = "VMCreateFailed"
+
+	// VMUnschedulableReason (Severity=Warning) documents a KubevirtMachine whose VM can't be scheduled on any node of
+	// the infra cluster, e.g. because no node has the host devices it requests.
+	VMUnschedulableReason = "VMUnschedulable"
+
+	// WaitingForIPAddressesReason (Severity=Info) documents a KubevirtMachine waiting for the IPAM providers to
+	// allocate the static IP addresses of its VM.
+	WaitingForIPAddressesReason = "WaitingForIPAddresses"
+
+	// WaitingForVMToAdoptReason (Severity=Info) documents a KubevirtMachine waiting for the existing VM it adopts to
+	// be found in the infra cluster.
+	WaitingForVMToAdoptReason = "WaitingForVMToAdopt"
+
+	// WaitingForExternallyManagedVMReason (Severity=Info) documents a KubevirtMachine waiting for its externally
+	// managed VM to be created in the infra cluster.
+	WaitingForExternallyManagedVMReason = "WaitingForExternallyManagedVM"
+
+	// WaitingForCapacityReason (Severity=Warning) documents a KubevirtMachine whose VM is not created yet, because no
+	// node of the infra cluster has the CPU, memory or devices it requests free.
+	WaitingForCapacityReason = "WaitingForCapacity"
+
+	// WaitingForTurnReason (Severity=Info) documents a KubevirtMachine whose VM is not created yet, because the VM
+	// creation rate limit of the cluster is reached.
+	WaitingForTurnReason = "WaitingForTurn"
+
+	// HugepagesUnavailableReason (Severity=Warning) documents a KubevirtMachine whose VM can't be scheduled because
+	// no node of the infra cluster can allocate the hugepages it requests.
+	HugepagesUnavailableReason = "HugepagesUnavailable"
 )
 
 const (
@@ -54,6 +82,125 @@ const (
 	// bootstrapping the Kubernetes node on the machine just provisioned; those kind of errors are usually
 	// transient and failed bootstrap are automatically re-tried by the controller.
 	BootstrapFailedReason = "BootstrapFailed"
+
+	// BootstrapTimedOutReason documents (Severity=Error) a KubevirtMachine whose bootstrap check kept failing for
+	// longer than the timeout of the bootstrap check; the KubevirtMachine is marked as failed.
+	BootstrapTimedOutReason = "BootstrapTimedOut"
+)
+
+const (
+	// DrainingSucceededCondition provides an observation of the drain of the tenant cluster node, when the VM is
+	// evacuated from its infra cluster node.
+	DrainingSucceededCondition clusterv1.ConditionType = "DrainingSucceeded"
+
+	// DrainingReason (Severity=Info) documents a KubevirtMachine controller draining the tenant cluster node; pods
+	// are still being evicted when a drain attempt times out, and the drain is retried.
+	DrainingReason = "Draining"
+
+	// PodEvictionFailedReason (Severity=Warning) documents a KubevirtMachine controller that failed to evict the pods
+	// of the tenant cluster node, and is waiting before retrying; the wait time exponentially grows with every failed
+	// attempt.
+	PodEvictionFailedReason = "PodEvictionFailed"
+
+	// DrainGracePeriodExceededReason (Severity=Warning) documents a KubevirtMachine controller that deleted the VMI
+	// before the tenant cluster node was drained, because the VMI deletion grace period was exceeded.
+	DrainGracePeriodExceededReason = "DrainGracePeriodExceeded"
+
+	// GuestShutdownSkippedReason (Severity=Info) documents a KubevirtMachine controller that drained the tenant cluster
+	// node, but deleted the VMI without shutting down its guest OS, e.g. because the VMI wasn't running.
+	GuestShutdownSkippedReason = "GuestShutdownSkipped"
+
+	// WaitingForConcurrentDrainsReason (Severity=Info) documents a KubevirtMachine controller waiting for the drain
+	// of other nodes of the tenant cluster to complete, before draining the node.
+	WaitingForConcurrentDrainsReason = "WaitingForConcurrentDrains"
+
+	// PodDisruptionBudgetBlockedReason (Severity=Warning) documents a KubevirtMachine controller that can't evict the
+	// pods of the tenant cluster node, because their PodDisruptionBudgets don't allow any disruption.
+	PodDisruptionBudgetBlockedReason = "PodDisruptionBudgetBlocked"
+
+	// TenantClusterUnreachableReason (Severity=Warning) documents a KubevirtMachine controller that can't reach the
+	// API server of the tenant cluster to drain the node; the VMI is deleted without draining the node once the API
+	// server is unreachable for longer than the tenant unreachable timeout.
+	TenantClusterUnreachableReason = "TenantClusterUnreachable"
+
+	// TenantClusterClientFailedReason (Severity=Warning) documents a KubevirtMachine controller that failed to create
+	// a client for the tenant cluster to drain the node, e.g. because the kubeconfig secret can't be read; the drain
+	// is retried, and as soon as the kubeconfig secret changes, until the tenant unreachable timeout, after which the
+	// VMI is deleted without draining the node.
+	TenantClusterClientFailedReason = "TenantClusterClientFailed"
+
+	// WaitingForDeletionHooksReason (Severity=Info) documents a KubevirtMachine controller waiting for the pre-drain
+	// or pre-terminate deletion hooks of the Machine being deleted to be removed, before draining the tenant cluster
+	// node or deleting the evacuated VMI, up to the VMI deletion grace period.
+	WaitingForDeletionHooksReason = "WaitingForDeletionHooks"
+)
+
+const (
+	// VMMigrationSucceededCondition provides an observation of the last live migration of the VM between the infra
+	// cluster nodes. It is only set once the VM is migrated.
+	VMMigrationSucceededCondition clusterv1.ConditionType = "VMMigrationSucceeded"
+
+	// VMMigratingReason (Severity=Info) documents a KubevirtMachine whose VM is being live migrated to another infra
+	// cluster node; the tenant cluster node may briefly go NotReady meanwhile.
+	VMMigratingReason = "VMMigrating"
+
+	// VMMigrationFailedReason (Severity=Warning) documents a KubevirtMachine whose VM failed to be live migrated to
+	// another infra cluster node.
+	VMMigrationFailedReason = "VMMigrationFailed"
+)
+
+const (
+	// MemoryHotplugSucceededCondition provides an observation of the hotplug of the guest memory added to the
+	// KubevirtMachine into its running VM. It is only set when the memory hotplug of the VM is enabled.
+	MemoryHotplugSucceededCondition clusterv1.ConditionType = "MemoryHotplugSucceeded"
+
+	// MemoryHotplugInProgressReason (Severity=Info) documents a KubevirtMachine whose guest memory is being hot-plugged
+	// into the running VM by KubeVirt.
+	MemoryHotplugInProgressReason = "MemoryHotplugInProgress"
+
+	// MemoryHotplugNotSupportedReason (Severity=Warning) documents a KubevirtMachine whose guest memory change can't
+	// be hot-plugged into the running VM, because it decreases the memory or exceeds the maximum guest memory of the
+	// VM; the machine must be replaced for the change to apply.
+	MemoryHotplugNotSupportedReason = "MemoryHotplugNotSupported"
+)
+
+const (
+	// SpecOutOfDateCondition is true when the VM doesn't match the KubevirtMachine anymore, because fields of the VM
+	// that can't be changed in place were changed in the KubevirtMachine; the machine must be replaced, e.g. by a
+	// rollout of its MachineDeployment, for the changes to apply. Unlike the other conditions, it has a negative
+	// polarity.
+	SpecOutOfDateCondition clusterv1.ConditionType = "SpecOutOfDate"
+
+	// ImmutableFieldsChangedReason (Severity=Info) documents a KubevirtMachine whose VM doesn't have the changes of
+	// the fields listed in the condition message, which are only applied by replacing the machine.
+	ImmutableFieldsChangedReason = "ImmutableFieldsChanged"
+)
+
+const (
+	// VMHealthyCondition provides an observation of the health of the VM at the hypervisor level, once it has been
+	// ready: it turns false as soon as the VMI stops running or being ready, or its guest agent disconnects, even if
+	// the tenant cluster node still looks ready.
+	VMHealthyCondition clusterv1.ConditionType = "VMHealthy"
+
+	// VMCrashLoopBackOffReason (Severity=Error) documents a KubevirtMachine whose VM keeps crashing and being
+	// restarted by KubeVirt.
+	VMCrashLoopBackOffReason = "VMCrashLoopBackOff"
+
+	// VMINotRunningReason (Severity=Error) documents a KubevirtMachine whose VMI doesn't exist or is not running,
+	// e.g. because it crashed or was stopped.
+	VMINotRunningReason = "VMINotRunning"
+
+	// VMINotReadyReason (Severity=Warning) documents a KubevirtMachine whose running VMI is not ready, e.g. because
+	// the virt-handler of its infra cluster node is unresponsive.
+	VMINotReadyReason = "VMINotReady"
+
+	// GuestAgentDisconnectedReason (Severity=Warning) documents a KubevirtMachine whose VM guest agent disconnected,
+	// e.g. because the guest OS hangs.
+	GuestAgentDisconnectedReason = "GuestAgentDisconnected"
+
+	// VMPreemptedReason (Severity=Info) documents a preemptible KubevirtMachine whose VMI failed, e.g. because its
+	// virt-launcher pod was preempted; its owner Machine is deleted to be replaced.
+	VMPreemptedReason = "VMPreempted"
 )
 
 // Conditions and condition Reasons for the KubevirtCluster object
@@ -66,4 +213,28 @@ const (
 	// an error while provisioning the service that provides the cluster load balancer; those kind of
 	// errors are usually transient and failed provisioning are automatically re-tried by the controller.
 	LoadBalancerProvisioningFailedReason = "LoadBalancerProvisioningFailed"
+
+	// WaitingForControlPlaneEndpointHostReason (Severity=Info) documents a KubevirtCluster exposing its control plane
+	// with a service of type NodePort, waiting for a ready infra node with an address to publish its control plane
+	// endpoint on.
+	WaitingForControlPlaneEndpointHostReason = "WaitingForControlPlaneEndpointHost"
+
+	// ControlPlaneEndpointNodeNotReadyReason (Severity=Warning) documents a KubevirtCluster exposing its control plane
+	// with a service of type NodePort, whose control plane endpoint is published on an infra node address that is no
+	// longer the address of a ready node matching the node selector of the service.
+	ControlPlaneEndpointNodeNotReadyReason = "ControlPlaneEndpointNodeNotReady"
+)
+
+const (
+	// ControlPlaneEndpointReachableCondition documents whether the control plane endpoint of a KubevirtCluster accepts
+	// TLS connections from the KubevirtCluster controller.
+	ControlPlaneEndpointReachableCondition clusterv1.ConditionType = "ControlPlaneEndpointReachable"
+
+	// WaitingForControlPlaneReason (Severity=Info) documents a KubevirtCluster whose control plane endpoint is not
+	// probed yet, because the control plane of the cluster is not initialized.
+	WaitingForControlPlaneReason = "WaitingForControlPlane"
+
+	// ControlPlaneEndpointUnreachableReason (Severity=Warning) documents a KubevirtCluster whose control plane endpoint
+	// doesn't accept TLS connections while its control plane is initialized, e.g. because of a broken load balancer.
+	ControlPlaneEndpointUnreachableReason = "ControlPlaneEndpointUnreachable"
 )
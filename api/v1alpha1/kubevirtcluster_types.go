@@ -38,6 +38,38 @@ const ( //labels
 const ( // annotations
 	VmiDeletionGraceTime       = "capk.cluster.x-k8s.io/vmi-deletion-grace-time"
 	VmiDeletionGraceTimeEscape = "capk.cluster.x-k8s.io~1vmi-deletion-grace-time"
+
+	// SkipDrainAnnotation can be set on a KubevirtMachine or on its VMI to delete an evacuated VMI
+	// right away, without draining the tenant cluster node first.
+	SkipDrainAnnotation = "capk.cluster.x-k8s.io/skip-drain"
+
+	// CordonedNodeAnnotation is set by the controller on a KubevirtMachine, with the name of the tenant cluster
+	// node it cordoned when draining it, so the node can be uncordoned if the evacuation is cancelled.
+	CordonedNodeAnnotation       = "capk.cluster.x-k8s.io/cordoned-node"
+	CordonedNodeAnnotationEscape = "capk.cluster.x-k8s.io~1cordoned-node"
+
+	// EvacuatedNodeAnnotation is set by the controller on a KubevirtMachine, with the name of the tenant cluster node
+	// to delete once the evacuated VMI is deleted.
+	EvacuatedNodeAnnotation       = "capk.cluster.x-k8s.io/evacuated-node"
+	EvacuatedNodeAnnotationEscape = "capk.cluster.x-k8s.io~1evacuated-node"
+
+	// DrainDryRunAnnotation can be set to "true" on a KubevirtCluster to only log and record events for what the
+	// controller would do when the VMs of the cluster are evacuated, without cordoning or draining the tenant cluster
+	// nodes, nor deleting the VMIs or Machines.
+	DrainDryRunAnnotation = "capk.cluster.x-k8s.io/drain-dry-run"
+
+	// ExternallyManagedVMAnnotation can be set on a KubevirtMachine whose VM is managed by another tool, e.g. a GitOps
+	// pipeline. The controller then never creates, updates or deletes the VM, but only tracks its readiness and
+	// reports the provider ID of the machine.
+	ExternallyManagedVMAnnotation = "capk.cluster.x-k8s.io/externally-managed-vm"
+
+	// DegradedMachineAnnotation is set by the controller on the owner Machine of a degraded VM, with the reason it
+	// set the cluster-api delete-machine annotation along with it, so both are removed once the VM recovers.
+	DegradedMachineAnnotation = "capk.cluster.x-k8s.io/degraded"
+
+	// ControlPlaneNodeAddressAnnotation is set by the controller on a KubevirtCluster, with the infra node address it
+	// published the control plane endpoint of a service of type NodePort on, so the endpoint is never moved.
+	ControlPlaneNodeAddressAnnotation = "capk.cluster.x-k8s.io/control-plane-node-address"
 )
 
 // KubevirtClusterSpec defines the desired state of KubevirtCluster.
@@ -57,13 +89,167 @@ type KubevirtClusterSpec struct {
 	// +optional
 	ControlPlaneServiceTemplate ControlPlaneServiceTemplate `json:"controlPlaneServiceTemplate,omitempty"`
 
+	// KubeVIP, when set, exposes the control plane endpoint with a virtual IP announced by kube-vip from the control
+	// plane nodes, instead of a service of the infra cluster, e.g. when the tenant network is routable but the
+	// services of the infra cluster are not. The kube-vip static pod is added to the bootstrap data of the control
+	// plane machines, and the ControlPlaneServiceTemplate is ignored.
+	// +optional
+	KubeVIP *KubeVIPSpec `json:"kubeVIP,omitempty"`
+
+	// ControlPlaneRoute, when set, exposes the control plane through a TLSRoute of a Gateway of the infra cluster, or
+	// an SNI routed Ingress, forwarding the TLS connections for its hostname to the service fronting the control
+	// plane, e.g. to share one external address across many tenant clusters. It is ignored when KubeVIP is set.
+	// +optional
+	ControlPlaneRoute *ControlPlaneRoute `json:"controlPlaneRoute,omitempty"`
+
 	// SSHKeys is a reference to a local struct for SSH keys persistence.
 	// +optional
 	SshKeys SSHKeys `json:"sshKeys,omitempty"`
 
+	// SSHAuthorizedKeys are additional public SSH keys authorized to log into all the nodes of the cluster as the
+	// capk user, e.g. to debug them, on top of the ones of each KubevirtMachine.
+	// +optional
+	SSHAuthorizedKeys *SSHAuthorizedKeys `json:"sshAuthorizedKeys,omitempty"`
+
 	// InfraClusterSecretRef is a reference to a secret with a kubeconfig for external cluster used for infra.
 	// +optional
 	InfraClusterSecretRef *corev1.ObjectReference `json:"infraClusterSecretRef,omitempty"`
+
+	// InfraClusterNamespace is the namespace of the infra cluster the VMs, DataVolumes and generated secrets of the
+	// tenant cluster, as well as the service fronting its control plane, are placed in. When empty, the namespace of
+	// the KubevirtCluster is used for the local infra cluster, and the namespace of the kubeconfig context of the
+	// InfraClusterSecretRef for an external one.
+	// +optional
+	InfraClusterNamespace string `json:"infraClusterNamespace,omitempty"`
+
+	// InfraClusters are additional infra clusters the worker machines of the cluster can be placed on, when their
+	// KubevirtMachine does not set an InfraClusterSecretRef. Each new machine is placed on one of them according to
+	// the InfraClusterPlacementPolicy, and the infra cluster it landed on is reported in its status. The control
+	// plane machines are always placed on the infra cluster of the InfraClusterSecretRef, for the service fronting
+	// the control plane to select them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	InfraClusters []InfraClusterReference `json:"infraClusters,omitempty"`
+
+	// InfraClusterPlacementPolicy defines how the worker machines are spread across the InfraClusters. Defaults to
+	// Spread.
+	// +optional
+	// +kubebuilder:validation:Enum=Spread;Weighted
+	// +kubebuilder:default=Spread
+	InfraClusterPlacementPolicy InfraClusterPlacementPolicy `json:"infraClusterPlacementPolicy,omitempty"`
+
+	// DrainPodExclusionSelector selects the pods of the tenant cluster that are never evicted when a node is
+	// drained before its VM is evacuated from the infra node, e.g. storage daemons that must keep running until
+	// the VM is removed.
+	// +optional
+	DrainPodExclusionSelector *metav1.LabelSelector `json:"drainPodExclusionSelector,omitempty"`
+
+	// MaxConcurrentDrains is the maximum number of tenant cluster nodes drained at the same time, when their VMs are
+	// evacuated from the infra nodes. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentDrains *int32 `json:"maxConcurrentDrains,omitempty"`
+
+	// DrainPolicyRef is a reference to a KubevirtDrainPolicy, in the namespace of the KubevirtCluster, defining how
+	// the tenant cluster nodes are drained. The drain settings of the KubevirtCluster and KubevirtMachine specs take
+	// precedence over the policy.
+	// +optional
+	DrainPolicyRef *corev1.LocalObjectReference `json:"drainPolicyRef,omitempty"`
+
+	// DNS is the default DNS resolver configuration of the VMs of the cluster. The DNS configuration of a
+	// KubevirtMachine takes precedence over it.
+	// +optional
+	DNS *DNSConfig `json:"dns,omitempty"`
+
+	// FailureDomains are the zones of the infra cluster the machines of the cluster are spread across, with the
+	// overrides of the VMs running in each of them. They are reported in the status of the KubevirtCluster for
+	// Cluster API to spread the machines across them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FailureDomains []FailureDomain `json:"failureDomains,omitempty"`
+
+	// VMCreationRateLimit limits how many VMs of the cluster are created per interval, e.g. for the DataVolume imports
+	// of a large MachineDeployment scale up not to overwhelm the storage of the infra cluster. The machines over the
+	// limit wait for their turn, with the WaitingForTurn reason of their VMProvisioned condition. The VM creations are
+	// not limited by default.
+	// +optional
+	VMCreationRateLimit *VMCreationRateLimit `json:"vmCreationRateLimit,omitempty"`
+}
+
+// VMCreationRateLimit is the maximum number of VMs of a cluster created per interval.
+type VMCreationRateLimit struct {
+	// VMs is the maximum number of VMs created per interval.
+	// +kubebuilder:validation:Minimum=1
+	VMs int32 `json:"vms"`
+
+	// Interval is the period the VM creations are counted over. Defaults to 1m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// InfraClusterPlacementPolicy defines how the worker machines of a cluster are spread across its infra clusters.
+type InfraClusterPlacementPolicy string
+
+const (
+	// SpreadInfraClusterPlacementPolicy places each new machine on the infra cluster with the fewest machines of the
+	// cluster.
+	SpreadInfraClusterPlacementPolicy InfraClusterPlacementPolicy = "Spread"
+
+	// WeightedInfraClusterPlacementPolicy places each new machine on the infra cluster with the fewest machines of the
+	// cluster relative to its weight.
+	WeightedInfraClusterPlacementPolicy InfraClusterPlacementPolicy = "Weighted"
+)
+
+// InfraClusterReference is an infra cluster the machines of a cluster can be placed on.
+type InfraClusterReference struct {
+	// Name identifies the infra cluster in the status of the KubevirtMachines placed on it.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SecretRef is a reference to a secret with a kubeconfig for the infra cluster. When nil, the machines are placed
+	// on the cluster the controller runs in.
+	// +optional
+	SecretRef *corev1.ObjectReference `json:"secretRef,omitempty"`
+
+	// Namespace is the namespace of the infra cluster the VMs are placed in. When empty, the namespace of the
+	// kubeconfig context of the SecretRef is used, or the namespace of the KubevirtCluster for the local cluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Weight is the relative share of the machines placed on the infra cluster with the Weighted placement policy.
+	// An infra cluster with a weight of 0 does not get new machines. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// FailureDomain is a zone of the infra cluster, with the overrides of the VMs of the machines of this failure domain.
+type FailureDomain struct {
+	// Name is the name of the failure domain. The VMs of this failure domain are scheduled on the infra nodes with
+	// the topology.kubernetes.io/zone label of this value, unless NodeSelector is set.
+	Name string `json:"name"`
+
+	// ControlPlane is true if the control plane machines can run in this failure domain.
+	// +optional
+	ControlPlane bool `json:"controlPlane,omitempty"`
+
+	// NodeSelector selects the infra nodes of this failure domain, instead of the topology.kubernetes.io/zone label.
+	// It is merged into the node selector of the VM template.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// StorageClassName is the storage class of the DataVolumes of the VMs of this failure domain, overriding the
+	// storage class of the root disk, of the data disks and of the DataVolume templates of the VM template.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// NetworkAttachmentDefinitions are the NetworkAttachmentDefinitions of the additional networks of the VMs of this
+	// failure domain, by name of additional network, overriding the NetworkAttachmentDefinitions of the machines.
+	// +optional
+	NetworkAttachmentDefinitions map[string]string `json:"networkAttachmentDefinitions,omitempty"`
 }
 
 // KubevirtClusterStatus defines the observed state of KubevirtCluster.
@@ -72,10 +258,14 @@ type KubevirtClusterStatus struct {
 	// +kubebuilder:default:=false
 	Ready bool `json:"ready"`
 
-	// FailureDomains don't mean much in CAPD since it's all local, but we can see how the rest of cluster API
-	// will use this if we populate it.
+	// FailureDomains are the failure domains of the spec, for Cluster API to spread the machines across them.
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 
+	// ControlPlaneNodeAddresses are the addresses of the infra nodes the control plane service of type NodePort is
+	// reachable on, when the control plane endpoint is published on one of them, e.g. to publish them all in the DNS.
+	// +optional
+	ControlPlaneNodeAddresses []string `json:"controlPlaneNodeAddresses,omitempty"`
+
 	// Conditions defines current service state of the KubevirtCluster.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -103,10 +293,90 @@ type SSHKeys struct {
 	DataSecretName *string `json:"dataSecretName,omitempty"`
 }
 
+// SSHAuthorizedKey is a public SSH key, in the authorized_keys format, on a single line.
+// +kubebuilder:validation:Pattern=`^[^\r\n]*$`
+type SSHAuthorizedKey string
+
+// SSHAuthorizedKeys are public SSH keys authorized to log into the nodes.
+type SSHAuthorizedKeys struct {
+	// Keys are the public keys, in the authorized_keys format, e.g. "ssh-ed25519 AAAA... alice@example.com".
+	// +optional
+	Keys []SSHAuthorizedKey `json:"keys,omitempty"`
+
+	// SecretRefs are references to secrets, in the namespace of the cluster, with public keys in their
+	// "authorized_keys" key, one per line.
+	// +optional
+	SecretRefs []corev1.LocalObjectReference `json:"secretRefs,omitempty"`
+}
+
+// KubeVIPSpec describes the kube-vip static pod announcing the virtual IP of the control plane endpoint.
+type KubeVIPSpec struct {
+	// Address is the virtual IP of the control plane endpoint. It must be an unused address of the tenant network.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// Interface is the network interface of the control plane nodes the virtual IP is announced on. Defaults to eth0.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+
+	// Image is the kube-vip image. Defaults to ghcr.io/kube-vip/kube-vip:v0.6.4.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// ControlPlaneRoute describes the route of the control plane endpoint through a Gateway or an Ingress controller of the
+// infra cluster.
+type ControlPlaneRoute struct {
+	// Hostname is the host of the control plane endpoint, matched with the server name of the TLS connections. It
+	// must resolve to the Gateway or the Ingress controller, and be a subject alternative name of the certificate of
+	// the API server.
+	// +kubebuilder:validation:MinLength=1
+	Hostname string `json:"hostname"`
+
+	// Port is the port of the control plane endpoint, which the Gateway or the Ingress controller listens on. Defaults
+	// to 443.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Gateway is the Gateway the TLSRoute of the control plane is attached to. Its listener must have the TLS
+	// protocol, in the Passthrough mode. When not set, an Ingress is created instead.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+
+	// IngressClassName is the class of the Ingress of the control plane, when no Gateway is set. The Ingress controller
+	// must pass the TLS connections through, e.g. ingress-nginx with its ssl-passthrough enabled.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Annotations are set on the TLSRoute or the Ingress of the control plane. The Ingress has the
+	// nginx.ingress.kubernetes.io/ssl-passthrough annotation unless set otherwise.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayReference is a reference to a listener of a Gateway.
+type GatewayReference struct {
+	// Name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the namespace of the service fronting the control plane.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener of the Gateway. Defaults to all the listeners allowing the route.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
 // ControlPlaneServiceTemplate describes the template for the control plane service.
 type ControlPlaneServiceTemplate struct {
 	// Service metadata allows to set labels, annotations and namespace for the service.
 	// When infraClusterSecretRef is used, ControlPlaneService take the kubeconfig namespace by default if metadata.namespace is not specified.
+	// The changes of the labels and annotations are applied to the existing service, e.g. for a load balancer
+	// implementation or external-dns to pick them up; the ones removed from the template are left on the service.
 	// This field is optional.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +nullable
@@ -120,10 +390,44 @@ type ControlPlaneServiceTemplate struct {
 // ServiceSpecTemplate describes the service spec template.
 type ServiceSpecTemplate struct {
 	// Type determines how the Service is exposed. Defaults to ClusterIP. Valid
-	// options are ExternalName, ClusterIP, NodePort, and LoadBalancer.
+	// options are ClusterIP, NodePort, and LoadBalancer.
+	// The control plane endpoint is the cluster IP of a ClusterIP service, the external address of a LoadBalancer
+	// service, and the node port of a NodePort service on the host set in controlPlaneEndpoint, or on the address of
+	// one of the infra nodes selected by the NodeSelector when no host is set.
 	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
 	// +optional
 	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// LoadBalancerIP requests a specific IP address to the load balancer implementation, for a service of type
+	// LoadBalancer. It is ignored when the load balancer implementation doesn't support it.
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// LoadBalancerClass is the class of the load balancer implementation of a service of type LoadBalancer, e.g.
+	// to use another implementation than the default one of the infra cluster.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// IPFamilies are the IP families of the service, e.g. [IPv4, IPv6] for a dual-stack tenant cluster. The control
+	// plane endpoint is an address of the first family. Defaults to the IP family of the infra cluster.
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// IPFamilyPolicy is the dual-stack policy of the service. Defaults to SingleStack, or to PreferDualStack when
+	// IPFamilies has two families.
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	// +optional
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// NodeSelector selects the infra nodes the control plane endpoint of a service of type NodePort is published on,
+	// when the host of the controlPlaneEndpoint is not set. The external IPs of the ready nodes, or their internal IPs
+	// when they have none, are reported in the status, and the control plane endpoint is published on the first one.
+	// It is never moved to another address once published. Defaults to all the infra nodes.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
 }
 
 // +kubebuilder:resource:path=kubevirtclusters,scope=Namespaced,categories=cluster-api
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubevirtDrainPolicySpec defines how the tenant cluster nodes are drained when their VMs are evacuated from the
+// infra cluster nodes. The values set in the KubevirtMachine and KubevirtCluster specs take precedence over the
+// policy.
+type KubevirtDrainPolicySpec struct {
+	// DrainTimeout is the maximum time a single drain attempt of the tenant node waits for pods to be evicted.
+	// Defaults to 20s.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+
+	// DrainRetryInterval is the time to wait before retrying a failed drain attempt of the tenant node.
+	// Defaults to 1s.
+	// +optional
+	DrainRetryInterval *metav1.Duration `json:"drainRetryInterval,omitempty"`
+
+	// SkipWaitForDeleteTimeout is how long the drain waits for the deleted pods of an unreachable tenant cluster
+	// node to be gone. Defaults to the controller --skip-wait-for-delete-timeout flag.
+	// +optional
+	SkipWaitForDeleteTimeout *metav1.Duration `json:"skipWaitForDeleteTimeout,omitempty"`
+
+	// TenantUnreachableTimeout is how long the drain retries while the API server of the tenant cluster is
+	// unreachable, before deleting the VMI without draining the node. Defaults to the controller
+	// --tenant-unreachable-timeout flag.
+	// +optional
+	TenantUnreachableTimeout *metav1.Duration `json:"tenantUnreachableTimeout,omitempty"`
+
+	// PodEvictionGracePeriod overrides the termination grace period of the pods evicted when a tenant cluster node is
+	// drained. When not set, the terminationGracePeriodSeconds of each pod is used.
+	// +optional
+	PodEvictionGracePeriod *metav1.Duration `json:"podEvictionGracePeriod,omitempty"`
+
+	// PodDisruptionBudgetTimeout is how long the drain respects the PodDisruptionBudgets of the tenant cluster
+	// before deleting the pods they protect. The PodDisruptionBudgets are always respected if not set.
+	// +optional
+	PodDisruptionBudgetTimeout *metav1.Duration `json:"podDisruptionBudgetTimeout,omitempty"`
+
+	// GuestShutdownGracePeriod is how long KubeVirt waits for the guest OS to shut down, with an ACPI shutdown, when
+	// the evacuated VMI is deleted. The VMI is deleted with its own termination grace period if not set.
+	// +optional
+	GuestShutdownGracePeriod *metav1.Duration `json:"guestShutdownGracePeriod,omitempty"`
+
+	// PodExclusionSelector selects the pods of the tenant cluster that are never evicted when a node is drained.
+	// +optional
+	PodExclusionSelector *metav1.LabelSelector `json:"podExclusionSelector,omitempty"`
+
+	// MaxConcurrentDrains is the maximum number of tenant cluster nodes drained at the same time. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentDrains *int32 `json:"maxConcurrentDrains,omitempty"`
+
+	// Force deletes the pods of the tenant cluster node that are not managed by a controller. Defaults to true.
+	// +optional
+	Force *bool `json:"force,omitempty"`
+
+	// EvacuationPolicy defines what the controller does when the VM is evacuated from its infra cluster node.
+	// Possible values are: "Migrate", "DrainAndDelete", "DeleteMachine" or "AnnotateMachine". Defaults to "Migrate".
+	// +optional
+	// +kubebuilder:validation:Enum=Migrate;DrainAndDelete;DeleteMachine;AnnotateMachine
+	EvacuationPolicy EvacuationPolicy `json:"evacuationPolicy,omitempty"`
+
+	// DeschedulerEvictionPolicy defines what the controller does when the VM is evicted while its infra cluster node
+	// is still schedulable. Possible values are: "Migrate", "DrainAndDelete", "DeleteMachine" or "AnnotateMachine".
+	// Defaults to "Migrate".
+	// +optional
+	// +kubebuilder:validation:Enum=Migrate;DrainAndDelete;DeleteMachine;AnnotateMachine
+	DeschedulerEvictionPolicy EvacuationPolicy `json:"deschedulerEvictionPolicy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=kubevirtdrainpolicies,scope=Namespaced,categories=cluster-api,shortName=kdp
+
+// KubevirtDrainPolicy is the Schema for the kubevirtdrainpolicies API.
+type KubevirtDrainPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KubevirtDrainPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubevirtDrainPolicyList contains a list of KubevirtDrainPolicy.
+type KubevirtDrainPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubevirtDrainPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubevirtDrainPolicy{}, &KubevirtDrainPolicyList{})
+}
@@ -18,8 +18,10 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
 )
@@ -30,6 +32,26 @@ const (
 	MachineFinalizer = "kubevirtmachine.infrastructure.cluster.x-k8s.io"
 )
 
+// EvacuationPolicy defines what the controller does when the VM is evacuated from its infra cluster node.
+type EvacuationPolicy string
+
+const (
+	// MigrateEvacuationPolicy lets KubeVirt live migrate the VM, and only drains the tenant node and deletes the VMI
+	// if the VMI is not migratable, or if the migration failed.
+	MigrateEvacuationPolicy EvacuationPolicy = "Migrate"
+
+	// DrainAndDeleteEvacuationPolicy drains the tenant node and deletes the VMI, even if the VMI could be migrated.
+	DrainAndDeleteEvacuationPolicy EvacuationPolicy = "DrainAndDelete"
+
+	// DeleteMachineEvacuationPolicy deletes the owner Machine, to let cluster-api drain the node and replace the
+	// machine.
+	DeleteMachineEvacuationPolicy EvacuationPolicy = "DeleteMachine"
+
+	// AnnotateMachineEvacuationPolicy annotates the owner Machine with the cluster-api delete-machine annotation, so
+	// the MachineSet deletes it first on the next scale down, and leaves the VM to KubeVirt.
+	AnnotateMachineEvacuationPolicy EvacuationPolicy = "AnnotateMachine"
+)
+
 // VirtualMachineTemplateSpec defines the desired state of the kubevirt VM.
 type VirtualMachineTemplateSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -51,21 +73,750 @@ type KubevirtMachineSpec struct {
 	// +optional
 	BootstrapCheckSpec VirtualMachineBootstrapCheckSpec `json:"virtualMachineBootstrapCheck,omitempty"`
 
+	// CloudInitDatasource is how the bootstrap data is delivered to the guest: with a "ConfigDrive" volume, which is
+	// the default, or with a "NoCloud" volume, for the guest images only supporting the NoCloud datasource of
+	// cloud-init. Changing it only applies to the VMs created afterwards.
+	// +optional
+	// +kubebuilder:validation:Enum=ConfigDrive;NoCloud
+	CloudInitDatasource CloudInitDatasource `json:"cloudInitDatasource,omitempty"`
+
+	// AdditionalUserDataSecretRef is a reference to a secret, in the namespace of the KubevirtMachine, with a
+	// cloud-config in its "userdata" key, which is merged into the cloud-config bootstrap data, e.g. to configure a
+	// proxy, add CA certificates or install agents. The mappings are merged recursively, the lists of the additional
+	// cloud-config, e.g. its write_files, runcmd or users, are prepended to the ones of the bootstrap data, and the
+	// other values of the bootstrap data take precedence. It is ignored with the ignition and raw bootstrap data.
+	// +optional
+	AdditionalUserDataSecretRef *corev1.LocalObjectReference `json:"additionalUserDataSecretRef,omitempty"`
+
+	// SSHAuthorizedKeys are additional public SSH keys authorized to log into the node as the capk user, e.g. to
+	// debug it, added to the cloud-config or Ignition bootstrap data together with the ones of the KubevirtCluster.
+	// They are added even when the controller doesn't inject its own SSH key, e.g. with the guestAgent bootstrap
+	// check strategy. Changing them only applies to the VMs created afterwards.
+	// +optional
+	SSHAuthorizedKeys *SSHAuthorizedKeys `json:"sshAuthorizedKeys,omitempty"`
+
+	// BootstrapDataFormat is the format of the bootstrap data: "cloud-config", "ignition" or "raw". When not set,
+	// the format is read from the bootstrap data secret of the Machine, and defaults to "cloud-config"; the formats
+	// other than cloud-config and ignition, e.g. the talos format, are handled as raw. The raw bootstrap data is
+	// passed through unmodified, as the user data of the cloudInitDatasource volume.
+	// +optional
+	// +kubebuilder:validation:Enum=cloud-config;ignition;raw
+	BootstrapDataFormat BootstrapDataFormat `json:"bootstrapDataFormat,omitempty"`
+
 	// InfraClusterSecretRef is a reference to a secret with a kubeconfig for external cluster used for infra.
 	// When nil, this defaults to the value present in the KubevirtCluster object's spec associated with this machine.
 	// +optional
 	InfraClusterSecretRef *corev1.ObjectReference `json:"infraClusterSecretRef,omitempty"`
+
+	// InfraClusterNamespace is the namespace of the infra cluster the VM, its DataVolumes and its generated secrets
+	// are placed in. When empty, this defaults to the value present in the KubevirtCluster object's spec associated
+	// with this machine. The namespace set in the metadata of the VirtualMachineTemplate takes precedence over it.
+	// +optional
+	InfraClusterNamespace string `json:"infraClusterNamespace,omitempty"`
+
+	// DrainTimeout is the maximum time a single drain attempt of the tenant node waits for pods to be
+	// evicted, when the VM is evacuated from its infra node. Pods that are not evicted in time are retried
+	// on the next attempt. Defaults to 20s.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+
+	// DrainRetryInterval is the time to wait before retrying a failed drain attempt of the tenant node.
+	// Defaults to 1s.
+	// +optional
+	DrainRetryInterval *metav1.Duration `json:"drainRetryInterval,omitempty"`
+
+	// SkipWaitForDeleteTimeout is how long the drain of an unreachable tenant node waits for the deleted pods to be
+	// gone, before ignoring them. When not set, the controller --skip-wait-for-delete-timeout flag value is used.
+	// +optional
+	SkipWaitForDeleteTimeout *metav1.Duration `json:"skipWaitForDeleteTimeout,omitempty"`
+
+	// TenantUnreachableTimeout is how long the drain retries while the API server of the tenant cluster is
+	// unreachable, before deleting the VMI without draining the node. When not set, the controller
+	// --tenant-unreachable-timeout flag value is used.
+	// +optional
+	TenantUnreachableTimeout *metav1.Duration `json:"tenantUnreachableTimeout,omitempty"`
+
+	// PodEvictionGracePeriod overrides the termination grace period of the pods evicted when the tenant node is
+	// drained, e.g. to cap the termination time of pods with long grace periods during an evacuation. When not set,
+	// the terminationGracePeriodSeconds of each pod is used.
+	// +optional
+	PodEvictionGracePeriod *metav1.Duration `json:"podEvictionGracePeriod,omitempty"`
+
+	// EvacuationPolicy defines what the controller does when the VM is evacuated from its infra cluster node.
+	// Possible values are: "Migrate", "DrainAndDelete", "DeleteMachine" or "AnnotateMachine". Defaults to "Migrate".
+	// +optional
+	// +kubebuilder:validation:Enum=Migrate;DrainAndDelete;DeleteMachine;AnnotateMachine
+	EvacuationPolicy EvacuationPolicy `json:"evacuationPolicy,omitempty"`
+
+	// DeschedulerEvictionPolicy defines what the controller does when the VM is evicted while its infra cluster node
+	// is still schedulable, as the descheduler does to rebalance the infra cluster. Such evictions don't require the
+	// infra cluster node to be emptied, so they are handled separately from the infra cluster node drains.
+	// Possible values are: "Migrate", "DrainAndDelete", "DeleteMachine" or "AnnotateMachine". Defaults to "Migrate".
+	// +optional
+	// +kubebuilder:validation:Enum=Migrate;DrainAndDelete;DeleteMachine;AnnotateMachine
+	DeschedulerEvictionPolicy EvacuationPolicy `json:"deschedulerEvictionPolicy,omitempty"`
+
+	// PodDisruptionBudgetTimeout is how long the drain of the tenant node respects the PodDisruptionBudgets of the
+	// tenant cluster pods. Once exceeded, the pods left on the node are deleted, bypassing their
+	// PodDisruptionBudgets. When not set, the PodDisruptionBudgets are respected until the VMI deletion grace period
+	// is exceeded.
+	// +optional
+	PodDisruptionBudgetTimeout *metav1.Duration `json:"podDisruptionBudgetTimeout,omitempty"`
+
+	// GuestShutdownGracePeriod is how long KubeVirt waits for the guest OS to shut down when the evacuated VMI is
+	// deleted. When set, the VMI is deleted with this grace period, and KubeVirt sends an ACPI shutdown to the guest
+	// OS, so that it cleanly unmounts its file systems, before killing the VM once the grace period is exceeded. When
+	// not set, the VMI is deleted with its own termination grace period.
+	// +optional
+	GuestShutdownGracePeriod *metav1.Duration `json:"guestShutdownGracePeriod,omitempty"`
+
+	// DeleteNodeAfterEvacuation deletes the tenant cluster node once the evacuated VMI is deleted, instead of
+	// leaving it NotReady until the VM is started again.
+	// +optional
+	DeleteNodeAfterEvacuation bool `json:"deleteNodeAfterEvacuation,omitempty"`
+
+	// AdditionalNetworks attaches the VM to Multus networks of the infra cluster, in addition to the networks of the
+	// VM template. The pod network is attached first if the VM template has no network. The IP addresses of the
+	// additional networks are reported in the KubevirtMachine status as internal IPs.
+	// +optional
+	AdditionalNetworks []AdditionalNetwork `json:"additionalNetworks,omitempty"`
+
+	// PodNetworkBinding is how the VM interface is bound to the pod network of the infra cluster. Possible values
+	// are: "Bridge", "Masquerade" or "Passt". It overrides the binding of the pod network interface of the VM
+	// template, if any. When not set, the binding of the VM template or the default binding of KubeVirt is used.
+	// +optional
+	// +kubebuilder:validation:Enum=Bridge;Masquerade;Passt
+	PodNetworkBinding PodNetworkBinding `json:"podNetworkBinding,omitempty"`
+
+	// DNS is the DNS resolver configuration of the VM, e.g. the resolvers of an air-gapped environment. It overrides
+	// the DNS configuration of the KubevirtCluster, if any.
+	// +optional
+	DNS *DNSConfig `json:"dns,omitempty"`
+
+	// RootDisk is the root disk of the VM: an ephemeral container disk, or a persistent disk cloned from a golden image
+	// by CDI. The VM boots from it instead of the disks of the VM template. The DataVolume of a persistent root disk is
+	// owned by the VM, and deleted with it unless the DiskRetentionPolicy is Retain.
+	// +optional
+	RootDisk *RootDisk `json:"rootDisk,omitempty"`
+
+	// DataDisks are the persistent data disks of the VM, e.g. for the etcd data or the container runtime storage. A
+	// blank DataVolume is created for each of them, owned by the VM, and deleted with it unless the
+	// DiskRetentionPolicy is Retain.
+	// +optional
+	DataDisks []DataDisk `json:"dataDisks,omitempty"`
+
+	// HotplugVolumes are the PVCs and DataVolumes of the infra cluster hot-plugged into the VM. Unlike the volumes of
+	// the VM template, they can be changed without replacing the machine: the volumes added to the list are hot-plugged
+	// into the running VM, and the volumes removed from the list are unplugged.
+	// +optional
+	HotplugVolumes []HotplugVolume `json:"hotplugVolumes,omitempty"`
+
+	// ConfigDisks attach ConfigMaps and Secrets of the infra cluster to the VM as disks, e.g. to deliver the node
+	// certificates or the registry credentials without baking them into the images. The ConfigMaps and Secrets must
+	// be in the namespace of the VM.
+	// +optional
+	ConfigDisks []ConfigDisk `json:"configDisks,omitempty"`
+
+	// CDROMs attach ISO images to the VM as CD-ROMs, e.g. the virtio drivers of the Windows nodes.
+	// +optional
+	CDROMs []CDROM `json:"cdroms,omitempty"`
+
+	// DiskRetentionPolicy is what happens to the DataVolumes and the PVCs of the VM when the KubevirtMachine is
+	// deleted. Possible values are:
+	// - Delete (default): the DataVolumes of the VM, and their PVCs, are deleted with the VM.
+	// - Retain: the DataVolumes of the VM, and their PVCs, are orphaned and kept on the infra cluster, e.g. to
+	//   recover the data of a failed node. They must be deleted manually.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DiskRetentionPolicy DiskRetentionPolicy `json:"diskRetentionPolicy,omitempty"`
+
+	// RunStrategy is the run strategy of the VM, overriding the running field and the run strategy of the VM
+	// template. Possible values are:
+	// - Always: the VM is restarted whenever its guest stops, crashes or is shut down.
+	// - RerunOnFailure: the VM is restarted when its guest crashes, but not when it is shut down, which makes the
+	//   machine fail and lets a MachineHealthCheck remediate it.
+	// - Once: the VM is never restarted, so that the machine fails and a MachineHealthCheck remediates it whenever
+	//   the guest stops.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;RerunOnFailure;Once
+	RunStrategy *kubevirtv1.VirtualMachineRunStrategy `json:"runStrategy,omitempty"`
+
+	// EvictionStrategy is the eviction strategy of the VM when its infra node is drained, overriding the eviction
+	// strategy of the VM template and of KubeVirt. Possible values are:
+	// - LiveMigrate: the VM is live migrated to another infra node, and the machine is kept.
+	// - LiveMigrateIfPossible: the VM is live migrated if it is migratable, and evicted otherwise.
+	// - External: the VM is not evicted by KubeVirt, the controller drains the tenant cluster node and deletes the VM.
+	// - None: the VM is evicted without draining the tenant cluster node.
+	// +optional
+	// +kubebuilder:validation:Enum=LiveMigrate;LiveMigrateIfPossible;External;None
+	EvictionStrategy *kubevirtv1.EvictionStrategy `json:"evictionStrategy,omitempty"`
+
+	// ControlPlaneAntiAffinity is the pod anti-affinity added to the control plane VMs, so that they run on distinct
+	// infra nodes. It is ignored for the worker machines. Possible values are:
+	// - Required (default): the control plane VMs never run on the same infra node, even if some of them can't be
+	//   scheduled or live migrated.
+	// - Preferred: the scheduler avoids running the control plane VMs on the same infra node.
+	// - None: no anti-affinity is added.
+	// +optional
+	// +kubebuilder:validation:Enum=Required;Preferred;None
+	ControlPlaneAntiAffinity AntiAffinityPolicy `json:"controlPlaneAntiAffinity,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the virt-launcher pod of the VM, overriding the priority class of the
+	// VM template, e.g. so that the control plane VMs preempt the workloads of lower priority of a congested infra
+	// cluster instead of staying unschedulable.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Preemptible makes the machine preemptible, e.g. for cheap burst capacity pools: its VM runs with a low priority
+	// class, whose virt-launcher pod the scheduler of the infra cluster preempts for the pods of higher priority, and
+	// is neither restarted nor, unless the evictionStrategy is set, live migrated or drained when evicted. The owner
+	// Machine of a preemptible machine whose VMI failed, e.g. because it was preempted, is deleted for its MachineSet
+	// to create a replacement, instead of the machine being marked as failed.
+	// +optional
+	Preemptible *PreemptibleSpec `json:"preemptible,omitempty"`
+
+	// Memory sets the guest memory of the VM independently of the memory request of its virt-launcher pod, overriding
+	// the memory of the VM template.
+	// +optional
+	Memory *MemorySpec `json:"memory,omitempty"`
+
+	// AdoptExistingVM makes the KubevirtMachine adopt the existing VM of the same name in the infra cluster instead of
+	// creating one, e.g. to move the nodes of a cluster built by hand under the management of Cluster API. The VM is
+	// labeled as belonging to the KubevirtMachine, and only its lifecycle is managed: it is neither updated from the
+	// KubevirtMachine nor checked for drift, but it is deleted with the KubevirtMachine.
+	// +optional
+	AdoptExistingVM bool `json:"adoptExistingVM,omitempty"`
+
+	// QOSClass is the QoS class of the virt-launcher pod of the VM. Possible values are:
+	// - Burstable (default): the resources of the VM template are used as is.
+	// - Guaranteed: the CPU and memory limits of the VM are set to its requests, the CPU request defaulting to the
+	//   number of vCPUs and the memory request to the guest memory, e.g. for the static CPU manager pinning of the
+	//   latency-sensitive nodes. The memory of the VM can't be overcommitted.
+	// +optional
+	// +kubebuilder:validation:Enum=Burstable;Guaranteed
+	QOSClass corev1.PodQOSClass `json:"qosClass,omitempty"`
+
+	// ProvisioningFailureTimeout is how long the VM may fail to start, because its virt-launcher pod can't be
+	// scheduled, its container disk images can't be pulled, or its DataVolumes failed to import, before the
+	// KubevirtMachine is marked as failed, so that its machine is replaced by a MachineHealthCheck. The failures are
+	// waited on forever if not set.
+	// +optional
+	ProvisioningFailureTimeout *metav1.Duration `json:"provisioningFailureTimeout,omitempty"`
+
+	// KernelBoot boots the VM directly from the kernel and initrd of a container image, with the given kernel
+	// arguments, rather than via the bootloader of its root disk, e.g. for the immutable OS images. It overrides
+	// the kernelBoot of the firmware of the VM template.
+	// +optional
+	KernelBoot *kubevirtv1.KernelBoot `json:"kernelBoot,omitempty"`
+
+	// SerialConsoleLog captures the serial console output of the VM until the KubevirtMachine is ready, so that the
+	// boot failures happening before the bootstrap check can reach the VM are diagnosable from the management cluster.
+	// It enables the logSerialConsole of the VM, whose output KubeVirt writes to the guest-console-log container of
+	// the virt-launcher pod.
+	// +optional
+	SerialConsoleLog *SerialConsoleLogSpec `json:"serialConsoleLog,omitempty"`
+
+	// VMNaming is how the VM and the bootstrap data secret of the KubevirtMachine are named in the infra cluster, e.g.
+	// to follow the naming conventions of the infra cluster, or to avoid the name collisions of the KubevirtMachines
+	// of different namespaces sharing an infra cluster namespace. When not set, they are named after the
+	// KubevirtMachine and its bootstrap data secret. The hostname of the guest, and so the name of the tenant cluster
+	// node, remains the name of the KubevirtMachine.
+	// +optional
+	VMNaming *VMNamingSpec `json:"vmNaming,omitempty"`
+
+	// MetadataPropagation selects the labels and annotations of the KubevirtMachine that are propagated to its VM,
+	// and through the VMI template to the VMI and its virt-launcher pod, so that the tooling of the infra cluster,
+	// e.g. cost allocation, network policies or monitoring, can select the VMs of a tenant cluster. The labels and
+	// annotations set by the controller and by the VM template take precedence. They are only propagated when the VM
+	// is created.
+	// +optional
+	MetadataPropagation *MetadataPropagationSpec `json:"metadataPropagation,omitempty"`
+
+	// NodeLabels are the labels applied by the controller to the tenant cluster node of the machine, once it has
+	// registered, in addition to the ones set by the kubelet flags of the bootstrap data. The labels, annotations and
+	// taints of the node are reapplied on every reconcile, but removing one from the machine leaves it on the node.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// NodeAnnotations are the annotations applied by the controller to the tenant cluster node of the machine, once
+	// it has registered.
+	// +optional
+	NodeAnnotations map[string]string `json:"nodeAnnotations,omitempty"`
+
+	// NodeTaints are the taints applied by the controller to the tenant cluster node of the machine, once it has
+	// registered. A taint of the node with the same key and effect is replaced.
+	// +optional
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+
+	// NodeTopologyFromInfraNode applies the topology.kubernetes.io/zone and topology.kubernetes.io/region labels of
+	// the infra cluster node running the VM to the tenant cluster node of the machine, once it has registered.
+	// +optional
+	NodeTopologyFromInfraNode bool `json:"nodeTopologyFromInfraNode,omitempty"`
+}
+
+// MetadataPropagationSpec is the allowlist of the labels and annotations of a KubevirtMachine propagated to its VM.
+// An entry ending with "*" matches all the keys starting with the rest of the entry, e.g. "example.com/*".
+type MetadataPropagationSpec struct {
+	// Labels are the keys of the labels propagated to the VM, the VMI and the virt-launcher pod.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations are the keys of the annotations propagated to the VM, the VMI and the virt-launcher pod.
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// VMNamingSpec defines the names of the VM and of the bootstrap data secret of the KubevirtMachine in the infra
+// cluster: <prefix><name><suffix>-<hash>, the name being truncated so that the names are at most 63 characters long.
+type VMNamingSpec struct {
+	// Prefix is prepended to the names.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][-a-z0-9]*$`
+	// +kubebuilder:validation:MaxLength=20
+	Prefix string `json:"prefix,omitempty"`
+
+	// Suffix is appended to the names.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[-a-z0-9]*[a-z0-9]$`
+	// +kubebuilder:validation:MaxLength=20
+	Suffix string `json:"suffix,omitempty"`
+
+	// HashLength is the number of the hexadecimal characters of the hash of the namespace and the name of the
+	// KubevirtMachine that are appended to the names, after a dash, so that the names are unique across the
+	// namespaces of the management cluster. No hash is appended when not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=16
+	HashLength int32 `json:"hashLength,omitempty"`
+}
+
+// SerialConsoleLogTarget is where the serial console output of the VM is captured.
+type SerialConsoleLogTarget string
+
+const (
+	// LogSerialConsoleLogTarget writes the serial console output of the VM to the controller logs.
+	LogSerialConsoleLogTarget SerialConsoleLogTarget = "Log"
+
+	// ConfigMapSerialConsoleLogTarget stores the serial console output of the VM in a ConfigMap of the KubevirtMachine.
+	ConfigMapSerialConsoleLogTarget SerialConsoleLogTarget = "ConfigMap"
+)
+
+// SerialConsoleLogSpec defines how the serial console output of the VM is captured.
+type SerialConsoleLogSpec struct {
+	// Target is where the serial console output is captured. Possible values are: "Log", to write it to the controller
+	// logs, or "ConfigMap", to store it in the "<name>-serial-console" ConfigMap, in the namespace of the
+	// KubevirtMachine, which is deleted with the KubevirtMachine. Defaults to "ConfigMap".
+	// +optional
+	// +kubebuilder:validation:Enum=Log;ConfigMap
+	Target SerialConsoleLogTarget `json:"target,omitempty"`
+
+	// TailLines is the number of the last lines of the serial console output that are captured. Defaults to 100.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TailLines *int64 `json:"tailLines,omitempty"`
+}
+
+// MemorySpec is the guest memory of the VM, and the memory request of its virt-launcher pod. At most one of Request
+// or OvercommitPercent can be set.
+type MemorySpec struct {
+	// Guest is the memory of the guest. When not set, the guest memory of the VM template is used, or else its memory
+	// request.
+	// +optional
+	Guest *resource.Quantity `json:"guest,omitempty"`
+
+	// Request is the memory requested for the guest by the virt-launcher pod, to which KubeVirt adds the memory
+	// overhead of the VM. When not set, the memory request of the VM template is used, or else the guest memory.
+	// +optional
+	Request *resource.Quantity `json:"request,omitempty"`
+
+	// OvercommitPercent overcommits the guest memory: the memory request is the guest memory divided by this
+	// percentage, e.g. 150 requests 2/3 of the guest memory.
+	// +optional
+	// +kubebuilder:validation:Minimum=100
+	OvercommitPercent *int32 `json:"overcommitPercent,omitempty"`
+}
+
+// AntiAffinityPolicy is the pod anti-affinity added to the VMs.
+type AntiAffinityPolicy string
+
+const (
+	// RequiredAntiAffinityPolicy adds a required pod anti-affinity.
+	RequiredAntiAffinityPolicy AntiAffinityPolicy = "Required"
+
+	// PreferredAntiAffinityPolicy adds a preferred pod anti-affinity.
+	PreferredAntiAffinityPolicy AntiAffinityPolicy = "Preferred"
+
+	// NoneAntiAffinityPolicy doesn't add any pod anti-affinity.
+	NoneAntiAffinityPolicy AntiAffinityPolicy = "None"
+)
+
+// DiskRetentionPolicy is what happens to the DataVolumes and the PVCs of the VM when the KubevirtMachine is deleted.
+type DiskRetentionPolicy string
+
+const (
+	// DeleteDiskRetentionPolicy deletes the DataVolumes and the PVCs of the VM with the VM.
+	DeleteDiskRetentionPolicy DiskRetentionPolicy = "Delete"
+
+	// RetainDiskRetentionPolicy keeps the DataVolumes and the PVCs of the VM on the infra cluster after the VM is
+	// deleted.
+	RetainDiskRetentionPolicy DiskRetentionPolicy = "Retain"
+)
+
+// CDROM is an ISO image attached to the VM as a CD-ROM. Exactly one of Image or PVC must be set.
+type CDROM struct {
+	// Name is the name of the volume and of the CD-ROM in the VM.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Image is the container disk image holding the ISO image, e.g. quay.io/kubevirt/virtio-container-disk.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// PVC references the PersistentVolumeClaim holding the ISO image, in the namespace of the VM. It is attached
+	// read-only.
+	// +optional
+	PVC *corev1.LocalObjectReference `json:"pvc,omitempty"`
+
+	// Bus is the bus of the CD-ROM. Possible values are: "sata" or "scsi". Defaults to "sata".
+	// +optional
+	// +kubebuilder:validation:Enum=sata;scsi
+	Bus kubevirtv1.DiskBus `json:"bus,omitempty"`
+
+	// BootOrder is the boot order of the CD-ROM, 1 being the first device to boot from. The devices without a boot order
+	// are not bootable once a device of the VM has one.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BootOrder *uint `json:"bootOrder,omitempty"`
+}
+
+// ConfigDisk is a ConfigMap or a Secret attached to the VM as a disk. Exactly one of ConfigMap or Secret must be set.
+type ConfigDisk struct {
+	// Name is the name of the volume and of the disk in the VM.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// ConfigMap references the ConfigMap attached to the VM.
+	// +optional
+	ConfigMap *corev1.LocalObjectReference `json:"configMap,omitempty"`
+
+	// Secret references the Secret attached to the VM.
+	// +optional
+	Secret *corev1.LocalObjectReference `json:"secret,omitempty"`
+
+	// Serial is the serial number of the disk, so that the guest can find it in /dev/disk/by-id.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.+-]+$`
+	Serial string `json:"serial,omitempty"`
+}
+
+// HotplugVolume is a volume hot-plugged into the VM as a SCSI disk.
+type HotplugVolume struct {
+	// Name is the name of the volume and of the disk in the VM.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// HotplugVolumeSource is the PVC or the DataVolume of the volume, in the namespace of the VM.
+	kubevirtv1.HotplugVolumeSource `json:",inline"`
+
+	// Serial is the serial number of the disk, so that the guest can find it in /dev/disk/by-id.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.+-]+$`
+	Serial string `json:"serial,omitempty"`
+}
+
+// ProvisioningStrategy is how the persistent root disk is cloned from the golden image.
+type ProvisioningStrategy string
+
+const (
+	// CloneProvisioningStrategy lets CDI pick the clone strategy of the storage class of the root disk.
+	CloneProvisioningStrategy ProvisioningStrategy = "Clone"
+
+	// SmartCloneProvisioningStrategy prefers the snapshot and CSI clones of the golden image, which are much faster
+	// than copying it, but require the root disk to be on the storage class of the golden image.
+	SmartCloneProvisioningStrategy ProvisioningStrategy = "SmartClone"
+)
+
+// DataDisk is a persistent data disk of the VM.
+type DataDisk struct {
+	// Name is the name of the volume and of the disk in the VM. The name of the DataVolume is prefixed by the name of
+	// the KubevirtMachine.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Size is the size of the data disk.
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName is the storage class of the data disk. When not set, the default storage class of the infra
+	// cluster is used.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessMode is the access mode of the data disk. Possible values are: "ReadWriteOnce" or "ReadWriteMany", which the
+	// live migration of the VM requires. When not set, CDI picks it from the storage profile of the storage class.
+	// +optional
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+
+	// VolumeMode is the volume mode of the data disk. Possible values are: "Block" or "Filesystem". When not set, CDI picks
+	// it from the storage profile of the storage class.
+	// +optional
+	// +kubebuilder:validation:Enum=Block;Filesystem
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// Bus is the bus of the data disk. Possible values are: "virtio", "sata" or "scsi". Defaults to "virtio".
+	// +optional
+	// +kubebuilder:validation:Enum=virtio;sata;scsi
+	Bus kubevirtv1.DiskBus `json:"bus,omitempty"`
+
+	// Serial is the serial number of the data disk, so that the guest can find it in /dev/disk/by-id.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.+-]+$`
+	Serial string `json:"serial,omitempty"`
+
+	// BootOrder is the boot order of the data disk, 1 being the first device to boot from. The devices without a boot order
+	// are not bootable once a device of the VM has one.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BootOrder *uint `json:"bootOrder,omitempty"`
+}
+
+// RootDisk is the root disk of the VM. Exactly one of Image, SourceRef or PVC must be set.
+type RootDisk struct {
+	// Image is the container disk image of an ephemeral root disk, e.g. quay.io/capk/ubuntu-2204-container-disk:v1.27.6.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// SourceRef references the CDI DataSource of the golden image.
+	// +optional
+	SourceRef *cdiv1.DataVolumeSourceRef `json:"sourceRef,omitempty"`
+
+	// PVC references the PersistentVolumeClaim of the golden image.
+	// +optional
+	PVC *cdiv1.DataVolumeSourcePVC `json:"pvc,omitempty"`
+
+	// Size is the size of the persistent root disk. When not set, it is the size of the golden image.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName is the storage class of the persistent root disk. When not set, the default storage class of the infra
+	// cluster is used. Use the storage class of the golden image to let the CSI driver clone it efficiently.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessMode is the access mode of the persistent root disk. Possible values are: "ReadWriteOnce" or "ReadWriteMany", which the
+	// live migration of the VM requires. When not set, CDI picks it from the storage profile of the storage class.
+	// +optional
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+
+	// VolumeMode is the volume mode of the persistent root disk. Possible values are: "Block" or "Filesystem". When not set, CDI picks
+	// it from the storage profile of the storage class.
+	// +optional
+	// +kubebuilder:validation:Enum=Block;Filesystem
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// Bus is the bus of the root disk. Possible values are: "virtio", "sata" or "scsi". Defaults to "virtio".
+	// +optional
+	// +kubebuilder:validation:Enum=virtio;sata;scsi
+	Bus kubevirtv1.DiskBus `json:"bus,omitempty"`
+
+	// ProvisioningStrategy is how the persistent root disk is cloned from the golden image. Possible values are:
+	// "Clone", to let CDI pick the clone strategy of the storage class of the root disk, or "SmartClone", to prefer the
+	// snapshot and CSI clones of the golden image, by putting the root disk on the storage class of the golden image
+	// when it has no storage class. Defaults to "Clone".
+	// +optional
+	// +kubebuilder:validation:Enum=Clone;SmartClone
+	ProvisioningStrategy ProvisioningStrategy `json:"provisioningStrategy,omitempty"`
+
+	// BootOrder is the boot order of the root disk, 1 being the first device to boot from. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BootOrder *uint `json:"bootOrder,omitempty"`
+}
+
+// DNSConfig is the DNS resolver configuration of the VMs.
+type DNSConfig struct {
+	// Nameservers are the IP addresses of the DNS servers of the VM. When set, they replace the DNS servers of the
+	// infra cluster.
+	// +optional
+	// +kubebuilder:validation:MaxItems=3
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// Searches are the DNS search domains of the VM.
+	// +optional
+	// +kubebuilder:validation:MaxItems=6
+	Searches []string `json:"searches,omitempty"`
+}
+
+// PodNetworkBinding is how the VM interface is bound to the pod network.
+type PodNetworkBinding string
+
+const (
+	// BridgePodNetworkBinding connects the VM interface to the pod network with a bridge, so the guest gets the IP
+	// address of the virt-launcher pod. The VM can't be live migrated.
+	BridgePodNetworkBinding PodNetworkBinding = "Bridge"
+
+	// MasqueradePodNetworkBinding connects the VM interface to the pod network with NAT.
+	MasqueradePodNetworkBinding PodNetworkBinding = "Masquerade"
+
+	// PasstPodNetworkBinding connects the VM interface to the pod network with passt, which requires the Passt
+	// feature gate of KubeVirt.
+	PasstPodNetworkBinding PodNetworkBinding = "Passt"
+)
+
+// AdditionalNetwork attaches the VM to a Multus network of the infra cluster.
+type AdditionalNetwork struct {
+	// Name is the name of the network and of the VM interface attached to it.
+	Name string `json:"name"`
+
+	// NetworkAttachmentDefinition is the name of the NetworkAttachmentDefinition of the infra cluster, prefixed by its
+	// namespace ("<namespace>/<name>") when it is not in the namespace of the VM.
+	NetworkAttachmentDefinition string `json:"networkAttachmentDefinition"`
+
+	// Binding is how the VM interface is bound to the network. Possible values are: "Bridge", or "SRIOV" for the
+	// NetworkAttachmentDefinitions of the SR-IOV CNI, whose resourceName annotation selects the VFs to attach.
+	// Defaults to "Bridge".
+	// +optional
+	// +kubebuilder:validation:Enum=Bridge;SRIOV
+	Binding NetworkBinding `json:"binding,omitempty"`
+
+	// PCIAddress is the PCI address of the VM interface in the guest, e.g. 0000:81:01.0, so that the guest OS names it
+	// predictably. When not set, KubeVirt picks the PCI address.
+	// +optional
+	PCIAddress string `json:"pciAddress,omitempty"`
+
+	// MACAddress is the MAC address of the VM interface, e.g. to match the DHCP reservations of the network. When not
+	// set, the MAC address recorded in the KubevirtMachine status, if any, is reused.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`
+	MACAddress string `json:"macAddress,omitempty"`
+
+	// AddressesFromPools are the IP address pools of the cluster-api IPAM providers to claim the static IP addresses
+	// of the VM interface from, one address per pool. The claimed addresses are configured in the guest with the
+	// cloud-init network configuration, for the networks without DHCP.
+	// +optional
+	AddressesFromPools []corev1.TypedLocalObjectReference `json:"addressesFromPools,omitempty"`
+
+	// Addresses are the static IP addresses of the VM interface, in the CIDR notation, e.g. 10.10.0.5/24, configured
+	// in the guest with the cloud-init network configuration together with the addresses claimed from the
+	// AddressesFromPools. The VM interfaces without addresses are configured with DHCP.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// Gateway is the IP address of the gateway of the default route through the VM interface, configured in the
+	// guest with the cloud-init network configuration. The gateways of the IPAM pools are not used, since the
+	// default route of the VM is usually the one of the pod network.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// BootOrder is the boot order of the VM interface, to boot the VM from the network with PXE, 1 being the first
+	// device to boot from. The devices without a boot order are not bootable once a device of the VM has one.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BootOrder *uint `json:"bootOrder,omitempty"`
+}
+
+// NetworkBinding is how the VM interface is bound to an additional network.
+type NetworkBinding string
+
+const (
+	// BridgeNetworkBinding connects the VM interface to the network with a bridge.
+	BridgeNetworkBinding NetworkBinding = "Bridge"
+
+	// SRIOVNetworkBinding passes an SR-IOV virtual function of the infra node through to the VM.
+	SRIOVNetworkBinding NetworkBinding = "SRIOV"
+)
+
+// CloudInitDatasource is the cloud-init datasource delivering the bootstrap data to the guest.
+type CloudInitDatasource string
+
+const (
+	// ConfigDriveCloudInitDatasource delivers the bootstrap data with a cloudInitConfigDrive volume.
+	ConfigDriveCloudInitDatasource CloudInitDatasource = "ConfigDrive"
+
+	// NoCloudCloudInitDatasource delivers the bootstrap data with a cloudInitNoCloud volume.
+	NoCloudCloudInitDatasource CloudInitDatasource = "NoCloud"
+)
+
+// BootstrapDataFormat is the format of the bootstrap data, as set by the CAPI bootstrap providers.
+type BootstrapDataFormat string
+
+const (
+	// CloudConfigBootstrapDataFormat is the cloud-init cloud-config format.
+	CloudConfigBootstrapDataFormat BootstrapDataFormat = "cloud-config"
+
+	// IgnitionBootstrapDataFormat is the Ignition format, e.g. of Flatcar and Fedora CoreOS.
+	IgnitionBootstrapDataFormat BootstrapDataFormat = "ignition"
+
+	// RawBootstrapDataFormat is any other format, e.g. the Talos machine config, passed through unmodified.
+	RawBootstrapDataFormat BootstrapDataFormat = "raw"
+)
+
+// SSHKeyType is the type of the SSH key the controller logs into the VM with.
+type SSHKeyType string
+
+const (
+	// ECDSASSHKeyType is an ECDSA key, on the P-384 curve.
+	ECDSASSHKeyType SSHKeyType = "ecdsa"
+
+	// ED25519SSHKeyType is an Ed25519 key.
+	ED25519SSHKeyType SSHKeyType = "ed25519"
+
+	// RSASSHKeyType is a 3072 bits RSA key.
+	RSASSHKeyType SSHKeyType = "rsa"
+)
+
+// PreemptibleSpec defines how a preemptible machine runs.
+type PreemptibleSpec struct {
+	// PriorityClassName is the low PriorityClass of the virt-launcher pods of the preemptible VMs, overriding the
+	// priorityClassName of the machine. It must exist in the infra cluster.
+	// +kubebuilder:validation:MinLength=1
+	PriorityClassName string `json:"priorityClassName"`
 }
 
 // VirtualMachineBootstrapCheckSpec defines how the controller will remotely check CAPI Sentinel file content.
 type VirtualMachineBootstrapCheckSpec struct {
 	// CheckStrategy describes how CAPK controller will validate a successful CAPI bootstrap.
 	// Following specified method, CAPK will try to retrieve the state of the CAPI Sentinel file from the VM.
-	// Possible values are: "none" or "ssh" (default is "ssh") and this value is validated by apiserver.
+	// Possible values are: "none", "ssh" or "guestAgent" (default is "ssh") and this value is validated by apiserver.
+	// With "guestAgent", the Sentinel file is checked by a readiness probe of the VMI, run by the qemu-guest-agent
+	// of the VM, so that no SSH key is injected in the bootstrap data; the VM image must run the guest agent, and
+	// the virtualMachineTemplate must not set its own readiness probe.
 	// +optional
-	// +kubebuilder:validation:Enum=none;ssh
+	// +kubebuilder:validation:Enum=none;ssh;guestAgent
 	// +kubebuilder:default:=ssh
 	CheckStrategy string `json:"checkStrategy,omitempty"`
+
+	// Timeout is how long the bootstrap check keeps failing before the KubevirtMachine is marked as failed, so that
+	// its Machine can be remediated, e.g. by a MachineHealthCheck. When not set, the check is retried forever.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Interval is how long to wait between two bootstrap checks. Defaults to 10s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// User is the name of the user the controller logs into the VM as with the "ssh" check strategy, and which is
+	// added to the bootstrap data with the SSH key of the controller. It must be allowed to log in with SSH. Defaults
+	// to "capk".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_-]*$`
+	// +kubebuilder:validation:MaxLength=32
+	User string `json:"user,omitempty"`
+
+	// Port is the port of the SSH server of the VM, with the "ssh" check strategy. Defaults to 22.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// KeyType is the type of the SSH key the controller logs into the VM with, with the "ssh" check strategy:
+	// "ecdsa", which is the default, "ed25519" or "rsa", e.g. for the images whose SSH server only accepts some key
+	// types. The keys are generated once per cluster and key type, and stored in the SSH keys secret of the
+	// KubevirtCluster. Changing it only applies to the VMs created afterwards.
+	// +optional
+	// +kubebuilder:validation:Enum=ecdsa;ed25519;rsa
+	KeyType SSHKeyType `json:"keyType,omitempty"`
 }
 
 // KubevirtMachineStatus defines the observed state of KubevirtMachine.
@@ -128,6 +879,82 @@ type KubevirtMachineStatus struct {
 	// controller's output.
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Migration describes the last live migration of the VM between the infra cluster nodes.
+	// +optional
+	Migration *VirtualMachineMigrationStatus `json:"migration,omitempty"`
+
+	// MACAddresses are the MAC addresses of the VM interfaces, as allocated by KubeVirt or a MAC address pool such as
+	// kubemacpool. They are reused for the interfaces without a MAC address when the VM is re-created, so that DHCP
+	// reservations and licenses tied to the MAC addresses survive.
+	// +optional
+	MACAddresses []InterfaceMACAddress `json:"macAddresses,omitempty"`
+
+	// HotplugVolumes are the names of the volumes hot-plugged into the VM from the hotplugVolumes of the spec. Only
+	// these volumes are unplugged when they are removed from the spec.
+	// +optional
+	HotplugVolumes []string `json:"hotplugVolumes,omitempty"`
+
+	// GuestMemory describes the guest memory hot-plugged into the running VM, when its memory hotplug is enabled.
+	// +optional
+	GuestMemory *GuestMemoryStatus `json:"guestMemory,omitempty"`
+
+	// InfraCluster is the name of the infra cluster, among the infraClusters of the KubevirtCluster, the VM of the
+	// machine is placed on.
+	// +optional
+	InfraCluster string `json:"infraCluster,omitempty"`
+
+	// InfraNodeTopology are the topology zone and region labels of the infra cluster node running the VM, applied to
+	// the tenant cluster node when nodeTopologyFromInfraNode is set.
+	// +optional
+	InfraNodeTopology map[string]string `json:"infraNodeTopology,omitempty"`
+}
+
+// GuestMemoryStatus describes the guest memory of the VM, as desired by the KubevirtMachine and as applied to the
+// running VM.
+type GuestMemoryStatus struct {
+	// Desired is the guest memory of the KubevirtMachine, set by its memory or else by its VM template.
+	// +optional
+	Desired *resource.Quantity `json:"desired,omitempty"`
+
+	// Applied is the guest memory currently available to the guest OS of the running VM, as reported by KubeVirt.
+	// +optional
+	Applied *resource.Quantity `json:"applied,omitempty"`
+}
+
+// InterfaceMACAddress is the MAC address of a VM interface.
+type InterfaceMACAddress struct {
+	// Name is the name of the VM interface.
+	Name string `json:"name"`
+
+	// MACAddress is the MAC address of the VM interface.
+	MACAddress string `json:"macAddress"`
+}
+
+// VirtualMachineMigrationStatus describes a live migration of the VM between the infra cluster nodes.
+type VirtualMachineMigrationStatus struct {
+	// Name is the name of the VirtualMachineInstanceMigration.
+	Name string `json:"name"`
+
+	// Phase is the phase of the migration.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// SourceNode is the infra cluster node the VM is migrated from.
+	// +optional
+	SourceNode string `json:"sourceNode,omitempty"`
+
+	// TargetNode is the infra cluster node the VM is migrated to.
+	// +optional
+	TargetNode string `json:"targetNode,omitempty"`
+
+	// StartTime is the time the migration started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is the time the migration ended.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
 }
 
 // +kubebuilder:resource:path=kubevirtmachines,scope=Namespaced,categories=cluster-api
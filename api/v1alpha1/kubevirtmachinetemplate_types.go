@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,16 +26,29 @@ type KubevirtMachineTemplateSpec struct {
 	Template KubevirtMachineTemplateResource `json:"template"`
 }
 
+// KubevirtMachineTemplateStatus defines the observed state of KubevirtMachineTemplate.
+type KubevirtMachineTemplateStatus struct {
+	// Capacity is the capacity of the nodes of the machines created from the template: the number of vCPUs, the guest
+	// memory and the number of GPUs of their VM, as "cpu", "memory" and "nvidia.com/gpu". The cluster autoscaler reads
+	// it to scale the node groups of the template from zero. The CPU and memory of the VMs with an instancetype are
+	// unknown; the capacity annotations of the cluster autoscaler, e.g.
+	// "capacity.cluster-autoscaler.kubernetes.io/memory", set on the template take precedence.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=kubevirtmachinetemplates,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // KubevirtMachineTemplate is the Schema for the kubevirtmachinetemplates API.
 type KubevirtMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec KubevirtMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   KubevirtMachineTemplateSpec   `json:"spec,omitempty"`
+	Status KubevirtMachineTemplateStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
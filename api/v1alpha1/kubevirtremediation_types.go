@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationStrategyType is how a KubevirtRemediation remediates an unhealthy machine.
+type RemediationStrategyType string
+
+const (
+	// RestartRemediationStrategy restarts the VM of the unhealthy machine, keeping its disks.
+	RestartRemediationStrategy RemediationStrategyType = "Restart"
+)
+
+// RemediationPhase is the phase of a KubevirtRemediation.
+type RemediationPhase string
+
+const (
+	// RunningRemediationPhase is the phase of a KubevirtRemediation restarting the VM of the unhealthy machine.
+	RunningRemediationPhase RemediationPhase = "Running"
+
+	// WaitingRemediationPhase is the phase of a KubevirtRemediation waiting for the restarted machine to become
+	// healthy.
+	WaitingRemediationPhase RemediationPhase = "Waiting"
+
+	// DeletingRemediationPhase is the phase of a KubevirtRemediation that gave up or couldn't restart the VM, and
+	// deleted the unhealthy machine for it to be replaced.
+	DeletingRemediationPhase RemediationPhase = "Deleting"
+)
+
+// RemediationStrategy is how an unhealthy machine is remediated.
+type RemediationStrategy struct {
+	// Type of the remediation. The only possible value is "Restart", which restarts the VM of the machine, keeping its
+	// disks. The machines whose VM doesn't have the Always run strategy are deleted instead. Defaults to "Restart".
+	// +optional
+	// +kubebuilder:validation:Enum=Restart
+	Type RemediationStrategyType `json:"type,omitempty"`
+
+	// RetryLimit is the maximum number of restarts of the VM, after which the machine is deleted, to be recreated by
+	// its MachineSet. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RetryLimit int `json:"retryLimit,omitempty"`
+
+	// Timeout is how long the machine has to become healthy after a restart of its VM. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// KubevirtRemediationSpec defines how the unhealthy machine is remediated.
+type KubevirtRemediationSpec struct {
+	// Strategy of the remediation.
+	// +optional
+	Strategy *RemediationStrategy `json:"strategy,omitempty"`
+}
+
+// KubevirtRemediationStatus defines the observed state of the remediation.
+type KubevirtRemediationStatus struct {
+	// Phase of the remediation: "Running", "Waiting" or "Deleting".
+	// +optional
+	Phase RemediationPhase `json:"phase,omitempty"`
+
+	// RetryCount is the number of restarts of the VM.
+	// +optional
+	RetryCount int `json:"retryCount,omitempty"`
+
+	// LastRemediated is the time of the last restart of the VM.
+	// +optional
+	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=kubevirtremediations,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the remediation"
+// +kubebuilder:printcolumn:name="Retries",type="integer",JSONPath=".status.retryCount",description="Number of restarts of the VM"
+
+// KubevirtRemediation is the Schema for the kubevirtremediations API. It is created by a MachineHealthCheck, with the
+// name of the unhealthy Machine, to remediate it by restarting its VM instead of replacing it.
+type KubevirtRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KubevirtRemediationSpec   `json:"spec,omitempty"`
+	Status KubevirtRemediationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubevirtRemediationList contains a list of KubevirtRemediation.
+type KubevirtRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubevirtRemediation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubevirtRemediation{}, &KubevirtRemediationList{})
+}
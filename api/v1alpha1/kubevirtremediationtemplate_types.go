@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubevirtRemediationTemplateSpec defines the desired state of KubevirtRemediationTemplate.
+type KubevirtRemediationTemplateSpec struct {
+	Template KubevirtRemediationTemplateResource `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=kubevirtremediationtemplates,scope=Namespaced,categories=cluster-api
+
+// KubevirtRemediationTemplate is the Schema for the kubevirtremediationtemplates API. It is referenced by the
+// remediationTemplate of a MachineHealthCheck, to remediate the unhealthy machines by restarting their VMs.
+type KubevirtRemediationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KubevirtRemediationTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubevirtRemediationTemplateList contains a list of KubevirtRemediationTemplate.
+type KubevirtRemediationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubevirtRemediationTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubevirtRemediationTemplate{}, &KubevirtRemediationTemplateList{})
+}
+
+// KubevirtRemediationTemplateResource describes the data needed to create a KubevirtRemediation from a template.
+type KubevirtRemediationTemplateResource struct {
+	// Spec is the specification of the desired behavior of the remediation.
+	Spec KubevirtRemediationSpec `json:"spec"`
+}
@@ -23,7 +23,10 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "kubevirt.io/api/core/v1"
+	corev1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
 )
@@ -43,11 +46,125 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalNetwork) DeepCopyInto(out *AdditionalNetwork) {
+	*out = *in
+	if in.AddressesFromPools != nil {
+		in, out := &in.AddressesFromPools, &out.AddressesFromPools
+		*out = make([]v1.TypedLocalObjectReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = new(uint)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalNetwork.
+func (in *AdditionalNetwork) DeepCopy() *AdditionalNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CDROM) DeepCopyInto(out *CDROM) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = new(uint)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CDROM.
+func (in *CDROM) DeepCopy() *CDROM {
+	if in == nil {
+		return nil
+	}
+	out := new(CDROM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigDisk) DeepCopyInto(out *ConfigDisk) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigDisk.
+func (in *ConfigDisk) DeepCopy() *ConfigDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneRoute) DeepCopyInto(out *ControlPlaneRoute) {
+	*out = *in
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayReference)
+		**out = **in
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneRoute.
+func (in *ControlPlaneRoute) DeepCopy() *ControlPlaneRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneServiceTemplate) DeepCopyInto(out *ControlPlaneServiceTemplate) {
 	*out = *in
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneServiceTemplate.
@@ -55,13 +172,1017 @@ func (in *ControlPlaneServiceTemplate) DeepCopy() *ControlPlaneServiceTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ControlPlaneServiceTemplate)
+	out := new(ControlPlaneServiceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSConfig) DeepCopyInto(out *DNSConfig) {
+	*out = *in
+	if in.Nameservers != nil {
+		in, out := &in.Nameservers, &out.Nameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Searches != nil {
+		in, out := &in.Searches, &out.Searches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSConfig.
+func (in *DNSConfig) DeepCopy() *DNSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataDisk) DeepCopyInto(out *DataDisk) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.VolumeMode != nil {
+		in, out := &in.VolumeMode, &out.VolumeMode
+		*out = new(v1.PersistentVolumeMode)
+		**out = **in
+	}
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = new(uint)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
+func (in *DataDisk) DeepCopy() *DataDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(DataDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureDomain) DeepCopyInto(out *FailureDomain) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.NetworkAttachmentDefinitions != nil {
+		in, out := &in.NetworkAttachmentDefinitions, &out.NetworkAttachmentDefinitions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomain.
+func (in *FailureDomain) DeepCopy() *FailureDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestMemoryStatus) DeepCopyInto(out *GuestMemoryStatus) {
+	*out = *in
+	if in.Desired != nil {
+		in, out := &in.Desired, &out.Desired
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestMemoryStatus.
+func (in *GuestMemoryStatus) DeepCopy() *GuestMemoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestMemoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HotplugVolume) DeepCopyInto(out *HotplugVolume) {
+	*out = *in
+	in.HotplugVolumeSource.DeepCopyInto(&out.HotplugVolumeSource)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HotplugVolume.
+func (in *HotplugVolume) DeepCopy() *HotplugVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(HotplugVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraClusterReference) DeepCopyInto(out *InfraClusterReference) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraClusterReference.
+func (in *InfraClusterReference) DeepCopy() *InfraClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceMACAddress) DeepCopyInto(out *InterfaceMACAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceMACAddress.
+func (in *InterfaceMACAddress) DeepCopy() *InterfaceMACAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceMACAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVIPSpec) DeepCopyInto(out *KubeVIPSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVIPSpec.
+func (in *KubeVIPSpec) DeepCopy() *KubeVIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtCluster) DeepCopyInto(out *KubevirtCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtCluster.
+func (in *KubevirtCluster) DeepCopy() *KubevirtCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterList) DeepCopyInto(out *KubevirtClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubevirtCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterList.
+func (in *KubevirtClusterList) DeepCopy() *KubevirtClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterSpec) DeepCopyInto(out *KubevirtClusterSpec) {
+	*out = *in
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	in.ControlPlaneServiceTemplate.DeepCopyInto(&out.ControlPlaneServiceTemplate)
+	if in.KubeVIP != nil {
+		in, out := &in.KubeVIP, &out.KubeVIP
+		*out = new(KubeVIPSpec)
+		**out = **in
+	}
+	if in.ControlPlaneRoute != nil {
+		in, out := &in.ControlPlaneRoute, &out.ControlPlaneRoute
+		*out = new(ControlPlaneRoute)
+		(*in).DeepCopyInto(*out)
+	}
+	in.SshKeys.DeepCopyInto(&out.SshKeys)
+	if in.SSHAuthorizedKeys != nil {
+		in, out := &in.SSHAuthorizedKeys, &out.SSHAuthorizedKeys
+		*out = new(SSHAuthorizedKeys)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InfraClusterSecretRef != nil {
+		in, out := &in.InfraClusterSecretRef, &out.InfraClusterSecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.InfraClusters != nil {
+		in, out := &in.InfraClusters, &out.InfraClusters
+		*out = make([]InfraClusterReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DrainPodExclusionSelector != nil {
+		in, out := &in.DrainPodExclusionSelector, &out.DrainPodExclusionSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxConcurrentDrains != nil {
+		in, out := &in.MaxConcurrentDrains, &out.MaxConcurrentDrains
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DrainPolicyRef != nil {
+		in, out := &in.DrainPolicyRef, &out.DrainPolicyRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(DNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]FailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VMCreationRateLimit != nil {
+		in, out := &in.VMCreationRateLimit, &out.VMCreationRateLimit
+		*out = new(VMCreationRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterSpec.
+func (in *KubevirtClusterSpec) DeepCopy() *KubevirtClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterStatus) DeepCopyInto(out *KubevirtClusterStatus) {
+	*out = *in
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make(v1beta1.FailureDomains, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ControlPlaneNodeAddresses != nil {
+		in, out := &in.ControlPlaneNodeAddresses, &out.ControlPlaneNodeAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterStatus.
+func (in *KubevirtClusterStatus) DeepCopy() *KubevirtClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterTemplate) DeepCopyInto(out *KubevirtClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplate.
+func (in *KubevirtClusterTemplate) DeepCopy() *KubevirtClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterTemplateList) DeepCopyInto(out *KubevirtClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubevirtClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplateList.
+func (in *KubevirtClusterTemplateList) DeepCopy() *KubevirtClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterTemplateResource) DeepCopyInto(out *KubevirtClusterTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplateResource.
+func (in *KubevirtClusterTemplateResource) DeepCopy() *KubevirtClusterTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtClusterTemplateSpec) DeepCopyInto(out *KubevirtClusterTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplateSpec.
+func (in *KubevirtClusterTemplateSpec) DeepCopy() *KubevirtClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtDrainPolicy) DeepCopyInto(out *KubevirtDrainPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtDrainPolicy.
+func (in *KubevirtDrainPolicy) DeepCopy() *KubevirtDrainPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtDrainPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtDrainPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtDrainPolicyList) DeepCopyInto(out *KubevirtDrainPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubevirtDrainPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtDrainPolicyList.
+func (in *KubevirtDrainPolicyList) DeepCopy() *KubevirtDrainPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtDrainPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtDrainPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtDrainPolicySpec) DeepCopyInto(out *KubevirtDrainPolicySpec) {
+	*out = *in
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainRetryInterval != nil {
+		in, out := &in.DrainRetryInterval, &out.DrainRetryInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SkipWaitForDeleteTimeout != nil {
+		in, out := &in.SkipWaitForDeleteTimeout, &out.SkipWaitForDeleteTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TenantUnreachableTimeout != nil {
+		in, out := &in.TenantUnreachableTimeout, &out.TenantUnreachableTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodEvictionGracePeriod != nil {
+		in, out := &in.PodEvictionGracePeriod, &out.PodEvictionGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodDisruptionBudgetTimeout != nil {
+		in, out := &in.PodDisruptionBudgetTimeout, &out.PodDisruptionBudgetTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GuestShutdownGracePeriod != nil {
+		in, out := &in.GuestShutdownGracePeriod, &out.GuestShutdownGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodExclusionSelector != nil {
+		in, out := &in.PodExclusionSelector, &out.PodExclusionSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxConcurrentDrains != nil {
+		in, out := &in.MaxConcurrentDrains, &out.MaxConcurrentDrains
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Force != nil {
+		in, out := &in.Force, &out.Force
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtDrainPolicySpec.
+func (in *KubevirtDrainPolicySpec) DeepCopy() *KubevirtDrainPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtDrainPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachine) DeepCopyInto(out *KubevirtMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachine.
+func (in *KubevirtMachine) DeepCopy() *KubevirtMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineList) DeepCopyInto(out *KubevirtMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubevirtMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineList.
+func (in *KubevirtMachineList) DeepCopy() *KubevirtMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineSpec) DeepCopyInto(out *KubevirtMachineSpec) {
+	*out = *in
+	in.VirtualMachineTemplate.DeepCopyInto(&out.VirtualMachineTemplate)
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	in.BootstrapCheckSpec.DeepCopyInto(&out.BootstrapCheckSpec)
+	if in.AdditionalUserDataSecretRef != nil {
+		in, out := &in.AdditionalUserDataSecretRef, &out.AdditionalUserDataSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SSHAuthorizedKeys != nil {
+		in, out := &in.SSHAuthorizedKeys, &out.SSHAuthorizedKeys
+		*out = new(SSHAuthorizedKeys)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InfraClusterSecretRef != nil {
+		in, out := &in.InfraClusterSecretRef, &out.InfraClusterSecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainRetryInterval != nil {
+		in, out := &in.DrainRetryInterval, &out.DrainRetryInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SkipWaitForDeleteTimeout != nil {
+		in, out := &in.SkipWaitForDeleteTimeout, &out.SkipWaitForDeleteTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TenantUnreachableTimeout != nil {
+		in, out := &in.TenantUnreachableTimeout, &out.TenantUnreachableTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodEvictionGracePeriod != nil {
+		in, out := &in.PodEvictionGracePeriod, &out.PodEvictionGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PodDisruptionBudgetTimeout != nil {
+		in, out := &in.PodDisruptionBudgetTimeout, &out.PodDisruptionBudgetTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GuestShutdownGracePeriod != nil {
+		in, out := &in.GuestShutdownGracePeriod, &out.GuestShutdownGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AdditionalNetworks != nil {
+		in, out := &in.AdditionalNetworks, &out.AdditionalNetworks
+		*out = make([]AdditionalNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(DNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RootDisk != nil {
+		in, out := &in.RootDisk, &out.RootDisk
+		*out = new(RootDisk)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataDisks != nil {
+		in, out := &in.DataDisks, &out.DataDisks
+		*out = make([]DataDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HotplugVolumes != nil {
+		in, out := &in.HotplugVolumes, &out.HotplugVolumes
+		*out = make([]HotplugVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigDisks != nil {
+		in, out := &in.ConfigDisks, &out.ConfigDisks
+		*out = make([]ConfigDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CDROMs != nil {
+		in, out := &in.CDROMs, &out.CDROMs
+		*out = make([]CDROM, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RunStrategy != nil {
+		in, out := &in.RunStrategy, &out.RunStrategy
+		*out = new(corev1.VirtualMachineRunStrategy)
+		**out = **in
+	}
+	if in.EvictionStrategy != nil {
+		in, out := &in.EvictionStrategy, &out.EvictionStrategy
+		*out = new(corev1.EvictionStrategy)
+		**out = **in
+	}
+	if in.Preemptible != nil {
+		in, out := &in.Preemptible, &out.Preemptible
+		*out = new(PreemptibleSpec)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(MemorySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningFailureTimeout != nil {
+		in, out := &in.ProvisioningFailureTimeout, &out.ProvisioningFailureTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.KernelBoot != nil {
+		in, out := &in.KernelBoot, &out.KernelBoot
+		*out = new(corev1.KernelBoot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SerialConsoleLog != nil {
+		in, out := &in.SerialConsoleLog, &out.SerialConsoleLog
+		*out = new(SerialConsoleLogSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VMNaming != nil {
+		in, out := &in.VMNaming, &out.VMNaming
+		*out = new(VMNamingSpec)
+		**out = **in
+	}
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(MetadataPropagationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeAnnotations != nil {
+		in, out := &in.NodeAnnotations, &out.NodeAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineSpec.
+func (in *KubevirtMachineSpec) DeepCopy() *KubevirtMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineStatus) DeepCopyInto(out *KubevirtMachineStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]v1beta1.MachineAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(VirtualMachineMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MACAddresses != nil {
+		in, out := &in.MACAddresses, &out.MACAddresses
+		*out = make([]InterfaceMACAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.HotplugVolumes != nil {
+		in, out := &in.HotplugVolumes, &out.HotplugVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GuestMemory != nil {
+		in, out := &in.GuestMemory, &out.GuestMemory
+		*out = new(GuestMemoryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InfraNodeTopology != nil {
+		in, out := &in.InfraNodeTopology, &out.InfraNodeTopology
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineStatus.
+func (in *KubevirtMachineStatus) DeepCopy() *KubevirtMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineTemplate) DeepCopyInto(out *KubevirtMachineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplate.
+func (in *KubevirtMachineTemplate) DeepCopy() *KubevirtMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtMachineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineTemplateList) DeepCopyInto(out *KubevirtMachineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubevirtMachineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateList.
+func (in *KubevirtMachineTemplateList) DeepCopy() *KubevirtMachineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubevirtMachineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineTemplateResource) DeepCopyInto(out *KubevirtMachineTemplateResource) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateResource.
+func (in *KubevirtMachineTemplateResource) DeepCopy() *KubevirtMachineTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineTemplateSpec) DeepCopyInto(out *KubevirtMachineTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateSpec.
+func (in *KubevirtMachineTemplateSpec) DeepCopy() *KubevirtMachineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubevirtMachineTemplateStatus) DeepCopyInto(out *KubevirtMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateStatus.
+func (in *KubevirtMachineTemplateStatus) DeepCopy() *KubevirtMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubevirtMachineTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtCluster) DeepCopyInto(out *KubevirtCluster) {
+func (in *KubevirtRemediation) DeepCopyInto(out *KubevirtRemediation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -69,18 +1190,18 @@ func (in *KubevirtCluster) DeepCopyInto(out *KubevirtCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtCluster.
-func (in *KubevirtCluster) DeepCopy() *KubevirtCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediation.
+func (in *KubevirtRemediation) DeepCopy() *KubevirtRemediation {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtCluster)
+	out := new(KubevirtRemediation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtCluster) DeepCopyObject() runtime.Object {
+func (in *KubevirtRemediation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -88,31 +1209,31 @@ func (in *KubevirtCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterList) DeepCopyInto(out *KubevirtClusterList) {
+func (in *KubevirtRemediationList) DeepCopyInto(out *KubevirtRemediationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KubevirtCluster, len(*in))
+		*out = make([]KubevirtRemediation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterList.
-func (in *KubevirtClusterList) DeepCopy() *KubevirtClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationList.
+func (in *KubevirtRemediationList) DeepCopy() *KubevirtRemediationList {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterList)
+	out := new(KubevirtRemediationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtClusterList) DeepCopyObject() runtime.Object {
+func (in *KubevirtRemediationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -120,77 +1241,64 @@ func (in *KubevirtClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterSpec) DeepCopyInto(out *KubevirtClusterSpec) {
+func (in *KubevirtRemediationSpec) DeepCopyInto(out *KubevirtRemediationSpec) {
 	*out = *in
-	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
-	in.ControlPlaneServiceTemplate.DeepCopyInto(&out.ControlPlaneServiceTemplate)
-	in.SshKeys.DeepCopyInto(&out.SshKeys)
-	if in.InfraClusterSecretRef != nil {
-		in, out := &in.InfraClusterSecretRef, &out.InfraClusterSecretRef
-		*out = new(v1.ObjectReference)
-		**out = **in
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(RemediationStrategy)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterSpec.
-func (in *KubevirtClusterSpec) DeepCopy() *KubevirtClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationSpec.
+func (in *KubevirtRemediationSpec) DeepCopy() *KubevirtRemediationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterSpec)
+	out := new(KubevirtRemediationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterStatus) DeepCopyInto(out *KubevirtClusterStatus) {
+func (in *KubevirtRemediationStatus) DeepCopyInto(out *KubevirtRemediationStatus) {
 	*out = *in
-	if in.FailureDomains != nil {
-		in, out := &in.FailureDomains, &out.FailureDomains
-		*out = make(v1beta1.FailureDomains, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1beta1.Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastRemediated != nil {
+		in, out := &in.LastRemediated, &out.LastRemediated
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterStatus.
-func (in *KubevirtClusterStatus) DeepCopy() *KubevirtClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationStatus.
+func (in *KubevirtRemediationStatus) DeepCopy() *KubevirtRemediationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterStatus)
+	out := new(KubevirtRemediationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterTemplate) DeepCopyInto(out *KubevirtClusterTemplate) {
+func (in *KubevirtRemediationTemplate) DeepCopyInto(out *KubevirtRemediationTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplate.
-func (in *KubevirtClusterTemplate) DeepCopy() *KubevirtClusterTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationTemplate.
+func (in *KubevirtRemediationTemplate) DeepCopy() *KubevirtRemediationTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterTemplate)
+	out := new(KubevirtRemediationTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtClusterTemplate) DeepCopyObject() runtime.Object {
+func (in *KubevirtRemediationTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -198,31 +1306,31 @@ func (in *KubevirtClusterTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterTemplateList) DeepCopyInto(out *KubevirtClusterTemplateList) {
+func (in *KubevirtRemediationTemplateList) DeepCopyInto(out *KubevirtRemediationTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KubevirtClusterTemplate, len(*in))
+		*out = make([]KubevirtRemediationTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplateList.
-func (in *KubevirtClusterTemplateList) DeepCopy() *KubevirtClusterTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationTemplateList.
+func (in *KubevirtRemediationTemplateList) DeepCopy() *KubevirtRemediationTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterTemplateList)
+	out := new(KubevirtRemediationTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtClusterTemplateList) DeepCopyObject() runtime.Object {
+func (in *KubevirtRemediationTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -230,287 +1338,308 @@ func (in *KubevirtClusterTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterTemplateResource) DeepCopyInto(out *KubevirtClusterTemplateResource) {
+func (in *KubevirtRemediationTemplateResource) DeepCopyInto(out *KubevirtRemediationTemplateResource) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplateResource.
-func (in *KubevirtClusterTemplateResource) DeepCopy() *KubevirtClusterTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationTemplateResource.
+func (in *KubevirtRemediationTemplateResource) DeepCopy() *KubevirtRemediationTemplateResource {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterTemplateResource)
+	out := new(KubevirtRemediationTemplateResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtClusterTemplateSpec) DeepCopyInto(out *KubevirtClusterTemplateSpec) {
+func (in *KubevirtRemediationTemplateSpec) DeepCopyInto(out *KubevirtRemediationTemplateSpec) {
 	*out = *in
 	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtClusterTemplateSpec.
-func (in *KubevirtClusterTemplateSpec) DeepCopy() *KubevirtClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtRemediationTemplateSpec.
+func (in *KubevirtRemediationTemplateSpec) DeepCopy() *KubevirtRemediationTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtClusterTemplateSpec)
+	out := new(KubevirtRemediationTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachine) DeepCopyInto(out *KubevirtMachine) {
+func (in *MemorySpec) DeepCopyInto(out *MemorySpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Guest != nil {
+		in, out := &in.Guest, &out.Guest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.OvercommitPercent != nil {
+		in, out := &in.OvercommitPercent, &out.OvercommitPercent
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachine.
-func (in *KubevirtMachine) DeepCopy() *KubevirtMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemorySpec.
+func (in *MemorySpec) DeepCopy() *MemorySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachine)
+	out := new(MemorySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtMachine) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineList) DeepCopyInto(out *KubevirtMachineList) {
+func (in *MetadataPropagationSpec) DeepCopyInto(out *MetadataPropagationSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KubevirtMachine, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineList.
-func (in *KubevirtMachineList) DeepCopy() *KubevirtMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPropagationSpec.
+func (in *MetadataPropagationSpec) DeepCopy() *MetadataPropagationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineList)
+	out := new(MetadataPropagationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtMachineList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreemptibleSpec) DeepCopyInto(out *PreemptibleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreemptibleSpec.
+func (in *PreemptibleSpec) DeepCopy() *PreemptibleSpec {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(PreemptibleSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineSpec) DeepCopyInto(out *KubevirtMachineSpec) {
+func (in *RemediationStrategy) DeepCopyInto(out *RemediationStrategy) {
 	*out = *in
-	in.VirtualMachineTemplate.DeepCopyInto(&out.VirtualMachineTemplate)
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
-		**out = **in
-	}
-	out.BootstrapCheckSpec = in.BootstrapCheckSpec
-	if in.InfraClusterSecretRef != nil {
-		in, out := &in.InfraClusterSecretRef, &out.InfraClusterSecretRef
-		*out = new(v1.ObjectReference)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineSpec.
-func (in *KubevirtMachineSpec) DeepCopy() *KubevirtMachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStrategy.
+func (in *RemediationStrategy) DeepCopy() *RemediationStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineSpec)
+	out := new(RemediationStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineStatus) DeepCopyInto(out *KubevirtMachineStatus) {
+func (in *RootDisk) DeepCopyInto(out *RootDisk) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]v1beta1.MachineAddress, len(*in))
-		copy(*out, *in)
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1beta1.Conditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.SourceRef != nil {
+		in, out := &in.SourceRef, &out.SourceRef
+		*out = new(corev1beta1.DataVolumeSourceRef)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.MachineStatusError)
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(corev1beta1.DataVolumeSourcePVC)
 		**out = **in
 	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
 		**out = **in
 	}
+	if in.VolumeMode != nil {
+		in, out := &in.VolumeMode, &out.VolumeMode
+		*out = new(v1.PersistentVolumeMode)
+		**out = **in
+	}
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = new(uint)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineStatus.
-func (in *KubevirtMachineStatus) DeepCopy() *KubevirtMachineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootDisk.
+func (in *RootDisk) DeepCopy() *RootDisk {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineStatus)
+	out := new(RootDisk)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineTemplate) DeepCopyInto(out *KubevirtMachineTemplate) {
+func (in *SSHAuthorizedKeys) DeepCopyInto(out *SSHAuthorizedKeys) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]SSHAuthorizedKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplate.
-func (in *KubevirtMachineTemplate) DeepCopy() *KubevirtMachineTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHAuthorizedKeys.
+func (in *SSHAuthorizedKeys) DeepCopy() *SSHAuthorizedKeys {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineTemplate)
+	out := new(SSHAuthorizedKeys)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtMachineTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineTemplateList) DeepCopyInto(out *KubevirtMachineTemplateList) {
+func (in *SSHKeys) DeepCopyInto(out *SSHKeys) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KubevirtMachineTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ConfigRef != nil {
+		in, out := &in.ConfigRef, &out.ConfigRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.DataSecretName != nil {
+		in, out := &in.DataSecretName, &out.DataSecretName
+		*out = new(string)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateList.
-func (in *KubevirtMachineTemplateList) DeepCopy() *KubevirtMachineTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeys.
+func (in *SSHKeys) DeepCopy() *SSHKeys {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineTemplateList)
+	out := new(SSHKeys)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubevirtMachineTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineTemplateResource) DeepCopyInto(out *KubevirtMachineTemplateResource) {
+func (in *SerialConsoleLogSpec) DeepCopyInto(out *SerialConsoleLogSpec) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.TailLines != nil {
+		in, out := &in.TailLines, &out.TailLines
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateResource.
-func (in *KubevirtMachineTemplateResource) DeepCopy() *KubevirtMachineTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SerialConsoleLogSpec.
+func (in *SerialConsoleLogSpec) DeepCopy() *SerialConsoleLogSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineTemplateResource)
+	out := new(SerialConsoleLogSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubevirtMachineTemplateSpec) DeepCopyInto(out *KubevirtMachineTemplateSpec) {
+func (in *ServiceSpecTemplate) DeepCopyInto(out *ServiceSpecTemplate) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(v1.IPFamilyPolicy)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubevirtMachineTemplateSpec.
-func (in *KubevirtMachineTemplateSpec) DeepCopy() *KubevirtMachineTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpecTemplate.
+func (in *ServiceSpecTemplate) DeepCopy() *ServiceSpecTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(KubevirtMachineTemplateSpec)
+	out := new(ServiceSpecTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SSHKeys) DeepCopyInto(out *SSHKeys) {
+func (in *VMCreationRateLimit) DeepCopyInto(out *VMCreationRateLimit) {
 	*out = *in
-	if in.ConfigRef != nil {
-		in, out := &in.ConfigRef, &out.ConfigRef
-		*out = new(v1.ObjectReference)
-		**out = **in
-	}
-	if in.DataSecretName != nil {
-		in, out := &in.DataSecretName, &out.DataSecretName
-		*out = new(string)
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeys.
-func (in *SSHKeys) DeepCopy() *SSHKeys {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMCreationRateLimit.
+func (in *VMCreationRateLimit) DeepCopy() *VMCreationRateLimit {
 	if in == nil {
 		return nil
 	}
-	out := new(SSHKeys)
+	out := new(VMCreationRateLimit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceSpecTemplate) DeepCopyInto(out *ServiceSpecTemplate) {
+func (in *VMNamingSpec) DeepCopyInto(out *VMNamingSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpecTemplate.
-func (in *ServiceSpecTemplate) DeepCopy() *ServiceSpecTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMNamingSpec.
+func (in *VMNamingSpec) DeepCopy() *VMNamingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceSpecTemplate)
+	out := new(VMNamingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -518,6 +1647,16 @@ func (in *ServiceSpecTemplate) DeepCopy() *ServiceSpecTemplate {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineBootstrapCheckSpec) DeepCopyInto(out *VirtualMachineBootstrapCheckSpec) {
 	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBootstrapCheckSpec.
@@ -530,6 +1669,29 @@ func (in *VirtualMachineBootstrapCheckSpec) DeepCopy() *VirtualMachineBootstrapC
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineMigrationStatus) DeepCopyInto(out *VirtualMachineMigrationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineMigrationStatus.
+func (in *VirtualMachineMigrationStatus) DeepCopy() *VirtualMachineMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineTemplateSpec) DeepCopyInto(out *VirtualMachineTemplateSpec) {
 	*out = *in
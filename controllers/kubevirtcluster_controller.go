@@ -19,22 +19,27 @@ package controllers
 import (
 	gocontext "context"
 	"fmt"
+	"net"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/strings/slices"
 	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/ssh"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -43,9 +48,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
+// endpointProbeInterval is the period the control plane endpoint is probed at while it doesn't accept TLS connections.
+const endpointProbeInterval = 30 * time.Second
+
+// nodeAddressesResyncPeriod is the period the addresses of the infra nodes a control plane service of type NodePort is
+// published on are refreshed at, on top of the changes of the nodes of the local infra cluster.
+const nodeAddressesResyncPeriod = 5 * time.Minute
+
 // KubevirtClusterReconciler reconciles a KubevirtCluster object.
 type KubevirtClusterReconciler struct {
 	client.Client
@@ -55,6 +69,9 @@ type KubevirtClusterReconciler struct {
 	APIReader    client.Reader
 	InfraCluster infracluster.InfraCluster
 	Log          logr.Logger
+	// EndpointProber checks that the control plane endpoint at the given address accepts TLS connections. The control
+	// plane endpoint is not probed when nil.
+	EndpointProber func(ctx gocontext.Context, address string) error
 }
 
 func GetLoadBalancerNamespace(kc *infrav1.KubevirtCluster, infraClusterNamespace string) string {
@@ -62,6 +79,10 @@ func GetLoadBalancerNamespace(kc *infrav1.KubevirtCluster, infraClusterNamespace
 	if kc.Spec.ControlPlaneServiceTemplate.ObjectMeta.Namespace != "" {
 		return kc.Spec.ControlPlaneServiceTemplate.ObjectMeta.Namespace
 	}
+	// Then the namespace the VMs of the cluster are placed in, for the service to select them
+	if kc.Spec.InfraClusterNamespace != "" {
+		return kc.Spec.InfraClusterNamespace
+	}
 	return infraClusterNamespace
 }
 
@@ -69,6 +90,9 @@ func GetLoadBalancerNamespace(kc *infrav1.KubevirtCluster, infraClusterNamespace
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=services;,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts;configmaps,verbs=delete;list
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tlsroutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=delete;list
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=delete;list
 
@@ -160,22 +184,60 @@ func (r *KubevirtClusterReconciler) Reconcile(goctx gocontext.Context, req ctrl.
 }
 
 func (r *KubevirtClusterReconciler) reconcileNormal(ctx *context.ClusterContext, externalLoadBalancer *loadbalancer.LoadBalancer) (ctrl.Result, error) {
-	// Create the service serving as load balancer, if not existing
-	if !externalLoadBalancer.IsFound() {
+	// Create the service serving as load balancer, if not existing, unless kube-vip announces the control plane endpoint
+	if ctx.KubevirtCluster.Spec.KubeVIP == nil && !externalLoadBalancer.IsFound() {
 		if err := externalLoadBalancer.Create(ctx); err != nil {
 			conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 			return ctrl.Result{}, errors.Wrap(err, "failed to create load balancer")
 		}
+	} else if err := externalLoadBalancer.UpdateMetadata(ctx); err != nil {
+		conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "failed to update load balancer")
+	}
+
+	// Route the TLS connections for the hostname of the control plane route to the load balancer
+	if ctx.KubevirtCluster.Spec.KubeVIP == nil {
+		if err := externalLoadBalancer.ReconcileRoute(ctx); err != nil {
+			conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, errors.Wrap(err, "failed to route the load balancer")
+		}
 	}
 
+	var result ctrl.Result
+	var endpointNodeNotReady string
+	serviceType := ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type
+	nodeAddressesDiscovered := isNodeAddressesDiscovered(ctx.KubevirtCluster)
+	if !nodeAddressesDiscovered {
+		ctx.KubevirtCluster.Status.ControlPlaneNodeAddresses = nil
+	}
+	switch {
 	// Get the ControlPlane Host and Port manually set by the user if existing
-	if ctx.KubevirtCluster.Spec.ControlPlaneEndpoint.Host != "" {
+	case ctx.KubevirtCluster.Spec.ControlPlaneEndpoint.Host != "" && !nodeAddressesDiscovered && (ctx.KubevirtCluster.Spec.ControlPlaneEndpoint.Port != 0 || serviceType != corev1.ServiceTypeNodePort):
 		ctx.KubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{
 			Host: ctx.KubevirtCluster.Spec.ControlPlaneEndpoint.Host,
 			Port: ctx.KubevirtCluster.Spec.ControlPlaneEndpoint.Port,
 		}
-		// Get LoadBalancer ExternalIP if cluster Service Type is LoadBalancer
-	} else if ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type == "LoadBalancer" {
+
+	// Get the virtual IP announced by kube-vip
+	case ctx.KubevirtCluster.Spec.KubeVIP != nil:
+		ctx.KubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{
+			Host: ctx.KubevirtCluster.Spec.KubeVIP.Address,
+			Port: 6443,
+		}
+
+	// Get the hostname of the route through a Gateway or an Ingress controller
+	case ctx.KubevirtCluster.Spec.ControlPlaneRoute != nil:
+		port := int(ctx.KubevirtCluster.Spec.ControlPlaneRoute.Port)
+		if port == 0 {
+			port = loadbalancer.DefaultRoutePort
+		}
+		ctx.KubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{
+			Host: ctx.KubevirtCluster.Spec.ControlPlaneRoute.Hostname,
+			Port: port,
+		}
+
+	// Get LoadBalancer ExternalIP if cluster Service Type is LoadBalancer
+	case serviceType == corev1.ServiceTypeLoadBalancer:
 		lbip4, err := externalLoadBalancer.ExternalIP(ctx)
 		if err != nil {
 			conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
@@ -186,8 +248,44 @@ func (r *KubevirtClusterReconciler) reconcileNormal(ctx *context.ClusterContext,
 			Port: 6443,
 		}
 
-		// Get Cluster IP if cluster Service Type is CusterIP
-	} else {
+	// Get the NodePort on the host set by the user if cluster Service Type is NodePort
+	case serviceType == corev1.ServiceTypeNodePort:
+		nodePort, err := externalLoadBalancer.NodePort(ctx)
+		if err != nil {
+			conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, errors.Wrap(err, "failed to get NodePort for the load balancer")
+		}
+		host := ctx.KubevirtCluster.Spec.ControlPlaneEndpoint.Host
+		if nodeAddressesDiscovered {
+			addresses, err := externalLoadBalancer.NodeAddresses(ctx, ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.NodeSelector)
+			if err != nil {
+				conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				return ctrl.Result{}, errors.Wrap(err, "failed to get the infra node addresses for the load balancer")
+			}
+			ctx.KubevirtCluster.Status.ControlPlaneNodeAddresses = addresses
+			// the infra nodes of an external infra cluster are not watched.
+			result.RequeueAfter = nodeAddressesResyncPeriod
+			switch {
+			case host == "" && len(addresses) == 0:
+				conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.WaitingForControlPlaneEndpointHostReason, clusterv1.ConditionSeverityInfo,
+					"no ready infra node with an address matches the node selector of the control plane service")
+				return result, nil
+			case host == "":
+				host = addresses[0]
+				annotations.AddAnnotations(ctx.KubevirtCluster, map[string]string{infrav1.ControlPlaneNodeAddressAnnotation: host})
+			case !slices.Contains(addresses, host):
+				// The kubeconfig and the certificates of the tenant cluster refer to the control plane endpoint, which
+				// must never move
+				endpointNodeNotReady = fmt.Sprintf("the control plane endpoint host %s is no longer the address of a ready infra node matching the node selector of the control plane service", host)
+			}
+		}
+		ctx.KubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{
+			Host: host,
+			Port: int(nodePort),
+		}
+
+	// Get Cluster IP if cluster Service Type is CusterIP
+	default:
 		lbip4, err := externalLoadBalancer.IP(ctx)
 		if err != nil {
 			conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
@@ -199,7 +297,11 @@ func (r *KubevirtClusterReconciler) reconcileNormal(ctx *context.ClusterContext,
 		}
 	}
 
-	conditions.MarkTrue(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition)
+	if endpointNodeNotReady != "" {
+		conditions.MarkFalse(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition, infrav1.ControlPlaneEndpointNodeNotReadyReason, clusterv1.ConditionSeverityWarning, endpointNodeNotReady)
+	} else {
+		conditions.MarkTrue(ctx.KubevirtCluster, infrav1.LoadBalancerAvailableCondition)
+	}
 
 	// Generate ssh keys for cluster nodes, and persist them to a secret
 	clusterNodeSSHKeys := ssh.NewClusterNodeSshKeys(ctx, r.Client)
@@ -223,10 +325,59 @@ func (r *KubevirtClusterReconciler) reconcileNormal(ctx *context.ClusterContext,
 		}
 	}
 
+	// Report the failure domains, for Cluster API to spread the machines across them
+	ctx.KubevirtCluster.Status.FailureDomains = nil
+	for _, failureDomain := range ctx.KubevirtCluster.Spec.FailureDomains {
+		if ctx.KubevirtCluster.Status.FailureDomains == nil {
+			ctx.KubevirtCluster.Status.FailureDomains = clusterv1.FailureDomains{}
+		}
+		ctx.KubevirtCluster.Status.FailureDomains[failureDomain.Name] = clusterv1.FailureDomainSpec{ControlPlane: failureDomain.ControlPlane}
+	}
+
+	// Probe the control plane endpoint, to flag the broken load balancers before the control plane provider times out
+	if r.EndpointProber != nil && !r.probeControlPlaneEndpoint(ctx) {
+		if result.RequeueAfter == 0 || result.RequeueAfter > endpointProbeInterval {
+			result.RequeueAfter = endpointProbeInterval
+		}
+	}
+
 	// Mark the KubevirtCluster ready
 	ctx.KubevirtCluster.Status.Ready = true
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// probeControlPlaneEndpoint reflects whether the control plane endpoint accepts TLS connections in the
+// ControlPlaneEndpointReachable condition, and returns whether it does. The endpoint is only expected to be reachable
+// once the control plane is initialized, and it isn't probed before, not to block the reconciliation on the dial.
+func (r *KubevirtClusterReconciler) probeControlPlaneEndpoint(ctx *context.ClusterContext) bool {
+	if !conditions.IsTrue(ctx.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+		conditions.MarkFalse(ctx.KubevirtCluster, infrav1.ControlPlaneEndpointReachableCondition, infrav1.WaitingForControlPlaneReason, clusterv1.ConditionSeverityInfo, "")
+		return false
+	}
+
+	endpoint := ctx.KubevirtCluster.Spec.ControlPlaneEndpoint
+	address := net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+	if err := r.EndpointProber(ctx, address); err != nil {
+		conditions.MarkFalse(ctx.KubevirtCluster, infrav1.ControlPlaneEndpointReachableCondition, infrav1.ControlPlaneEndpointUnreachableReason, clusterv1.ConditionSeverityWarning,
+			"the control plane endpoint %s doesn't accept TLS connections: %v", address, err)
+		return false
+	}
+
+	conditions.MarkTrue(ctx.KubevirtCluster, infrav1.ControlPlaneEndpointReachableCondition)
+	return true
+}
+
+// isNodeAddressesDiscovered returns whether the control plane endpoint of the KubevirtCluster is published on the
+// address of one of the infra nodes its control plane service of type NodePort is reachable on: when the host of the
+// endpoint is not set, or is the node address the controller published it on.
+func isNodeAddressesDiscovered(kubevirtCluster *infrav1.KubevirtCluster) bool {
+	if kubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type != corev1.ServiceTypeNodePort ||
+		kubevirtCluster.Spec.KubeVIP != nil || kubevirtCluster.Spec.ControlPlaneRoute != nil {
+		return false
+	}
+	host := kubevirtCluster.Spec.ControlPlaneEndpoint.Host
+	return host == "" || kubevirtCluster.Annotations[infrav1.ControlPlaneNodeAddressAnnotation] == host
 }
 
 func (r *KubevirtClusterReconciler) reconcileDelete(ctx *context.ClusterContext, externalLoadBalancer *loadbalancer.LoadBalancer) (ctrl.Result, error) {
@@ -234,6 +385,9 @@ func (r *KubevirtClusterReconciler) reconcileDelete(ctx *context.ClusterContext,
 	if err := externalLoadBalancer.Delete(ctx); err != nil {
 		ctx.Logger.Error(err, "Failed to delete load balancer service.")
 	}
+	if err := externalLoadBalancer.DeleteRoute(ctx); err != nil {
+		ctx.Logger.Error(err, "Failed to delete load balancer route.")
+	}
 
 	// Set the LoadBalancerAvailableCondition reporting delete is started, and issue a patch in order to make
 	// this visible to the users.
@@ -279,9 +433,55 @@ func (r *KubevirtClusterReconciler) SetupWithManager(ctx gocontext.Context, mgr
 			)),
 			builder.WithPredicates(predicates.ClusterUnpaused(r.Log)),
 		).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.InfraNodeToKubevirtClusters),
+			builder.WithPredicates(nodeAddressesChanged()),
+		).
 		Complete(r)
 }
 
+// InfraNodeToKubevirtClusters is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation of the
+// KubevirtClusters publishing their control plane endpoint on the addresses of the nodes of the local infra cluster.
+func (r *KubevirtClusterReconciler) InfraNodeToKubevirtClusters(ctx gocontext.Context, _ client.Object) []ctrl.Request {
+	var result []ctrl.Request
+
+	kubevirtClusters := &infrav1.KubevirtClusterList{}
+	if err := r.Client.List(ctx, kubevirtClusters); err != nil {
+		return nil
+	}
+	for i := range kubevirtClusters.Items {
+		kubevirtCluster := &kubevirtClusters.Items[i]
+		if kubevirtCluster.Spec.InfraClusterSecretRef != nil || !isNodeAddressesDiscovered(kubevirtCluster) {
+			continue
+		}
+		result = append(result, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+	}
+
+	return result
+}
+
+// nodeAddressesChanged returns a predicate that only passes the nodes that were added, removed, relabeled, or whose
+// addresses or readiness changed.
+func nodeAddressesChanged() predicate.Funcs {
+	return predicate.Funcs{
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+				!equality.Semantic.DeepEqual(oldNode.Status.Addresses, newNode.Status.Addresses) ||
+				noderefutil.IsNodeReady(oldNode) != noderefutil.IsNodeReady(newNode)
+		},
+	}
+}
+
 func (r *KubevirtClusterReconciler) deleteExtraGVK(ctx *context.ClusterContext, extraGVK schema.GroupVersionKind) error {
 	if ctx.KubevirtCluster == nil {
 		return nil
@@ -7,12 +7,17 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	. "sigs.k8s.io/controller-runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/controllers"
@@ -89,6 +94,36 @@ var _ = Describe("Reconcile", func() {
 			Expect(result.Requeue).To(BeFalse())
 		})
 
+		It("should report the failure domains", func() {
+			kubevirtCluster.Spec.FailureDomains = []infrav1.FailureDomain{
+				{Name: "zone-a", ControlPlane: true},
+				{Name: "zone-b"},
+			}
+			kubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: "10.0.0.1", Port: 6443}
+			controllerutil.AddFinalizer(kubevirtCluster, infrav1.ClusterFinalizer)
+			objects := []client.Object{
+				cluster,
+				kubevirtCluster,
+			}
+			setupClient(objects)
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			_, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{
+				NamespacedName: client.ObjectKey{
+					Namespace: kubevirtCluster.Namespace,
+					Name:      kubevirtCluster.Name,
+				},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			Expect(reconciled.Status.FailureDomains).To(Equal(clusterv1.FailureDomains{
+				"zone-a": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"zone-b": clusterv1.FailureDomainSpec{},
+			}))
+		})
+
 		It("should not create cluster when namespace and kubevirtCluster is not specified", func() {
 			result, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{
 				NamespacedName: client.ObjectKey{
@@ -144,6 +179,264 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	Context("reconcile a cluster exposing its control plane with a NodePort service", func() {
+		var lbService *corev1.Service
+
+		BeforeEach(func() {
+			clusterName = "test-cluster"
+			kubevirtClusterName = "test-kubevirt-cluster"
+			kubevirtCluster = testing.NewKubevirtCluster(kubevirtClusterName, kubevirtClusterName)
+			kubevirtCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+			kubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type = corev1.ServiceTypeNodePort
+			cluster = testing.NewCluster(kubevirtClusterName, kubevirtCluster)
+			lbService = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: kubevirtCluster.Namespace, Name: cluster.Name + "-lb"},
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{{Port: 6443, NodePort: 30443}},
+				},
+			}
+		})
+
+		reconcile := func() *infrav1.KubevirtCluster {
+			setupClient([]client.Object{cluster, kubevirtCluster, lbService})
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			_, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			return reconciled
+		}
+
+		It("should publish the node port on the host of the control plane endpoint", func() {
+			kubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: "infra.example.com"}
+
+			reconciled := reconcile()
+			Expect(reconciled.Spec.ControlPlaneEndpoint).To(Equal(infrav1.APIEndpoint{Host: "infra.example.com", Port: 30443}))
+			Expect(reconciled.Status.Ready).To(BeTrue())
+		})
+
+		It("should update the annotations of the existing service", func() {
+			kubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: "infra.example.com"}
+			kubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta.Annotations = map[string]string{
+				"external-dns.alpha.kubernetes.io/hostname": "api.example.com",
+			}
+
+			reconcile()
+			updated := &corev1.Service{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(lbService), updated)).To(Succeed())
+			Expect(updated.Annotations).To(HaveKeyWithValue("external-dns.alpha.kubernetes.io/hostname", "api.example.com"))
+			Expect(updated.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+		})
+
+		It("should publish the node port on the address of an infra node", func() {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "infra-node"},
+				Status: corev1.NodeStatus{
+					Addresses:  []corev1.NodeAddress{{Type: corev1.NodeExternalIP, Address: "203.0.113.1"}},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			otherNode := node.DeepCopy()
+			otherNode.Name = "other-infra-node"
+			otherNode.Status.Addresses[0].Address = "203.0.113.2"
+			kubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: "203.0.113.2", Port: 30443}
+			kubevirtCluster.Annotations = map[string]string{infrav1.ControlPlaneNodeAddressAnnotation: "203.0.113.2"}
+			setupClient([]client.Object{cluster, kubevirtCluster, lbService, node, otherNode})
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			result, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			// the endpoint stays on the address it was published on
+			Expect(reconciled.Spec.ControlPlaneEndpoint).To(Equal(infrav1.APIEndpoint{Host: "203.0.113.2", Port: 30443}))
+			Expect(reconciled.Status.ControlPlaneNodeAddresses).To(Equal([]string{"203.0.113.1", "203.0.113.2"}))
+			Expect(reconciled.Status.Ready).To(BeTrue())
+
+			Expect(kubevirtClusterReconciler.InfraNodeToKubevirtClusters(fakeContext, node)).To(ConsistOf(
+				Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)},
+			))
+		})
+
+		It("should publish the node port on the first infra node address when no host is set", func() {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "infra-node"},
+				Status: corev1.NodeStatus{
+					Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			setupClient([]client.Object{cluster, kubevirtCluster, lbService, node})
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			_, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			Expect(reconciled.Spec.ControlPlaneEndpoint).To(Equal(infrav1.APIEndpoint{Host: "10.0.0.1", Port: 30443}))
+			Expect(reconciled.Annotations).To(HaveKeyWithValue(infrav1.ControlPlaneNodeAddressAnnotation, "10.0.0.1"))
+			Expect(conditions.IsTrue(reconciled, infrav1.LoadBalancerAvailableCondition)).To(BeTrue())
+		})
+
+		It("should not move the control plane endpoint off an infra node that is no longer ready", func() {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "infra-node"},
+				Status: corev1.NodeStatus{
+					Addresses:  []corev1.NodeAddress{{Type: corev1.NodeExternalIP, Address: "203.0.113.1"}},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			notReadyNode := node.DeepCopy()
+			notReadyNode.Name = "not-ready-infra-node"
+			notReadyNode.Status.Addresses[0].Address = "203.0.113.2"
+			notReadyNode.Status.Conditions[0].Status = corev1.ConditionFalse
+			kubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: "203.0.113.2", Port: 30443}
+			kubevirtCluster.Annotations = map[string]string{infrav1.ControlPlaneNodeAddressAnnotation: "203.0.113.2"}
+			setupClient([]client.Object{cluster, kubevirtCluster, lbService, node, notReadyNode})
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			_, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			Expect(reconciled.Spec.ControlPlaneEndpoint).To(Equal(infrav1.APIEndpoint{Host: "203.0.113.2", Port: 30443}))
+			Expect(reconciled.Status.ControlPlaneNodeAddresses).To(Equal([]string{"203.0.113.1"}))
+			Expect(conditions.GetReason(reconciled, infrav1.LoadBalancerAvailableCondition)).To(Equal(infrav1.ControlPlaneEndpointNodeNotReadyReason))
+			Expect(reconciled.Status.Ready).To(BeTrue())
+		})
+
+		It("should wait for the host of the control plane endpoint", func() {
+			reconciled := reconcile()
+			Expect(reconciled.Spec.ControlPlaneEndpoint.Port).To(BeZero())
+			Expect(reconciled.Status.Ready).To(BeFalse())
+			Expect(conditions.GetReason(reconciled, infrav1.LoadBalancerAvailableCondition)).To(Equal(infrav1.WaitingForControlPlaneEndpointHostReason))
+		})
+	})
+
+	Context("reconcile a cluster exposing its control plane with kube-vip", func() {
+		BeforeEach(func() {
+			clusterName = "test-cluster"
+			kubevirtClusterName = "test-kubevirt-cluster"
+			kubevirtCluster = testing.NewKubevirtCluster(kubevirtClusterName, kubevirtClusterName)
+			kubevirtCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+			kubevirtCluster.Spec.KubeVIP = &infrav1.KubeVIPSpec{Address: "10.0.0.100"}
+			cluster = testing.NewCluster(kubevirtClusterName, kubevirtCluster)
+		})
+
+		It("should publish the virtual IP without creating a service", func() {
+			setupClient([]client.Object{cluster, kubevirtCluster})
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			_, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			Expect(reconciled.Spec.ControlPlaneEndpoint).To(Equal(infrav1.APIEndpoint{Host: "10.0.0.100", Port: 6443}))
+			Expect(reconciled.Status.Ready).To(BeTrue())
+
+			services := &corev1.ServiceList{}
+			Expect(fakeClient.List(fakeContext, services)).To(Succeed())
+			Expect(services.Items).To(BeEmpty())
+		})
+	})
+
+	Context("reconcile a cluster exposing its control plane with a route", func() {
+		BeforeEach(func() {
+			clusterName = "test-cluster"
+			kubevirtClusterName = "test-kubevirt-cluster"
+			kubevirtCluster = testing.NewKubevirtCluster(kubevirtClusterName, kubevirtClusterName)
+			kubevirtCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+			kubevirtCluster.Spec.ControlPlaneRoute = &infrav1.ControlPlaneRoute{Hostname: "tenant.example.com"}
+			cluster = testing.NewCluster(kubevirtClusterName, kubevirtCluster)
+		})
+
+		It("should publish the hostname of the route", func() {
+			setupClient([]client.Object{cluster, kubevirtCluster})
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			_, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			Expect(reconciled.Spec.ControlPlaneEndpoint).To(Equal(infrav1.APIEndpoint{Host: "tenant.example.com", Port: 443}))
+			Expect(reconciled.Status.Ready).To(BeTrue())
+
+			ingresses := &networkingv1.IngressList{}
+			Expect(fakeClient.List(fakeContext, ingresses)).To(Succeed())
+			Expect(ingresses.Items).To(HaveLen(1))
+		})
+	})
+
+	Context("reconcile a cluster probing its control plane endpoint", func() {
+		var probedAddress string
+
+		BeforeEach(func() {
+			clusterName = "test-cluster"
+			kubevirtClusterName = "test-kubevirt-cluster"
+			kubevirtCluster = testing.NewKubevirtCluster(kubevirtClusterName, kubevirtClusterName)
+			kubevirtCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+			kubevirtCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{Host: "10.0.0.1", Port: 6443}
+			cluster = testing.NewCluster(kubevirtClusterName, kubevirtCluster)
+			probedAddress = ""
+		})
+
+		reconcile := func(probeErr error) (ctrl.Result, *infrav1.KubevirtCluster) {
+			setupClient([]client.Object{cluster, kubevirtCluster})
+			kubevirtClusterReconciler.EndpointProber = func(_ goContext.Context, address string) error {
+				probedAddress = address
+				return probeErr
+			}
+			infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, kubevirtCluster.Namespace, nil)
+
+			result, err := kubevirtClusterReconciler.Reconcile(fakeContext, Request{NamespacedName: client.ObjectKeyFromObject(kubevirtCluster)})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			reconciled := &infrav1.KubevirtCluster{}
+			Expect(fakeClient.Get(fakeContext, client.ObjectKeyFromObject(kubevirtCluster), reconciled)).To(Succeed())
+			return result, reconciled
+		}
+
+		It("should report a reachable control plane endpoint", func() {
+			conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+
+			result, reconciled := reconcile(nil)
+
+			Expect(probedAddress).To(Equal("10.0.0.1:6443"))
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(conditions.IsTrue(reconciled, infrav1.ControlPlaneEndpointReachableCondition)).To(BeTrue())
+		})
+
+		It("should wait for the control plane to be initialized without probing the endpoint", func() {
+			result, reconciled := reconcile(nil)
+
+			Expect(probedAddress).To(BeEmpty())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+			Expect(conditions.GetReason(reconciled, infrav1.ControlPlaneEndpointReachableCondition)).To(Equal(infrav1.WaitingForControlPlaneReason))
+			Expect(*conditions.GetSeverity(reconciled, infrav1.ControlPlaneEndpointReachableCondition)).To(Equal(clusterv1.ConditionSeverityInfo))
+			Expect(reconciled.Status.Ready).To(BeTrue())
+		})
+
+		It("should report an unreachable control plane endpoint of an initialized control plane", func() {
+			conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+
+			result, reconciled := reconcile(errors.New("connection refused"))
+
+			Expect(result.RequeueAfter).ToNot(BeZero())
+			Expect(conditions.GetReason(reconciled, infrav1.ControlPlaneEndpointReachableCondition)).To(Equal(infrav1.ControlPlaneEndpointUnreachableReason))
+			Expect(*conditions.GetSeverity(reconciled, infrav1.ControlPlaneEndpointReachableCondition)).To(Equal(clusterv1.ConditionSeverityWarning))
+			Expect(conditions.GetMessage(reconciled, infrav1.ControlPlaneEndpointReachableCondition)).To(ContainSubstring("connection refused"))
+		})
+	})
+
 	Context("reconcile cluster with finalizer and deletion time stamp", func() {
 		BeforeEach(func() {
 			clusterName = "test-cluster"
@@ -197,6 +490,12 @@ var _ = Describe("Reconcile", func() {
 			ns := controllers.GetLoadBalancerNamespace(kubevirtCluster, kubeconfigNamespace)
 			Expect(ns).To(Equal("lb-namespace"))
 		})
+		It("should use the infra cluster namespace if LB namespace is not set", func() {
+			kubevirtCluster = testing.NewKubevirtClusterWithNamespacedLB(kubevirtClusterName, kubevirtClusterName, "")
+			kubevirtCluster.Spec.InfraClusterNamespace = "infra-namespace"
+			ns := controllers.GetLoadBalancerNamespace(kubevirtCluster, kubeconfigNamespace)
+			Expect(ns).To(Equal("infra-namespace"))
+		})
 		It("should use kubeconfig namespace if LB namespace is not set", func() {
 			kubevirtCluster = testing.NewKubevirtClusterWithNamespacedLB(kubevirtClusterName, kubevirtClusterName, "")
 			ns := controllers.GetLoadBalancerNamespace(kubevirtCluster, kubeconfigNamespace)
@@ -18,8 +18,12 @@ package controllers
 
 import (
 	gocontext "context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net"
 	"regexp"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -27,10 +31,12 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	kubevirtv1 "kubevirt.io/api/core/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster"
@@ -40,6 +46,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -49,7 +56,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// serialConsoleContainerName is the container of the virt-launcher pod KubeVirt logs the serial console of the VM
+	// to, when the logSerialConsole of the VM is enabled.
+	serialConsoleContainerName = "guest-console-log"
+
+	// serialConsoleConfigMapKey is the key of the serial console output in the serial console ConfigMap.
+	serialConsoleConfigMapKey = "serial-console.log"
+
+	defaultSerialConsoleTailLines = int64(100)
+
+	// defaultVMCreationInterval is the period the VM creations of a cluster are counted over, when its VM creation
+	// rate limit doesn't set one.
+	defaultVMCreationInterval = time.Minute
+
+	// kubeVIPManifestPath is the static pod manifest of kube-vip on the control plane nodes.
+	kubeVIPManifestPath = "/etc/kubernetes/manifests/kube-vip.yaml"
+
+	defaultKubeVIPImage     = "ghcr.io/kube-vip/kube-vip:v0.6.4"
+	defaultKubeVIPInterface = "eth0"
 )
 
 // KubevirtMachineReconciler reconciles a KubevirtMachine object.
@@ -58,14 +89,35 @@ type KubevirtMachineReconciler struct {
 	InfraCluster    infracluster.InfraCluster
 	WorkloadCluster workloadcluster.WorkloadCluster
 	MachineFactory  kubevirt.MachineFactory
+	DrainOptions    kubevirt.DrainOptions
+	CreationLimiter *kubevirt.VMCreationLimiter
+	DefaultCPUModel string
+	CPUHotplug      bool
+	MemoryHotplug   bool
+	CapacityCheck   bool
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtdrainpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=delete;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachines;,verbs=get;create;update;patch;delete
-// +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachineinstances;,verbs=get;delete
+// +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachines/status,verbs=get;patch
+// +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachineinstances;,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachineinstancemigrations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubevirt.io,resources=kubevirts,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cdi.kubevirt.io,resources=datasources;storageprofiles,verbs=get
+// +kubebuilder:rbac:groups=cdi.kubevirt.io,resources=datavolumes,verbs=get;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
 
 // Reconcile handles KubevirtMachine events.
 func (r *KubevirtMachineReconciler) Reconcile(goctx gocontext.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
@@ -133,6 +185,15 @@ func (r *KubevirtMachineReconciler) Reconcile(goctx gocontext.Context, req ctrl.
 
 	log = log.WithValues("kubevirt-cluster", kubevirtCluster.Name)
 
+	// Fetch the KubevirtDrainPolicy, if any.
+	drainPolicy, err := r.getDrainPolicy(goctx, kubevirtCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if drainPolicy == nil && kubevirtCluster.Spec.DrainPolicyRef != nil {
+		log.Info(fmt.Sprintf("KubevirtDrainPolicy %s is not available; using the default drain settings", kubevirtCluster.Spec.DrainPolicyRef.Name))
+	}
+
 	// Create the machine context for this request.
 	machineContext := &context.MachineContext{
 		Context:         goctx,
@@ -140,6 +201,11 @@ func (r *KubevirtMachineReconciler) Reconcile(goctx gocontext.Context, req ctrl.
 		KubevirtCluster: kubevirtCluster,
 		Machine:         machine,
 		KubevirtMachine: kubevirtMachine,
+		DrainPolicy:     drainPolicy,
+		DefaultCPUModel: r.DefaultCPUModel,
+		CPUHotplug:      r.CPUHotplug,
+		MemoryHotplug:   r.MemoryHotplug,
+		CapacityCheck:   r.CapacityCheck,
 		Logger:          ctrl.LoggerFrom(goctx).WithName(req.Namespace).WithName(req.Name),
 	}
 
@@ -203,21 +269,54 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 	// Fetch SSH keys to be used for cluster nodes, and update bootstrap script cloud-init with public key
 	var clusterNodeSshKeys *ssh.ClusterNodeSshKeys
 
-	if !annotations.IsExternallyManaged(ctx.KubevirtCluster) {
+	// The guest agent bootstrap check doesn't need the ssh keys.
+	if !annotations.IsExternallyManaged(ctx.KubevirtCluster) && ctx.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy != "guestAgent" {
 		clusterNodeSshKeys = ssh.NewClusterNodeSshKeys(ctx.ClusterContext(), r.Client)
+		clusterNodeSshKeys.KeyType = ctx.KubevirtMachine.Spec.BootstrapCheckSpec.KeyType
 		if persisted := clusterNodeSshKeys.IsPersistedToSecret(); !persisted {
-			ctx.Logger.Info("Waiting for ssh keys data secret to be created by KubevirtCluster controller...")
-			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			if _, err := clusterNodeSshKeys.GetKeysDataSecret(); err != nil {
+				ctx.Logger.Info("Waiting for ssh keys data secret to be created by KubevirtCluster controller...")
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+
+			// The keys of the other types than the default one are added to the secret for the first machine using them.
+			if err := clusterNodeSshKeys.GenerateNewKeys(); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to generate new ssh keys")
+			}
+			if _, err := clusterNodeSshKeys.PersistKeysToSecret(); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to persist ssh keys to secret")
+			}
 		}
 		if err := clusterNodeSshKeys.FetchPersistedKeysFromSecret(); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to fetch ssh keys for cluster nodes")
 		}
 	}
 
-	// Default the infra cluster secret ref when the
-	// machine does not have one set.
-	if ctx.KubevirtMachine.Spec.InfraClusterSecretRef == nil {
-		ctx.KubevirtMachine.Spec.InfraClusterSecretRef = ctx.KubevirtCluster.Spec.InfraClusterSecretRef
+	// Place the worker machine on one of the infra clusters of the
+	// cluster when the machine does not have a secret ref set.
+	if ctx.KubevirtMachine.Spec.InfraClusterSecretRef == nil && len(ctx.KubevirtCluster.Spec.InfraClusters) > 0 && !util.IsControlPlaneMachine(ctx.Machine) {
+		infraCluster, err := r.placeOnInfraCluster(ctx)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to place the machine on an infra cluster")
+		}
+		ctx.KubevirtMachine.Spec.InfraClusterSecretRef = infraCluster.SecretRef
+		if ctx.KubevirtMachine.Spec.InfraClusterNamespace == "" {
+			ctx.KubevirtMachine.Spec.InfraClusterNamespace = infraCluster.Namespace
+		}
+	}
+
+	if ctx.KubevirtMachine.Status.InfraCluster == "" {
+		// Default the infra cluster secret ref when the
+		// machine does not have one set.
+		if ctx.KubevirtMachine.Spec.InfraClusterSecretRef == nil {
+			ctx.KubevirtMachine.Spec.InfraClusterSecretRef = ctx.KubevirtCluster.Spec.InfraClusterSecretRef
+		}
+
+		// Default the infra cluster namespace when the
+		// machine does not have one set.
+		if ctx.KubevirtMachine.Spec.InfraClusterNamespace == "" {
+			ctx.KubevirtMachine.Spec.InfraClusterNamespace = ctx.KubevirtCluster.Spec.InfraClusterNamespace
+		}
 	}
 
 	infraClusterClient, infraClusterNamespace, err := r.InfraCluster.GenerateInfraClusterClient(ctx.KubevirtMachine.Spec.InfraClusterSecretRef, ctx.KubevirtMachine.Namespace, ctx.Context)
@@ -225,15 +324,7 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to generate infra cluster client")
 	}
 
-	// If there is not a namespace explicitly set on the vm template, then
-	// use the infra namespace as a default. For internal clusters, the infraNamespace
-	// will be the same as the KubeVirtCluster object, for external clusters the
-	// infraNamespace will attempt to be detected from the infraClusterSecretRef's
-	// kubeconfig
-	vmNamespace := ctx.KubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Namespace
-	if vmNamespace == "" {
-		vmNamespace = infraClusterNamespace
-	}
+	vmNamespace := GetVMNamespace(ctx.KubevirtMachine, infraClusterNamespace)
 
 	if infraClusterClient == nil {
 		ctx.Logger.Info("Waiting for infra cluster client...")
@@ -245,12 +336,29 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to fetch kubevirt bootstrap secret")
 	}
 
+	allocated, err := r.reconcileIPAddresses(ctx)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to claim the static IP addresses")
+	}
+	if !allocated {
+		ctx.Logger.Info("Waiting for the static IP addresses to be allocated...")
+		conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForIPAddressesReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	// Create a helper for managing the KubeVirt VM hosting the machine.
 	externalMachine, err := r.MachineFactory.NewMachine(ctx, infraClusterClient, vmNamespace, clusterNodeSshKeys)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine")
 	}
 
+	// The preempted VMs are replaced, rather than marked as failed.
+	if ctx.KubevirtMachine.Spec.Preemptible != nil && externalMachine.Preempted() {
+		ctx.KubevirtMachine.Status.Ready = false
+		conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMHealthyCondition, infrav1.VMPreemptedReason, clusterv1.ConditionSeverityInfo, "the preemptible VM was preempted")
+		return r.deleteOwnerMachine(ctx, "the preemptible VM was preempted")
+	}
+
 	isTerminal, terminalReason, err := externalMachine.IsTerminal()
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed checking VM for terminal state")
@@ -261,9 +369,41 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 		ctx.KubevirtMachine.Status.FailureMessage = &terminalReason
 	}
 
+	// Wait for the externally managed VM, instead of creating one
+	externallyManaged := isVMExternallyManaged(ctx.KubevirtMachine)
+	if externallyManaged && !externalMachine.Exists() {
+		ctx.Logger.Info("Waiting for the externally managed VM...")
+		conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForExternallyManagedVMReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+	}
+
+	// Wait for the existing VM to adopt, instead of creating one
+	if ctx.KubevirtMachine.Spec.AdoptExistingVM && !externallyManaged {
+		if !externalMachine.Exists() {
+			ctx.Logger.Info("Waiting for the VM to adopt...")
+			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForVMToAdoptReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+		}
+		if err := externalMachine.Adopt(); err != nil {
+			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForVMToAdoptReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, errors.Wrap(err, "failed to adopt the VM")
+		}
+	}
+
 	// Provision the underlying VM if not existing
 	if !isTerminal && !externalMachine.Exists() {
 		ctx.KubevirtMachine.Status.Ready = false
+		if message := externalMachine.InsufficientCapacity(ctx.Context); message != "" {
+			ctx.Logger.Info("Waiting for the infra cluster capacity to create the VM...", "reason", message)
+			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForCapacityReason, clusterv1.ConditionSeverityWarning, message)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+		if wait := r.vmCreationWait(ctx); wait > 0 {
+			ctx.Logger.Info("Waiting for the VM creation rate limit of the cluster...", "wait", wait)
+			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.WaitingForTurnReason, clusterv1.ConditionSeverityInfo,
+				"waiting %s for the VM creation rate limit of the cluster", wait.Round(time.Second))
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
 		if err := externalMachine.Create(ctx.Context); err != nil {
 			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, infrav1.VMCreateFailedReason, clusterv1.ConditionSeverityError, fmt.Sprintf("Failed vm creation: %v", err))
 			return ctrl.Result{}, errors.Wrap(err, "failed to create VM instance")
@@ -272,6 +412,37 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
 	}
 
+	externalMachine.PinInstancetypeRevisions()
+
+	// the adopted and the externally managed VMs are not rendered from the KubevirtMachine
+	updateVM := !ctx.KubevirtMachine.Spec.AdoptExistingVM && !externallyManaged
+
+	if !isTerminal && updateVM {
+		if err := externalMachine.ReconcileMutableFields(); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to update the VM")
+		}
+		externalMachine.UpdateSpecOutOfDateCondition(ctx.Context)
+	}
+
+	if ctx.KubevirtMachine.Spec.DeleteNodeAfterEvacuation {
+		if err := externalMachine.DeleteEvacuatedNode(r.WorkloadCluster); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete the evacuated node")
+		}
+	}
+
+	externalMachine.UpdateHealthCondition()
+	if reason := externalMachine.DegradedReason(); reason != "" {
+		if err := r.annotateOwnerMachineForDeletion(ctx, reason, true); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if err := r.unannotateRecoveredOwnerMachine(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if ctx.KubevirtMachine.Spec.SerialConsoleLog != nil && !ctx.KubevirtMachine.Status.Ready {
+		r.captureSerialConsoleLog(ctx, vmNamespace)
+	}
+
 	// Checks to see if a VM's active VMI is ready or not
 	if externalMachine.IsReady() {
 		// Mark VMProvisionedCondition to indicate that the VM has successfully started
@@ -279,6 +450,9 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 	} else {
 		// Waiting for VM to boot
 		ctx.KubevirtMachine.Status.Ready = false
+		if message := externalMachine.UnschedulableMessage(); message != "" {
+			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.VMProvisionedCondition, unschedulableReason(ctx.KubevirtMachine, message), clusterv1.ConditionSeverityWarning, message)
+		}
 		ctx.Logger.Info("KubeVirt VM is not fully provisioned and running...")
 		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
 	}
@@ -300,7 +474,53 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
 	}
 
-	retryDuration, err := externalMachine.DrainNodeIfNeeded(r.WorkloadCluster)
+	if err := externalMachine.UpdateMigrationStatus(); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to update the VM migration status")
+	}
+	externalMachine.UpdateMACAddresses()
+	if updateVM {
+		if err := externalMachine.ReconcileHotplugVolumes(); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to reconcile the hotplug volumes of the VM")
+		}
+		if err := externalMachine.ReconcileCPUHotplug(); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to hot-plug the CPU sockets of the VM")
+		}
+		if err := externalMachine.ReconcileMemoryHotplug(); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to hot-plug the guest memory of the VM")
+		}
+	}
+
+	if r.DrainOptions.ProactiveDrain {
+		if err := externalMachine.CheckInfraNodeCordoned(); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to check the infra cluster node")
+		}
+	}
+
+	if ctx.KubevirtMachine.Spec.NodeTopologyFromInfraNode {
+		topology, err := externalMachine.InfraNodeTopology()
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to get the topology of the infra cluster node")
+		}
+		if topology != nil {
+			ctx.KubevirtMachine.Status.InfraNodeTopology = topology
+		}
+	}
+
+	if externalMachine.EvacuationRequested() {
+		policy := externalMachine.EvacuationPolicy()
+		switch {
+		case r.DrainOptions.IsDryRun(ctx.KubevirtCluster) && (policy == infrav1.DeleteMachineEvacuationPolicy || policy == infrav1.AnnotateMachineEvacuationPolicy):
+			r.reportOwnerMachineDryRun(ctx, policy)
+		case policy == infrav1.DeleteMachineEvacuationPolicy:
+			return r.deleteOwnerMachine(ctx, "the VM is evacuated from its infra cluster node")
+		case policy == infrav1.AnnotateMachineEvacuationPolicy:
+			if err := r.annotateOwnerMachineForDeletion(ctx, "the VM is evacuated from its infra cluster node", false); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	retryDuration, err := externalMachine.DrainNodeIfNeeded(r.WorkloadCluster, r.DrainOptions)
 	if err != nil {
 		return ctrl.Result{RequeueAfter: retryDuration}, errors.Wrap(err, "failed to drain node")
 	}
@@ -309,11 +529,26 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 	}
 
 	if externalMachine.SupportsCheckingIsBootstrapped() && !conditions.IsTrue(ctx.KubevirtMachine, infrav1.BootstrapExecSucceededCondition) {
+		// The failed machine is left to the remediation of its Machine.
+		if conditions.GetReason(ctx.KubevirtMachine, infrav1.BootstrapExecSucceededCondition) == infrav1.BootstrapTimedOutReason {
+			ctx.KubevirtMachine.Status.Ready = false
+			return ctrl.Result{}, nil
+		}
 		if !externalMachine.IsBootstrapped() {
+			if timeout := ctx.KubevirtMachine.Spec.BootstrapCheckSpec.Timeout; timeout != nil && bootstrapCheckFailingFor(ctx.KubevirtMachine) > timeout.Duration {
+				ctx.Logger.Info("The underlying VM did not bootstrap in time", "timeout", timeout.Duration)
+				message := fmt.Sprintf("VM not bootstrapped after %s", timeout.Duration)
+				conditions.MarkFalse(ctx.KubevirtMachine, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapTimedOutReason, clusterv1.ConditionSeverityError, message)
+				failureErr := capierrors.CreateMachineError
+				ctx.KubevirtMachine.Status.FailureReason = &failureErr
+				ctx.KubevirtMachine.Status.FailureMessage = &message
+				ctx.KubevirtMachine.Status.Ready = false
+				return ctrl.Result{}, nil
+			}
 			ctx.Logger.Info("Waiting for underlying VM to bootstrap...")
 			conditions.MarkFalse(ctx.KubevirtMachine, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, "VM not bootstrapped yet")
 			ctx.KubevirtMachine.Status.Ready = false
-			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			return ctrl.Result{RequeueAfter: bootstrapCheckInterval(ctx.KubevirtMachine)}, nil
 		}
 		// Update the condition BootstrapExecSucceededCondition
 		conditions.MarkTrue(ctx.KubevirtMachine, infrav1.BootstrapExecSucceededCondition)
@@ -338,6 +573,15 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 			Address: ctx.KubevirtMachine.Name,
 		},
 	}
+	additionalAddresses := externalMachine.AdditionalAddresses()
+	// The primary address of the other IP family of a dual-stack VM is reported like the primary address.
+	if address := otherIPFamilyAddress(ipAddress, additionalAddresses); address != "" {
+		ctx.KubevirtMachine.Status.Addresses = append(ctx.KubevirtMachine.Status.Addresses, clusterv1.MachineAddress{
+			Type:    clusterv1.MachineExternalIP,
+			Address: address,
+		})
+	}
+	ctx.KubevirtMachine.Status.Addresses = append(ctx.KubevirtMachine.Status.Addresses, additionalAddresses...)
 
 	if ctx.KubevirtMachine.Spec.ProviderID == nil || *ctx.KubevirtMachine.Spec.ProviderID == "" {
 		providerID, err := externalMachine.GenerateProviderID()
@@ -360,6 +604,38 @@ func (r *KubevirtMachineReconciler) reconcileNormal(ctx *context.MachineContext)
 	return ctrl.Result{}, nil
 }
 
+// otherIPFamilyAddress returns the first internal IP of the additional addresses of a VM which is not of the IP family of
+// its primary address, or an empty string for a single-stack VM.
+func otherIPFamilyAddress(primary string, additionalAddresses []clusterv1.MachineAddress) string {
+	primaryIP := net.ParseIP(primary)
+	if primaryIP == nil {
+		return ""
+	}
+	for _, address := range additionalAddresses {
+		ip := net.ParseIP(address.Address)
+		if address.Type == clusterv1.MachineInternalIP && ip != nil && (ip.To4() == nil) != (primaryIP.To4() == nil) {
+			return address.Address
+		}
+	}
+	return ""
+}
+
+// bootstrapCheckFailingFor returns how long the bootstrap check of the KubevirtMachine has been failing.
+func bootstrapCheckFailingFor(kubevirtMachine *infrav1.KubevirtMachine) time.Duration {
+	if !conditions.IsFalse(kubevirtMachine, infrav1.BootstrapExecSucceededCondition) {
+		return 0
+	}
+	return time.Since(conditions.GetLastTransitionTime(kubevirtMachine, infrav1.BootstrapExecSucceededCondition).Time)
+}
+
+// bootstrapCheckInterval returns how long to wait before checking the bootstrap of the KubevirtMachine again.
+func bootstrapCheckInterval(kubevirtMachine *infrav1.KubevirtMachine) time.Duration {
+	if interval := kubevirtMachine.Spec.BootstrapCheckSpec.Interval; interval != nil && interval.Duration > 0 {
+		return interval.Duration
+	}
+	return 10 * time.Second
+}
+
 func machineHasKnownInternalIP(kubevirtMachine *infrav1.KubevirtMachine) bool {
 	for _, addr := range kubevirtMachine.Status.Addresses {
 		if addr.Type == clusterv1.MachineInternalIP && addr.Address != "" {
@@ -369,9 +645,295 @@ func machineHasKnownInternalIP(kubevirtMachine *infrav1.KubevirtMachine) bool {
 	return false
 }
 
+// reconcileIPAddresses claims the static IP addresses of the additional networks from their IPAM pools, and sets the
+// allocated ones in the machine context, after the static addresses of the networks. It returns false until all the addresses are allocated.
+func (r *KubevirtMachineReconciler) reconcileIPAddresses(ctx *context.MachineContext) (bool, error) {
+	ipAddresses := map[string][]string{}
+	allocated := true
+
+	for _, network := range ctx.KubevirtMachine.Spec.AdditionalNetworks {
+		if len(network.Addresses) > 0 {
+			ipAddresses[network.Name] = append(ipAddresses[network.Name], network.Addresses...)
+		}
+		for i, poolRef := range network.AddressesFromPools {
+			claim := &ipamv1.IPAddressClaim{}
+			key := client.ObjectKey{Namespace: ctx.KubevirtMachine.Namespace, Name: ipAddressClaimName(ctx.KubevirtMachine, network.Name, i)}
+			if err := r.Client.Get(ctx, key, claim); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return false, err
+				}
+				if err := r.createIPAddressClaim(ctx, key, poolRef); err != nil {
+					return false, err
+				}
+				allocated = false
+				continue
+			}
+
+			if claim.Status.AddressRef.Name == "" {
+				allocated = false
+				continue
+			}
+
+			address := &ipamv1.IPAddress{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}, address); err != nil {
+				if apierrors.IsNotFound(err) {
+					allocated = false
+					continue
+				}
+				return false, err
+			}
+			ipAddresses[network.Name] = append(ipAddresses[network.Name], fmt.Sprintf("%s/%d", address.Spec.Address, address.Spec.Prefix))
+		}
+	}
+
+	if !allocated {
+		return false, nil
+	}
+	if len(ipAddresses) > 0 {
+		ctx.IPAddresses = ipAddresses
+	}
+	return true, nil
+}
+
+func (r *KubevirtMachineReconciler) createIPAddressClaim(ctx *context.MachineContext, key client.ObjectKey, poolRef corev1.TypedLocalObjectReference) error {
+	claim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: ctx.Cluster.Name,
+			},
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: poolRef,
+		},
+	}
+	if err := controllerutil.SetControllerReference(ctx.KubevirtMachine, claim, r.Client.Scheme()); err != nil {
+		return err
+	}
+
+	ctx.Logger.Info("Claiming a static IP address", "claim", key.Name, "pool", poolRef.Name)
+	return r.Client.Create(ctx, claim)
+}
+
+// deleteIPAddressClaims releases the static IP addresses of the additional networks.
+func (r *KubevirtMachineReconciler) deleteIPAddressClaims(ctx *context.MachineContext) error {
+	for _, network := range ctx.KubevirtMachine.Spec.AdditionalNetworks {
+		for i := range network.AddressesFromPools {
+			claim := &ipamv1.IPAddressClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ipAddressClaimName(ctx.KubevirtMachine, network.Name, i),
+					Namespace: ctx.KubevirtMachine.Namespace,
+				},
+			}
+			if err := r.Client.Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func ipAddressClaimName(kubevirtMachine *infrav1.KubevirtMachine, networkName string, index int) string {
+	return fmt.Sprintf("%s-%s-%d", kubevirtMachine.Name, networkName, index)
+}
+
+// captureSerialConsoleLog writes the last lines of the serial console output of the VM to the controller logs, or to
+// the serial console ConfigMap of the KubevirtMachine. The failures to capture it are only logged, as the machine
+// doesn't depend on it.
+func (r *KubevirtMachineReconciler) captureSerialConsoleLog(ctx *context.MachineContext, vmNamespace string) {
+	output, err := r.serialConsoleLog(ctx, vmNamespace)
+	if err != nil {
+		ctx.Logger.Info("Failed to capture the serial console of the VM", "error", err.Error())
+		return
+	}
+	if output == "" {
+		return
+	}
+
+	if ctx.KubevirtMachine.Spec.SerialConsoleLog.Target == infrav1.LogSerialConsoleLogTarget {
+		ctx.Logger.Info("Serial console of the VM", "output", output)
+		return
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serialConsoleConfigMapName(ctx.KubevirtMachine),
+			Namespace: ctx.KubevirtMachine.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if ctx.Cluster != nil {
+			configMap.Labels = map[string]string{clusterv1.ClusterNameLabel: ctx.Cluster.Name}
+		}
+		configMap.Data = map[string]string{serialConsoleConfigMapKey: output}
+		return controllerutil.SetControllerReference(ctx.KubevirtMachine, configMap, r.Client.Scheme())
+	})
+	if err != nil {
+		ctx.Logger.Info("Failed to store the serial console of the VM", "configmap", configMap.Name, "error", err.Error())
+	}
+}
+
+// serialConsoleLog returns the last lines of the serial console output of the VM, as logged by the guest-console-log
+// container of its newest virt-launcher pod, or an empty string if the VM has no virt-launcher pod yet.
+func (r *KubevirtMachineReconciler) serialConsoleLog(ctx *context.MachineContext, vmNamespace string) (string, error) {
+	k8sClient, err := r.InfraCluster.GenerateInfraClusterK8sClient(ctx.KubevirtMachine.Spec.InfraClusterSecretRef, ctx.KubevirtMachine.Namespace, ctx.Context)
+	if err != nil {
+		return "", err
+	}
+
+	selector := fmt.Sprintf("%s=virt-launcher,%s=%s", kubevirtv1.AppLabel, kubevirtv1.VirtualMachineNameLabel, kubevirt.VMName(ctx.KubevirtMachine))
+	pods, err := k8sClient.CoreV1().Pods(vmNamespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list the virt-launcher pods of the VM")
+	}
+
+	var pod *corev1.Pod
+	for i := range pods.Items {
+		if pod == nil || pod.CreationTimestamp.Before(&pods.Items[i].CreationTimestamp) {
+			pod = &pods.Items[i]
+		}
+	}
+	if pod == nil || pod.Status.Phase == corev1.PodPending {
+		return "", nil
+	}
+
+	tailLines := defaultSerialConsoleTailLines
+	if ctx.KubevirtMachine.Spec.SerialConsoleLog.TailLines != nil {
+		tailLines = *ctx.KubevirtMachine.Spec.SerialConsoleLog.TailLines
+	}
+	output, err := k8sClient.CoreV1().Pods(vmNamespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: serialConsoleContainerName,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the logs of the virt-launcher pod %s", pod.Name)
+	}
+
+	return string(output), nil
+}
+
+func serialConsoleConfigMapName(kubevirtMachine *infrav1.KubevirtMachine) string {
+	return kubevirtMachine.Name + "-serial-console"
+}
+
+// vmCreationWait returns how long the VM of the machine must wait for its turn to be created, according to the VM
+// creation rate limit of the cluster, or 0 if it can be created now.
+func (r *KubevirtMachineReconciler) vmCreationWait(ctx *context.MachineContext) time.Duration {
+	rateLimit := ctx.KubevirtCluster.Spec.VMCreationRateLimit
+	if rateLimit == nil || rateLimit.VMs < 1 {
+		return 0
+	}
+
+	interval := defaultVMCreationInterval
+	if rateLimit.Interval != nil && rateLimit.Interval.Duration > 0 {
+		interval = rateLimit.Interval.Duration
+	}
+
+	cluster := types.NamespacedName{Namespace: ctx.Cluster.Namespace, Name: ctx.Cluster.Name}
+	return r.CreationLimiter.Reserve(cluster, ctx.KubevirtMachine.Name, int(rateLimit.VMs), interval)
+}
+
+// placeOnInfraCluster returns the infra cluster the machine is placed on, among the infra clusters of the cluster. A
+// new machine is placed according to the placement policy of the cluster, and the infra cluster it landed on is
+// recorded in its status.
+func (r *KubevirtMachineReconciler) placeOnInfraCluster(ctx *context.MachineContext) (*infrav1.InfraClusterReference, error) {
+	infraClusters := ctx.KubevirtCluster.Spec.InfraClusters
+
+	if name := ctx.KubevirtMachine.Status.InfraCluster; name != "" {
+		for i := range infraClusters {
+			if infraClusters[i].Name == name {
+				return &infraClusters[i], nil
+			}
+		}
+		return nil, errors.Errorf("infra cluster %q of the machine is not in the infra clusters of the KubevirtCluster", name)
+	}
+
+	kubevirtMachines := &infrav1.KubevirtMachineList{}
+	if err := r.Client.List(ctx, kubevirtMachines, client.InNamespace(ctx.KubevirtMachine.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: ctx.Cluster.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the machines of the cluster")
+	}
+	machineCounts := map[string]int{}
+	for _, kubevirtMachine := range kubevirtMachines.Items {
+		if kubevirtMachine.UID != ctx.KubevirtMachine.UID && kubevirtMachine.Status.InfraCluster != "" {
+			machineCounts[kubevirtMachine.Status.InfraCluster]++
+		}
+	}
+
+	infraCluster := selectInfraCluster(infraClusters, ctx.KubevirtCluster.Spec.InfraClusterPlacementPolicy, machineCounts)
+	if infraCluster == nil {
+		return nil, errors.New("no infra cluster of the KubevirtCluster accepts new machines")
+	}
+
+	ctx.Logger.Info("Placing the machine on an infra cluster", "infraCluster", infraCluster.Name)
+	ctx.KubevirtMachine.Status.InfraCluster = infraCluster.Name
+
+	return infraCluster, nil
+}
+
+// selectInfraCluster returns the infra cluster a new machine is placed on, given the number of machines of the cluster
+// already placed on each infra cluster, or nil if none accepts new machines. With the Spread policy, this is the infra
+// cluster with the fewest machines, and with the Weighted policy, the one with the fewest machines relative to its
+// weight. Ties are broken by the order of the infra clusters.
+func selectInfraCluster(infraClusters []infrav1.InfraClusterReference, policy infrav1.InfraClusterPlacementPolicy, machineCounts map[string]int) *infrav1.InfraClusterReference {
+	var selected *infrav1.InfraClusterReference
+	var selectedWeight int64
+	for i := range infraClusters {
+		infraCluster := &infraClusters[i]
+
+		weight := int64(1)
+		if policy == infrav1.WeightedInfraClusterPlacementPolicy && infraCluster.Weight != nil {
+			weight = int64(*infraCluster.Weight)
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		// Compare (count+1)/weight across the infra clusters without dividing.
+		if selected == nil || int64(machineCounts[infraCluster.Name]+1)*selectedWeight < int64(machineCounts[selected.Name]+1)*weight {
+			selected = infraCluster
+			selectedWeight = weight
+		}
+	}
+
+	return selected
+}
+
+// GetVMNamespace returns the namespace of the infra cluster the VM of the KubevirtMachine is placed in. If there is
+// not a namespace explicitly set on the vm template nor on the machine, then the infra namespace is used as a default.
+// For internal clusters, the infraNamespace will be the same as the KubeVirtCluster object, for external clusters the
+// infraNamespace will attempt to be detected from the infraClusterSecretRef's kubeconfig.
+func GetVMNamespace(kubevirtMachine *infrav1.KubevirtMachine, infraClusterNamespace string) string {
+	if kubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Namespace != "" {
+		return kubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Namespace
+	}
+	if kubevirtMachine.Spec.InfraClusterNamespace != "" {
+		return kubevirtMachine.Spec.InfraClusterNamespace
+	}
+	return infraClusterNamespace
+}
+
+// unschedulableReason returns the reason of the VMProvisioned condition of a KubevirtMachine whose VM can't be
+// scheduled, telling apart the VMs requesting hugepages that no infra cluster node can allocate.
+func unschedulableReason(kubevirtMachine *infrav1.KubevirtMachine, message string) string {
+	vmiTemplate := kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate != nil && vmiTemplate.Spec.Domain.Memory != nil && vmiTemplate.Spec.Domain.Memory.Hugepages != nil &&
+		strings.Contains(message, corev1.ResourceHugePagesPrefix) {
+		return infrav1.HugepagesUnavailableReason
+	}
+	return infrav1.VMUnschedulableReason
+}
+
+// isVMExternallyManaged returns true if the VM of the KubevirtMachine is managed by another tool, and must never be
+// created, updated or deleted by the controller.
+func isVMExternallyManaged(kubevirtMachine *infrav1.KubevirtMachine) bool {
+	_, found := kubevirtMachine.Annotations[infrav1.ExternallyManagedVMAnnotation]
+	return found
+}
+
 func (r *KubevirtMachineReconciler) updateNodeProviderID(ctx *context.MachineContext) (ctrl.Result, error) {
-	// If the provider ID is already updated on the Node, return
-	if ctx.KubevirtMachine.Status.NodeUpdated {
+	// If the provider ID is already updated on the Node, return unless the Node metadata must still be reconciled
+	if ctx.KubevirtMachine.Status.NodeUpdated && !hasNodeMetadata(ctx.KubevirtMachine) {
 		return ctrl.Result{}, nil
 	}
 
@@ -396,19 +958,27 @@ func (r *KubevirtMachineReconciler) updateNodeProviderID(ctx *context.MachineCon
 		}
 	}
 
-	if workloadClusterNode.Spec.ProviderID == *ctx.KubevirtMachine.Spec.ProviderID {
+	updatedNode := workloadClusterNode.DeepCopy()
+	updatedNode.Spec.ProviderID = *ctx.KubevirtMachine.Spec.ProviderID
+	applyNodeMetadata(updatedNode, ctx.KubevirtMachine)
+
+	if equality.Semantic.DeepEqual(workloadClusterNode, updatedNode) {
 		// Node is already updated, return
+		ctx.KubevirtMachine.Status.NodeUpdated = true
 		return ctrl.Result{}, nil
 	}
 
-	// Patch node with provider id.
+	// Patch node with provider id, and the labels, annotations and taints of the machine.
 	// Usually a cloud provider will do this, but there is no cloud provider for KubeVirt.
-	ctx.Logger.Info("Patching node with provider id...")
+	if workloadClusterNode.Spec.ProviderID != updatedNode.Spec.ProviderID {
+		ctx.Logger.Info("Patching node with provider id...")
+	} else {
+		ctx.Logger.Info("Patching node with the node metadata of the machine...")
+	}
 
 	// using workload cluster client, patch cluster node
-	patchStr := fmt.Sprintf(`{"spec": {"providerID": "%s"}}`, *ctx.KubevirtMachine.Spec.ProviderID)
-	mergePatch := client.RawPatch(types.MergePatchType, []byte(patchStr))
-	if err := workloadClusterClient.Patch(ctx, workloadClusterNode, mergePatch); err != nil {
+	mergePatch := client.MergeFromWithOptions(workloadClusterNode, client.MergeFromWithOptimisticLock{})
+	if err := workloadClusterClient.Patch(ctx, updatedNode, mergePatch); err != nil {
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, errors.Wrapf(err, "failed to patch workload cluster node")
 	}
 	ctx.KubevirtMachine.Status.NodeUpdated = true
@@ -416,6 +986,139 @@ func (r *KubevirtMachineReconciler) updateNodeProviderID(ctx *context.MachineCon
 	return ctrl.Result{}, nil
 }
 
+// applyNodeMetadata sets the node labels, annotations and taints of the KubevirtMachine on its tenant cluster node,
+// as well as the topology labels of the infra cluster node running its VM when requested.
+func applyNodeMetadata(node *corev1.Node, kubevirtMachine *infrav1.KubevirtMachine) {
+	node.Labels = setNodeMetadata(node.Labels, kubevirtMachine.Spec.NodeLabels)
+	if kubevirtMachine.Spec.NodeTopologyFromInfraNode {
+		node.Labels = setNodeMetadata(node.Labels, kubevirtMachine.Status.InfraNodeTopology)
+	}
+	node.Annotations = setNodeMetadata(node.Annotations, kubevirtMachine.Spec.NodeAnnotations)
+
+	for _, taint := range kubevirtMachine.Spec.NodeTaints {
+		replaced := false
+		for i := range node.Spec.Taints {
+			if node.Spec.Taints[i].MatchTaint(&taint) {
+				node.Spec.Taints[i] = taint
+				replaced = true
+			}
+		}
+		if !replaced {
+			node.Spec.Taints = append(node.Spec.Taints, taint)
+		}
+	}
+}
+
+// hasNodeMetadata returns true if the KubevirtMachine sets labels, annotations or taints on its tenant cluster node,
+// which are then reconciled on every reconcile rather than only until the provider ID is set.
+func hasNodeMetadata(kubevirtMachine *infrav1.KubevirtMachine) bool {
+	return len(kubevirtMachine.Spec.NodeLabels) > 0 || len(kubevirtMachine.Spec.NodeAnnotations) > 0 ||
+		len(kubevirtMachine.Spec.NodeTaints) > 0 || kubevirtMachine.Spec.NodeTopologyFromInfraNode
+}
+
+// setNodeMetadata sets the entries of src in the node labels or annotations dst, and returns dst.
+func setNodeMetadata(dst map[string]string, src map[string]string) map[string]string {
+	for key, value := range src {
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		dst[key] = value
+	}
+	return dst
+}
+
+// annotateOwnerMachineForDeletion sets the cluster-api delete-machine annotation on the owner Machine of an evacuated
+// or degraded VM, so the MachineSet deletes it first on the next scale down. The annotation set for a degraded VM is
+// marked as such, to be removed once the VM recovers.
+func (r *KubevirtMachineReconciler) annotateOwnerMachineForDeletion(ctx *context.MachineContext, reason string, degraded bool) error {
+	_, found := ctx.Machine.Annotations[clusterv1.DeleteMachineAnnotation]
+	_, marked := ctx.Machine.Annotations[infrav1.DegradedMachineAnnotation]
+	if found && (degraded || !marked) {
+		return nil
+	}
+
+	ctx.Logger.Info("Annotating the owner Machine for deletion...", "machine", ctx.Machine.Name, "reason", reason)
+	original := ctx.Machine.DeepCopy()
+	annotations.AddAnnotations(ctx.Machine, map[string]string{clusterv1.DeleteMachineAnnotation: ""})
+	if degraded {
+		annotations.AddAnnotations(ctx.Machine, map[string]string{infrav1.DegradedMachineAnnotation: reason})
+	} else {
+		// the evacuated VM is not going to recover
+		delete(ctx.Machine.Annotations, infrav1.DegradedMachineAnnotation)
+	}
+	if err := r.Client.Patch(ctx, ctx.Machine, client.MergeFrom(original)); err != nil {
+		return errors.Wrap(err, "failed to annotate the owner Machine for deletion")
+	}
+
+	return nil
+}
+
+// unannotateRecoveredOwnerMachine removes the cluster-api delete-machine annotation set by the controller on the owner
+// Machine of a degraded VM, once the VM recovered.
+func (r *KubevirtMachineReconciler) unannotateRecoveredOwnerMachine(ctx *context.MachineContext) error {
+	if _, marked := ctx.Machine.Annotations[infrav1.DegradedMachineAnnotation]; !marked {
+		return nil
+	}
+
+	ctx.Logger.Info("Removing the deletion annotation of the owner Machine, as the VM recovered...", "machine", ctx.Machine.Name)
+	original := ctx.Machine.DeepCopy()
+	delete(ctx.Machine.Annotations, clusterv1.DeleteMachineAnnotation)
+	delete(ctx.Machine.Annotations, infrav1.DegradedMachineAnnotation)
+	if err := r.Client.Patch(ctx, ctx.Machine, client.MergeFrom(original)); err != nil {
+		return errors.Wrap(err, "failed to remove the deletion annotation of the owner Machine")
+	}
+
+	return nil
+}
+
+// reportOwnerMachineDryRun logs and records an event for what the evacuation policy would do to the owner Machine.
+func (r *KubevirtMachineReconciler) reportOwnerMachineDryRun(ctx *context.MachineContext, policy infrav1.EvacuationPolicy) {
+	action := "delete"
+	if policy == infrav1.AnnotateMachineEvacuationPolicy {
+		action = "annotate for deletion"
+	}
+
+	msg := fmt.Sprintf("Dry run: would %s the owner Machine %s", action, ctx.Machine.Name)
+	ctx.Logger.Info(msg)
+	if r.DrainOptions.Recorder != nil {
+		r.DrainOptions.Recorder.Event(ctx.KubevirtMachine, corev1.EventTypeNormal, kubevirt.DrainDryRunEventReason, msg)
+	}
+}
+
+// getDrainPolicy returns the KubevirtDrainPolicy referenced by the KubevirtCluster, or nil if the KubevirtCluster
+// doesn't reference any, or if the policy doesn't exist.
+func (r *KubevirtMachineReconciler) getDrainPolicy(ctx gocontext.Context, kubevirtCluster *infrav1.KubevirtCluster) (*infrav1.KubevirtDrainPolicy, error) {
+	if kubevirtCluster.Spec.DrainPolicyRef == nil {
+		return nil, nil
+	}
+
+	drainPolicy := &infrav1.KubevirtDrainPolicy{}
+	key := client.ObjectKey{Namespace: kubevirtCluster.Namespace, Name: kubevirtCluster.Spec.DrainPolicyRef.Name}
+	if err := r.Client.Get(ctx, key, drainPolicy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get KubevirtDrainPolicy %s", key.Name)
+	}
+
+	return drainPolicy, nil
+}
+
+// deleteOwnerMachine deletes the owner Machine of an evacuated or preempted VM, so the cluster-api deletion flow drains
+// the node and the MachineSet creates a replacement.
+func (r *KubevirtMachineReconciler) deleteOwnerMachine(ctx *context.MachineContext, reason string) (ctrl.Result, error) {
+	if !ctx.Machine.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	ctx.Logger.Info("Deleting the owner Machine...", "machine", ctx.Machine.Name, "reason", reason)
+	if err := r.Client.Delete(ctx, ctx.Machine); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, errors.Wrap(err, "failed to delete the owner Machine")
+	}
+
+	return ctrl.Result{}, nil
+}
+
 func (r *KubevirtMachineReconciler) reconcileDelete(ctx *context.MachineContext) (ctrl.Result, error) {
 
 	patchHelper, err := patch.NewHelper(ctx.KubevirtMachine, r.Client)
@@ -423,6 +1126,9 @@ func (r *KubevirtMachineReconciler) reconcileDelete(ctx *context.MachineContext)
 		return ctrl.Result{}, err
 	}
 
+	// a drain in progress is never completed once the machine is deleted; release it for the other nodes of the cluster.
+	r.DrainOptions.Tracker.DrainDone(kubevirthandler.NodeDrainKey(ctx.KubevirtMachine))
+
 	infraClusterClient, infraClusterNamespace, err := r.InfraCluster.GenerateInfraClusterClient(ctx.KubevirtMachine.Spec.InfraClusterSecretRef, ctx.KubevirtMachine.Namespace, ctx.Context)
 	if err != nil {
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to generate infra cluster client")
@@ -432,33 +1138,34 @@ func (r *KubevirtMachineReconciler) reconcileDelete(ctx *context.MachineContext)
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
-	// If there is not a namespace explicitly set on the vm template, then
-	// use the infra namespace as a default. For internal clusters, the infraNamespace
-	// will be the same as the KubeVirtCluster object, for external clusters the
-	// infraNamespace will attempt to be detected from the infraClusterSecretRef's
-	// kubeconfig
-	vmNamespace := ctx.KubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Namespace
-	if vmNamespace == "" {
-		vmNamespace = infraClusterNamespace
-	}
+	vmNamespace := GetVMNamespace(ctx.KubevirtMachine, infraClusterNamespace)
 
 	ctx.Logger.Info("Deleting VM bootstrap secret...")
 	if err := r.deleteKubevirtBootstrapSecret(ctx, infraClusterClient, vmNamespace); err != nil {
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to delete bootstrap secret")
 	}
 
-	ctx.Logger.Info("Deleting VM...")
-	externalMachine, err := kubevirthandler.NewMachine(ctx, infraClusterClient, vmNamespace, nil)
-	if err != nil {
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to create helper for externalMachine access")
-	}
+	if isVMExternallyManaged(ctx.KubevirtMachine) {
+		ctx.Logger.Info("Leaving the externally managed VM in place")
+	} else {
+		ctx.Logger.Info("Deleting VM...")
+		externalMachine, err := kubevirthandler.NewMachine(ctx, infraClusterClient, vmNamespace, nil)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to create helper for externalMachine access")
+		}
 
-	if externalMachine.Exists() {
-		if err := externalMachine.Delete(); err != nil {
-			return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to delete VM")
+		if externalMachine.Exists() {
+			if err := externalMachine.Delete(); err != nil {
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to delete VM")
+			}
 		}
 	}
 
+	ctx.Logger.Info("Releasing the static IP addresses...")
+	if err := r.deleteIPAddressClaims(ctx); err != nil {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, errors.Wrap(err, "failed to release the static IP addresses")
+	}
+
 	// Machine is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(ctx.KubevirtMachine, infrav1.MachineFinalizer)
 
@@ -481,7 +1188,7 @@ func (r *KubevirtMachineReconciler) SetupWithManager(goctx gocontext.Context, mg
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1.KubevirtMachine{}).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(goctx))).
@@ -498,7 +1205,167 @@ func (r *KubevirtMachineReconciler) SetupWithManager(goctx gocontext.Context, mg
 			handler.EnqueueRequestsFromMapFunc(clusterToKubevirtMachines),
 			builder.WithPredicates(predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(goctx))),
 		).
-		Complete(r)
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.KubeconfigSecretToKubevirtMachines),
+		)
+
+	// the infra cluster nodes can only be watched when the VMs run in the management cluster; otherwise the cordoned
+	// nodes are found on the next resync.
+	if r.DrainOptions.ProactiveDrain {
+		b = b.Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.InfraNodeToKubevirtMachines),
+			builder.WithPredicates(nodeCordonChanged()),
+		)
+	}
+
+	// the IP address claims can only be watched when a cluster-api IPAM provider is installed.
+	claimGVK := ipamv1.GroupVersion.WithKind("IPAddressClaim")
+	if _, err := mgr.GetRESTMapper().RESTMapping(claimGVK.GroupKind(), claimGVK.Version); err == nil {
+		b = b.Owns(&ipamv1.IPAddressClaim{})
+	}
+
+	// the VM migrations can only be watched when KubeVirt is installed in the management cluster; otherwise the
+	// migration status is updated on the next resync.
+	migrationGVK := kubevirtv1.SchemeGroupVersion.WithKind("VirtualMachineInstanceMigration")
+	if _, err := mgr.GetRESTMapper().RESTMapping(migrationGVK.GroupKind(), migrationGVK.Version); err == nil {
+		b = b.Watches(
+			&kubevirtv1.VirtualMachineInstanceMigration{},
+			handler.EnqueueRequestsFromMapFunc(r.MigrationToKubevirtMachine),
+		)
+	}
+
+	// the VMIs can only be watched when they run in the management cluster; otherwise the addresses of the VMs are
+	// refreshed on the next resync.
+	vmiGVK := kubevirtv1.SchemeGroupVersion.WithKind("VirtualMachineInstance")
+	if _, err := mgr.GetRESTMapper().RESTMapping(vmiGVK.GroupKind(), vmiGVK.Version); err == nil {
+		b = b.Watches(
+			&kubevirtv1.VirtualMachineInstance{},
+			handler.EnqueueRequestsFromMapFunc(r.VMIToKubevirtMachine),
+			builder.WithPredicates(vmiInterfacesChanged()),
+		)
+	}
+
+	return b.Complete(r)
+}
+
+// VMIToKubevirtMachine is a handler.ToRequestsFunc to be used to enqueue a request for reconciliation of the
+// KubevirtMachine of the VMI.
+func (r *KubevirtMachineReconciler) VMIToKubevirtMachine(_ gocontext.Context, o client.Object) []ctrl.Request {
+	name, found := o.GetLabels()[infrav1.KubevirtMachineNameLabel]
+	if !found {
+		return nil
+	}
+
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: o.GetLabels()[infrav1.KubevirtMachineNamespaceLabel], Name: name}}}
+}
+
+// MigrationToKubevirtMachine is a handler.ToRequestsFunc to be used to enqueue a request for reconciliation of the
+// KubevirtMachine whose VMI is migrated.
+func (r *KubevirtMachineReconciler) MigrationToKubevirtMachine(ctx gocontext.Context, o client.Object) []ctrl.Request {
+	migration, ok := o.(*kubevirtv1.VirtualMachineInstanceMigration)
+	if !ok {
+		panic(fmt.Sprintf("Expected a VirtualMachineInstanceMigration but got a %T", o))
+	}
+
+	vmi := &kubevirtv1.VirtualMachineInstance{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: migration.Namespace, Name: migration.Spec.VMIName}, vmi); err != nil {
+		return nil
+	}
+
+	name, found := vmi.Labels[infrav1.KubevirtMachineNameLabel]
+	if !found {
+		return nil
+	}
+
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: vmi.Labels[infrav1.KubevirtMachineNamespaceLabel], Name: name}}}
+}
+
+// InfraNodeToKubevirtMachines is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation of the
+// KubevirtMachines whose VMIs run on the infra cluster node.
+func (r *KubevirtMachineReconciler) InfraNodeToKubevirtMachines(ctx gocontext.Context, o client.Object) []ctrl.Request {
+	var result []ctrl.Request
+
+	vmiList := &kubevirtv1.VirtualMachineInstanceList{}
+	if err := r.Client.List(ctx, vmiList, client.HasLabels{infrav1.KubevirtMachineNameLabel, infrav1.KubevirtMachineNamespaceLabel}); err != nil {
+		return nil
+	}
+	for _, vmi := range vmiList.Items {
+		if vmi.Status.NodeName != o.GetName() {
+			continue
+		}
+		name := client.ObjectKey{Namespace: vmi.Labels[infrav1.KubevirtMachineNamespaceLabel], Name: vmi.Labels[infrav1.KubevirtMachineNameLabel]}
+		result = append(result, ctrl.Request{NamespacedName: name})
+	}
+
+	return result
+}
+
+// KubeconfigSecretToKubevirtMachines is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation of
+// the KubevirtMachines that failed to get a client to their tenant cluster, when its kubeconfig secret changes.
+func (r *KubevirtMachineReconciler) KubeconfigSecretToKubevirtMachines(ctx gocontext.Context, o client.Object) []ctrl.Request {
+	clusterName, found := o.GetLabels()[clusterv1.ClusterNameLabel]
+	if !found || o.GetName() != clusterName+"-kubeconfig" {
+		return nil
+	}
+
+	kubevirtMachines := &infrav1.KubevirtMachineList{}
+	if err := r.Client.List(ctx, kubevirtMachines, client.InNamespace(o.GetNamespace()), client.MatchingLabels{clusterv1.ClusterNameLabel: clusterName}); err != nil {
+		return nil
+	}
+
+	var result []ctrl.Request
+	for i := range kubevirtMachines.Items {
+		kubevirtMachine := &kubevirtMachines.Items[i]
+		if conditions.GetReason(kubevirtMachine, infrav1.DrainingSucceededCondition) != infrav1.TenantClusterClientFailedReason {
+			continue
+		}
+		result = append(result, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kubevirtMachine)})
+	}
+
+	return result
+}
+
+// nodeCordonChanged returns a predicate that only passes the updates of the nodes that were cordoned or uncordoned.
+func nodeCordonChanged() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable
+		},
+	}
+}
+
+// vmiInterfacesChanged returns a predicate that only passes the updates of the VMIs whose interfaces changed, e.g.
+// when the guest agent reports a new IP address.
+func vmiInterfacesChanged() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldVMI, ok := e.ObjectOld.(*kubevirtv1.VirtualMachineInstance)
+			if !ok {
+				return false
+			}
+			newVMI, ok := e.ObjectNew.(*kubevirtv1.VirtualMachineInstance)
+			if !ok {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldVMI.Status.Interfaces, newVMI.Status.Interfaces)
+		},
+	}
 }
 
 // KubevirtClusterToKubevirtMachines is a handler.ToRequestsFunc to be used to enqueue
@@ -551,10 +1418,59 @@ func (r *KubevirtMachineReconciler) reconcileKubevirtBootstrapSecret(ctx *contex
 		return errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
 
-	if sshKeys != nil {
+	format := ctx.KubevirtMachine.Spec.BootstrapDataFormat
+	if format == "" {
+		format = bootstrapDataFormat(s.Data["format"])
+	}
+
+	if ref := ctx.KubevirtMachine.Spec.AdditionalUserDataSecretRef; ref != nil && (format == "" || format == infrav1.CloudConfigBootstrapDataFormat) {
+		additional := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ctx.KubevirtMachine.Namespace, Name: ref.Name}, additional); err != nil {
+			return errors.Wrapf(err, "failed to retrieve the additional user data secret %s", ref.Name)
+		}
 		var err error
+		if value, err = mergeCloudInitConfig(value, additional.Data["userdata"]); err != nil {
+			return errors.Wrapf(err, "failed to merge the additional user data of KubevirtMachine %s/%s", ctx.KubevirtMachine.Namespace, ctx.KubevirtMachine.Name)
+		}
+	}
+
+	// kube-vip announces the virtual IP of the control plane endpoint from the control plane nodes.
+	if kubeVIP := ctx.KubevirtCluster.Spec.KubeVIP; kubeVIP != nil && util.IsControlPlaneMachine(ctx.Machine) && format != infrav1.RawBootstrapDataFormat {
+		addKubeVIP := addKubeVIPToCloudInitConfig
+		if format == infrav1.IgnitionBootstrapDataFormat {
+			addKubeVIP = addKubeVIPToIgnitionConfig
+		}
+		manifest, err := kubeVIPManifest(kubeVIP)
+		if err != nil {
+			return err
+		}
+		if value, err = addKubeVIP(value, manifest); err != nil {
+			return errors.Wrapf(err, "failed to add the kube-vip static pod to KubevirtMachine %s/%s userdata", ctx.Machine.GetNamespace(), ctx.Machine.GetName())
+		}
+	}
+
+	authorizedKeys, err := r.sshAuthorizedKeys(ctx)
+	if err != nil {
+		return err
+	}
+	if sshKeys != nil {
+		authorizedKeys = append([][]byte{sshKeys.PublicKey}, authorizedKeys...)
+	}
+
+	// The raw bootstrap data, e.g. the Talos machine config, is passed through unmodified.
+	if len(authorizedKeys) > 0 && format != infrav1.RawBootstrapDataFormat {
+		addCapkUser := addCapkUserToCloudInitConfig
+		if format == infrav1.IgnitionBootstrapDataFormat {
+			addCapkUser = addCapkUserToIgnitionConfig
+		}
+
+		user := ctx.KubevirtMachine.Spec.BootstrapCheckSpec.User
+		if user == "" {
+			user = ssh.DefaultUser
+		}
+
 		var modified bool
-		if value, modified, err = addCapkUserToCloudInitConfig(value, sshKeys.PublicKey); err != nil {
+		if value, modified, err = addCapkUser(value, user, authorizedKeys...); err != nil {
 			return errors.Wrapf(err, "failed to add capk user to KubevirtMachine %s/%s userdata", ctx.Machine.GetNamespace(), ctx.Machine.GetName())
 		} else if modified {
 			ctx.Logger.Info("Add capk user with ssh config to bootstrap userdata")
@@ -563,18 +1479,21 @@ func (r *KubevirtMachineReconciler) reconcileKubevirtBootstrapSecret(ctx *contex
 
 	newBootstrapDataSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.Name + "-userdata",
+			Name:      kubevirt.UserDataSecretName(ctx.KubevirtMachine, s.Name),
 			Namespace: vmNamespace,
 			Labels:    s.Labels,
 		},
 	}
 	ctx.BootstrapDataSecret = newBootstrapDataSecret
 
-	_, err := controllerutil.CreateOrUpdate(ctx, infraClusterClient, newBootstrapDataSecret, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, infraClusterClient, newBootstrapDataSecret, func() error {
 		newBootstrapDataSecret.Type = clusterv1.ClusterSecretType
 		newBootstrapDataSecret.Data = map[string][]byte{
 			"userdata": value,
 		}
+		if format != "" {
+			newBootstrapDataSecret.Data["format"] = []byte(format)
+		}
 
 		return nil
 	})
@@ -586,6 +1505,44 @@ func (r *KubevirtMachineReconciler) reconcileKubevirtBootstrapSecret(ctx *contex
 	return nil
 }
 
+// sshAuthorizedKeys returns the additional public SSH keys of the KubevirtCluster and of the KubevirtMachine.
+func (r *KubevirtMachineReconciler) sshAuthorizedKeys(ctx *context.MachineContext) ([][]byte, error) {
+	var keys [][]byte
+	for _, authorizedKeys := range []*infrav1.SSHAuthorizedKeys{ctx.KubevirtCluster.Spec.SSHAuthorizedKeys, ctx.KubevirtMachine.Spec.SSHAuthorizedKeys} {
+		if authorizedKeys == nil {
+			continue
+		}
+		for _, key := range authorizedKeys.Keys {
+			keys = append(keys, []byte(key))
+		}
+		for _, ref := range authorizedKeys.SecretRefs {
+			secret := &corev1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ctx.KubevirtMachine.Namespace, Name: ref.Name}, secret); err != nil {
+				return nil, errors.Wrapf(err, "failed to retrieve the ssh authorized keys secret %s", ref.Name)
+			}
+			for _, line := range strings.Split(string(secret.Data["authorized_keys"]), "\n") {
+				if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+					keys = append(keys, []byte(line))
+				}
+			}
+		}
+	}
+	return keys, nil
+}
+
+// bootstrapDataFormat returns the format of the bootstrap data from the format set in its secret by the bootstrap
+// provider; the formats other than cloud-config and ignition, e.g. talos, are passed through as raw bootstrap data.
+func bootstrapDataFormat(format []byte) infrav1.BootstrapDataFormat {
+	switch infrav1.BootstrapDataFormat(format) {
+	case "":
+		return ""
+	case infrav1.CloudConfigBootstrapDataFormat, infrav1.IgnitionBootstrapDataFormat:
+		return infrav1.BootstrapDataFormat(format)
+	default:
+		return infrav1.RawBootstrapDataFormat
+	}
+}
+
 // deleteKubevirtBootstrapSecret deletes bootstrap cloud-init secret for KubeVirt virtual machines
 func (r *KubevirtMachineReconciler) deleteKubevirtBootstrapSecret(ctx *context.MachineContext, infraClusterClient client.Client, vmNamespace string) error {
 
@@ -595,7 +1552,7 @@ func (r *KubevirtMachineReconciler) deleteKubevirtBootstrapSecret(ctx *context.M
 	}
 
 	bootstrapDataSecret := &corev1.Secret{}
-	bootstrapDataSecretKey := client.ObjectKey{Namespace: vmNamespace, Name: *ctx.Machine.Spec.Bootstrap.DataSecretName + "-userdata"}
+	bootstrapDataSecretKey := client.ObjectKey{Namespace: vmNamespace, Name: kubevirt.UserDataSecretName(ctx.KubevirtMachine, *ctx.Machine.Spec.Bootstrap.DataSecretName)}
 	if err := infraClusterClient.Get(ctx, bootstrapDataSecretKey, bootstrapDataSecret); err != nil {
 		// the secret does not exist, exit without error
 		return nil
@@ -608,12 +1565,12 @@ func (r *KubevirtMachineReconciler) deleteKubevirtBootstrapSecret(ctx *context.M
 	return nil
 }
 
-// addCapkUserToCloudInitConfig adds the 'capk' user with the provided ssh authorized key to the
+// addCapkUserToCloudInitConfig adds the capk user, with the given name and the provided ssh authorized keys, to the
 // machine cloud-init bootstrap user-data.
 // If the user-data is not the expected cloud-init config, then returns the latter content as-is.
 // If a capk user is already defined, then overrides it.
 // The returned boolean indicates whether the userdata was modified or not.
-func addCapkUserToCloudInitConfig(userdata, sshAuthorizedKey []byte) ([]byte, bool, error) {
+func addCapkUserToCloudInitConfig(userdata []byte, user string, sshAuthorizedKeys ...[]byte) ([]byte, bool, error) {
 
 	// This uses yaml.Node and not an interface{} to preserve the comments, ordering, etc. of the
 	// cloud-init user-data (the indentation might be modified and aligned).
@@ -627,23 +1584,8 @@ func addCapkUserToCloudInitConfig(userdata, sshAuthorizedKey []byte) ([]byte, bo
 		return nil, false, fmt.Errorf("failed to parse userdata yaml: %w", err)
 	}
 
-	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 {
-		return userdata, false, nil
-	}
-	data := root.Content[0]
-	if data.Kind != yaml.MappingNode || len(data.Content) == 0 {
-		return userdata, false, nil
-	}
-
-	// This resolves the first comment in the document; which can be associated with different nodes
-	// based on how it is written.
-	var headerComment string
-	for _, headerComment = range []string{root.HeadComment, data.HeadComment, data.Content[0].HeadComment} {
-		if headerComment != "" {
-			break
-		}
-	}
-	if !regexp.MustCompile(`(?m)^#cloud-config`).MatchString(headerComment) {
+	data := cloudInitConfigData(root)
+	if data == nil {
 		return userdata, false, nil
 	}
 
@@ -655,7 +1597,7 @@ func addCapkUserToCloudInitConfig(userdata, sshAuthorizedKey []byte) ([]byte, bo
 		}
 	}
 
-	usersKey, usersWithCapk, err := usersYamlNodes(sshAuthorizedKey)
+	usersKey, usersWithCapk, err := usersYamlNodes(user, sshAuthorizedKeys)
 	if err != nil {
 		return nil, false, err
 	}
@@ -667,10 +1609,10 @@ func addCapkUserToCloudInitConfig(userdata, sshAuthorizedKey []byte) ([]byte, bo
 		data.Content = append(data.Content, usersKey, usersWithCapk)
 	} else {
 
-		for i, user := range users.Content {
-			for j, field := range user.Content {
-				if j%2 == 1 && user.Content[j-1].Value == "name" {
-					if field.Value == "capk" {
+		for i, existing := range users.Content {
+			for j, field := range existing.Content {
+				if j%2 == 1 && existing.Content[j-1].Value == "name" {
+					if field.Value == user {
 						users.Content[i] = usersWithCapk.Content[0]
 						ud, err := yaml.Marshal(root)
 						return ud, true, err
@@ -687,23 +1629,290 @@ func addCapkUserToCloudInitConfig(userdata, sshAuthorizedKey []byte) ([]byte, bo
 	return ud, true, err
 }
 
+// addCapkUserToIgnitionConfig adds the capk user, with the given name and the provided ssh authorized keys, to the
+// machine Ignition bootstrap config. If a capk user is already defined, then overrides it.
+// The returned boolean indicates whether the userdata was modified or not.
+func addCapkUserToIgnitionConfig(userdata []byte, user string, sshAuthorizedKeys ...[]byte) ([]byte, bool, error) {
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(userdata, &config); err != nil {
+		return nil, false, fmt.Errorf("failed to parse userdata ignition config: %w", err)
+	}
+
+	passwd, ok := config["passwd"].(map[string]interface{})
+	if !ok {
+		passwd = map[string]interface{}{}
+		config["passwd"] = passwd
+	}
+	users, _ := passwd["users"].([]interface{})
+
+	// Ignition can't add the user to groups which may not exist in the image, e.g. to grant it sudo, so the capk
+	// user is only used to check the bootstrap.
+	keys := []interface{}{}
+	for _, key := range sshAuthorizedKeys {
+		keys = append(keys, strings.TrimSpace(string(key)))
+	}
+	capkUser := map[string]interface{}{
+		"name":              user,
+		"gecos":             "CAPK User",
+		"sshAuthorizedKeys": keys,
+	}
+
+	found := false
+	for i, existing := range users {
+		if fields, ok := existing.(map[string]interface{}); ok && fields["name"] == user {
+			users[i] = capkUser
+			found = true
+			break
+		}
+	}
+	if !found {
+		users = append(users, capkUser)
+	}
+	passwd["users"] = users
+
+	ud, err := json.Marshal(config)
+	return ud, true, err
+}
+
+// kubeVIPManifest renders the static pod of kube-vip announcing the virtual IP of the control plane endpoint with ARP,
+// from the leader of the control plane nodes.
+func kubeVIPManifest(kubeVIP *infrav1.KubeVIPSpec) ([]byte, error) {
+	address := net.ParseIP(kubeVIP.Address)
+	if address == nil {
+		return nil, fmt.Errorf("invalid kube-vip address %q", kubeVIP.Address)
+	}
+	cidr := "32"
+	if address.To4() == nil {
+		cidr = "128"
+	}
+	image := kubeVIP.Image
+	if image == "" {
+		image = defaultKubeVIPImage
+	}
+	iface := kubeVIP.Interface
+	if iface == "" {
+		iface = defaultKubeVIPInterface
+	}
+
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-vip", Namespace: metav1.NamespaceSystem},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "kube-vip",
+				Image: image,
+				Args:  []string{"manager"},
+				Env: []corev1.EnvVar{
+					{Name: "vip_arp", Value: "true"},
+					{Name: "port", Value: "6443"},
+					{Name: "vip_interface", Value: iface},
+					{Name: "vip_cidr", Value: cidr},
+					{Name: "cp_enable", Value: "true"},
+					{Name: "cp_namespace", Value: metav1.NamespaceSystem},
+					{Name: "vip_leaderelection", Value: "true"},
+					{Name: "vip_leasename", Value: "plndr-cp-lock"},
+					{Name: "vip_leaseduration", Value: "15"},
+					{Name: "vip_renewdeadline", Value: "10"},
+					{Name: "vip_retryperiod", Value: "2"},
+					{Name: "address", Value: kubeVIP.Address},
+				},
+				SecurityContext: &corev1.SecurityContext{
+					Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "NET_RAW"}},
+				},
+				VolumeMounts: []corev1.VolumeMount{{Name: "kubeconfig", MountPath: "/etc/kubernetes/admin.conf"}},
+			}},
+			HostAliases: []corev1.HostAlias{{IP: "127.0.0.1", Hostnames: []string{"kubernetes"}}},
+			HostNetwork: true,
+			Volumes: []corev1.Volume{{
+				Name:         "kubeconfig",
+				VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/kubernetes/admin.conf"}},
+			}},
+		},
+	}
+
+	manifest, err := k8syaml.Marshal(pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the kube-vip static pod: %w", err)
+	}
+	return manifest, nil
+}
+
+// addKubeVIPToCloudInitConfig adds the kube-vip static pod manifest to the files written by the machine cloud-init
+// bootstrap user-data, before kubeadm runs. If the user-data is not a cloud-init config, then returns it as-is.
+func addKubeVIPToCloudInitConfig(userdata, manifest []byte) ([]byte, error) {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(userdata, root); err != nil {
+		return nil, fmt.Errorf("failed to parse userdata yaml: %w", err)
+	}
+	data := cloudInitConfigData(root)
+	if data == nil {
+		return userdata, nil
+	}
+
+	writeFiles := &yaml.Node{}
+	if err := writeFiles.Encode(map[string]interface{}{
+		"write_files": []map[string]string{{
+			"path":        kubeVIPManifestPath,
+			"owner":       "root:root",
+			"permissions": "0644",
+			"content":     string(manifest),
+		}},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to render the kube-vip cloud-init config: %w", err)
+	}
+
+	mergeYamlMappings(data, writeFiles)
+	return yaml.Marshal(root)
+}
+
+// addKubeVIPToIgnitionConfig adds the kube-vip static pod manifest to the files of the machine Ignition bootstrap
+// config. If the manifest is already defined, then overrides it.
+func addKubeVIPToIgnitionConfig(userdata, manifest []byte) ([]byte, error) {
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(userdata, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse userdata ignition config: %w", err)
+	}
+
+	storage, ok := config["storage"].(map[string]interface{})
+	if !ok {
+		storage = map[string]interface{}{}
+		config["storage"] = storage
+	}
+	files, _ := storage["files"].([]interface{})
+
+	file := map[string]interface{}{
+		"path": kubeVIPManifestPath,
+		"mode": 0644,
+		"contents": map[string]interface{}{
+			"source": "data:;base64," + base64.StdEncoding.EncodeToString(manifest),
+		},
+	}
+	// the files of the version 2 configs, e.g. rendered from Container Linux Configs, must set their filesystem.
+	if ignition, ok := config["ignition"].(map[string]interface{}); ok {
+		if version, _ := ignition["version"].(string); strings.HasPrefix(version, "2.") {
+			file["filesystem"] = "root"
+		}
+	}
+
+	found := false
+	for i, existing := range files {
+		if fields, ok := existing.(map[string]interface{}); ok && fields["path"] == kubeVIPManifestPath {
+			files[i] = file
+			found = true
+			break
+		}
+	}
+	if !found {
+		files = append(files, file)
+	}
+	storage["files"] = files
+
+	return json.Marshal(config)
+}
+
+// cloudInitConfigData returns the mapping node of the cloud-init config document, or nil if the
+// document is not a cloud-init config.
+func cloudInitConfigData(root *yaml.Node) *yaml.Node {
+	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 {
+		return nil
+	}
+	data := root.Content[0]
+	if data.Kind != yaml.MappingNode || len(data.Content) == 0 {
+		return nil
+	}
+
+	// This resolves the first comment in the document; which can be associated with different nodes
+	// based on how it is written.
+	var headerComment string
+	for _, headerComment = range []string{root.HeadComment, data.HeadComment, data.Content[0].HeadComment} {
+		if headerComment != "" {
+			break
+		}
+	}
+	if !regexp.MustCompile(`(?m)^#cloud-config`).MatchString(headerComment) {
+		return nil
+	}
+	return data
+}
+
+// mergeCloudInitConfig merges the additional cloud-init config into the machine cloud-init
+// bootstrap user-data. If the user-data is not a cloud-init config, then returns it as-is.
+func mergeCloudInitConfig(userdata, additional []byte) ([]byte, error) {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(userdata, root); err != nil {
+		return nil, fmt.Errorf("failed to parse userdata yaml: %w", err)
+	}
+	data := cloudInitConfigData(root)
+	if data == nil {
+		return userdata, nil
+	}
+
+	additionalRoot := &yaml.Node{}
+	if err := yaml.Unmarshal(additional, additionalRoot); err != nil {
+		return nil, fmt.Errorf("failed to parse additional userdata yaml: %w", err)
+	}
+	additionalData := cloudInitConfigData(additionalRoot)
+	if additionalData == nil {
+		return nil, errors.New("the additional userdata is not a cloud-init config")
+	}
+
+	mergeYamlMappings(data, additionalData)
+	return yaml.Marshal(root)
+}
+
+// mergeYamlMappings merges the src mapping node into the dst one: the mappings are merged
+// recursively, the sequences of src are prepended to the ones of dst, and the other values of dst
+// are kept.
+func mergeYamlMappings(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+
+		found := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value != key.Value {
+				continue
+			}
+			found = true
+			switch dstValue := dst.Content[j+1]; {
+			case dstValue.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+				mergeYamlMappings(dstValue, value)
+			case dstValue.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+				dstValue.Content = append(append([]*yaml.Node{}, value.Content...), dstValue.Content...)
+			}
+			break
+		}
+		if !found {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+}
+
+// capkCloudInitUser is the capk user in the 'users' sequence of a cloud-init config.
+type capkCloudInitUser struct {
+	Name              string   `yaml:"name"`
+	Gecos             string   `yaml:"gecos"`
+	Sudo              string   `yaml:"sudo"`
+	Groups            string   `yaml:"groups"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys"`
+}
+
 // usersYamlNodes generates the yaml.Nodes representing the 'users' key and the sequence of users
-// with the capk user and the specified ssh authorized key.
-func usersYamlNodes(sshAuthorizedKey []byte) (*yaml.Node, *yaml.Node, error) {
-	usersYaml :=
-		`users:
-- name: capk
-  gecos: CAPK User
-  sudo: ALL=(ALL) NOPASSWD:ALL
-  groups: users, admin
-  ssh_authorized_keys:
-  - ` + string(sshAuthorizedKey)
-
-	var node yaml.Node
-	if err := yaml.Unmarshal([]byte(usersYaml), &node); err != nil {
+// with the capk user and the specified ssh authorized keys.
+func usersYamlNodes(user string, sshAuthorizedKeys [][]byte) (*yaml.Node, *yaml.Node, error) {
+	capkUser := capkCloudInitUser{
+		Name:   user,
+		Gecos:  "CAPK User",
+		Sudo:   "ALL=(ALL) NOPASSWD:ALL",
+		Groups: "users, admin",
+	}
+	for _, key := range sshAuthorizedKeys {
+		capkUser.SSHAuthorizedKeys = append(capkUser.SSHAuthorizedKeys, strings.TrimSpace(string(key)))
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(map[string][]capkCloudInitUser{"users": {capkUser}}); err != nil {
 		return nil, nil, fmt.Errorf("failed to render capk user as valid yaml: %w", err)
 	}
 
-	data := node.Content[0].Content
-	return data[0], data[1], nil
+	return node.Content[0], node.Content[1], nil
 }
@@ -30,22 +30,29 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/kubevirt"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	k8syaml "sigs.k8s.io/yaml"
 
 	machinemocks "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/kubevirt/mock"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 	infraclustermock "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster/mock"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/ssh"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/testing"
 	workloadclustermock "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster/mock"
 )
@@ -140,11 +147,615 @@ var _ = Describe("KubevirtClusterToKubevirtMachines", func() {
 	})
 })
 
+var _ = Describe("InfraNodeToKubevirtMachines", func() {
+	const infraNodeName = "infra-node1"
+
+	newVMI := func(name, nodeName string) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "infra-namespace",
+				Labels: map[string]string{
+					infrav1.KubevirtMachineNameLabel:      name,
+					infrav1.KubevirtMachineNamespaceLabel: "default",
+				},
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				NodeName: nodeName,
+			},
+		}
+	}
+
+	It("should generate requests for the Kubevirt machines running on the infra node", func() {
+		objects := []client.Object{
+			newVMI("machine-on-node", infraNodeName),
+			newVMI("machine-on-other-node", "infra-node2"),
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(objects...).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{
+			Client: fakeClient,
+		}
+
+		infraNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: infraNodeName}}
+		out := kubevirtMachineReconciler.InfraNodeToKubevirtMachines(gocontext.Background(), infraNode)
+		Expect(out).To(ConsistOf(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "machine-on-node"}}))
+	})
+
+	It("should only pass the node cordon changes", func() {
+		oldNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: infraNodeName}}
+		newNode := oldNode.DeepCopy()
+		newNode.Labels = map[string]string{"foo": "bar"}
+		Expect(nodeCordonChanged().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeFalse())
+
+		newNode.Spec.Unschedulable = true
+		Expect(nodeCordonChanged().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeTrue())
+	})
+})
+
+var _ = Describe("MigrationToKubevirtMachine", func() {
+	newMigration := func(vmiName string) *kubevirtv1.VirtualMachineInstanceMigration {
+		return &kubevirtv1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "infra-namespace"},
+			Spec:       kubevirtv1.VirtualMachineInstanceMigrationSpec{VMIName: vmiName},
+		}
+	}
+
+	BeforeEach(func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: "infra-namespace",
+				Labels: map[string]string{
+					infrav1.KubevirtMachineNameLabel:      "machine",
+					infrav1.KubevirtMachineNamespaceLabel: "default",
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(vmi).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{
+			Client: fakeClient,
+		}
+	})
+
+	It("should generate a request for the Kubevirt machine of the migrated VMI", func() {
+		out := kubevirtMachineReconciler.MigrationToKubevirtMachine(gocontext.Background(), newMigration("machine"))
+		Expect(out).To(ConsistOf(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "machine"}}))
+	})
+
+	It("should not generate a request if the VMI does not exist", func() {
+		out := kubevirtMachineReconciler.MigrationToKubevirtMachine(gocontext.Background(), newMigration("missing"))
+		Expect(out).To(BeEmpty())
+	})
+})
+
+var _ = Describe("VMIToKubevirtMachine", func() {
+	It("should generate a request for the Kubevirt machine of the VMI", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: "infra-namespace",
+				Labels: map[string]string{
+					infrav1.KubevirtMachineNameLabel:      "machine",
+					infrav1.KubevirtMachineNamespaceLabel: "default",
+				},
+			},
+		}
+		out := kubevirtMachineReconciler.VMIToKubevirtMachine(gocontext.Background(), vmi)
+		Expect(out).To(ConsistOf(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "machine"}}))
+	})
+
+	It("should not generate a request for a VMI of no Kubevirt machine", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{ObjectMeta: metav1.ObjectMeta{Name: "vmi", Namespace: "infra-namespace"}}
+		Expect(kubevirtMachineReconciler.VMIToKubevirtMachine(gocontext.Background(), vmi)).To(BeEmpty())
+	})
+
+	It("should only pass the VMI updates changing its interfaces", func() {
+		oldVMI := &kubevirtv1.VirtualMachineInstance{
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Interfaces: []kubevirtv1.VirtualMachineInstanceNetworkInterface{{IP: "10.0.0.1"}},
+			},
+		}
+		newVMI := oldVMI.DeepCopy()
+		newVMI.Status.Phase = kubevirtv1.Running
+		Expect(vmiInterfacesChanged().Update(event.UpdateEvent{ObjectOld: oldVMI, ObjectNew: newVMI})).To(BeFalse())
+
+		newVMI.Status.Interfaces[0].IPs = []string{"10.0.0.1", "fd00::1"}
+		Expect(vmiInterfacesChanged().Update(event.UpdateEvent{ObjectOld: oldVMI, ObjectNew: newVMI})).To(BeTrue())
+	})
+})
+
+var _ = Describe("KubeconfigSecretToKubevirtMachines", func() {
+	newKubevirtMachine := func(name, reason string) *infrav1.KubevirtMachine {
+		kubevirtMachine := &infrav1.KubevirtMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+			},
+		}
+		if reason != "" {
+			conditions.MarkFalse(kubevirtMachine, infrav1.DrainingSucceededCondition, reason, clusterv1.ConditionSeverityWarning, "")
+		}
+		return kubevirtMachine
+	}
+
+	BeforeEach(func() {
+		objects := []client.Object{
+			newKubevirtMachine("failed-machine", infrav1.TenantClusterClientFailedReason),
+			newKubevirtMachine("draining-machine", infrav1.DrainingReason),
+			newKubevirtMachine("machine", ""),
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(objects...).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{
+			Client: fakeClient,
+		}
+	})
+
+	It("should generate requests for the Kubevirt machines that failed to get a tenant cluster client", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-kubeconfig",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+		}}
+		out := kubevirtMachineReconciler.KubeconfigSecretToKubevirtMachines(gocontext.Background(), secret)
+		Expect(out).To(ConsistOf(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "failed-machine"}}))
+	})
+
+	It("should ignore the other secrets of the cluster", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-ca",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+		}}
+		Expect(kubevirtMachineReconciler.KubeconfigSecretToKubevirtMachines(gocontext.Background(), secret)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("getDrainPolicy", func() {
+	var kubevirtClusterWithPolicy *infrav1.KubevirtCluster
+
+	BeforeEach(func() {
+		kubevirtClusterWithPolicy = &infrav1.KubevirtCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			Spec: infrav1.KubevirtClusterSpec{
+				DrainPolicyRef: &corev1.LocalObjectReference{Name: "drain-policy"},
+			},
+		}
+	})
+
+	It("should return the KubevirtDrainPolicy referenced by the KubevirtCluster", func() {
+		drainPolicy := &infrav1.KubevirtDrainPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "drain-policy", Namespace: "default"},
+			Spec:       infrav1.KubevirtDrainPolicySpec{EvacuationPolicy: infrav1.DrainAndDeleteEvacuationPolicy},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(drainPolicy).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{Client: fakeClient}
+
+		out, err := kubevirtMachineReconciler.getDrainPolicy(gocontext.Background(), kubevirtClusterWithPolicy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).ToNot(BeNil())
+		Expect(out.Spec.EvacuationPolicy).To(Equal(infrav1.DrainAndDeleteEvacuationPolicy))
+	})
+
+	It("should return nil if the KubevirtDrainPolicy does not exist", func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{Client: fakeClient}
+
+		out, err := kubevirtMachineReconciler.getDrainPolicy(gocontext.Background(), kubevirtClusterWithPolicy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(BeNil())
+	})
+})
+
+var _ = Describe("reconcileIPAddresses", func() {
+	var machineContext *context.MachineContext
+
+	BeforeEach(func() {
+		kubevirtMachineWithPools := &infrav1.KubevirtMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default", UID: "machine-uid"},
+			Spec: infrav1.KubevirtMachineSpec{
+				AdditionalNetworks: []infrav1.AdditionalNetwork{
+					{
+						Name:                        "storage",
+						NetworkAttachmentDefinition: "storage-net",
+						AddressesFromPools: []corev1.TypedLocalObjectReference{
+							{APIGroup: pointer.String("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "storage-pool"},
+						},
+					},
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(kubevirtMachineWithPools).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{Client: fakeClient}
+		machineContext = &context.MachineContext{
+			Context:         gocontext.Background(),
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+			KubevirtMachine: kubevirtMachineWithPools,
+			Logger:          ctrl.Log.WithName("test"),
+		}
+	})
+
+	It("should claim the IP addresses and wait for them to be allocated", func() {
+		allocated, err := kubevirtMachineReconciler.reconcileIPAddresses(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocated).To(BeFalse())
+
+		claim := &ipamv1.IPAddressClaim{}
+		Expect(fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: "default", Name: "machine-storage-0"}, claim)).To(Succeed())
+		Expect(claim.Spec.PoolRef.Name).To(Equal("storage-pool"))
+		Expect(claim.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "cluster"))
+		Expect(claim.OwnerReferences).To(HaveLen(1))
+
+		allocated, err = kubevirtMachineReconciler.reconcileIPAddresses(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocated).To(BeFalse())
+		Expect(machineContext.IPAddresses).To(BeNil())
+	})
+
+	It("should set the allocated IP addresses in the machine context", func() {
+		claim := &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine-storage-0", Namespace: "default"},
+			Status:     ipamv1.IPAddressClaimStatus{AddressRef: corev1.LocalObjectReference{Name: "storage-address"}},
+		}
+		address := &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage-address", Namespace: "default"},
+			Spec:       ipamv1.IPAddressSpec{Address: "10.10.0.5", Prefix: 24},
+		}
+		Expect(fakeClient.Create(gocontext.Background(), claim)).To(Succeed())
+		Expect(fakeClient.Create(gocontext.Background(), address)).To(Succeed())
+
+		allocated, err := kubevirtMachineReconciler.reconcileIPAddresses(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocated).To(BeTrue())
+		Expect(machineContext.IPAddresses).To(Equal(map[string][]string{"storage": {"10.10.0.5/24"}}))
+	})
+
+	It("should set the static IP addresses before the allocated ones", func() {
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks[0].Addresses = []string{"10.10.1.5/24"}
+		claim := &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine-storage-0", Namespace: "default"},
+			Status:     ipamv1.IPAddressClaimStatus{AddressRef: corev1.LocalObjectReference{Name: "storage-address"}},
+		}
+		address := &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage-address", Namespace: "default"},
+			Spec:       ipamv1.IPAddressSpec{Address: "10.10.0.5", Prefix: 24},
+		}
+		Expect(fakeClient.Create(gocontext.Background(), claim)).To(Succeed())
+		Expect(fakeClient.Create(gocontext.Background(), address)).To(Succeed())
+
+		allocated, err := kubevirtMachineReconciler.reconcileIPAddresses(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocated).To(BeTrue())
+		Expect(machineContext.IPAddresses).To(Equal(map[string][]string{"storage": {"10.10.1.5/24", "10.10.0.5/24"}}))
+	})
+
+	It("should release the IP addresses", func() {
+		_, err := kubevirtMachineReconciler.reconcileIPAddresses(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(kubevirtMachineReconciler.deleteIPAddressClaims(machineContext)).To(Succeed())
+
+		claims := &ipamv1.IPAddressClaimList{}
+		Expect(fakeClient.List(gocontext.Background(), claims)).To(Succeed())
+		Expect(claims.Items).To(BeEmpty())
+	})
+})
+
+var _ = Describe("captureSerialConsoleLog", func() {
+	var (
+		machineContext  *context.MachineContext
+		k8sClient       *k8sfake.Clientset
+		launcherPodName = "virt-launcher-machine-abcde"
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		infraClusterMock := infraclustermock.NewMockInfraCluster(mockCtrl)
+
+		kubevirtMachineWithConsole := &infrav1.KubevirtMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default", UID: "machine-uid"},
+			Spec: infrav1.KubevirtMachineSpec{
+				SerialConsoleLog: &infrav1.SerialConsoleLogSpec{},
+			},
+		}
+		k8sClient = k8sfake.NewSimpleClientset(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      launcherPodName,
+				Namespace: "default",
+				Labels:    map[string]string{kubevirtv1.AppLabel: "virt-launcher", kubevirtv1.VirtualMachineNameLabel: "machine"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		})
+		infraClusterMock.EXPECT().GenerateInfraClusterK8sClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(k8sClient, nil).AnyTimes()
+
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(kubevirtMachineWithConsole).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{Client: fakeClient, InfraCluster: infraClusterMock}
+		machineContext = &context.MachineContext{
+			Context:         gocontext.Background(),
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+			KubevirtMachine: kubevirtMachineWithConsole,
+			Logger:          ctrl.Log.WithName("test"),
+		}
+	})
+
+	It("should store the serial console of the VM in the serial console ConfigMap", func() {
+		kubevirtMachineReconciler.captureSerialConsoleLog(machineContext, "default")
+
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: "default", Name: "machine-serial-console"}, configMap)).To(Succeed())
+		Expect(configMap.Data).To(HaveKeyWithValue(serialConsoleConfigMapKey, "fake logs"))
+		Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "cluster"))
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("should only log the serial console of the VM with the Log target", func() {
+		machineContext.KubevirtMachine.Spec.SerialConsoleLog.Target = infrav1.LogSerialConsoleLogTarget
+
+		kubevirtMachineReconciler.captureSerialConsoleLog(machineContext, "default")
+
+		configMaps := &corev1.ConfigMapList{}
+		Expect(fakeClient.List(gocontext.Background(), configMaps)).To(Succeed())
+		Expect(configMaps.Items).To(BeEmpty())
+	})
+
+	It("should read the logs of the guest-console-log container of the virt-launcher pod", func() {
+		machineContext.KubevirtMachine.Spec.SerialConsoleLog.TailLines = pointer.Int64(20)
+
+		output, err := kubevirtMachineReconciler.serialConsoleLog(machineContext, "default")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("fake logs"))
+
+		var logOptions *corev1.PodLogOptions
+		for _, action := range k8sClient.Actions() {
+			if action.GetSubresource() == "log" {
+				logOptions = action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+			}
+		}
+		Expect(logOptions).ToNot(BeNil())
+		Expect(logOptions.Container).To(Equal(serialConsoleContainerName))
+		Expect(logOptions.TailLines).To(Equal(pointer.Int64(20)))
+	})
+
+	It("should not capture anything before the virt-launcher pod is created", func() {
+		Expect(k8sClient.CoreV1().Pods("default").Delete(gocontext.Background(), launcherPodName, metav1.DeleteOptions{})).To(Succeed())
+
+		output, err := kubevirtMachineReconciler.serialConsoleLog(machineContext, "default")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(BeEmpty())
+	})
+})
+
+var _ = Describe("placeOnInfraCluster", func() {
+	var machineContext *context.MachineContext
+
+	newKubevirtMachine := func(name string, infraCluster string) *infrav1.KubevirtMachine {
+		return &infrav1.KubevirtMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name + "-uid"),
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+			},
+			Status: infrav1.KubevirtMachineStatus{InfraCluster: infraCluster},
+		}
+	}
+
+	BeforeEach(func() {
+		kubevirtMachine := newKubevirtMachine("machine", "")
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(
+			kubevirtMachine,
+			newKubevirtMachine("machine-a", "infra-a"),
+			newKubevirtMachine("machine-b", "infra-a"),
+			newKubevirtMachine("machine-c", "infra-b"),
+		).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{Client: fakeClient}
+		machineContext = &context.MachineContext{
+			Context: gocontext.Background(),
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+			KubevirtCluster: &infrav1.KubevirtCluster{
+				Spec: infrav1.KubevirtClusterSpec{
+					InfraClusters: []infrav1.InfraClusterReference{
+						{Name: "infra-a", Weight: pointer.Int32(3)},
+						{Name: "infra-b", SecretRef: &corev1.ObjectReference{Name: "infra-b-kubeconfig"}, Namespace: "vms"},
+					},
+				},
+			},
+			KubevirtMachine: kubevirtMachine,
+			Logger:          ctrl.Log.WithName("test"),
+		}
+	})
+
+	It("should place the machine on the infra cluster with the fewest machines with the Spread policy", func() {
+		infraCluster, err := kubevirtMachineReconciler.placeOnInfraCluster(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(infraCluster.Name).To(Equal("infra-b"))
+		Expect(infraCluster.SecretRef.Name).To(Equal("infra-b-kubeconfig"))
+		Expect(machineContext.KubevirtMachine.Status.InfraCluster).To(Equal("infra-b"))
+	})
+
+	It("should place the machine according to the weights of the infra clusters with the Weighted policy", func() {
+		machineContext.KubevirtCluster.Spec.InfraClusterPlacementPolicy = infrav1.WeightedInfraClusterPlacementPolicy
+
+		infraCluster, err := kubevirtMachineReconciler.placeOnInfraCluster(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(infraCluster.Name).To(Equal("infra-a"))
+		Expect(machineContext.KubevirtMachine.Status.InfraCluster).To(Equal("infra-a"))
+	})
+
+	It("should keep the infra cluster the machine landed on", func() {
+		machineContext.KubevirtMachine.Status.InfraCluster = "infra-a"
+
+		infraCluster, err := kubevirtMachineReconciler.placeOnInfraCluster(machineContext)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(infraCluster.Name).To(Equal("infra-a"))
+	})
+
+	It("should fail when no infra cluster accepts new machines", func() {
+		machineContext.KubevirtCluster.Spec.InfraClusterPlacementPolicy = infrav1.WeightedInfraClusterPlacementPolicy
+		for i := range machineContext.KubevirtCluster.Spec.InfraClusters {
+			machineContext.KubevirtCluster.Spec.InfraClusters[i].Weight = pointer.Int32(0)
+		}
+
+		_, err := kubevirtMachineReconciler.placeOnInfraCluster(machineContext)
+		Expect(err).To(HaveOccurred())
+		Expect(machineContext.KubevirtMachine.Status.InfraCluster).To(BeEmpty())
+	})
+})
+
+var _ = Describe("vmCreationWait", func() {
+	var machineContext *context.MachineContext
+
+	BeforeEach(func() {
+		kubevirtMachineReconciler = KubevirtMachineReconciler{CreationLimiter: kubevirt.NewVMCreationLimiter()}
+		machineContext = &context.MachineContext{
+			Context:         gocontext.Background(),
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+			KubevirtCluster: &infrav1.KubevirtCluster{},
+			KubevirtMachine: testing.NewKubevirtMachine("machine-a", "machine-a"),
+			Logger:          ctrl.Log.WithName("test"),
+		}
+	})
+
+	It("should not limit the VM creations by default", func() {
+		for _, name := range []string{"machine-a", "machine-b", "machine-c"} {
+			machineContext.KubevirtMachine.Name = name
+			Expect(kubevirtMachineReconciler.vmCreationWait(machineContext)).To(BeZero())
+		}
+	})
+
+	It("should make the machines over the VM creation rate limit of the cluster wait for their turn", func() {
+		machineContext.KubevirtCluster.Spec.VMCreationRateLimit = &infrav1.VMCreationRateLimit{
+			VMs:      2,
+			Interval: &metav1.Duration{Duration: 5 * time.Minute},
+		}
+
+		Expect(kubevirtMachineReconciler.vmCreationWait(machineContext)).To(BeZero())
+		machineContext.KubevirtMachine.Name = "machine-b"
+		Expect(kubevirtMachineReconciler.vmCreationWait(machineContext)).To(BeZero())
+		machineContext.KubevirtMachine.Name = "machine-c"
+		Expect(kubevirtMachineReconciler.vmCreationWait(machineContext)).To(BeNumerically("~", 5*time.Minute, time.Second))
+		machineContext.KubevirtMachine.Name = "machine-a"
+		Expect(kubevirtMachineReconciler.vmCreationWait(machineContext)).To(BeZero())
+	})
+})
+
+var _ = Describe("reconcileKubevirtBootstrapSecret", func() {
+	var (
+		bootstrapDataSecret *corev1.Secret
+		machineContext      *context.MachineContext
+	)
+
+	BeforeEach(func() {
+		bootstrapDataSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-secret"},
+			Data: map[string][]byte{
+				"value":  []byte("version: v1alpha1\nmachine:\n  type: worker\n"),
+				"format": []byte("talos"),
+			},
+		}
+		kubevirtMachine := testing.NewKubevirtMachine("kubevirt-machine", "machine")
+		machine := testing.NewMachine("cluster", "machine", kubevirtMachine)
+		machine.Spec.Bootstrap.DataSecretName = pointer.String(bootstrapDataSecret.Name)
+		machineContext = &context.MachineContext{
+			Context:         gocontext.Background(),
+			Machine:         machine,
+			KubevirtCluster: testing.NewKubevirtCluster("cluster", "machine"),
+			KubevirtMachine: kubevirtMachine,
+			Logger:          ctrl.Log.WithName("test"),
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(bootstrapDataSecret).Build()
+		kubevirtMachineReconciler = KubevirtMachineReconciler{Client: fakeClient}
+	})
+
+	It("should merge the additional user data into the cloud-config bootstrap data", func() {
+		bootstrapDataSecret.Data = map[string][]byte{"value": []byte("#cloud-config\nruncmd:\n  - kubeadm join\n")}
+		Expect(fakeClient.Update(gocontext.Background(), bootstrapDataSecret)).To(Succeed())
+		additional := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: machineContext.KubevirtMachine.Namespace},
+			Data:       map[string][]byte{"userdata": []byte("#cloud-config\nruncmd:\n  - update-ca-trust\n")},
+		}
+		Expect(fakeClient.Create(gocontext.Background(), additional)).To(Succeed())
+		machineContext.KubevirtMachine.Spec.AdditionalUserDataSecretRef = &corev1.LocalObjectReference{Name: additional.Name}
+
+		Expect(kubevirtMachineReconciler.reconcileKubevirtBootstrapSecret(machineContext, fakeClient, "default", nil)).To(Succeed())
+
+		Expect(string(machineContext.BootstrapDataSecret.Data["userdata"])).To(Equal("#cloud-config\nruncmd:\n    - update-ca-trust\n    - kubeadm join\n"))
+	})
+
+	It("should authorize the ssh keys of the cluster and of the machine", func() {
+		bootstrapDataSecret.Data = map[string][]byte{"value": []byte("#cloud-config\nruncmd:\n  - kubeadm join\n")}
+		Expect(fakeClient.Update(gocontext.Background(), bootstrapDataSecret)).To(Succeed())
+		keysSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "operators", Namespace: machineContext.KubevirtMachine.Namespace},
+			Data:       map[string][]byte{"authorized_keys": []byte("# operators\nssh-ed25519 AAAA-bob\n\nssh-ed25519 AAAA-carol\n")},
+		}
+		Expect(fakeClient.Create(gocontext.Background(), keysSecret)).To(Succeed())
+		machineContext.KubevirtCluster.Spec.SSHAuthorizedKeys = &infrav1.SSHAuthorizedKeys{
+			SecretRefs: []corev1.LocalObjectReference{{Name: keysSecret.Name}},
+		}
+		machineContext.KubevirtMachine.Spec.SSHAuthorizedKeys = &infrav1.SSHAuthorizedKeys{Keys: []infrav1.SSHAuthorizedKey{"ssh-ed25519 AAAA-alice"}}
+
+		Expect(kubevirtMachineReconciler.reconcileKubevirtBootstrapSecret(machineContext, fakeClient, "default", nil)).To(Succeed())
+
+		Expect(string(machineContext.BootstrapDataSecret.Data["userdata"])).To(ContainSubstring(`ssh_authorized_keys:
+        - ssh-ed25519 AAAA-bob
+        - ssh-ed25519 AAAA-carol
+        - ssh-ed25519 AAAA-alice
+`))
+	})
+
+	It("should add the user of the bootstrap check to the bootstrap data", func() {
+		bootstrapDataSecret.Data = map[string][]byte{"value": []byte("#cloud-config\nruncmd:\n  - kubeadm join\n")}
+		Expect(fakeClient.Update(gocontext.Background(), bootstrapDataSecret)).To(Succeed())
+		machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.User = "cloud-user"
+
+		sshKeys := &ssh.ClusterNodeSshKeys{PublicKey: []byte("ssh-ed25519 5678")}
+		Expect(kubevirtMachineReconciler.reconcileKubevirtBootstrapSecret(machineContext, fakeClient, "default", sshKeys)).To(Succeed())
+
+		Expect(string(machineContext.BootstrapDataSecret.Data["userdata"])).To(ContainSubstring(`users:
+    - name: cloud-user
+      gecos: CAPK User`))
+		Expect(machineContext.HasInjectedCapkSSHKeys(sshKeys.PublicKey)).To(BeTrue())
+	})
+
+	It("should add the kube-vip static pod to the bootstrap data of the control plane machines", func() {
+		bootstrapDataSecret.Data = map[string][]byte{"value": []byte("#cloud-config\nruncmd:\n  - kubeadm init\n")}
+		Expect(fakeClient.Update(gocontext.Background(), bootstrapDataSecret)).To(Succeed())
+		machineContext.KubevirtCluster.Spec.KubeVIP = &infrav1.KubeVIPSpec{Address: "10.0.0.100"}
+		machineContext.Machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+
+		Expect(kubevirtMachineReconciler.reconcileKubevirtBootstrapSecret(machineContext, fakeClient, "default", nil)).To(Succeed())
+
+		userdata := string(machineContext.BootstrapDataSecret.Data["userdata"])
+		Expect(userdata).To(ContainSubstring("path: /etc/kubernetes/manifests/kube-vip.yaml"))
+		Expect(userdata).To(ContainSubstring("value: 10.0.0.100"))
+	})
+
+	It("should not add the kube-vip static pod to the bootstrap data of the worker machines", func() {
+		bootstrapDataSecret.Data = map[string][]byte{"value": []byte("#cloud-config\nruncmd:\n  - kubeadm join\n")}
+		Expect(fakeClient.Update(gocontext.Background(), bootstrapDataSecret)).To(Succeed())
+		machineContext.KubevirtCluster.Spec.KubeVIP = &infrav1.KubeVIPSpec{Address: "10.0.0.100"}
+
+		Expect(kubevirtMachineReconciler.reconcileKubevirtBootstrapSecret(machineContext, fakeClient, "default", nil)).To(Succeed())
+
+		Expect(string(machineContext.BootstrapDataSecret.Data["userdata"])).To(Equal("#cloud-config\nruncmd:\n  - kubeadm join\n"))
+	})
+
+	It("should pass the raw bootstrap data through unmodified", func() {
+		sshKeys := &ssh.ClusterNodeSshKeys{PublicKey: []byte("sha-rsa 5678")}
+		Expect(kubevirtMachineReconciler.reconcileKubevirtBootstrapSecret(machineContext, fakeClient, "default", sshKeys)).To(Succeed())
+
+		Expect(machineContext.BootstrapDataSecret.Data).To(HaveKeyWithValue("userdata", bootstrapDataSecret.Data["value"]))
+		Expect(machineContext.BootstrapDataFormat()).To(Equal(infrav1.RawBootstrapDataFormat))
+		Expect(machineContext.HasInjectedCapkSSHKeys(sshKeys.PublicKey)).To(BeFalse())
+	})
+})
+
 var _ = Describe("utility functions", func() {
 
 	DescribeTable("capk user",
 		func(userData []byte, sshAuthorizedKey string, expectedOrNil []byte) {
-			actual, modified, err := addCapkUserToCloudInitConfig(userData, []byte(sshAuthorizedKey))
+			actual, modified, err := addCapkUserToCloudInitConfig(userData, "capk", []byte(sshAuthorizedKey))
 			Expect(err).ShouldNot(HaveOccurred())
 			if expectedOrNil == nil {
 				Expect(modified).To(BeFalse())
@@ -223,15 +834,227 @@ runcmd:
     - 'kubeadm init --config /run/kubeadm/kubeadm.yaml  && echo success > /run/cluster-api/bootstrap-success.complete'
 `),
 		),
-		Entry("should not be added to non cloud-init config", []byte("hello: world"), "sha-rsa 5678", nil),
-	)
-})
-
-var _ = Describe("reconcile a kubevirt machine", func() {
-	var (
-		mockCtrl            *gomock.Controller
-		workloadClusterMock *workloadclustermock.MockWorkloadCluster
-		infraClusterMock    *infraclustermock.MockInfraCluster
+		Entry(
+			"should quote the ssh authorized keys that aren't plain yaml scalars",
+			[]byte(`#cloud-config
+runcmd:
+  - 'kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml'
+`),
+			"ssh-ed25519 AAAA- alice: #admin",
+			[]byte(`#cloud-config
+runcmd:
+    - 'kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml'
+users:
+    - name: capk
+      gecos: CAPK User
+      sudo: ALL=(ALL) NOPASSWD:ALL
+      groups: users, admin
+      ssh_authorized_keys:
+        - 'ssh-ed25519 AAAA- alice: #admin'
+`),
+		),
+		Entry("should not be added to non cloud-init config", []byte("hello: world"), "sha-rsa 5678", nil),
+	)
+
+	DescribeTable("capk ignition user",
+		func(userData string, expected string) {
+			actual, modified, err := addCapkUserToIgnitionConfig([]byte(userData), "capk", []byte("sha-rsa 5678\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			Expect(actual).To(MatchJSON(expected))
+		},
+		Entry(
+			"should be added to ignition config",
+			`{"ignition":{"version":"3.3.0"},"passwd":{"users":[{"name":"core"}]}}`,
+			`{"ignition":{"version":"3.3.0"},"passwd":{"users":[{"name":"core"},{"name":"capk","gecos":"CAPK User","sshAuthorizedKeys":["sha-rsa 5678"]}]}}`,
+		),
+		Entry(
+			"should be added to ignition config without users",
+			`{"ignition":{"version":"3.3.0"}}`,
+			`{"ignition":{"version":"3.3.0"},"passwd":{"users":[{"name":"capk","gecos":"CAPK User","sshAuthorizedKeys":["sha-rsa 5678"]}]}}`,
+		),
+		Entry(
+			"should be overridden when already in ignition config",
+			`{"ignition":{"version":"3.3.0"},"passwd":{"users":[{"name":"capk","groups":["wheel"]}]}}`,
+			`{"ignition":{"version":"3.3.0"},"passwd":{"users":[{"name":"capk","gecos":"CAPK User","sshAuthorizedKeys":["sha-rsa 5678"]}]}}`,
+		),
+	)
+
+	It("should fail to add the capk user to invalid ignition config", func() {
+		_, _, err := addCapkUserToIgnitionConfig([]byte("#cloud-config"), "capk", []byte("sha-rsa 5678"))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should merge the additional user data into the cloud-init config", func() {
+		userData := []byte(`## template: jinja
+#cloud-config
+write_files:
+-   path: /etc/kubernetes/pki/ca.crt
+    content: "ca"
+runcmd:
+  - 'kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml'
+`)
+		additional := []byte(`#cloud-config
+write_files:
+  - path: /etc/pki/ca-trust/source/anchors/proxy.crt
+    content: "proxy-ca"
+runcmd:
+  - update-ca-trust
+ntp:
+  servers:
+    - ntp.example.com
+`)
+		merged, err := mergeCloudInitConfig(userData, additional)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(merged)).To(Equal(`## template: jinja
+#cloud-config
+write_files:
+    - path: /etc/pki/ca-trust/source/anchors/proxy.crt
+      content: "proxy-ca"
+    - path: /etc/kubernetes/pki/ca.crt
+      content: "ca"
+runcmd:
+    - update-ca-trust
+    - 'kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml'
+ntp:
+    servers:
+        - ntp.example.com
+`))
+	})
+
+	It("should fail to merge additional user data which is not a cloud-init config", func() {
+		_, err := mergeCloudInitConfig([]byte("#cloud-config\nruncmd: []\n"), []byte("hello: world"))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should render the kube-vip static pod", func() {
+		manifest, err := kubeVIPManifest(&infrav1.KubeVIPSpec{Address: "fd00::100", Interface: "enp1s0", Image: "kube-vip:test"})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		pod := &corev1.Pod{}
+		Expect(k8syaml.Unmarshal(manifest, pod)).To(Succeed())
+		Expect(pod.Namespace).To(Equal("kube-system"))
+		Expect(pod.Spec.HostNetwork).To(BeTrue())
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Spec.Containers[0].Image).To(Equal("kube-vip:test"))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "address", Value: "fd00::100"},
+			corev1.EnvVar{Name: "vip_interface", Value: "enp1s0"},
+			corev1.EnvVar{Name: "vip_cidr", Value: "128"},
+		))
+	})
+
+	It("should fail to render the kube-vip static pod with an invalid address", func() {
+		_, err := kubeVIPManifest(&infrav1.KubeVIPSpec{Address: "api.example.com"})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should add the kube-vip static pod to the cloud-init config", func() {
+		actual, err := addKubeVIPToCloudInitConfig([]byte("#cloud-config\nruncmd:\n  - kubeadm init\n"), []byte("kind: Pod\n"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(actual)).To(Equal(`#cloud-config
+runcmd:
+    - kubeadm init
+write_files:
+    - content: |
+        kind: Pod
+      owner: root:root
+      path: /etc/kubernetes/manifests/kube-vip.yaml
+      permissions: "0644"
+`))
+	})
+
+	DescribeTable("kube-vip ignition static pod",
+		func(userData string, expected string) {
+			actual, err := addKubeVIPToIgnitionConfig([]byte(userData), []byte("kind: Pod\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(actual).To(MatchJSON(expected))
+		},
+		Entry(
+			"should be added to ignition config",
+			`{"ignition":{"version":"3.3.0"},"storage":{"files":[{"path":"/etc/hostname"}]}}`,
+			`{"ignition":{"version":"3.3.0"},"storage":{"files":[{"path":"/etc/hostname"},{"path":"/etc/kubernetes/manifests/kube-vip.yaml","mode":420,"contents":{"source":"data:;base64,a2luZDogUG9kCg=="}}]}}`,
+		),
+		Entry(
+			"should be added to the root filesystem of version 2 ignition config",
+			`{"ignition":{"version":"2.3.0"}}`,
+			`{"ignition":{"version":"2.3.0"},"storage":{"files":[{"filesystem":"root","path":"/etc/kubernetes/manifests/kube-vip.yaml","mode":420,"contents":{"source":"data:;base64,a2luZDogUG9kCg=="}}]}}`,
+		),
+		Entry(
+			"should be overridden when already in ignition config",
+			`{"ignition":{"version":"3.3.0"},"storage":{"files":[{"path":"/etc/kubernetes/manifests/kube-vip.yaml","mode":384}]}}`,
+			`{"ignition":{"version":"3.3.0"},"storage":{"files":[{"path":"/etc/kubernetes/manifests/kube-vip.yaml","mode":420,"contents":{"source":"data:;base64,a2luZDogUG9kCg=="}}]}}`,
+		),
+	)
+
+	DescribeTable("other IP family address",
+		func(primary string, additional []clusterv1.MachineAddress, expected string) {
+			Expect(otherIPFamilyAddress(primary, additional)).To(Equal(expected))
+		},
+		Entry("should be the first IPv6 address of an IPv4 VM", "10.0.0.5", []clusterv1.MachineAddress{
+			{Type: clusterv1.MachineInternalIP, Address: "10.10.0.5"},
+			{Type: clusterv1.MachineInternalIP, Address: "fd10::5"},
+			{Type: clusterv1.MachineInternalIP, Address: "fd00::5"},
+		}, "fd10::5"),
+		Entry("should be the first IPv4 address of an IPv6 VM", "fd10::5", []clusterv1.MachineAddress{
+			{Type: clusterv1.MachineInternalDNS, Address: "node1.nodes.default.svc"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.0.5"},
+		}, "10.0.0.5"),
+		Entry("should be empty for a single-stack VM", "10.0.0.5", []clusterv1.MachineAddress{
+			{Type: clusterv1.MachineInternalIP, Address: "10.10.0.5"},
+		}, ""),
+		Entry("should be empty without primary address", "", []clusterv1.MachineAddress{
+			{Type: clusterv1.MachineInternalIP, Address: "fd10::5"},
+		}, ""),
+	)
+
+	DescribeTable("bootstrap data format",
+		func(format string, expected infrav1.BootstrapDataFormat) {
+			Expect(bootstrapDataFormat([]byte(format))).To(Equal(expected))
+		},
+		Entry("should be unset without format", "", infrav1.BootstrapDataFormat("")),
+		Entry("should be cloud-config", "cloud-config", infrav1.CloudConfigBootstrapDataFormat),
+		Entry("should be ignition", "ignition", infrav1.IgnitionBootstrapDataFormat),
+		Entry("should be raw for the other formats", "talos", infrav1.RawBootstrapDataFormat),
+	)
+
+	DescribeTable("unschedulable reason",
+		func(memory *kubevirtv1.Memory, message string, expected string) {
+			kubevirtMachine := &infrav1.KubevirtMachine{
+				Spec: infrav1.KubevirtMachineSpec{
+					VirtualMachineTemplate: infrav1.VirtualMachineTemplateSpec{
+						Spec: kubevirtv1.VirtualMachineSpec{
+							Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+								Spec: kubevirtv1.VirtualMachineInstanceSpec{
+									Domain: kubevirtv1.DomainSpec{Memory: memory},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(unschedulableReason(kubevirtMachine, message)).To(Equal(expected))
+		},
+		Entry("should be HugepagesUnavailable if no node has the hugepages",
+			&kubevirtv1.Memory{Hugepages: &kubevirtv1.Hugepages{PageSize: "1Gi"}},
+			"0/3 nodes are available: 3 Insufficient hugepages-1Gi.",
+			infrav1.HugepagesUnavailableReason),
+		Entry("should be VMUnschedulable if the VM doesn't request hugepages",
+			nil,
+			"0/3 nodes are available: 3 Insufficient nvidia.com/gpu_dev1.",
+			infrav1.VMUnschedulableReason),
+		Entry("should be VMUnschedulable if the hugepages are not the scheduling failure",
+			&kubevirtv1.Memory{Hugepages: &kubevirtv1.Hugepages{PageSize: "2Mi"}},
+			"0/3 nodes are available: 3 Insufficient cpu.",
+			infrav1.VMUnschedulableReason),
+	)
+})
+
+var _ = Describe("reconcile a kubevirt machine", func() {
+	var (
+		mockCtrl            *gomock.Controller
+		workloadClusterMock *workloadclustermock.MockWorkloadCluster
+		infraClusterMock    *infraclustermock.MockInfraCluster
 
 		machineFactoryMock *machinemocks.MockMachineFactory
 		machineMock        *machinemocks.MockMachineInterface
@@ -402,13 +1225,28 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 		setupClient(machineFactoryMock, objects)
 
 		machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+		machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
 		machineMock.EXPECT().Exists().Return(true).Times(1)
 		machineMock.EXPECT().IsReady().Return(false).AnyTimes()
+		machineMock.EXPECT().UnschedulableMessage().Return("").AnyTimes()
 		machineMock.EXPECT().Address().Return("1.1.1.1").AnyTimes()
+		machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+		machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+		machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+		machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+		machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+		machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+		machineMock.EXPECT().Adopt().AnyTimes()
+		machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+		machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+		machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+		machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+		machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
 		machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(false).AnyTimes()
 		machineMock.EXPECT().GenerateProviderID().Return("abc", nil).AnyTimes()
 		machineMock.EXPECT().GenerateProviderID().Return("abc", nil).AnyTimes()
-		machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+		machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+		machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
 		machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
 		infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil).Times(3)
@@ -581,6 +1419,42 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 		Expect(bootstrapDataSecret.Labels).To(HaveKeyWithValue("hello", "world"))
 	})
 
+	It("should create KubeVirt VM in the infra cluster namespace of the KubevirtCluster", func() {
+
+		infraNamespace := "tenant-infra"
+		kubevirtCluster.Spec.InfraClusterNamespace = infraNamespace
+
+		objects := []client.Object{
+			cluster,
+			kubevirtCluster,
+			machine,
+			kubevirtMachine,
+			sshKeySecret,
+			bootstrapSecret,
+		}
+
+		setupClient(kubevirt.DefaultMachineFactory{}, objects)
+
+		infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+		out, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).To(Equal(ctrl.Result{RequeueAfter: 20 * time.Second}))
+
+		// should default the namespace of the machine from the KubevirtCluster
+		Expect(machineContext.KubevirtMachine.Spec.InfraClusterNamespace).To(Equal(infraNamespace))
+
+		vm := &kubevirtv1.VirtualMachine{}
+		vmKey := client.ObjectKey{Namespace: infraNamespace, Name: kubevirtMachine.Name}
+		Expect(fakeClient.Get(gocontext.Background(), vmKey, vm)).To(Succeed())
+
+		machineBootstrapSecretReferenceName := machineContext.Machine.Spec.Bootstrap.DataSecretName
+		machineBootstrapSecretReferenceKey := client.ObjectKey{Namespace: infraNamespace, Name: *machineBootstrapSecretReferenceName + "-userdata"}
+		bootstrapDataSecret := &corev1.Secret{}
+		Expect(fakeClient.Get(gocontext.Background(), machineBootstrapSecretReferenceKey, bootstrapDataSecret)).To(Succeed())
+	})
+
 	It("should create KubeVirt VM in custom namespace", func() {
 
 		customNamespace := "custom"
@@ -814,8 +1688,81 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 				Expect(conditions[1].Type).To(Equal(infrav1.VMProvisionedCondition))
 				Expect(conditions[1].Reason).To(Equal(clusterv1.DeletingReason))
 			})
+
+			It("releases the drain in progress of the tenant cluster node", func() {
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					sshKeySecret,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+				}
+
+				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+				tracker := kubevirt.NewNodeDrainTracker()
+				kubevirtMachineReconciler.DrainOptions = kubevirt.DrainOptions{Tracker: tracker}
+				drainKey := kubevirt.NodeDrainKey(kubevirtMachine)
+				Expect(tracker.StartDrain(drainKey, 1)).To(BeTrue())
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+				_, err := kubevirtMachineReconciler.reconcileDelete(machineContext)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(tracker.DrainsInProgress(drainKey.Cluster)).To(BeZero())
+			})
+
+			It("leaves the externally managed VM in place", func() {
+				kubevirtMachine.Annotations = map[string]string{infrav1.ExternallyManagedVMAnnotation: ""}
+				vm.Namespace = kubevirtMachine.Namespace
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					sshKeySecret,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					vm,
+				}
+
+				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+				_, err := kubevirtMachineReconciler.reconcileDelete(machineContext)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(fakeClient.Get(machineContext, client.ObjectKeyFromObject(vm), &kubevirtv1.VirtualMachine{})).To(Succeed())
+			})
 		})
 		Context("reconcileNormal", func() {
+			It("adds a failed VMProvisionedCondition with reason WaitingForExternallyManagedVM when the externally managed VM doesn't exist", func() {
+				kubevirtMachine.Annotations = map[string]string{infrav1.ExternallyManagedVMAnnotation: ""}
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					sshKeySecret,
+					bootstrapSecret,
+				}
+
+				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				out, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(out).To(Equal(ctrl.Result{RequeueAfter: 20 * time.Second}))
+				Expect(conditions.GetReason(machineContext.KubevirtMachine, infrav1.VMProvisionedCondition)).To(Equal(infrav1.WaitingForExternallyManagedVMReason))
+
+				vms := &kubevirtv1.VirtualMachineList{}
+				Expect(fakeClient.List(machineContext, vms)).To(Succeed())
+				Expect(vms.Items).To(BeEmpty())
+			})
+
 			It("adds a failed VMProvisionedCondition with reason WaitingForControlPlaneAvailableReason when the control plane is not yet available", func() {
 				machine.Spec.Bootstrap.DataSecretName = nil
 				delete(machine.ObjectMeta.Labels, clusterv1.MachineControlPlaneNameLabel)
@@ -826,109 +1773,579 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 					kubevirtCluster,
 					machine,
 					kubevirtMachine,
-					sshKeySecret,
+					sshKeySecret,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+				}
+
+				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+
+				Expect(err).ShouldNot(HaveOccurred())
+
+				conditions := machineContext.KubevirtMachine.GetConditions()
+				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
+				Expect(conditions[0].Reason).To(Equal(clusterv1.WaitingForControlPlaneAvailableReason))
+			})
+			It("adds a failed VMProvisionedCondition with reason WaitingForBootstrapDataReason when bootstrap data is not yet available", func() {
+				machine.Spec.Bootstrap.DataSecretName = nil
+				delete(machine.ObjectMeta.Labels, clusterv1.MachineControlPlaneNameLabel)
+				conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					sshKeySecret,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+				}
+
+				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+
+				Expect(err).ShouldNot(HaveOccurred())
+
+				conditions := machineContext.KubevirtMachine.GetConditions()
+				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
+				Expect(conditions[0].Reason).To(Equal(infrav1.WaitingForBootstrapDataReason))
+			})
+			It("adds a failed VMProvisionedCondition with reason WaitingForBootstrapDataReason when failng to get bootstrap data secret", func() {
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					sshKeySecret,
+				}
+
+				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).Should(HaveOccurred())
+
+				conditions := machineContext.KubevirtMachine.GetConditions()
+				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
+				Expect(conditions[0].Reason).To(Equal(infrav1.WaitingForBootstrapDataReason))
+			})
+
+			It("adds a failed VMProvisionedCondition with reason VMCreateFailed when failng to create VM", func() {
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					sshKeySecret,
+					bootstrapSecret,
+				}
+
+				injectErr := interceptor.Funcs{
+					Create: func(ctx gocontext.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+
+						_, ok := obj.(*kubevirtv1.VirtualMachine)
+						if ok {
+							return errors.New("vm create error")
+						}
+						return nil
+					},
+				}
+
+				setupClientWithInterceptors(kubevirt.DefaultMachineFactory{}, objects, injectErr)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+
+				Expect(err).Should(HaveOccurred())
+
+				// should expect condition
+				conditions := machineContext.KubevirtMachine.GetConditions()
+				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
+				Expect(conditions[0].Status).To(Equal(corev1.ConditionFalse))
+				Expect(conditions[0].Reason).To(Equal(infrav1.VMCreateFailedReason))
+			})
+
+			It("adds a succeeded VMProvisionedCondition", func() {
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
+				}
+
+				setupClient(machineFactoryMock, objects)
+
+				machineMock.EXPECT().IsReady().Return(true).Times(2)
+				machineMock.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).Times(1)
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(false).Times(1)
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil)
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				conditions := machineContext.KubevirtMachine.GetConditions()
+				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
+				Expect(conditions[0].Status).To(Equal(corev1.ConditionTrue))
+			})
+			It("does not hot-plug the guest memory or the volumes of an externally managed VM", func() {
+				kubevirtMachine.Annotations = map[string]string{infrav1.ExternallyManagedVMAnnotation: ""}
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
+				}
+
+				setupClient(machineFactoryMock, objects)
+				kubevirtMachineReconciler.MemoryHotplug = true
+				machineContext.MemoryHotplug = true
+
+				machineMock.EXPECT().IsReady().Return(true).AnyTimes()
+				machineMock.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).AnyTimes()
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).AnyTimes()
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Times(0)
+				machineMock.EXPECT().ReconcileCPUHotplug().Times(0)
+				machineMock.EXPECT().ReconcileMemoryHotplug().Times(0)
+				machineMock.EXPECT().ReconcileMutableFields().Times(0)
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(false).AnyTimes()
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(conditions.IsTrue(machineContext.KubevirtMachine, infrav1.VMProvisionedCondition)).To(BeTrue())
+			})
+			It("adds a failed BootstrapExecSucceededCondition with reason BootstrapFailedReason when bootstraping is possible and failed", func() {
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+
+					{
+						IP: "1.1.1.1",
+					},
+				}
+				sshKeySecret.Data["pub"] = []byte("shell")
+
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
+				}
+
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().Create(nil).Return(nil).AnyTimes()
+				machineMock.EXPECT().IsReady().Return(true).Times(1)
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).AnyTimes()
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(true)
+				machineMock.EXPECT().IsBootstrapped().Return(false)
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil)
+
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				setupClient(machineFactoryMock, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				conditions := machineContext.KubevirtMachine.GetConditions()
+
+				Expect(conditions[0].Type).To(Equal(infrav1.BootstrapExecSucceededCondition))
+				Expect(conditions[0].Reason).To(Equal(infrav1.BootstrapFailedReason))
+			})
+
+			It("marks the machine as failed when the bootstrap check timed out", func() {
+				kubevirtMachine.Spec.BootstrapCheckSpec.Timeout = &metav1.Duration{Duration: time.Minute}
+				conditions.Set(kubevirtMachine, &clusterv1.Condition{
+					Type:               infrav1.BootstrapExecSucceededCondition,
+					Status:             corev1.ConditionFalse,
+					Reason:             infrav1.BootstrapFailedReason,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+				})
+
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+
+					{
+						IP: "1.1.1.1",
+					},
+				}
+				sshKeySecret.Data["pub"] = []byte("shell")
+
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
+				}
+
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().Create(nil).Return(nil).AnyTimes()
+				machineMock.EXPECT().IsReady().Return(true).Times(1)
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).AnyTimes()
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(true)
+				machineMock.EXPECT().IsBootstrapped().Return(false)
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil)
+
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				setupClient(machineFactoryMock, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				out, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(out).To(Equal(ctrl.Result{}))
+
+				Expect(conditions.GetReason(machineContext.KubevirtMachine, infrav1.BootstrapExecSucceededCondition)).To(Equal(infrav1.BootstrapTimedOutReason))
+				Expect(machineContext.KubevirtMachine.Status.FailureReason).ToNot(BeNil())
+				Expect(machineContext.KubevirtMachine.Status.FailureMessage).To(HaveValue(ContainSubstring("not bootstrapped")))
+				Expect(machineContext.KubevirtMachine.Status.Ready).To(BeFalse())
+			})
+
+			It("adds a succeeded BootstrapExecSucceededCondition", func() {
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+
+					{
+						IP: "1.1.1.1",
+					},
+				}
+				sshKeySecret.Data["pub"] = []byte("shell")
+
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
+				}
+
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().IsReady().Return(true).Times(2)
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).Times(1)
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(true)
+				machineMock.EXPECT().IsBootstrapped().Return(true)
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil)
+
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				setupClient(machineFactoryMock, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				conditions := machineContext.KubevirtMachine.GetConditions()
+
+				Expect(conditions[0].Type).To(Equal(infrav1.BootstrapExecSucceededCondition))
+				Expect(conditions[0].Status).To(Equal(corev1.ConditionTrue))
+			})
+
+			It("should requeue on node draining", func() {
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+
+					{
+						IP: "1.1.1.1",
+					},
+				}
+				sshKeySecret.Data["pub"] = []byte("shell")
+
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
 					bootstrapSecret,
 					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
 				}
 
-				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+				const requeueDurationSeconds = 3
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().IsReady().Return(true).Times(1)
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Second*requeueDurationSeconds, nil).Times(1)
 
-				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
+				setupClient(machineFactoryMock, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				res, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				conditions := machineContext.KubevirtMachine.GetConditions()
-				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
-				Expect(conditions[0].Reason).To(Equal(clusterv1.WaitingForControlPlaneAvailableReason))
+				Expect(res.RequeueAfter).To(Equal(time.Second * requeueDurationSeconds))
 			})
-			It("adds a failed VMProvisionedCondition with reason WaitingForBootstrapDataReason when bootstrap data is not yet available", func() {
-				machine.Spec.Bootstrap.DataSecretName = nil
-				delete(machine.ObjectMeta.Labels, clusterv1.MachineControlPlaneNameLabel)
-				conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
 
-				objects := []client.Object{
-					cluster,
-					kubevirtCluster,
-					machine,
-					kubevirtMachine,
-					sshKeySecret,
-					bootstrapSecret,
-					bootstrapUserDataSecret,
+			It("should requeue on node draining error + requeue duration", func() {
+				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
+					Type:   kubevirtv1.VirtualMachineInstanceReady,
+					Status: corev1.ConditionTrue,
 				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
 
-				setupClient(kubevirt.DefaultMachineFactory{}, objects)
-
-				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
-
-				Expect(err).ShouldNot(HaveOccurred())
+					{
+						IP: "1.1.1.1",
+					},
+				}
+				sshKeySecret.Data["pub"] = []byte("shell")
 
-				conditions := machineContext.KubevirtMachine.GetConditions()
-				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
-				Expect(conditions[0].Reason).To(Equal(infrav1.WaitingForBootstrapDataReason))
-			})
-			It("adds a failed VMProvisionedCondition with reason WaitingForBootstrapDataReason when failng to get bootstrap data secret", func() {
 				objects := []client.Object{
 					cluster,
 					kubevirtCluster,
 					machine,
 					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
 					sshKeySecret,
+					vm,
+					vmi,
 				}
 
-				setupClient(kubevirt.DefaultMachineFactory{}, objects)
+				const requeueDurationSeconds = 3
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().IsReady().Return(true).Times(1)
+				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().EvacuationRequested().Return(false).AnyTimes()
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Second*requeueDurationSeconds, fmt.Errorf("mock error")).Times(1)
+
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				setupClient(machineFactoryMock, objects)
 
 				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
 
-				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				res, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
 				Expect(err).Should(HaveOccurred())
+				Expect(errors.Unwrap(err).Error()).Should(ContainSubstring("failed to drain node: mock error"))
 
-				conditions := machineContext.KubevirtMachine.GetConditions()
-				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
-				Expect(conditions[0].Reason).To(Equal(infrav1.WaitingForBootstrapDataReason))
+				Expect(res.RequeueAfter).To(Equal(time.Second * requeueDurationSeconds))
 			})
 
-			It("adds a failed VMProvisionedCondition with reason VMCreateFailed when failng to create VM", func() {
+			It("should delete the owner Machine of a preempted VM instead of failing the machine", func() {
+				vmi.Status.Phase = kubevirtv1.Failed
+				kubevirtMachine.Spec.Preemptible = &infrav1.PreemptibleSpec{PriorityClassName: "preemptible"}
+
 				objects := []client.Object{
 					cluster,
 					kubevirtCluster,
 					machine,
 					kubevirtMachine,
-					sshKeySecret,
 					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
+					vmi,
 				}
 
-				injectErr := interceptor.Funcs{
-					Create: func(ctx gocontext.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
-
-						_, ok := obj.(*kubevirtv1.VirtualMachine)
-						if ok {
-							return errors.New("vm create error")
-						}
-						return nil
-					},
-				}
+				machineMock.EXPECT().Preempted().Return(true).Times(1)
+				machineMock.EXPECT().IsTerminal().Times(0)
+				machineMock.EXPECT().Create(gomock.Any()).Times(0)
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
-				setupClientWithInterceptors(kubevirt.DefaultMachineFactory{}, objects, injectErr)
+				setupClient(machineFactoryMock, objects)
 
 				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
 
-				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
-
-				Expect(err).Should(HaveOccurred())
+				res, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(res).To(Equal(ctrl.Result{}))
 
-				// should expect condition
-				conditions := machineContext.KubevirtMachine.GetConditions()
-				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
-				Expect(conditions[0].Status).To(Equal(corev1.ConditionFalse))
-				Expect(conditions[0].Reason).To(Equal(infrav1.VMCreateFailedReason))
+				err = fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), &clusterv1.Machine{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				Expect(machineContext.KubevirtMachine.Status.FailureReason).To(BeNil())
+				Expect(conditions.GetReason(machineContext.KubevirtMachine, infrav1.VMHealthyCondition)).To(Equal(infrav1.VMPreemptedReason))
 			})
 
-			It("adds a succeeded VMProvisionedCondition", func() {
+			It("should delete the owner Machine when the VM is evacuated and the evacuation policy is DeleteMachine", func() {
 				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
 					Type:   kubevirtv1.VirtualMachineInstanceReady,
 					Status: corev1.ConditionTrue,
 				}
 				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
+				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+
+					{
+						IP: "1.1.1.1",
+					},
+				}
+				sshKeySecret.Data["pub"] = []byte("shell")
+				kubevirtMachine.Spec.EvacuationPolicy = infrav1.DeleteMachineEvacuationPolicy
+
 				objects := []client.Object{
 					cluster,
 					kubevirtCluster,
@@ -941,28 +2358,42 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 					vmi,
 				}
 
-				setupClient(machineFactoryMock, objects)
-
-				machineMock.EXPECT().IsReady().Return(true).Times(2)
-				machineMock.EXPECT().IsBootstrapped().Return(true).AnyTimes()
-				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).Times(1)
 				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
 				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().IsReady().Return(true).Times(1)
 				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
-				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(false).Times(1)
-				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any()).Return(time.Duration(0), nil)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().EvacuationRequested().Return(true).Times(1)
+				machineMock.EXPECT().EvacuationPolicy().Return(infrav1.DeleteMachineEvacuationPolicy).Times(1)
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Times(0)
+
 				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
+				setupClient(machineFactoryMock, objects)
+
 				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
 
-				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				res, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
 				Expect(err).ShouldNot(HaveOccurred())
+				Expect(res).To(Equal(ctrl.Result{}))
 
-				conditions := machineContext.KubevirtMachine.GetConditions()
-				Expect(conditions[0].Type).To(Equal(infrav1.VMProvisionedCondition))
-				Expect(conditions[0].Status).To(Equal(corev1.ConditionTrue))
+				err = fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), &clusterv1.Machine{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
 			})
-			It("adds a failed BootstrapExecSucceededCondition with reason BootstrapFailedReason when bootstraping is possible and failed", func() {
+
+			It("should not delete the owner Machine when the drain of the cluster is a dry run", func() {
 				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
 					Type:   kubevirtv1.VirtualMachineInstanceReady,
 					Status: corev1.ConditionTrue,
@@ -975,6 +2406,7 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 					},
 				}
 				sshKeySecret.Data["pub"] = []byte("shell")
+				kubevirtCluster.Annotations = map[string]string{infrav1.DrainDryRunAnnotation: "true"}
 
 				objects := []client.Object{
 					cluster,
@@ -989,14 +2421,27 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 				}
 
 				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
 				machineMock.EXPECT().Exists().Return(true).Times(1)
-				machineMock.EXPECT().Create(nil).Return(nil).AnyTimes()
-				machineMock.EXPECT().IsReady().Return(true).Times(1)
+				machineMock.EXPECT().IsReady().Return(true).Times(2)
 				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
-				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).AnyTimes()
-				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(true)
-				machineMock.EXPECT().IsBootstrapped().Return(false)
-				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any()).Return(time.Duration(0), nil)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().EvacuationRequested().Return(true).Times(1)
+				machineMock.EXPECT().EvacuationPolicy().Return(infrav1.DeleteMachineEvacuationPolicy).Times(1)
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(1)
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(false).Times(1)
+				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).Times(1)
 
 				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
@@ -1007,13 +2452,10 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				conditions := machineContext.KubevirtMachine.GetConditions()
-
-				Expect(conditions[0].Type).To(Equal(infrav1.BootstrapExecSucceededCondition))
-				Expect(conditions[0].Reason).To(Equal(infrav1.BootstrapFailedReason))
+				Expect(fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), &clusterv1.Machine{})).To(Succeed())
 			})
 
-			It("adds a succeeded BootstrapExecSucceededCondition", func() {
+			It("should annotate the owner Machine for deletion when the VM is evacuated and the evacuation policy is AnnotateMachine", func() {
 				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
 					Type:   kubevirtv1.VirtualMachineInstanceReady,
 					Status: corev1.ConditionTrue,
@@ -1040,13 +2482,27 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 				}
 
 				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
 				machineMock.EXPECT().Exists().Return(true).Times(1)
 				machineMock.EXPECT().IsReady().Return(true).Times(2)
 				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
+				machineMock.EXPECT().UpdateMigrationStatus().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileHotplugVolumes().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileCPUHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMemoryHotplug().Return(nil).AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().Adopt().AnyTimes()
+				machineMock.EXPECT().InsufficientCapacity(gomock.Any()).Return("").AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").AnyTimes()
+				machineMock.EXPECT().UpdateMACAddresses().AnyTimes()
+				machineMock.EXPECT().AdditionalAddresses().Return(nil).AnyTimes()
+				machineMock.EXPECT().EvacuationRequested().Return(true).Times(1)
+				machineMock.EXPECT().EvacuationPolicy().Return(infrav1.AnnotateMachineEvacuationPolicy).Times(1)
+				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(1)
+				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(false).Times(1)
 				machineMock.EXPECT().GenerateProviderID().Return("abc", nil).Times(1)
-				machineMock.EXPECT().SupportsCheckingIsBootstrapped().Return(true)
-				machineMock.EXPECT().IsBootstrapped().Return(true)
-				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any()).Return(time.Duration(0), nil)
 
 				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
@@ -1057,26 +2513,12 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				conditions := machineContext.KubevirtMachine.GetConditions()
-
-				Expect(conditions[0].Type).To(Equal(infrav1.BootstrapExecSucceededCondition))
-				Expect(conditions[0].Status).To(Equal(corev1.ConditionTrue))
+				ownerMachine := &clusterv1.Machine{}
+				Expect(fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), ownerMachine)).To(Succeed())
+				Expect(ownerMachine.Annotations).To(HaveKey(clusterv1.DeleteMachineAnnotation))
 			})
 
-			It("should requeue on node draining", func() {
-				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
-					Type:   kubevirtv1.VirtualMachineInstanceReady,
-					Status: corev1.ConditionTrue,
-				}
-				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
-				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
-
-					{
-						IP: "1.1.1.1",
-					},
-				}
-				sshKeySecret.Data["pub"] = []byte("shell")
-
+			It("should annotate the owner Machine for deletion when the VM is degraded", func() {
 				objects := []client.Object{
 					cluster,
 					kubevirtCluster,
@@ -1086,15 +2528,17 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 					bootstrapUserDataSecret,
 					sshKeySecret,
 					vm,
-					vmi,
 				}
 
-				const requeueDurationSeconds = 3
 				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
 				machineMock.EXPECT().Exists().Return(true).Times(1)
-				machineMock.EXPECT().IsReady().Return(true).Times(1)
-				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
-				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any()).Return(time.Second*requeueDurationSeconds, nil).Times(1)
+				machineMock.EXPECT().IsReady().Return(false).AnyTimes()
+				machineMock.EXPECT().UnschedulableMessage().Return("").AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("the VM is in the CrashLoopBackOff status").Times(1)
 
 				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
@@ -1102,26 +2546,58 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 
 				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
 
-				res, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				Expect(res.RequeueAfter).To(Equal(time.Second * requeueDurationSeconds))
+				ownerMachine := &clusterv1.Machine{}
+				Expect(fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), ownerMachine)).To(Succeed())
+				Expect(ownerMachine.Annotations).To(HaveKey(clusterv1.DeleteMachineAnnotation))
+				Expect(ownerMachine.Annotations).To(HaveKeyWithValue(infrav1.DegradedMachineAnnotation, "the VM is in the CrashLoopBackOff status"))
 			})
 
-			It("should requeue on node draining error + requeue duration", func() {
-				vmiReadyCondition := kubevirtv1.VirtualMachineInstanceCondition{
-					Type:   kubevirtv1.VirtualMachineInstanceReady,
-					Status: corev1.ConditionTrue,
+			It("should remove the deletion annotation of the owner Machine once the degraded VM recovered", func() {
+				machine.Annotations = map[string]string{
+					clusterv1.DeleteMachineAnnotation: "",
+					infrav1.DegradedMachineAnnotation: "the VM is in the CrashLoopBackOff status",
 				}
-				vmi.Status.Conditions = append(vmi.Status.Conditions, vmiReadyCondition)
-				vmi.Status.Interfaces = []kubevirtv1.VirtualMachineInstanceNetworkInterface{
-
-					{
-						IP: "1.1.1.1",
-					},
+				objects := []client.Object{
+					cluster,
+					kubevirtCluster,
+					machine,
+					kubevirtMachine,
+					bootstrapSecret,
+					bootstrapUserDataSecret,
+					sshKeySecret,
+					vm,
 				}
-				sshKeySecret.Data["pub"] = []byte("shell")
 
+				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
+				machineMock.EXPECT().Exists().Return(true).Times(1)
+				machineMock.EXPECT().IsReady().Return(false).AnyTimes()
+				machineMock.EXPECT().UnschedulableMessage().Return("").AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").Times(1)
+
+				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
+
+				setupClient(machineFactoryMock, objects)
+
+				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
+
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				ownerMachine := &clusterv1.Machine{}
+				Expect(fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), ownerMachine)).To(Succeed())
+				Expect(ownerMachine.Annotations).ToNot(HaveKey(clusterv1.DeleteMachineAnnotation))
+				Expect(ownerMachine.Annotations).ToNot(HaveKey(infrav1.DegradedMachineAnnotation))
+			})
+
+			It("should keep a deletion annotation of the owner Machine not set for a degraded VM", func() {
+				machine.Annotations = map[string]string{clusterv1.DeleteMachineAnnotation: ""}
 				objects := []client.Object{
 					cluster,
 					kubevirtCluster,
@@ -1131,15 +2607,17 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 					bootstrapUserDataSecret,
 					sshKeySecret,
 					vm,
-					vmi,
 				}
 
-				const requeueDurationSeconds = 3
 				machineMock.EXPECT().IsTerminal().Return(false, "", nil).Times(1)
+				machineMock.EXPECT().PinInstancetypeRevisions().AnyTimes()
 				machineMock.EXPECT().Exists().Return(true).Times(1)
-				machineMock.EXPECT().IsReady().Return(true).Times(1)
-				machineMock.EXPECT().Address().Return("1.1.1.1").Times(1)
-				machineMock.EXPECT().DrainNodeIfNeeded(gomock.Any()).Return(time.Second*requeueDurationSeconds, fmt.Errorf("mock error")).Times(1)
+				machineMock.EXPECT().IsReady().Return(false).AnyTimes()
+				machineMock.EXPECT().UnschedulableMessage().Return("").AnyTimes()
+				machineMock.EXPECT().ReconcileMutableFields().Return(nil).AnyTimes()
+				machineMock.EXPECT().UpdateSpecOutOfDateCondition(gomock.Any()).AnyTimes()
+				machineMock.EXPECT().UpdateHealthCondition().AnyTimes()
+				machineMock.EXPECT().DegradedReason().Return("").Times(1)
 
 				machineFactoryMock.EXPECT().NewMachine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(machineMock, nil).Times(1)
 
@@ -1147,11 +2625,12 @@ var _ = Describe("reconcile a kubevirt machine", func() {
 
 				infraClusterMock.EXPECT().GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, machineContext.Context).Return(fakeClient, kubevirtMachine.Namespace, nil)
 
-				res, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
-				Expect(err).Should(HaveOccurred())
-				Expect(errors.Unwrap(err).Error()).Should(ContainSubstring("failed to drain node: mock error"))
+				_, err := kubevirtMachineReconciler.reconcileNormal(machineContext)
+				Expect(err).ShouldNot(HaveOccurred())
 
-				Expect(res.RequeueAfter).To(Equal(time.Second * requeueDurationSeconds))
+				ownerMachine := &clusterv1.Machine{}
+				Expect(fakeClient.Get(machineContext, client.ObjectKeyFromObject(machine), ownerMachine)).To(Succeed())
+				Expect(ownerMachine.Annotations).To(HaveKey(clusterv1.DeleteMachineAnnotation))
 			})
 		})
 	})
@@ -1301,6 +2780,58 @@ var _ = Describe("updateNodeProviderID", func() {
 		Expect(kubevirtMachine.Status.NodeUpdated).To(BeTrue())
 	})
 
+	It("should apply the node labels, annotations and taints of the machine to the Node", func() {
+		kubevirtMachine.Spec.ProviderID = &expectedProviderId
+		kubevirtMachine.Spec.NodeLabels = map[string]string{"node-role.kubernetes.io/worker": ""}
+		kubevirtMachine.Spec.NodeAnnotations = map[string]string{"example.com/owner": "team-a"}
+		kubevirtMachine.Spec.NodeTaints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+		kubevirtMachine.Spec.NodeTopologyFromInfraNode = true
+		kubevirtMachine.Status.InfraNodeTopology = map[string]string{corev1.LabelTopologyZone: "zone-a"}
+		machineContext := &context.MachineContext{KubevirtMachine: kubevirtMachine, Logger: testLogger}
+		workloadClusterMock.EXPECT().GenerateWorkloadClusterClient(machineContext).Return(fakeWorkloadClusterClient, nil)
+		out, err := kubevirtMachineReconciler.updateNodeProviderID(machineContext)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).To(Equal(ctrl.Result{}))
+		workloadClusterNode := &corev1.Node{}
+		workloadClusterNodeKey := client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}
+		Expect(
+			fakeWorkloadClusterClient.Get(machineContext, workloadClusterNodeKey, workloadClusterNode),
+		).To(Succeed())
+		Expect(workloadClusterNode.Spec.ProviderID).To(Equal(expectedProviderId))
+		Expect(workloadClusterNode.Labels).To(HaveKeyWithValue("node-role.kubernetes.io/worker", ""))
+		Expect(workloadClusterNode.Labels).To(HaveKeyWithValue(corev1.LabelTopologyZone, "zone-a"))
+		Expect(workloadClusterNode.Annotations).To(HaveKeyWithValue("example.com/owner", "team-a"))
+		Expect(workloadClusterNode.Spec.Taints).To(ConsistOf(kubevirtMachine.Spec.NodeTaints))
+		Expect(kubevirtMachine.Status.NodeUpdated).To(BeTrue())
+	})
+
+	It("should reapply the node metadata of the machine once the providerID is set", func() {
+		kubevirtMachine.Spec.ProviderID = &expectedProviderId
+		kubevirtMachine.Spec.NodeLabels = map[string]string{"node-role.kubernetes.io/worker": ""}
+		kubevirtMachine.Status.NodeUpdated = true
+		machineContext := &context.MachineContext{KubevirtMachine: kubevirtMachine, Logger: testLogger}
+		workloadClusterMock.EXPECT().GenerateWorkloadClusterClient(machineContext).Return(fakeWorkloadClusterClient, nil)
+		out, err := kubevirtMachineReconciler.updateNodeProviderID(machineContext)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).To(Equal(ctrl.Result{}))
+		workloadClusterNode := &corev1.Node{}
+		workloadClusterNodeKey := client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}
+		Expect(
+			fakeWorkloadClusterClient.Get(machineContext, workloadClusterNodeKey, workloadClusterNode),
+		).To(Succeed())
+		Expect(workloadClusterNode.Labels).To(HaveKeyWithValue("node-role.kubernetes.io/worker", ""))
+		Expect(kubevirtMachine.Status.NodeUpdated).To(BeTrue())
+	})
+
+	It("should not get the Node once the providerID is set and the machine has no node metadata", func() {
+		kubevirtMachine.Spec.ProviderID = &expectedProviderId
+		kubevirtMachine.Status.NodeUpdated = true
+		machineContext := &context.MachineContext{KubevirtMachine: kubevirtMachine, Logger: testLogger}
+		out, err := kubevirtMachineReconciler.updateNodeProviderID(machineContext)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).To(Equal(ctrl.Result{}))
+	})
+
 	It("GenerateWorkloadClusterClient failure", func() {
 		kubevirtMachine.Spec.ProviderID = &expectedProviderId
 		machineContext := &context.MachineContext{KubevirtMachine: kubevirtMachine, Logger: testLogger}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	gocontext "context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/kubevirt"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// capacityAnnotationPrefix is the prefix of the annotations overriding the node capacity for the cluster
+	// autoscaler, e.g. "capacity.cluster-autoscaler.kubernetes.io/memory".
+	capacityAnnotationPrefix = "capacity.cluster-autoscaler.kubernetes.io/"
+
+	// gpuTypeCapacityAnnotation is the annotation with the resource name of the GPUs counted by the gpu-count
+	// capacity annotation.
+	gpuTypeCapacityAnnotation = capacityAnnotationPrefix + "gpu-type"
+)
+
+// capacityAnnotations are the capacity annotations of the cluster autoscaler, by the resource they set; the GPUs are
+// set by the gpu-count annotation, with the resource of the gpu-type annotation.
+var capacityAnnotations = map[string]corev1.ResourceName{
+	capacityAnnotationPrefix + "cpu":            corev1.ResourceCPU,
+	capacityAnnotationPrefix + "memory":         corev1.ResourceMemory,
+	capacityAnnotationPrefix + "ephemeral-disk": corev1.ResourceEphemeralStorage,
+	capacityAnnotationPrefix + "maxPods":        corev1.ResourcePods,
+}
+
+// KubevirtMachineTemplateReconciler reports the capacity of the nodes of the KubevirtMachineTemplates, for the cluster
+// autoscaler to scale their node groups from zero.
+type KubevirtMachineTemplateReconciler struct {
+	client.Client
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtmachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtmachinetemplates/status,verbs=get;update;patch
+
+// Reconcile sets the capacity of the nodes of the KubevirtMachineTemplate in its status.
+func (r *KubevirtMachineTemplateReconciler) Reconcile(goctx gocontext.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(goctx)
+
+	// Fetch the KubevirtMachineTemplate instance.
+	template := &infrav1.KubevirtMachineTemplate{}
+	if err := r.Client.Get(goctx, req.NamespacedName, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !template.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	capacity := templateCapacity(template)
+	if equality.Semantic.DeepEqual(capacity, template.Status.Capacity) {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(template, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	template.Status.Capacity = capacity
+	if err := patchHelper.Patch(goctx, template); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Updated the node capacity of the KubevirtMachineTemplate", "capacity", capacity)
+
+	return ctrl.Result{}, nil
+}
+
+// templateCapacity returns the capacity of the nodes of the KubevirtMachineTemplate, rendered from its VM template and
+// overridden by its capacity annotations; the annotations with invalid quantities are ignored.
+func templateCapacity(template *infrav1.KubevirtMachineTemplate) corev1.ResourceList {
+	capacity := kubevirt.NodeCapacity(&template.Spec.Template.Spec)
+
+	for annotation, name := range capacityAnnotations {
+		if value, found := template.Annotations[annotation]; found {
+			if quantity, err := resource.ParseQuantity(value); err == nil {
+				capacity[name] = quantity
+			}
+		}
+	}
+
+	if value, found := template.Annotations[capacityAnnotationPrefix+"gpu-count"]; found {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			gpuType := kubevirt.GPUResourceName
+			if value := template.Annotations[gpuTypeCapacityAnnotation]; value != "" {
+				delete(capacity, gpuType)
+				gpuType = corev1.ResourceName(value)
+			}
+			capacity[gpuType] = quantity
+		}
+	}
+
+	if len(capacity) == 0 {
+		return nil
+	}
+	return capacity
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *KubevirtMachineTemplateReconciler) SetupWithManager(goctx gocontext.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.KubevirtMachineTemplate{}).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(goctx))).
+		Complete(r)
+}
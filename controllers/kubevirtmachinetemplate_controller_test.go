@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	gocontext "context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/testing"
+)
+
+var _ = Describe("reconcile a kubevirt machine template", func() {
+	var (
+		template   *infrav1.KubevirtMachineTemplate
+		reconciler KubevirtMachineTemplateReconciler
+	)
+
+	BeforeEach(func() {
+		guest := resource.MustParse("4Gi")
+		template = &infrav1.KubevirtMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"},
+			Spec: infrav1.KubevirtMachineTemplateSpec{
+				Template: infrav1.KubevirtMachineTemplateResource{
+					Spec: infrav1.KubevirtMachineSpec{
+						VirtualMachineTemplate: infrav1.VirtualMachineTemplateSpec{
+							Spec: kubevirtv1.VirtualMachineSpec{
+								Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+									Spec: kubevirtv1.VirtualMachineInstanceSpec{
+										Domain: kubevirtv1.DomainSpec{
+											CPU:    &kubevirtv1.CPU{Cores: 2, Sockets: 2},
+											Memory: &kubevirtv1.Memory{Guest: &guest},
+											Devices: kubevirtv1.Devices{
+												GPUs: []kubevirtv1.GPU{{Name: "gpu1", DeviceName: "nvidia.com/TU104GL_Tesla_T4"}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	reconcile := func() *infrav1.KubevirtMachineTemplate {
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(template).WithStatusSubresource(template).Build()
+		reconciler = KubevirtMachineTemplateReconciler{Client: fakeClient}
+
+		result, err := reconciler.Reconcile(gocontext.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(template)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		updated := &infrav1.KubevirtMachineTemplate{}
+		Expect(fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(template), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should report the capacity of the VM in the status", func() {
+		capacity := reconcile().Status.Capacity
+
+		Expect(capacity.Cpu().Value()).To(Equal(int64(4)))
+		Expect(capacity.Memory().String()).To(Equal("4Gi"))
+		Expect(capacity).To(HaveKeyWithValue(corev1.ResourceName("nvidia.com/gpu"), resource.MustParse("1")))
+	})
+
+	It("should report the guest memory of the memory spec of the machine", func() {
+		guest := resource.MustParse("8Gi")
+		template.Spec.Template.Spec.Memory = &infrav1.MemorySpec{Guest: &guest}
+
+		Expect(reconcile().Status.Capacity.Memory().String()).To(Equal("8Gi"))
+	})
+
+	It("should only report the capacity annotations with an instancetype", func() {
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Instancetype = &kubevirtv1.InstancetypeMatcher{Name: "u1.large"}
+		template.Annotations = map[string]string{
+			"capacity.cluster-autoscaler.kubernetes.io/cpu":    "2",
+			"capacity.cluster-autoscaler.kubernetes.io/memory": "8G",
+		}
+
+		capacity := reconcile().Status.Capacity
+		Expect(capacity.Cpu().String()).To(Equal("2"))
+		Expect(capacity.Memory().String()).To(Equal("8G"))
+	})
+
+	It("should let the capacity annotations override the capacity of the VM", func() {
+		template.Annotations = map[string]string{
+			"capacity.cluster-autoscaler.kubernetes.io/memory":    "invalid",
+			"capacity.cluster-autoscaler.kubernetes.io/maxPods":   "200",
+			"capacity.cluster-autoscaler.kubernetes.io/gpu-count": "2",
+			"capacity.cluster-autoscaler.kubernetes.io/gpu-type":  "amd.com/gpu",
+		}
+
+		capacity := reconcile().Status.Capacity
+		Expect(capacity.Memory().String()).To(Equal("4Gi"))
+		Expect(capacity.Pods().Value()).To(Equal(int64(200)))
+		Expect(capacity).To(HaveKeyWithValue(corev1.ResourceName("amd.com/gpu"), resource.MustParse("2")))
+		Expect(capacity).NotTo(HaveKey(corev1.ResourceName("nvidia.com/gpu")))
+	})
+})
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	gocontext "context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+const (
+	// ProviderIDRepairedReason is the reason of the event recorded when the provider ID of a tenant cluster node is
+	// repaired.
+	ProviderIDRepairedReason = "ProviderIDRepaired"
+
+	// ProviderIDMismatchReason is the reason of the event recorded when a tenant cluster node registered with the
+	// provider ID of another machine, which can't be changed once set.
+	ProviderIDMismatchReason = "ProviderIDMismatch"
+)
+
+// KubevirtNodeReconciler repairs the provider ID of the tenant cluster nodes of the KubevirtMachines, e.g. when a node
+// re-joined the tenant cluster after its Node was deleted, so that their Machines get their node reference.
+type KubevirtNodeReconciler struct {
+	client.Client
+	WorkloadCluster workloadcluster.WorkloadCluster
+	Tracker         *remote.ClusterCacheTracker
+	Recorder        record.EventRecorder
+
+	controller controller.Controller
+}
+
+// Reconcile sets the provider ID of the KubevirtMachine on its tenant cluster node, when the node has none.
+func (r *KubevirtNodeReconciler) Reconcile(goctx gocontext.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(goctx)
+
+	// Fetch the KubevirtMachine instance.
+	kubevirtMachine := &infrav1.KubevirtMachine{}
+	if err := r.Client.Get(goctx, req.NamespacedName, kubevirtMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The node of a new machine is updated by the KubevirtMachine controller.
+	if !kubevirtMachine.DeletionTimestamp.IsZero() || kubevirtMachine.Spec.ProviderID == nil || !kubevirtMachine.Status.NodeUpdated {
+		return ctrl.Result{}, nil
+	}
+
+	machine, err := util.GetOwnerMachine(goctx, r.Client, kubevirtMachine.ObjectMeta)
+	if err != nil || machine == nil {
+		return ctrl.Result{}, err
+	}
+
+	cluster, err := util.GetClusterFromMetadata(goctx, r.Client, machine.ObjectMeta)
+	if err != nil || cluster == nil {
+		return ctrl.Result{}, err
+	}
+	if annotations.IsPaused(cluster, kubevirtMachine) {
+		return ctrl.Result{}, nil
+	}
+
+	kubevirtCluster := &infrav1.KubevirtCluster{}
+	kubevirtClusterName := client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := r.Client.Get(goctx, kubevirtClusterName, kubevirtCluster); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	machineContext := &context.MachineContext{
+		Context:         goctx,
+		Cluster:         cluster,
+		KubevirtCluster: kubevirtCluster,
+		Machine:         machine,
+		KubevirtMachine: kubevirtMachine,
+		Logger:          log,
+	}
+
+	if err := r.watchWorkloadClusterNodes(goctx, cluster.Namespace, client.ObjectKeyFromObject(cluster)); err != nil {
+		log.Info("Waiting for the workload cluster to watch its nodes", "error", err.Error())
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	workloadClusterClient, err := r.WorkloadCluster.GenerateWorkloadClusterClient(machineContext)
+	if err != nil || workloadClusterClient == nil {
+		log.Info("Waiting for workload cluster client...")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	node := &corev1.Node{}
+	if err := workloadClusterClient.Get(goctx, client.ObjectKey{Name: kubevirtMachine.Name}, node); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	providerID := *kubevirtMachine.Spec.ProviderID
+	switch node.Spec.ProviderID {
+	case providerID:
+		return ctrl.Result{}, nil
+	case "":
+		patchHelper := client.MergeFrom(node.DeepCopy())
+		node.Spec.ProviderID = providerID
+		if err := workloadClusterClient.Patch(goctx, node, patchHelper); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to patch the provider ID of the workload cluster node")
+		}
+		log.Info("Repaired the provider ID of the workload cluster node", "node", node.Name, "providerID", providerID)
+		r.Recorder.Eventf(kubevirtMachine, corev1.EventTypeNormal, ProviderIDRepairedReason,
+			"Set the provider ID %s on the workload cluster node %s", providerID, node.Name)
+	default:
+		// the provider ID of a node can't be changed once set; the node must be deleted and re-registered.
+		r.Recorder.Eventf(kubevirtMachine, corev1.EventTypeWarning, ProviderIDMismatchReason,
+			"The workload cluster node %s has the provider ID %s instead of %s; delete the node for it to re-register",
+			node.Name, node.Spec.ProviderID, providerID)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// watchWorkloadClusterNodes watches the nodes of the workload cluster, for their KubevirtMachines to be reconciled when
+// they re-register.
+func (r *KubevirtNodeReconciler) watchWorkloadClusterNodes(goctx gocontext.Context, namespace string, cluster client.ObjectKey) error {
+	if r.Tracker == nil || r.controller == nil {
+		return nil
+	}
+
+	return r.Tracker.Watch(goctx, remote.WatchInput{
+		Name:         "kubevirtnode-watchNodes",
+		Cluster:      cluster,
+		Watcher:      r.controller,
+		Kind:         &corev1.Node{},
+		EventHandler: handler.EnqueueRequestsFromMapFunc(r.nodeToKubevirtMachine(namespace)),
+	})
+}
+
+// nodeToKubevirtMachine returns a handler.MapFunc enqueuing the KubevirtMachine named after the workload cluster node,
+// in the namespace of the cluster.
+func (r *KubevirtNodeReconciler) nodeToKubevirtMachine(namespace string) handler.MapFunc {
+	return func(_ gocontext.Context, o client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: namespace, Name: o.GetName()}}}
+	}
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *KubevirtNodeReconciler) SetupWithManager(goctx gocontext.Context, mgr ctrl.Manager, options controller.Options) error {
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		Named("kubevirtnode").
+		For(&infrav1.KubevirtMachine{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(goctx))).
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.controller = c
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	gocontext "context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/testing"
+	workloadclustermock "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster/mock"
+)
+
+var _ = Describe("reconcile the tenant cluster node of a kubevirt machine", func() {
+	const providerID = "kubevirt://test-kubevirt-machine"
+
+	var (
+		workloadClusterMock   *workloadclustermock.MockWorkloadCluster
+		workloadClusterClient client.Client
+		nodeMachine           *infrav1.KubevirtMachine
+		node                  *corev1.Node
+		recorder              *record.FakeRecorder
+		reconciler            KubevirtNodeReconciler
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		workloadClusterMock = workloadclustermock.NewMockWorkloadCluster(mockCtrl)
+		recorder = record.NewFakeRecorder(10)
+
+		nodeMachine = testing.NewKubevirtMachine("test-kubevirt-machine", "test-machine")
+		nodeMachine.Spec.ProviderID = pointer.String(providerID)
+		nodeMachine.Status.NodeUpdated = true
+		node = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeMachine.Name}}
+	})
+
+	setupReconciler := func() {
+		nodeCluster := testing.NewKubevirtCluster("kvcluster", "kvcluster")
+		objects := []client.Object{
+			testing.NewCluster("kvcluster", nodeCluster),
+			nodeCluster,
+			testing.NewMachine("kvcluster", "test-machine", nodeMachine),
+			nodeMachine,
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(objects...).Build()
+		workloadClusterClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(node).Build()
+		reconciler = KubevirtNodeReconciler{
+			Client:          fakeClient,
+			WorkloadCluster: workloadClusterMock,
+			Recorder:        recorder,
+		}
+	}
+
+	reconcile := func() {
+		result, err := reconciler.Reconcile(gocontext.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(nodeMachine)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+	}
+
+	getNode := func() *corev1.Node {
+		updated := &corev1.Node{}
+		Expect(workloadClusterClient.Get(gocontext.Background(), client.ObjectKeyFromObject(node), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should set the provider ID on a node that re-registered without one", func() {
+		setupReconciler()
+		workloadClusterMock.EXPECT().GenerateWorkloadClusterClient(gomock.Any()).Return(workloadClusterClient, nil)
+
+		reconcile()
+
+		Expect(getNode().Spec.ProviderID).To(Equal(providerID))
+		Expect(recorder.Events).To(Receive(ContainSubstring(ProviderIDRepairedReason)))
+	})
+
+	It("should only record an event for a node with the provider ID of another machine", func() {
+		node.Spec.ProviderID = "kubevirt://other"
+		setupReconciler()
+		workloadClusterMock.EXPECT().GenerateWorkloadClusterClient(gomock.Any()).Return(workloadClusterClient, nil)
+
+		reconcile()
+
+		Expect(getNode().Spec.ProviderID).To(Equal("kubevirt://other"))
+		Expect(recorder.Events).To(Receive(ContainSubstring(ProviderIDMismatchReason)))
+	})
+
+	It("should leave the new machines to the KubevirtMachine controller", func() {
+		nodeMachine.Status.NodeUpdated = false
+		setupReconciler()
+		workloadClusterMock.EXPECT().GenerateWorkloadClusterClient(gomock.Any()).Times(0)
+
+		reconcile()
+
+		Expect(getNode().Spec.ProviderID).To(BeEmpty())
+	})
+})
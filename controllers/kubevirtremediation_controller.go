@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	gocontext "context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/kubevirt"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultRemediationRetryLimit = 1
+	defaultRemediationTimeout    = 5 * time.Minute
+)
+
+// KubevirtRemediationReconciler reconciles a KubevirtRemediation object.
+type KubevirtRemediationReconciler struct {
+	client.Client
+	InfraCluster infracluster.InfraCluster
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtremediations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kubevirtremediations/status,verbs=get;update;patch
+
+// Reconcile remediates the unhealthy Machine of a KubevirtRemediation by restarting its VM, and deletes the Machine
+// if it is still unhealthy after the last restart.
+func (r *KubevirtRemediationReconciler) Reconcile(goctx gocontext.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(goctx)
+
+	// Fetch the KubevirtRemediation instance.
+	remediation := &infrav1.KubevirtRemediation{}
+	if err := r.Client.Get(goctx, req.NamespacedName, remediation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !remediation.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the unhealthy Machine.
+	machine, err := util.GetOwnerMachine(goctx, r.Client, remediation.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machine == nil {
+		log.Info("Waiting for MachineHealthCheck Controller to set OwnerRef on KubevirtRemediation")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("machine", machine.Name)
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(remediation, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always attempt to Patch the KubevirtRemediation object and status after each reconciliation.
+	defer func() {
+		if err := patchHelper.Patch(goctx, remediation); err != nil {
+			log.Error(err, "failed to patch KubevirtRemediation")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	return r.reconcileNormal(goctx, log, remediation, machine)
+}
+
+func (r *KubevirtRemediationReconciler) reconcileNormal(ctx gocontext.Context, log logr.Logger, remediation *infrav1.KubevirtRemediation, machine *clusterv1.Machine) (ctrl.Result, error) {
+	retryLimit, timeout := defaultRemediationRetryLimit, defaultRemediationTimeout
+	if strategy := remediation.Spec.Strategy; strategy != nil {
+		if strategy.RetryLimit > 0 {
+			retryLimit = strategy.RetryLimit
+		}
+		if strategy.Timeout != nil {
+			timeout = strategy.Timeout.Duration
+		}
+	}
+
+	switch remediation.Status.Phase {
+	case "", infrav1.RunningRemediationPhase:
+		remediation.Status.Phase = infrav1.RunningRemediationPhase
+		log.Info("Restarting the VM of the unhealthy machine", "retry", remediation.Status.RetryCount+1)
+		restarted, err := r.restartVM(ctx, machine)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to restart the VM")
+		}
+		if !restarted {
+			log.Info("The VM of the unhealthy machine can't be restarted, as its run strategy is not Always; deleting the machine")
+			remediation.Status.Phase = infrav1.DeletingRemediationPhase
+			return ctrl.Result{}, r.deleteMachine(ctx, machine)
+		}
+		now := metav1.Now()
+		remediation.Status.RetryCount++
+		remediation.Status.LastRemediated = &now
+		remediation.Status.Phase = infrav1.WaitingRemediationPhase
+		return ctrl.Result{RequeueAfter: timeout}, nil
+
+	case infrav1.WaitingRemediationPhase:
+		// the MachineHealthCheck deletes the KubevirtRemediation once the machine is healthy again
+		if remediation.Status.LastRemediated != nil {
+			if remaining := time.Until(remediation.Status.LastRemediated.Add(timeout)); remaining > 0 {
+				return ctrl.Result{RequeueAfter: remaining}, nil
+			}
+		}
+		if remediation.Status.RetryCount < retryLimit {
+			remediation.Status.Phase = infrav1.RunningRemediationPhase
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Info("The machine is still unhealthy after the last restart of its VM; deleting it", "retries", remediation.Status.RetryCount)
+		remediation.Status.Phase = infrav1.DeletingRemediationPhase
+		fallthrough
+
+	case infrav1.DeletingRemediationPhase:
+		return ctrl.Result{}, r.deleteMachine(ctx, machine)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// deleteMachine deletes the unhealthy Machine, for its MachineSet to replace it.
+func (r *KubevirtRemediationReconciler) deleteMachine(ctx gocontext.Context, machine *clusterv1.Machine) error {
+	if !machine.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err := r.Client.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete the unhealthy machine")
+	}
+
+	return nil
+}
+
+// restartVM restarts the VM of the Machine by deleting its VMI, for KubeVirt to start a new one from the VM, keeping
+// the disks of the VM, and returns whether it did. Only the VMs with the Always run strategy are started again by
+// KubeVirt once their VMI is deleted; the other VMs, and the missing ones, are not restarted.
+func (r *KubevirtRemediationReconciler) restartVM(ctx gocontext.Context, machine *clusterv1.Machine) (bool, error) {
+	kubevirtMachine := &infrav1.KubevirtMachine{}
+	key := client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.InfrastructureRef.Name}
+	if err := r.Client.Get(ctx, key, kubevirtMachine); err != nil {
+		return false, err
+	}
+
+	infraClusterClient, infraClusterNamespace, err := r.InfraCluster.GenerateInfraClusterClient(kubevirtMachine.Spec.InfraClusterSecretRef, kubevirtMachine.Namespace, ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to generate infra cluster client")
+	}
+	if infraClusterClient == nil {
+		return false, errors.New("the infra cluster client is not available yet")
+	}
+
+	vmKey := client.ObjectKey{Namespace: GetVMNamespace(kubevirtMachine, infraClusterNamespace), Name: kubevirt.VMName(kubevirtMachine)}
+	vm := &kubevirtv1.VirtualMachine{}
+	if err := infraClusterClient.Get(ctx, vmKey, vm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	runStrategy, err := vm.RunStrategy()
+	if err != nil {
+		return false, err
+	}
+	if runStrategy != kubevirtv1.RunStrategyAlways {
+		return false, nil
+	}
+
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: vmKey.Namespace,
+			Name:      vmKey.Name,
+		},
+	}
+	if err := infraClusterClient.Delete(ctx, vmi); err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *KubevirtRemediationReconciler) SetupWithManager(goctx gocontext.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.KubevirtRemediation{}).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(goctx))).
+		Complete(r)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	gocontext "context"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	infraclustermock "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster/mock"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/testing"
+)
+
+var _ = Describe("reconcile a kubevirt remediation", func() {
+	var (
+		infraClusterMock *infraclustermock.MockInfraCluster
+		remediation      *infrav1.KubevirtRemediation
+		reconciler       KubevirtRemediationReconciler
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		infraClusterMock = infraclustermock.NewMockInfraCluster(mockCtrl)
+
+		kubevirtMachine = testing.NewKubevirtMachine("test-kubevirt-machine", "test-machine")
+		machine = testing.NewMachine("kvcluster", "test-machine", kubevirtMachine)
+		vmi = testing.NewVirtualMachineInstance(kubevirtMachine)
+		vm = testing.NewVirtualMachine(vmi)
+		always := kubevirtv1.RunStrategyAlways
+		vm.Spec.RunStrategy = &always
+
+		remediation = &infrav1.KubevirtRemediation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: machine.Name,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "Machine",
+						Name:       machine.Name,
+					},
+				},
+			},
+		}
+	})
+
+	setupReconciler := func() {
+		objects := []client.Object{machine, kubevirtMachine, vm, vmi, remediation}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(objects...).WithStatusSubresource(remediation).Build()
+		reconciler = KubevirtRemediationReconciler{
+			Client:       fakeClient,
+			InfraCluster: infraClusterMock,
+		}
+	}
+
+	reconcile := func() ctrl.Result {
+		result, err := reconciler.Reconcile(gocontext.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(remediation)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(remediation), remediation)).To(Succeed())
+		return result
+	}
+
+	It("should restart the VM of the unhealthy machine", func() {
+		setupReconciler()
+		infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, vmi.Namespace, nil)
+
+		result := reconcile()
+
+		Expect(result.RequeueAfter).To(Equal(defaultRemediationTimeout))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.WaitingRemediationPhase))
+		Expect(remediation.Status.RetryCount).To(Equal(1))
+		Expect(remediation.Status.LastRemediated).ToNot(BeNil())
+		err := fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(vmi), &kubevirtv1.VirtualMachineInstance{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should delete the machine right away when its VM can't be restarted", func() {
+		rerunOnFailure := kubevirtv1.RunStrategyRerunOnFailure
+		vm.Spec.RunStrategy = &rerunOnFailure
+		setupReconciler()
+		infraClusterMock.EXPECT().GenerateInfraClusterClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeClient, vmi.Namespace, nil)
+
+		reconcile()
+
+		Expect(remediation.Status.Phase).To(Equal(infrav1.DeletingRemediationPhase))
+		Expect(remediation.Status.RetryCount).To(BeZero())
+		Expect(fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(vmi), &kubevirtv1.VirtualMachineInstance{})).To(Succeed())
+		err := fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(machine), &clusterv1.Machine{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should restart the VM again until the retry limit is reached", func() {
+		remediation.Spec.Strategy = &infrav1.RemediationStrategy{RetryLimit: 2}
+		lastRemediated := metav1.NewTime(time.Now().Add(-time.Hour))
+		remediation.Status = infrav1.KubevirtRemediationStatus{
+			Phase:          infrav1.WaitingRemediationPhase,
+			RetryCount:     1,
+			LastRemediated: &lastRemediated,
+		}
+		setupReconciler()
+
+		result := reconcile()
+
+		Expect(result.Requeue).To(BeTrue())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.RunningRemediationPhase))
+	})
+
+	It("should wait for the machine to become healthy after a restart", func() {
+		lastRemediated := metav1.Now()
+		remediation.Status = infrav1.KubevirtRemediationStatus{
+			Phase:          infrav1.WaitingRemediationPhase,
+			RetryCount:     1,
+			LastRemediated: &lastRemediated,
+		}
+		setupReconciler()
+
+		result := reconcile()
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.WaitingRemediationPhase))
+		Expect(fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(machine), &clusterv1.Machine{})).To(Succeed())
+	})
+
+	It("should delete the machine still unhealthy after the last restart", func() {
+		lastRemediated := metav1.NewTime(time.Now().Add(-time.Hour))
+		remediation.Status = infrav1.KubevirtRemediationStatus{
+			Phase:          infrav1.WaitingRemediationPhase,
+			RetryCount:     1,
+			LastRemediated: &lastRemediated,
+		}
+		setupReconciler()
+
+		reconcile()
+
+		Expect(remediation.Status.Phase).To(Equal(infrav1.DeletingRemediationPhase))
+		err := fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(machine), &clusterv1.Machine{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
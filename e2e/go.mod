@@ -11,7 +11,7 @@ require (
 	k8s.io/apimachinery v0.28.3
 	k8s.io/client-go v12.0.0+incompatible
 	k8s.io/utils v0.0.0-20230505201702-9f6742963106
-	kubevirt.io/api v1.0.0
+	kubevirt.io/api v1.1.1
 	kubevirt.io/client-go v0.0.0-00010101000000-000000000000
 	sigs.k8s.io/cluster-api v1.5.2
 	sigs.k8s.io/cluster-api-provider-kubevirt v0.0.0-00010101000000-000000000000
@@ -118,7 +118,7 @@ replace (
 	k8s.io/sample-apiserver => k8s.io/sample-apiserver v0.26.3
 	k8s.io/sample-cli-plugin => k8s.io/sample-cli-plugin v0.26.3
 	k8s.io/sample-controller => k8s.io/sample-controller v0.26.3
-	kubevirt.io/client-go => kubevirt.io/client-go v1.0.0
+	kubevirt.io/client-go => kubevirt.io/client-go v1.1.1
 	sigs.k8s.io/apiserver-network-proxy/konnectivity-client => sigs.k8s.io/apiserver-network-proxy/konnectivity-client v0.0.24
 	sigs.k8s.io/cluster-api => sigs.k8s.io/cluster-api v1.4.0
 	sigs.k8s.io/cluster-api-provider-kubevirt => ../
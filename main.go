@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -35,7 +36,10 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,6 +50,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-kubevirt/controllers"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/infracluster"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/kubevirt"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster"
 	// +kubebuilder:scaffold:imports
 )
@@ -63,6 +68,16 @@ var (
 	webhookPort          int
 	webhookCertDir       string
 	watchNamespace       string
+	drainPodExclusion    string
+	proactiveDrain       bool
+	drainDryRun          bool
+	skipWaitForDelete    time.Duration
+	tenantUnreachable    time.Duration
+	defaultCPUModel      string
+	cpuHotplug           bool
+	memoryHotplug        bool
+	capacityCheck        bool
+	providerIDRepair     bool
 )
 
 func init() {
@@ -72,6 +87,8 @@ func init() {
 	_ = infrav1.AddToScheme(myscheme)
 	_ = clusterv1.AddToScheme(myscheme)
 	_ = kubevirtv1.AddToScheme(myscheme)
+	_ = cdiv1.AddToScheme(myscheme)
+	_ = ipamv1.AddToScheme(myscheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -93,6 +110,32 @@ func initFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
+	fs.StringVar(&drainPodExclusion, "drain-pod-exclusion-selector", "",
+		"Label selector of the tenant cluster pods that are never evicted when a node is drained (e.g. app=storage-daemon).")
+	fs.DurationVar(&skipWaitForDelete, "skip-wait-for-delete-timeout", kubevirt.DefaultSkipWaitForDeleteTimeout,
+		"How long the drain of an unreachable tenant cluster node waits for the deleted pods to be gone, before ignoring them (e.g. 5m).")
+	fs.DurationVar(&tenantUnreachable, "tenant-unreachable-timeout", kubevirt.DefaultTenantUnreachableTimeout,
+		"How long the drain of a tenant cluster node retries while the tenant cluster API server is unreachable, before deleting the VM without draining the node (e.g. 5m).")
+	fs.BoolVar(&proactiveDrain, "proactive-drain", false,
+		"Drain the tenant cluster nodes as soon as the infra cluster nodes running their VMs are cordoned, without waiting for KubeVirt to evacuate the VMs. The VMs KubeVirt live migrates are left to the migration.")
+	fs.BoolVar(&drainDryRun, "drain-dry-run", false,
+		"Only log and record events for what the controller would do when the VMs are evacuated, without cordoning or draining the tenant cluster nodes, nor deleting the VMIs or Machines.")
+
+	fs.StringVar(&defaultCPUModel, "default-cpu-model", "",
+		"CPU model of the VMs whose template sets neither a CPU model nor an instancetype (e.g. host-model, host-passthrough or Skylake-Server). If unspecified, the default CPU model of the infra cluster KubeVirt is used.")
+
+	fs.BoolVar(&cpuHotplug, "enable-cpu-hotplug", false,
+		"Hot-plug the sockets added to the CPU of the KubevirtMachines into their running VMs, when the VMLiveUpdateFeatures feature gate is enabled in the KubeVirt configuration of the infra cluster.")
+
+	fs.BoolVar(&memoryHotplug, "enable-memory-hotplug", false,
+		"Hot-plug the guest memory added to the KubevirtMachines into their running VMs, when the VMLiveUpdateFeatures feature gate is enabled in the KubeVirt configuration of the infra cluster.")
+
+	fs.BoolVar(&capacityCheck, "enable-capacity-check", false,
+		"Wait for a node of the infra cluster to have the CPU, memory and devices requested by a VM free before creating it, instead of creating VMs that can't be scheduled. The check lists the pods of the candidate nodes of the infra cluster, node by node, with a spec.nodeName field selector.")
+
+	fs.BoolVar(&providerIDRepair, "enable-provider-id-repair", false,
+		"Watch the nodes of the tenant clusters, and set the provider ID of their KubevirtMachine on the nodes that re-registered without one, e.g. after their Node was deleted.")
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -165,11 +208,51 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	podExclusionSelector, err := labels.Parse(drainPodExclusion)
+	if err != nil {
+		setupLog.Error(err, "invalid drain-pod-exclusion-selector flag")
+		os.Exit(1)
+	}
+
+	trackerLog := ctrl.Log.WithName("remote").WithName("ClusterCacheTracker")
+	tracker, err := remote.NewClusterCacheTracker(mgr, remote.ClusterCacheTrackerOptions{
+		Log:            &trackerLog,
+		ControllerName: "capk-controller-manager",
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to create cluster cache tracker")
+		os.Exit(1)
+	}
+
+	if err := (&remote.ClusterCacheReconciler{
+		Client:  mgr.GetClient(),
+		Tracker: tracker,
+	}).SetupWithManager(ctx, mgr, controller.Options{
+		MaxConcurrentReconciles: concurrency,
+	}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterCacheReconciler")
+		os.Exit(1)
+	}
+
 	if err := (&controllers.KubevirtMachineReconciler{
 		Client:          mgr.GetClient(),
-		InfraCluster:    infracluster.New(mgr.GetClient(), noCachedClient),
-		WorkloadCluster: workloadcluster.New(mgr.GetClient()),
+		InfraCluster:    infracluster.New(mgr.GetClient(), noCachedClient, mgr.GetConfig()),
+		WorkloadCluster: workloadcluster.NewWithTracker(mgr.GetClient(), tracker),
 		MachineFactory:  kubevirt.DefaultMachineFactory{},
+		CreationLimiter: kubevirt.NewVMCreationLimiter(),
+		DefaultCPUModel: defaultCPUModel,
+		CPUHotplug:      cpuHotplug,
+		MemoryHotplug:   memoryHotplug,
+		CapacityCheck:   capacityCheck,
+		DrainOptions: kubevirt.DrainOptions{
+			Tracker:                  kubevirt.NewNodeDrainTracker(),
+			PodExclusionSelector:     podExclusionSelector,
+			Recorder:                 mgr.GetEventRecorderFor("kubevirtmachine-controller"),
+			ProactiveDrain:           proactiveDrain,
+			DryRun:                   drainDryRun,
+			SkipWaitForDeleteTimeout: skipWaitForDelete,
+			TenantUnreachableTimeout: tenantUnreachable,
+		},
 	}).SetupWithManager(ctx, mgr, controller.Options{
 		MaxConcurrentReconciles: concurrency,
 	}); err != nil {
@@ -177,11 +260,41 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
-	if err := (&controllers.KubevirtClusterReconciler{
+	if providerIDRepair {
+		if err := (&controllers.KubevirtNodeReconciler{
+			Client:          mgr.GetClient(),
+			WorkloadCluster: workloadcluster.NewWithTracker(mgr.GetClient(), tracker),
+			Tracker:         tracker,
+			Recorder:        mgr.GetEventRecorderFor("kubevirtnode-controller"),
+		}).SetupWithManager(ctx, mgr, controller.Options{
+			MaxConcurrentReconciles: concurrency,
+		}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "KubevirtNode")
+			os.Exit(1)
+		}
+	}
+
+	if err := (&controllers.KubevirtRemediationReconciler{
 		Client:       mgr.GetClient(),
-		APIReader:    mgr.GetAPIReader(),
-		InfraCluster: infracluster.New(mgr.GetClient(), noCachedClient),
-		Log:          ctrl.Log.WithName("controllers").WithName("KubevirtCluster"),
+		InfraCluster: infracluster.New(mgr.GetClient(), noCachedClient, mgr.GetConfig()),
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KubevirtRemediation")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.KubevirtMachineTemplateReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KubevirtMachineTemplate")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.KubevirtClusterReconciler{
+		Client:         mgr.GetClient(),
+		APIReader:      mgr.GetAPIReader(),
+		InfraCluster:   infracluster.New(mgr.GetClient(), noCachedClient, mgr.GetConfig()),
+		Log:            ctrl.Log.WithName("controllers").WithName("KubevirtCluster"),
+		EndpointProber: loadbalancer.ProbeEndpoint,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubevirtCluster")
 		os.Exit(1)
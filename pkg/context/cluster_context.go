@@ -59,6 +59,7 @@ func (c *ClusterContext) PatchKubevirtCluster(patchHelper *patch.Helper) error {
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			infrav1.LoadBalancerAvailableCondition,
+			infrav1.ControlPlaneEndpointReachableCondition,
 		}},
 	)
 }
@@ -38,6 +38,12 @@ type MachineContext struct {
 	Machine             *clusterv1.Machine
 	KubevirtCluster     *infrav1.KubevirtCluster
 	KubevirtMachine     *infrav1.KubevirtMachine
+	DrainPolicy         *infrav1.KubevirtDrainPolicy
+	DefaultCPUModel     string
+	CPUHotplug          bool
+	MemoryHotplug       bool
+	CapacityCheck       bool
+	IPAddresses         map[string][]string
 	BootstrapDataSecret *corev1.Secret
 	Logger              logr.Logger
 }
@@ -65,6 +71,7 @@ func (c *MachineContext) PatchKubevirtMachine(patchHelper *patch.Helper) error {
 		conditions.WithConditions(
 			infrav1.VMProvisionedCondition,
 			infrav1.BootstrapExecSucceededCondition,
+			infrav1.VMHealthyCondition,
 		),
 		conditions.WithStepCounterIf(c.KubevirtMachine.ObjectMeta.DeletionTimestamp.IsZero()),
 	)
@@ -77,10 +84,23 @@ func (c *MachineContext) PatchKubevirtMachine(patchHelper *patch.Helper) error {
 			clusterv1.ReadyCondition,
 			infrav1.VMProvisionedCondition,
 			infrav1.BootstrapExecSucceededCondition,
+			infrav1.DrainingSucceededCondition,
+			infrav1.VMMigrationSucceededCondition,
+			infrav1.MemoryHotplugSucceededCondition,
+			infrav1.SpecOutOfDateCondition,
+			infrav1.VMHealthyCondition,
 		}},
 	)
 }
 
+// BootstrapDataFormat returns the format of the bootstrap data delivered to the VM.
+func (c *MachineContext) BootstrapDataFormat() infrav1.BootstrapDataFormat {
+	if c.BootstrapDataSecret != nil && len(c.BootstrapDataSecret.Data["format"]) > 0 {
+		return infrav1.BootstrapDataFormat(c.BootstrapDataSecret.Data["format"])
+	}
+	return infrav1.CloudConfigBootstrapDataFormat
+}
+
 func (c *MachineContext) HasInjectedCapkSSHKeys(sshPublicKey []byte) bool {
 	if c.BootstrapDataSecret == nil || len(sshPublicKey) == 0 {
 		return false
@@ -97,5 +117,5 @@ func (c *MachineContext) HasInjectedCapkSSHKeys(sshPublicKey []byte) bool {
 		return false
 	}
 
-	return strings.Contains(string(value), string(sshPublicKeyDecoded))
+	return strings.Contains(string(value), strings.TrimSpace(string(sshPublicKeyDecoded)))
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -14,14 +15,21 @@ import (
 //go:generate mockgen -source=./infracluster.go -destination=./mock/infracluster_generated.go -package=mock
 type InfraCluster interface {
 	GenerateInfraClusterClient(infraClusterSecretRef *corev1.ObjectReference, ownerNamespace string, context gocontext.Context) (k8sclient.Client, string, error)
+	GenerateInfraClusterK8sClient(infraClusterSecretRef *corev1.ObjectReference, ownerNamespace string, context gocontext.Context) (kubernetes.Interface, error)
 }
 
 // ClientFactoryFunc defines the function to create a new client
 type ClientFactoryFunc func(config *rest.Config, options k8sclient.Options) (k8sclient.Client, error)
 
-// New creates new InfraCluster instance
-func New(client k8sclient.Client, noCachedClient k8sclient.Client) InfraCluster {
-	return NewWithFactory(client, noCachedClient, k8sclient.New)
+// New creates new InfraCluster instance. The REST config of the management cluster is used for the kubernetes
+// clients of the infra cluster, when the infra cluster is the management cluster.
+func New(client k8sclient.Client, noCachedClient k8sclient.Client, restConfig *rest.Config) InfraCluster {
+	return &infraCluster{
+		Client:         client,
+		NoCachedClient: noCachedClient,
+		ClientFactory:  k8sclient.New,
+		RESTConfig:     restConfig,
+	}
 }
 
 // NewWithFactory creates new InfraCluster instance that uses the provided client factory function.
@@ -37,6 +45,7 @@ type infraCluster struct {
 	k8sclient.Client
 	NoCachedClient k8sclient.Client
 	ClientFactory  ClientFactoryFunc
+	RESTConfig     *rest.Config
 }
 
 // GenerateInfraClusterClient creates a client for infra cluster.
@@ -45,6 +54,44 @@ func (w *infraCluster) GenerateInfraClusterClient(infraClusterSecretRef *corev1.
 		return w.NoCachedClient, ownerNamespace, nil
 	}
 
+	restConfig, namespace, err := w.infraClusterRESTConfig(infraClusterSecretRef, ownerNamespace, context)
+	if err != nil {
+		return nil, "", err
+	}
+
+	infraClusterClient, err := w.ClientFactory(restConfig, k8sclient.Options{Scheme: w.Client.Scheme()})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create infra cluster client")
+	}
+
+	return infraClusterClient, namespace, nil
+}
+
+// GenerateInfraClusterK8sClient creates a kubernetes client for infra cluster, e.g. to read the logs of the
+// virt-launcher pods, which the controller-runtime client can't.
+func (w *infraCluster) GenerateInfraClusterK8sClient(infraClusterSecretRef *corev1.ObjectReference, ownerNamespace string, context gocontext.Context) (kubernetes.Interface, error) {
+	restConfig := w.RESTConfig
+	if infraClusterSecretRef != nil {
+		var err error
+		if restConfig, _, err = w.infraClusterRESTConfig(infraClusterSecretRef, ownerNamespace, context); err != nil {
+			return nil, err
+		}
+	}
+	if restConfig == nil {
+		return nil, errors.New("failed to create infra cluster kubernetes client: the REST config of the management cluster is not set")
+	}
+
+	infraClusterClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create infra cluster kubernetes client")
+	}
+
+	return infraClusterClient, nil
+}
+
+// infraClusterRESTConfig returns the REST config and the namespace of the infra cluster, from the kubeconfig of the
+// referenced secret.
+func (w *infraCluster) infraClusterRESTConfig(infraClusterSecretRef *corev1.ObjectReference, ownerNamespace string, context gocontext.Context) (*rest.Config, string, error) {
 	infraKubeconfigSecret := &corev1.Secret{}
 	secretNamespace := infraClusterSecretRef.Namespace
 	if secretNamespace == "" {
@@ -79,10 +126,5 @@ func (w *infraCluster) GenerateInfraClusterClient(infraClusterSecretRef *corev1.
 		return nil, "", errors.Wrap(err, "failed to create REST config")
 	}
 
-	infraClusterClient, err := w.ClientFactory(restConfig, k8sclient.Options{Scheme: w.Client.Scheme()})
-	if err != nil {
-		return nil, "", errors.Wrap(err, "failed to create infra cluster client")
-	}
-
-	return infraClusterClient, namespace, nil
+	return restConfig, namespace, nil
 }
@@ -44,7 +44,7 @@ var _ = Describe("InfraCluster", func() {
 	It("should return the management client and namespace when the infrastructure secret reference is nil", func() {
 		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).Build()
 
-		infraCluster := New(fakeClient, fakeClient)
+		infraCluster := New(fakeClient, fakeClient, nil)
 		infraClient, infraNamespace, err := infraCluster.GenerateInfraClusterClient(nil, ownerNamespace, nil)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(infraClient).To(BeIdenticalTo(fakeClient))
@@ -59,7 +59,7 @@ var _ = Describe("InfraCluster", func() {
 			Kind:       "Secret",
 			Name:       infraSecretName,
 		}
-		infraCluster := New(fakeClient, nil)
+		infraCluster := New(fakeClient, nil, nil)
 
 		_, _, err := infraCluster.GenerateInfraClusterClient(infraClusterSecretRef, ownerNamespace, nil)
 		Expect(errors.IsNotFound(err)).To(BeTrue())
@@ -81,7 +81,7 @@ var _ = Describe("InfraCluster", func() {
 			Name:       infraSecretName,
 		}
 
-		infraCluster := New(fakeClient, nil)
+		infraCluster := New(fakeClient, nil, nil)
 		_, _, err := infraCluster.GenerateInfraClusterClient(infraClusterSecretRef, ownerNamespace, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal("failed to retrieve infra kubeconfig from secret: 'kubeconfig' key is missing"))
@@ -105,7 +105,7 @@ var _ = Describe("InfraCluster", func() {
 			Name:       infraSecretName,
 		}
 
-		infraCluster := New(fakeClient, nil)
+		infraCluster := New(fakeClient, nil, nil)
 		_, _, err := infraCluster.GenerateInfraClusterClient(infraClusterSecretRef, ownerNamespace, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("failed to create K8s-API client config"))
@@ -174,4 +174,45 @@ var _ = Describe("InfraCluster", func() {
 		Expect(namespace).To(Equal("minastirith"))
 	})
 
+	It("should return a kubernetes client of the management cluster when the infrastructure secret reference is nil", func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).Build()
+
+		infraCluster := New(fakeClient, fakeClient, &rest.Config{Host: "https://rohan.com"})
+		infraClient, err := infraCluster.GenerateInfraClusterK8sClient(nil, ownerNamespace, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infraClient).NotTo(BeNil())
+	})
+
+	It("should fail to return a kubernetes client of the management cluster without its REST config", func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).Build()
+
+		infraCluster := New(fakeClient, fakeClient, nil)
+		_, err := infraCluster.GenerateInfraClusterK8sClient(nil, ownerNamespace, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return a kubernetes client of the infra cluster of the referenced secret", func() {
+		infraClusterSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      infraSecretName,
+				Namespace: ownerNamespace,
+			},
+			Data: map[string][]byte{
+				"kubeconfig": []byte(kubeconfig),
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(infraClusterSecret).Build()
+
+		infraClusterSecretRef := &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Name:       infraSecretName,
+		}
+
+		infraCluster := New(fakeClient, nil, nil)
+		infraClient, err := infraCluster.GenerateInfraClusterK8sClient(infraClusterSecretRef, ownerNamespace, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infraClient).NotTo(BeNil())
+	})
+
 })
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	v1 "k8s.io/api/core/v1"
+	kubernetes "k8s.io/client-go/kubernetes"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -51,3 +52,18 @@ func (mr *MockInfraClusterMockRecorder) GenerateInfraClusterClient(infraClusterS
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateInfraClusterClient", reflect.TypeOf((*MockInfraCluster)(nil).GenerateInfraClusterClient), infraClusterSecretRef, ownerNamespace, context)
 }
+
+// GenerateInfraClusterK8sClient mocks base method.
+func (m *MockInfraCluster) GenerateInfraClusterK8sClient(infraClusterSecretRef *v1.ObjectReference, ownerNamespace string, context context.Context) (kubernetes.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateInfraClusterK8sClient", infraClusterSecretRef, ownerNamespace, context)
+	ret0, _ := ret[0].(kubernetes.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateInfraClusterK8sClient indicates an expected call of GenerateInfraClusterK8sClient.
+func (mr *MockInfraClusterMockRecorder) GenerateInfraClusterK8sClient(infraClusterSecretRef, ownerNamespace, context interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateInfraClusterK8sClient", reflect.TypeOf((*MockInfraCluster)(nil).GenerateInfraClusterK8sClient), infraClusterSecretRef, ownerNamespace, context)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// VMCreationLimiter limits the rate the VMs of the tenant clusters are created at, across reconciliations.
+// A single limiter is shared by all the machines reconciled by the controller.
+type VMCreationLimiter struct {
+	lock      sync.Mutex
+	creations map[types.NamespacedName]map[string]time.Time
+}
+
+// NewVMCreationLimiter returns a new VMCreationLimiter, without any VM creation recorded.
+func NewVMCreationLimiter() *VMCreationLimiter {
+	return &VMCreationLimiter{
+		creations: map[types.NamespacedName]map[string]time.Time{},
+	}
+}
+
+// Reserve records the creation of the VM of the machine of the given cluster, unless maxVMs VMs of the cluster were
+// already created within the last interval. It returns 0 if the VM can be created, or the time to wait for its turn
+// otherwise. The VM of a machine whose creation was already recorded within the interval, e.g. when the creation
+// failed and is retried, can always be created.
+func (l *VMCreationLimiter) Reserve(cluster types.NamespacedName, machine string, maxVMs int, interval time.Duration) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	creations, found := l.creations[cluster]
+	if !found {
+		creations = map[string]time.Time{}
+		l.creations[cluster] = creations
+	}
+
+	// forget the creations out of the interval
+	var oldest time.Time
+	for name, created := range creations {
+		if now.Sub(created) >= interval {
+			delete(creations, name)
+		} else if oldest.IsZero() || created.Before(oldest) {
+			oldest = created
+		}
+	}
+
+	if _, found = creations[machine]; found {
+		return 0
+	}
+
+	if len(creations) >= maxVMs {
+		return oldest.Add(interval).Sub(now)
+	}
+
+	creations[machine] = now
+	return 0
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("VMCreationLimiter", func() {
+	cluster := types.NamespacedName{Namespace: "ns", Name: "cluster"}
+
+	It("should limit the VM creations per cluster and interval", func() {
+		limiter := NewVMCreationLimiter()
+		otherCluster := types.NamespacedName{Namespace: "ns", Name: "other-cluster"}
+
+		Expect(limiter.Reserve(cluster, "machine-1", 2, time.Minute)).To(BeZero())
+		Expect(limiter.Reserve(cluster, "machine-2", 2, time.Minute)).To(BeZero())
+		Expect(limiter.Reserve(cluster, "machine-3", 2, time.Minute)).To(BeNumerically("~", time.Minute, time.Second))
+		Expect(limiter.Reserve(otherCluster, "machine-3", 2, time.Minute)).To(BeZero())
+	})
+
+	It("should let the machines retry their VM creation", func() {
+		limiter := NewVMCreationLimiter()
+
+		Expect(limiter.Reserve(cluster, "machine-1", 1, time.Minute)).To(BeZero())
+		Expect(limiter.Reserve(cluster, "machine-1", 1, time.Minute)).To(BeZero())
+	})
+
+	It("should let the next machines create their VM after the interval", func() {
+		limiter := NewVMCreationLimiter()
+
+		Expect(limiter.Reserve(cluster, "machine-1", 1, 10*time.Millisecond)).To(BeZero())
+		Expect(limiter.Reserve(cluster, "machine-2", 1, 10*time.Millisecond)).To(BeNumerically(">", 0))
+		Eventually(func() time.Duration {
+			return limiter.Reserve(cluster, "machine-2", 1, 10*time.Millisecond)
+		}).Should(BeZero())
+	})
+
+	It("should not limit the VM creations without a limiter", func() {
+		var limiter *VMCreationLimiter
+		Expect(limiter.Reserve(cluster, "machine-1", 0, time.Minute)).To(BeZero())
+	})
+})
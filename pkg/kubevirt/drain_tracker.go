@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	maxDrainBackoff          = 5 * time.Minute
+	drainBackoffJitterFactor = 0.2
+)
+
+// NodeKey identifies a tenant cluster node in the NodeDrainTracker.
+type NodeKey struct {
+	// Cluster is the namespaced name of the tenant cluster.
+	Cluster types.NamespacedName
+	// Node is the name of the tenant cluster node.
+	Node string
+}
+
+// NodeDrainTracker keeps track of the drains of the tenant cluster nodes across reconciliations.
+// A single tracker is shared by all the machines reconciled by the controller.
+type NodeDrainTracker struct {
+	lock        sync.Mutex
+	failures    map[NodeKey]int
+	drains      map[types.NamespacedName]map[string]time.Time
+	unreachable map[NodeKey]time.Time
+}
+
+// NewNodeDrainTracker returns a new, empty, NodeDrainTracker.
+func NewNodeDrainTracker() *NodeDrainTracker {
+	return &NodeDrainTracker{
+		failures:    map[NodeKey]int{},
+		drains:      map[types.NamespacedName]map[string]time.Time{},
+		unreachable: map[NodeKey]time.Time{},
+	}
+}
+
+// StartDrain records the drain of the node identified by key as in progress, unless maxConcurrent drains of nodes
+// of the same cluster are already in progress. It returns false if the drain can't start yet.
+func (t *NodeDrainTracker) StartDrain(key NodeKey, maxConcurrent int) bool {
+	if t == nil {
+		return true
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	drains, found := t.drains[key.Cluster]
+	if !found {
+		drains = map[string]time.Time{}
+		t.drains[key.Cluster] = drains
+	}
+
+	if _, found = drains[key.Node]; found {
+		return true
+	}
+
+	if len(drains) >= maxConcurrent {
+		return false
+	}
+
+	drains[key.Node] = time.Now()
+	drainsStartedTotal.With(clusterLabels(key.Cluster)).Inc()
+	cordonedNodes.With(clusterLabels(key.Cluster)).Set(float64(len(drains)))
+	return true
+}
+
+// DrainsInProgress returns the number of drains of nodes of the cluster in progress.
+func (t *NodeDrainTracker) DrainsInProgress(cluster types.NamespacedName) int {
+	if t == nil {
+		return 0
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.drains[cluster])
+}
+
+// DrainStartTime returns the time the drain of the node identified by key started, if in progress.
+func (t *NodeDrainTracker) DrainStartTime(key NodeKey) (time.Time, bool) {
+	if t == nil {
+		return time.Time{}, false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	startTime, found := t.drains[key.Cluster][key.Node]
+	return startTime, found
+}
+
+// DrainFailed records a failed drain attempt of the node identified by key, and returns the time to wait before the
+// next attempt, together with the number of consecutive failures. The wait time starts from the base interval and
+// exponentially grows with every failure, with some jitter, up to maxDrainBackoff.
+func (t *NodeDrainTracker) DrainFailed(key NodeKey, base time.Duration) (time.Duration, int) {
+	if t == nil {
+		return base, 1
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.failures[key]++
+	failures := t.failures[key]
+
+	backoff := base
+	for i := 1; i < failures && backoff < maxDrainBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = wait.Jitter(backoff, drainBackoffJitterFactor)
+	if backoff > maxDrainBackoff {
+		backoff = maxDrainBackoff
+	}
+
+	return backoff, failures
+}
+
+// TenantUnreachable records that the API server of the tenant cluster could not be reached to drain the node
+// identified by key, and returns for how long it has been unreachable.
+func (t *NodeDrainTracker) TenantUnreachable(key NodeKey) time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	since, found := t.unreachable[key]
+	if !found {
+		since = time.Now()
+		t.unreachable[key] = since
+	}
+	return time.Since(since)
+}
+
+// TenantReachable forgets that the API server of the tenant cluster could not be reached to drain the node identified
+// by key.
+func (t *NodeDrainTracker) TenantReachable(key NodeKey) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.unreachable, key)
+}
+
+// DrainDone forgets the drain of the node identified by key, and its failed attempts.
+func (t *NodeDrainTracker) DrainDone(key NodeKey) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.failures, key)
+	delete(t.unreachable, key)
+
+	if drains, found := t.drains[key.Cluster]; found {
+		delete(drains, key.Node)
+		cordonedNodes.With(clusterLabels(key.Cluster)).Set(float64(len(drains)))
+		if len(drains) == 0 {
+			delete(t.drains, key.Cluster)
+		}
+	}
+}
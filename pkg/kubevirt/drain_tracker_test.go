@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("NodeDrainTracker", func() {
+	cluster := types.NamespacedName{Namespace: "ns", Name: "cluster"}
+	key := NodeKey{Cluster: cluster, Node: "node"}
+
+	It("should back off exponentially, up to the max backoff", func() {
+		tracker := NewNodeDrainTracker()
+
+		backoff, failures := tracker.DrainFailed(key, time.Minute)
+		Expect(failures).To(Equal(1))
+		Expect(backoff).To(BeNumerically("~", time.Minute, time.Minute/5))
+
+		backoff, failures = tracker.DrainFailed(key, time.Minute)
+		Expect(failures).To(Equal(2))
+		Expect(backoff).To(BeNumerically(">=", 2*time.Minute))
+
+		for i := 0; i < 10; i++ {
+			backoff, _ = tracker.DrainFailed(key, time.Minute)
+		}
+		Expect(backoff).To(Equal(maxDrainBackoff))
+	})
+
+	It("should start over after a successful drain", func() {
+		tracker := NewNodeDrainTracker()
+
+		tracker.DrainFailed(key, time.Second)
+		tracker.DrainFailed(key, time.Second)
+		tracker.DrainDone(key)
+
+		_, failures := tracker.DrainFailed(key, time.Second)
+		Expect(failures).To(Equal(1))
+	})
+
+	It("should track each node separately", func() {
+		tracker := NewNodeDrainTracker()
+
+		tracker.DrainFailed(key, time.Second)
+		_, failures := tracker.DrainFailed(NodeKey{Cluster: cluster, Node: "other-node"}, time.Second)
+		Expect(failures).To(Equal(1))
+	})
+
+	It("should limit the concurrent drains per cluster", func() {
+		tracker := NewNodeDrainTracker()
+		otherNode := NodeKey{Cluster: cluster, Node: "other-node"}
+		otherCluster := NodeKey{Cluster: types.NamespacedName{Namespace: "ns", Name: "other-cluster"}, Node: "node"}
+
+		Expect(tracker.StartDrain(key, 1)).To(BeTrue())
+		Expect(tracker.StartDrain(key, 1)).To(BeTrue())
+		Expect(tracker.StartDrain(otherNode, 1)).To(BeFalse())
+		Expect(tracker.StartDrain(otherCluster, 1)).To(BeTrue())
+		Expect(tracker.StartDrain(otherNode, 2)).To(BeTrue())
+		Expect(tracker.DrainsInProgress(cluster)).To(Equal(2))
+
+		tracker.DrainDone(key)
+		tracker.DrainDone(otherNode)
+		Expect(tracker.StartDrain(otherNode, 1)).To(BeTrue())
+	})
+
+	It("should report the started drains and the cordoned nodes", func() {
+		tracker := NewNodeDrainTracker()
+		metricsCluster := types.NamespacedName{Namespace: "ns", Name: "metrics-cluster"}
+		metricsKey := NodeKey{Cluster: metricsCluster, Node: "node"}
+
+		Expect(tracker.StartDrain(metricsKey, 1)).To(BeTrue())
+		Expect(tracker.StartDrain(metricsKey, 1)).To(BeTrue())
+		Expect(testutil.ToFloat64(drainsStartedTotal.With(clusterLabels(metricsCluster)))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(cordonedNodes.With(clusterLabels(metricsCluster)))).To(Equal(1.0))
+
+		startTime, found := tracker.DrainStartTime(metricsKey)
+		Expect(found).To(BeTrue())
+		Expect(startTime).ToNot(BeZero())
+
+		tracker.DrainDone(metricsKey)
+		Expect(testutil.ToFloat64(cordonedNodes.With(clusterLabels(metricsCluster)))).To(BeZero())
+		_, found = tracker.DrainStartTime(metricsKey)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should track for how long the tenant cluster is unreachable", func() {
+		tracker := NewNodeDrainTracker()
+
+		Expect(tracker.TenantUnreachable(key)).To(BeNumerically("<", time.Second))
+		tracker.unreachable[key] = time.Now().Add(-time.Hour)
+		Expect(tracker.TenantUnreachable(key)).To(BeNumerically(">=", time.Hour))
+
+		tracker.TenantReachable(key)
+		Expect(tracker.TenantUnreachable(key)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should fall back to the base interval when nil", func() {
+		var tracker *NodeDrainTracker
+
+		backoff, failures := tracker.DrainFailed(key, time.Second)
+		Expect(backoff).To(Equal(time.Second))
+		Expect(failures).To(Equal(1))
+		Expect(tracker.StartDrain(key, 1)).To(BeTrue())
+		Expect(tracker.DrainsInProgress(cluster)).To(BeZero())
+		tracker.DrainDone(key)
+	})
+})
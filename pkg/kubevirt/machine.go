@@ -21,16 +21,27 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	kubedrain "k8s.io/kubectl/pkg/drain"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sort"
+	"strings"
 	"time"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
@@ -40,6 +51,44 @@ import (
 
 const (
 	vmiDeleteGraceTimeoutDurationSeconds = 600 // 10 minutes
+
+	defaultDrainTimeout       = 20 * time.Second
+	defaultDrainRetryInterval = time.Second
+
+	defaultMaxConcurrentDrains = 1
+
+	// DefaultSkipWaitForDeleteTimeout is how long the drain of an unreachable node waits for the deleted pods to be
+	// gone by default.
+	DefaultSkipWaitForDeleteTimeout = 5 * time.Minute
+
+	// DefaultTenantUnreachableTimeout is how long the drain retries while the API server of the tenant cluster is
+	// unreachable by default, before deleting the VMI without draining the node.
+	DefaultTenantUnreachableTimeout = 5 * time.Minute
+
+	// bootstrapSentinelFile is written by the CAPI bootstrap once it succeeded.
+	bootstrapSentinelFile = "/run/cluster-api/bootstrap-success.complete"
+)
+
+// Reasons of the events recorded on the KubevirtMachine while draining the tenant cluster node.
+const (
+	DrainStartedEventReason   = "DrainStarted"
+	PodEvictedEventReason     = "PodEvicted"
+	DrainFailedEventReason    = "DrainFailed"
+	DrainSucceededEventReason = "DrainSucceeded"
+	VMIDeletedEventReason     = "VMIDeleted"
+	GuestShutdownEventReason  = "GuestShutdown"
+	DrainDryRunEventReason    = "DrainDryRun"
+)
+
+// errTenantUnreachableTimeout is returned by the drain when the API server of the tenant cluster is unreachable for
+// longer than the tenant unreachable timeout.
+var errTenantUnreachableTimeout = errors.New("the tenant cluster API server is unreachable")
+
+var (
+	// minTPMKubeVirtVersion is the first KubeVirt version supporting the virtual TPM of the VMs.
+	minTPMKubeVirtVersion = version.MustParseGeneric("v0.53.0")
+	// minPersistentTPMKubeVirtVersion is the first KubeVirt version keeping the state of the virtual TPM across reboots.
+	minPersistentTPMKubeVirtVersion = version.MustParseGeneric("v1.0.0")
 )
 
 // Machine implement a service for managing the KubeVirt VM hosting a kubernetes node.
@@ -50,8 +99,11 @@ type Machine struct {
 	vmiInstance    *kubevirtv1.VirtualMachineInstance
 	vmInstance     *kubevirtv1.VirtualMachine
 
+	// proactiveEvacuationNode is the cordoned infra cluster node the VMI is evacuated from, before KubeVirt asks to.
+	proactiveEvacuationNode string
+
 	sshKeys            *ssh.ClusterNodeSshKeys
-	getCommandExecutor func(string, *ssh.ClusterNodeSshKeys) ssh.VMCommandExecutor
+	getCommandExecutor func(string, int32, string, *ssh.ClusterNodeSshKeys) ssh.VMCommandExecutor
 }
 
 // NewMachine returns a new Machine service for the given context.
@@ -66,7 +118,7 @@ func NewMachine(ctx *context.MachineContext, client client.Client, namespace str
 		getCommandExecutor: ssh.NewVMCommandExecutor,
 	}
 
-	namespacedName := types.NamespacedName{Namespace: namespace, Name: ctx.KubevirtMachine.Name}
+	namespacedName := types.NamespacedName{Namespace: namespace, Name: VMName(ctx.KubevirtMachine)}
 	vm := &kubevirtv1.VirtualMachine{}
 	vmi := &kubevirtv1.VirtualMachineInstance{}
 
@@ -96,8 +148,18 @@ func NewMachine(ctx *context.MachineContext, client client.Client, namespace str
 // IsTerminal Reports back if the VM is either being requested to terminate or is terminated
 // in a way that it will never recover from.
 func (m *Machine) IsTerminal() (bool, string, error) {
-	if m.vmInstance == nil || m.vmiInstance == nil {
-		// vm/vmi hasn't been created yet
+	if m.vmInstance == nil {
+		// vm hasn't been created yet
+		return false, "", nil
+	}
+
+	// The VM failed to start for too long, e.g. because its image can't be pulled
+	if reason := m.provisioningFailure(); reason != "" {
+		return true, reason, nil
+	}
+
+	if m.vmiInstance == nil {
+		// vmi hasn't been created yet
 		return false, "", nil
 	}
 
@@ -154,16 +216,106 @@ func (m *Machine) IsTerminal() (bool, string, error) {
 	return false, "", nil
 }
 
+// Preempted checks if the VMI of a preemptible machine failed, e.g. because its virt-launcher pod was preempted by the
+// scheduler or evicted; the VMs of the preemptible machines are not restarted.
+func (m *Machine) Preempted() bool {
+	return m.machineContext.KubevirtMachine.Spec.Preemptible != nil && m.vmiInstance != nil && m.vmiInstance.Status.Phase == kubevirtv1.Failed
+}
+
+// provisioningFailedStatuses are the statuses of a VM failing to start, which KubeVirt can't recover from by itself.
+var provisioningFailedStatuses = sets.New(
+	kubevirtv1.VirtualMachineStatusUnschedulable,
+	kubevirtv1.VirtualMachineStatusErrImagePull,
+	kubevirtv1.VirtualMachineStatusImagePullBackOff,
+	kubevirtv1.VirtualMachineStatusPvcNotFound,
+	kubevirtv1.VirtualMachineStatusDataVolumeError,
+)
+
+// provisioningFailure returns why the VM failed to start for longer than the provisioning failure timeout of the
+// KubevirtMachine, or an empty string if it didn't. The failure is timed from the last time the VM stopped being
+// ready, or from its creation.
+func (m *Machine) provisioningFailure() string {
+	timeout := m.machineContext.KubevirtMachine.Spec.ProvisioningFailureTimeout
+	status := m.vmInstance.Status.PrintableStatus
+	if timeout == nil || !provisioningFailedStatuses.Has(status) {
+		return ""
+	}
+
+	since := m.vmInstance.CreationTimestamp.Time
+	for _, cond := range m.vmInstance.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineReady && !cond.LastTransitionTime.IsZero() {
+			since = cond.LastTransitionTime.Time
+		}
+	}
+	if time.Since(since) < timeout.Duration {
+		return ""
+	}
+
+	return fmt.Sprintf("The VM has been in the %s status for more than %s", status, timeout.Duration)
+}
+
 // Exists checks if the VM has been provisioned already.
 func (m *Machine) Exists() bool {
 	return m.vmInstance != nil
 }
 
+// Adopt labels the existing VM, and its VMI template, as belonging to the KubevirtMachine, unless they already are. A VM
+// belonging to another KubevirtMachine is never adopted.
+func (m *Machine) Adopt() error {
+	if m.vmInstance == nil {
+		return nil
+	}
+
+	kubevirtMachine := m.machineContext.KubevirtMachine
+	name, found := m.vmInstance.Labels[infrav1.KubevirtMachineNameLabel]
+	namespace := m.vmInstance.Labels[infrav1.KubevirtMachineNamespaceLabel]
+	if found {
+		if name != kubevirtMachine.Name || namespace != kubevirtMachine.Namespace {
+			return fmt.Errorf("the VM %s belongs to the KubevirtMachine %s/%s", m.vmInstance.Name, namespace, name)
+		}
+		return nil
+	}
+
+	vm := m.vmInstance.DeepCopy()
+	vm.Labels = mergeMaps(vm.Labels, map[string]string{
+		clusterv1.ClusterNameLabel:            m.machineContext.Cluster.Name,
+		infrav1.KubevirtMachineNameLabel:      kubevirtMachine.Name,
+		infrav1.KubevirtMachineNamespaceLabel: kubevirtMachine.Namespace,
+	})
+	if vm.Spec.Template != nil {
+		vm.Spec.Template.ObjectMeta.Labels = mergeMaps(vm.Spec.Template.ObjectMeta.Labels, map[string]string{
+			infrav1.KubevirtMachineNameLabel:      kubevirtMachine.Name,
+			infrav1.KubevirtMachineNamespaceLabel: kubevirtMachine.Namespace,
+		})
+	}
+
+	if err := m.client.Patch(m.machineContext, vm, client.MergeFrom(m.vmInstance)); err != nil {
+		return fmt.Errorf("failed to label the VM %s; %w", vm.Name, err)
+	}
+	m.machineContext.Logger.Info("adopted the existing VM", "VM", vm.Name)
+	m.vmInstance = vm
+
+	return nil
+}
+
 // Create creates a new VM for this machine.
 func (m *Machine) Create(ctx gocontext.Context) error {
 	m.machineContext.Logger.Info(fmt.Sprintf("Creating VM with role '%s'...", nodeRole(m.machineContext)))
 
+	if err := m.validateHostDevices(ctx); err != nil {
+		return err
+	}
+	if err := m.validateTPM(ctx); err != nil {
+		return err
+	}
+	if err := m.validateLaunchSecurity(ctx); err != nil {
+		return err
+	}
+
 	virtualMachine := newVirtualMachineFromKubevirtMachine(m.machineContext, m.namespace)
+	m.preferSmartClone(ctx, virtualMachine)
+	m.enableCPUHotplug(ctx, virtualMachine)
+	m.enableMemoryHotplug(ctx, virtualMachine)
 
 	mutateFn := func() (err error) {
 		if virtualMachine.Labels == nil {
@@ -188,255 +340,1777 @@ func (m *Machine) Create(ctx gocontext.Context) error {
 	return nil
 }
 
-// Returns if VMI has ready condition or not.
-func (m *Machine) hasReadyCondition() bool {
+// validateHostDevices checks that the GPUs and the host devices of the VM template are permitted host devices in the
+// KubeVirt configuration of the infra cluster, as the VM would never be scheduled otherwise. The check is skipped if
+// the KubeVirt configuration can't be read.
+func (m *Machine) validateHostDevices(ctx gocontext.Context) error {
+	vmiTemplate := m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil {
+		return nil
+	}
+	devices := vmiTemplate.Spec.Domain.Devices
+	if len(devices.GPUs) == 0 && len(devices.HostDevices) == 0 {
+		return nil
+	}
 
-	if m.vmiInstance == nil {
-		return false
+	permitted, found := m.permittedHostDevices(ctx)
+	if !found {
+		return nil
 	}
 
-	for _, cond := range m.vmiInstance.Status.Conditions {
-		if cond.Type == kubevirtv1.VirtualMachineInstanceReady &&
-			cond.Status == corev1.ConditionTrue {
-			return true
+	for _, gpu := range devices.GPUs {
+		if !permitted.Has(gpu.DeviceName) {
+			return fmt.Errorf("the deviceName %q of GPU %q is not a permitted host device in the KubeVirt configuration of the infra cluster", gpu.DeviceName, gpu.Name)
+		}
+	}
+	for _, hostDevice := range devices.HostDevices {
+		if !permitted.Has(hostDevice.DeviceName) {
+			return fmt.Errorf("the deviceName %q of host device %q is not a permitted host device in the KubeVirt configuration of the infra cluster", hostDevice.DeviceName, hostDevice.Name)
 		}
 	}
 
-	return false
+	return nil
 }
 
-// Address returns the IP address of the VM.
-func (m *Machine) Address() string {
-	if m.vmiInstance != nil && len(m.vmiInstance.Status.Interfaces) > 0 {
-		return m.vmiInstance.Status.Interfaces[0].IP
+// permittedHostDevices returns the resource names of the host devices permitted in the KubeVirt configuration of the
+// infra cluster. It returns false if the KubeVirt configuration can't be read.
+func (m *Machine) permittedHostDevices(ctx gocontext.Context) (sets.Set[string], bool) {
+	kubevirt := m.infraKubeVirt(ctx)
+	if kubevirt == nil {
+		return nil, false
 	}
 
-	return ""
-}
+	permitted := sets.New[string]()
+	if config := kubevirt.Spec.Configuration.PermittedHostDevices; config != nil {
+		for _, device := range config.PciHostDevices {
+			permitted.Insert(device.ResourceName)
+		}
+		for _, device := range config.MediatedDevices {
+			permitted.Insert(device.ResourceName)
+		}
+	}
 
-// IsReady checks if the VM is ready
-func (m *Machine) IsReady() bool {
-	return m.hasReadyCondition()
+	return permitted, true
 }
 
-// SupportsCheckingIsBootstrapped checks if we have a method of checking
-// that this bootstrapper has completed.
-func (m *Machine) SupportsCheckingIsBootstrapped() bool {
-	// Right now, we can only check if bootstrapping has
-	// completed if we are using a bootstrapper that allows
-	// for us to inject ssh keys into the guest.
-
-	if m.sshKeys != nil {
-		return m.machineContext.HasInjectedCapkSSHKeys(m.sshKeys.PublicKey)
+// validateTPM checks that the KubeVirt version of the infra cluster supports the virtual TPM of the VM template. The
+// check is skipped if the KubeVirt version can't be read.
+func (m *Machine) validateTPM(ctx gocontext.Context) error {
+	vmiTemplate := m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.Devices.TPM == nil {
+		return nil
 	}
-	return false
-}
-
-// IsBootstrapped checks if the VM is bootstrapped with Kubernetes.
-func (m *Machine) IsBootstrapped() bool {
-	// CheckStrategy value is already sanitized by apiserver
-	switch m.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy {
-	case "none":
-		// skip bootstrap check and always returns positively
-		return true
 
-	case "":
-		fallthrough // ssh is default check strategy, fallthrough
-	case "ssh":
-		return m.IsBootstrappedWithSSH()
+	kubevirt := m.infraKubeVirt(ctx)
+	if kubevirt == nil || kubevirt.Status.ObservedKubeVirtVersion == "" {
+		return nil
+	}
+	kubevirtVersion, err := version.ParseGeneric(kubevirt.Status.ObservedKubeVirtVersion)
+	if err != nil {
+		m.machineContext.Logger.Info("can't parse the KubeVirt version of the infra cluster; skipping the TPM validation", "version", kubevirt.Status.ObservedKubeVirtVersion)
+		return nil
+	}
 
-	default:
-		// Since CRD CheckStrategy field is validated by an enum, this case should never be hit
-		return false
+	minVersion := minTPMKubeVirtVersion
+	if persistent := vmiTemplate.Spec.Domain.Devices.TPM.Persistent; persistent != nil && *persistent {
+		minVersion = minPersistentTPMKubeVirtVersion
+	}
+	if kubevirtVersion.LessThan(minVersion) {
+		return fmt.Errorf("the KubeVirt version %s of the infra cluster doesn't support the TPM of the VM, it requires KubeVirt %s or later", kubevirt.Status.ObservedKubeVirtVersion, minVersion)
 	}
+
+	return nil
 }
 
-// IsBootstrappedWithSSH checks if the VM is bootstrapped with Kubernetes using SSH strategy.
-func (m *Machine) IsBootstrappedWithSSH() bool {
-	if !m.IsReady() || m.sshKeys == nil {
-		return false
+// validateLaunchSecurity checks that at least one node of the infra cluster can run the VM with the AMD Secure
+// Encrypted Virtualization (SEV) of the VM template, as the VM would never be scheduled otherwise. The check is skipped
+// if the infra cluster nodes can't be read.
+func (m *Machine) validateLaunchSecurity(ctx gocontext.Context) error {
+	vmiTemplate := m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.LaunchSecurity == nil || vmiTemplate.Spec.Domain.LaunchSecurity.SEV == nil {
+		return nil
 	}
 
-	executor := m.getCommandExecutor(m.Address(), m.sshKeys)
+	label := kubevirtv1.SEVLabel
+	if policy := vmiTemplate.Spec.Domain.LaunchSecurity.SEV.Policy; policy != nil && policy.EncryptedState != nil && *policy.EncryptedState {
+		label = kubevirtv1.SEVESLabel
+	}
 
-	output, err := executor.ExecuteCommand("cat /run/cluster-api/bootstrap-success.complete")
-	if err != nil || output != "success" {
-		return false
+	nodes := &corev1.NodeList{}
+	if err := m.client.List(ctx, nodes, client.HasLabels{label}); err != nil {
+		m.machineContext.Logger.Info("can't list the nodes of the infra cluster; skipping the launch security validation", "error", err.Error())
+		return nil
 	}
-	return true
+	if len(nodes.Items) == 0 {
+		return fmt.Errorf("no node of the infra cluster has the %s label required by the launch security of the VM", label)
+	}
+
+	return nil
 }
 
-// GenerateProviderID generates the KubeVirt provider ID to be used for the NodeRef
-func (m *Machine) GenerateProviderID() (string, error) {
-	if m.vmiInstance == nil {
-		return "", errors.New("Underlying Kubevirt VM is NOT running")
+// InsufficientCapacity returns which resources requested by the VM no schedulable node of the infra cluster has free,
+// or an empty string if a node has enough CPU, memory and devices free for the VM, or if the capacity check is not
+// enabled in the controller. The free resources of a node are its allocatable resources, minus the requests of the
+// pods running on it; the node selector of the VM is honoured, but not its affinity or the taints of the nodes. The
+// check is skipped if the nodes or the pods of the infra cluster can't be listed, and for the VMs with an
+// instancetype, whose resources are only known to KubeVirt.
+func (m *Machine) InsufficientCapacity(ctx gocontext.Context) string {
+	if !m.machineContext.CapacityCheck {
+		return ""
+	}
+	vmiTemplate := newVirtualMachineFromKubevirtMachine(m.machineContext, m.namespace).Spec.Template
+	if vmiTemplate == nil || m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Instancetype != nil {
+		return ""
 	}
+	requests := vmResourceRequests(&vmiTemplate.Spec)
 
-	providerID := fmt.Sprintf("kubevirt://%s", m.machineContext.KubevirtMachine.Name)
+	nodes := &corev1.NodeList{}
+	if err := m.client.List(ctx, nodes, client.MatchingLabels(vmiTemplate.Spec.NodeSelector)); err != nil {
+		m.machineContext.Logger.Info("can't list the nodes of the infra cluster; skipping the capacity check", "error", err.Error())
+		return ""
+	}
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable || !noderefutil.IsNodeReady(&node) {
+			continue
+		}
+		requested, err := m.nodeRequests(ctx, node.Name)
+		if err != nil {
+			m.machineContext.Logger.Info("can't list the pods of the infra cluster; skipping the capacity check", "error", err.Error())
+			return ""
+		}
+		fits := true
+		for name, quantity := range requests {
+			free := node.Status.Allocatable[name]
+			free.Sub(requested[name])
+			if free.Cmp(quantity) < 0 {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return ""
+		}
+	}
 
-	return providerID, nil
+	names := make([]string, 0, len(requests))
+	for name := range requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	missing := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := requests[corev1.ResourceName(name)]
+		missing = append(missing, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	return fmt.Sprintf("no schedulable node of the infra cluster has %s free for the VM", strings.Join(missing, ", "))
 }
 
-// Delete deletes VM for this machine.
-func (m *Machine) Delete() error {
-	namespacedName := types.NamespacedName{Namespace: m.namespace, Name: m.machineContext.KubevirtMachine.Name}
-	vm := &kubevirtv1.VirtualMachine{}
-	if err := m.client.Get(m.machineContext.Context, namespacedName, vm); err != nil {
-		if apierrors.IsNotFound(err) {
-			m.machineContext.Logger.Info("VM does not exist, nothing to do.")
-			return nil
-		}
-		return errors.Wrapf(err, "failed to retrieve VM to delete")
+// nodeRequests returns the resources requested by the pods running on the infra cluster node.
+func (m *Machine) nodeRequests(ctx gocontext.Context, nodeName string) (corev1.ResourceList, error) {
+	pods := &corev1.PodList{}
+	if err := m.client.List(ctx, pods, client.MatchingFields{"spec.nodeName": nodeName}); err != nil {
+		return nil, err
 	}
 
-	if err := m.client.Delete(gocontext.Background(), vm); err != nil {
-		return errors.Wrapf(err, "failed to delete VM")
+	requested := corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		addResources(requested, podRequests(&pod))
 	}
-
-	return nil
+	return requested, nil
 }
 
-func (m *Machine) DrainNodeIfNeeded(wrkldClstr workloadcluster.WorkloadCluster) (time.Duration, error) {
-	if m.vmiInstance == nil || !m.shouldGracefulDeleteVMI() {
-		if _, anntExists := m.machineContext.KubevirtMachine.Annotations[infrav1.VmiDeletionGraceTime]; anntExists {
-			if err := m.removeGracePeriodAnnotation(); err != nil {
-				return 100 * time.Millisecond, err
+// podRequests returns the resources the pod requests from its node, as the scheduler accounts for them: the sum of
+// the requests of its containers, or the largest request of its init containers when larger, plus the overhead of its
+// runtime class.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, found := requests[name]; !found || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
 			}
 		}
-		return 0, nil
 	}
+	addResources(requests, pod.Spec.Overhead)
+	return requests
+}
 
-	exceeded, err := m.drainGracePeriodExceeded()
-	if err != nil {
-		return 0, err
+// vmResourceRequests returns the resources the virt-launcher pod of the VM requests, leaving out its overhead: the
+// CPU request, which KubeVirt defaults to a tenth of a CPU per vCPU, or a CPU per vCPU with a dedicated CPU placement,
+// the memory request, which KubeVirt defaults to the guest memory, and a device per GPU and host device.
+func vmResourceRequests(spec *kubevirtv1.VirtualMachineInstanceSpec) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	domain := spec.Domain
+
+	if cpu, found := domain.Resources.Requests[corev1.ResourceCPU]; found {
+		requests[corev1.ResourceCPU] = cpu.DeepCopy()
+	} else if domain.CPU != nil && domain.CPU.DedicatedCPUPlacement {
+		requests[corev1.ResourceCPU] = *resource.NewQuantity(vCPUs(domain.CPU), resource.DecimalSI)
+	} else {
+		requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(vCPUs(domain.CPU)*100, resource.DecimalSI)
 	}
 
-	if !exceeded {
-		retryDuration, err := m.drainNode(wrkldClstr)
-		if err != nil {
-			return 0, err
-		}
-
-		if retryDuration > 0 {
-			return retryDuration, nil
-		}
+	if memory, found := domain.Resources.Requests[corev1.ResourceMemory]; found {
+		requests[corev1.ResourceMemory] = memory.DeepCopy()
+	} else if domain.Memory != nil && domain.Memory.Guest != nil {
+		requests[corev1.ResourceMemory] = domain.Memory.Guest.DeepCopy()
 	}
 
-	// now, when the node is drained (or vmiDeleteGraceTimeoutDurationSeconds has passed), we can delete the VMI
-	propagationPolicy := metav1.DeletePropagationForeground
-	err = m.client.Delete(m.machineContext, m.vmiInstance, &client.DeleteOptions{PropagationPolicy: &propagationPolicy})
-	if err != nil {
-		m.machineContext.Logger.Error(err, "failed to delete VirtualMachineInstance")
-		return 0, err
+	devices := corev1.ResourceList{}
+	for _, gpu := range domain.Devices.GPUs {
+		addResources(devices, corev1.ResourceList{corev1.ResourceName(gpu.DeviceName): resource.MustParse("1")})
 	}
-
-	if err = m.removeGracePeriodAnnotation(); err != nil {
-		return 100 * time.Millisecond, err
+	for _, hostDevice := range domain.Devices.HostDevices {
+		addResources(devices, corev1.ResourceList{corev1.ResourceName(hostDevice.DeviceName): resource.MustParse("1")})
 	}
+	addResources(requests, devices)
 
-	// requeue to force reading the VMI again
-	return time.Second * 10, nil
+	return requests
 }
 
-const removeGracePeriodAnnotationPatch = `[{"op": "remove", "path": "/metadata/annotations/` + infrav1.VmiDeletionGraceTimeEscape + `"}]`
+// addResources adds the quantities of the added resources to the resources.
+func addResources(resources, added corev1.ResourceList) {
+	for name, quantity := range added {
+		total := resources[name]
+		total.Add(quantity)
+		resources[name] = total
+	}
+}
 
-func (m *Machine) removeGracePeriodAnnotation() error {
-	patch := client.RawPatch(types.JSONPatchType, []byte(removeGracePeriodAnnotationPatch))
+// liveUpdateFeatureGate is the feature gate of KubeVirt enabling the CPU and memory hotplug.
+const liveUpdateFeatureGate = "VMLiveUpdateFeatures"
 
-	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patch); err != nil {
-		return fmt.Errorf("failed to remove the %s annotation to the KubeVirtMachine %s; %w", infrav1.VmiDeletionGraceTime, m.machineContext.KubevirtMachine.Name, err)
+// liveUpdateSupported checks if the KubeVirt configuration of the infra cluster supports the hotplug of the resource
+// of the VM.
+func (m *Machine) liveUpdateSupported(ctx gocontext.Context, resource string) bool {
+	kubevirt := m.infraKubeVirt(ctx)
+	if kubevirt == nil {
+		return false
+	}
+	devConfig := kubevirt.Spec.Configuration.DeveloperConfiguration
+	if devConfig == nil || !sets.New(devConfig.FeatureGates...).Has(liveUpdateFeatureGate) {
+		m.machineContext.Logger.Info(fmt.Sprintf("the %s feature gate is not enabled in the KubeVirt configuration of the infra cluster; the %s of the VM can't be hot-plugged", liveUpdateFeatureGate, resource))
+		return false
 	}
+	return true
+}
 
-	return nil
+// enableCPUHotplug enables the CPU hotplug of the VM, if enabled in the controller and supported by the KubeVirt
+// configuration of the infra cluster. The VMs with an instancetype are left alone, as the instancetype sets their CPU.
+func (m *Machine) enableCPUHotplug(ctx gocontext.Context, virtualMachine *kubevirtv1.VirtualMachine) {
+	if !m.machineContext.CPUHotplug || virtualMachine.Spec.Instancetype != nil || !m.liveUpdateSupported(ctx, "CPU") {
+		return
+	}
+
+	if virtualMachine.Spec.LiveUpdateFeatures == nil {
+		virtualMachine.Spec.LiveUpdateFeatures = &kubevirtv1.LiveUpdateFeatures{}
+	}
+	virtualMachine.Spec.LiveUpdateFeatures.CPU = &kubevirtv1.LiveUpdateCPU{}
 }
 
-func (m *Machine) shouldGracefulDeleteVMI() bool {
-	if m.vmiInstance.DeletionTimestamp != nil {
-		m.machineContext.Logger.V(4).Info("DrainNode: the virtualMachineInstance is already in deletion process. Nothing to do here")
-		return false
+// enableMemoryHotplug enables the memory hotplug of the VM, if enabled in the controller and supported by the KubeVirt
+// configuration of the infra cluster. The VMs with an instancetype are left alone, as the instancetype sets their
+// memory.
+func (m *Machine) enableMemoryHotplug(ctx gocontext.Context, virtualMachine *kubevirtv1.VirtualMachine) {
+	if !m.machineContext.MemoryHotplug || virtualMachine.Spec.Instancetype != nil || !m.liveUpdateSupported(ctx, "memory") {
+		return
 	}
 
-	if m.vmiInstance.Spec.EvictionStrategy == nil || *m.vmiInstance.Spec.EvictionStrategy != kubevirtv1.EvictionStrategyExternal {
-		m.machineContext.Logger.V(4).Info("DrainNode: graceful deletion is not supported for virtualMachineInstance. Nothing to do here")
-		return false
+	if virtualMachine.Spec.LiveUpdateFeatures == nil {
+		virtualMachine.Spec.LiveUpdateFeatures = &kubevirtv1.LiveUpdateFeatures{}
 	}
+	virtualMachine.Spec.LiveUpdateFeatures.Memory = &kubevirtv1.LiveUpdateMemory{}
+}
 
-	// KubeVirt will set the EvacuationNodeName field in case of guest node eviction. If the field is not set, there is
-	// nothing to do.
-	if len(m.vmiInstance.Status.EvacuationNodeName) == 0 {
-		m.machineContext.Logger.V(4).Info("DrainNode: the virtualMachineInstance is not marked for deletion. Nothing to do here")
-		return false
+// preferSmartClone puts the DataVolume of the root disk without a storage class on the storage class of the golden image,
+// as CDI can only clone the golden image with a snapshot or a CSI clone within the same storage class, and reports
+// when the storage class only supports the host-assisted copy. The storage class is left alone if the golden image
+// can't be read.
+func (m *Machine) preferSmartClone(ctx gocontext.Context, virtualMachine *kubevirtv1.VirtualMachine) {
+	rootDisk := m.machineContext.KubevirtMachine.Spec.RootDisk
+	if rootDisk == nil || rootDisk.Image != "" || rootDisk.ProvisioningStrategy != infrav1.SmartCloneProvisioningStrategy {
+		return
 	}
 
-	return true
+	var storage *cdiv1.StorageSpec
+	for i, dataVolumeTemplate := range virtualMachine.Spec.DataVolumeTemplates {
+		if dataVolumeTemplate.Name == virtualMachine.Name+"-"+rootDiskName {
+			storage = virtualMachine.Spec.DataVolumeTemplates[i].Spec.Storage
+		}
+	}
+	if storage == nil {
+		return
+	}
+	if storage.StorageClassName == nil {
+		storage.StorageClassName = m.goldenImageStorageClass(ctx, rootDisk)
+	}
+	if storage.StorageClassName == nil {
+		return
+	}
+
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: *storage.StorageClassName}, storageProfile); err != nil {
+		m.machineContext.Logger.Info("can't read the storage profile of the root disk", "storageClass", *storage.StorageClassName, "error", err.Error())
+		return
+	}
+	if cloneStrategy := storageProfile.Status.CloneStrategy; cloneStrategy != nil && *cloneStrategy == cdiv1.CloneStrategyHostAssisted {
+		m.machineContext.Logger.Info("the storage class of the root disk doesn't support smart clones; the golden image is copied", "storageClass", *storage.StorageClassName)
+	}
 }
 
-// wait vmiDeleteGraceTimeoutDurationSeconds to the node to be drained. If this time had passed, don't wait anymore.
-func (m *Machine) drainGracePeriodExceeded() (bool, error) {
-	if graceTime, found := m.machineContext.KubevirtMachine.Annotations[infrav1.VmiDeletionGraceTime]; found {
-		deletionGraceTime, err := time.Parse(time.RFC3339, graceTime)
-		if err != nil { // wrong format - rewrite
-			if err = m.setVmiDeletionGraceTime(); err != nil {
-				return false, err
-			}
-		} else {
-			return time.Now().UTC().After(deletionGraceTime), nil
+// goldenImageStorageClass returns the storage class of the PVC of the golden image of the root disk, or nil if it can't
+// be read.
+func (m *Machine) goldenImageStorageClass(ctx gocontext.Context, rootDisk *infrav1.RootDisk) *string {
+	source := rootDisk.PVC
+	if rootDisk.SourceRef != nil {
+		namespace := m.namespace
+		if rootDisk.SourceRef.Namespace != nil {
+			namespace = *rootDisk.SourceRef.Namespace
 		}
-	} else {
-		if err := m.setVmiDeletionGraceTime(); err != nil {
-			return false, err
+		dataSource := &cdiv1.DataSource{}
+		if err := m.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: rootDisk.SourceRef.Name}, dataSource); err != nil {
+			m.machineContext.Logger.Info("can't read the DataSource of the golden image", "name", rootDisk.SourceRef.Name, "error", err.Error())
+			return nil
 		}
+		source = dataSource.Spec.Source.PVC
+	}
+	if source == nil {
+		return nil
 	}
 
-	return false, nil
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: source.Namespace, Name: source.Name}, pvc); err != nil {
+		m.machineContext.Logger.Info("can't read the PVC of the golden image", "name", source.Name, "error", err.Error())
+		return nil
+	}
+	return pvc.Spec.StorageClassName
 }
 
-func (m *Machine) setVmiDeletionGraceTime() error {
-	m.machineContext.Logger.Info(fmt.Sprintf("setting the %s annotation", infrav1.VmiDeletionGraceTime))
-	graceTime := time.Now().Add(vmiDeleteGraceTimeoutDurationSeconds * time.Second).UTC().Format(time.RFC3339)
-	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s": "%s"}}}`, infrav1.VmiDeletionGraceTime, graceTime)
-	patchRequest := client.RawPatch(types.MergePatchType, []byte(patch))
-
-	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patchRequest); err != nil {
-		return fmt.Errorf("failed to add the %s annotation to the KubeVirtMachine %s; %w", infrav1.VmiDeletionGraceTime, m.machineContext.KubevirtMachine.Name, err)
+// infraKubeVirt returns the KubeVirt configuration of the infra cluster, or nil if it can't be read.
+func (m *Machine) infraKubeVirt(ctx gocontext.Context) *kubevirtv1.KubeVirt {
+	kubevirts := &kubevirtv1.KubeVirtList{}
+	if err := m.client.List(ctx, kubevirts); err != nil {
+		m.machineContext.Logger.Info("can't read the KubeVirt configuration of the infra cluster; skipping its validation", "error", err.Error())
+		return nil
+	}
+	if len(kubevirts.Items) == 0 {
+		return nil
 	}
 
-	return nil
+	return &kubevirts.Items[0]
 }
 
-// This functions drains a node from a tenant cluster.
-// The function returns 3 values:
-// * drain done - boolean
-// * retry time, or 0 if not needed
-// * error - to be returned if we want to retry
-func (m *Machine) drainNode(wrkldClstr workloadcluster.WorkloadCluster) (time.Duration, error) {
-	kubeClient, err := wrkldClstr.GenerateWorkloadClusterK8sClient(m.machineContext)
-	if err != nil {
-		m.machineContext.Logger.Error(err, "Error creating a remote client while deleting Machine, won't retry")
-		return 0, fmt.Errorf("failed to get client to remote cluster; %w", err)
+// PinInstancetypeRevisions records, in the VM template of the KubevirtMachine, the ControllerRevisions of the
+// instancetype and preference that KubeVirt captured when creating the VM, so that a re-created VM keeps the same
+// flavor, even if the instancetype or the preference changed since.
+func (m *Machine) PinInstancetypeRevisions() {
+	if m.vmInstance == nil {
+		return
 	}
 
-	nodeName := m.vmiInstance.Status.EvacuationNodeName
-	node, err := kubeClient.CoreV1().Nodes().Get(m.machineContext, nodeName, metav1.GetOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			// If an admin deletes the node directly, we'll end up here.
-			m.machineContext.Logger.Error(err, "Could not find node from noderef, it may have already been deleted")
-			return 0, nil
-		}
-		return 0, fmt.Errorf("unable to get node %q: %w", nodeName, err)
+	vmSpec := &m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec
+	if matcher := vmSpec.Instancetype; matcher != nil && matcher.RevisionName == "" && m.vmInstance.Spec.Instancetype != nil {
+		matcher.RevisionName = m.vmInstance.Spec.Instancetype.RevisionName
+	}
+	if matcher := vmSpec.Preference; matcher != nil && matcher.RevisionName == "" && m.vmInstance.Spec.Preference != nil {
+		matcher.RevisionName = m.vmInstance.Spec.Preference.RevisionName
+	}
+}
+
+// UpdateHealthCondition sets the VMHealthy condition of the KubevirtMachine from the state of the VM and its VMI. The
+// condition is only set once the VM is healthy for the first time, so that a VM being provisioned is not reported as
+// unhealthy.
+func (m *Machine) UpdateHealthCondition() {
+	kubevirtMachine := m.machineContext.KubevirtMachine
+
+	reason, severity, message := m.unhealthyReason()
+	if reason == "" {
+		conditions.MarkTrue(kubevirtMachine, infrav1.VMHealthyCondition)
+		return
+	}
+	if conditions.Has(kubevirtMachine, infrav1.VMHealthyCondition) {
+		conditions.MarkFalse(kubevirtMachine, infrav1.VMHealthyCondition, reason, severity, message)
+	}
+}
+
+// degradedStatuses are the statuses of a broken VM, which its machine is better replaced than kept.
+var degradedStatuses = sets.New(
+	kubevirtv1.VirtualMachineStatusCrashLoopBackOff,
+	kubevirtv1.VirtualMachineStatusDataVolumeError,
+	kubevirtv1.VirtualMachineStatusPvcNotFound,
+)
+
+// DegradedReason returns why the VM is broken, i.e. its virt-launcher pod is crash looping or its storage failed, or
+// an empty string if it isn't.
+func (m *Machine) DegradedReason() string {
+	if m.vmInstance == nil || !degradedStatuses.Has(m.vmInstance.Status.PrintableStatus) {
+		return ""
+	}
+	return fmt.Sprintf("the VM is in the %s status", m.vmInstance.Status.PrintableStatus)
+}
+
+// unhealthyReason returns why the VM is not healthy, or an empty reason if it is. The guest agent is considered
+// disconnected if the VMI reports the guest OS info, which only the guest agent provides, but not the agent
+// connection.
+func (m *Machine) unhealthyReason() (string, clusterv1.ConditionSeverity, string) {
+	if m.vmInstance != nil && m.vmInstance.Status.PrintableStatus == kubevirtv1.VirtualMachineStatusCrashLoopBackOff {
+		return infrav1.VMCrashLoopBackOffReason, clusterv1.ConditionSeverityError, "the VM keeps crashing"
+	}
+	if m.vmiInstance == nil {
+		return infrav1.VMINotRunningReason, clusterv1.ConditionSeverityError, "the VMI doesn't exist"
+	}
+	if m.vmiInstance.Status.Phase != kubevirtv1.Running {
+		return infrav1.VMINotRunningReason, clusterv1.ConditionSeverityError, fmt.Sprintf("the VMI is in the %s phase", m.vmiInstance.Status.Phase)
+	}
+	if !m.hasReadyCondition() {
+		return infrav1.VMINotReadyReason, clusterv1.ConditionSeverityWarning, "the VMI is not ready"
+	}
+
+	agentConnected := false
+	for _, cond := range m.vmiInstance.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceAgentConnected && cond.Status == corev1.ConditionTrue {
+			agentConnected = true
+		}
+	}
+	if !agentConnected && m.vmiInstance.Status.GuestOSInfo.Name != "" {
+		return infrav1.GuestAgentDisconnectedReason, clusterv1.ConditionSeverityWarning, "the guest agent of the VM disconnected"
+	}
+
+	return "", "", ""
+}
+
+// Returns if VMI has ready condition or not.
+func (m *Machine) hasReadyCondition() bool {
+
+	if m.vmiInstance == nil {
+		return false
+	}
+
+	for _, cond := range m.vmiInstance.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceReady &&
+			cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Address returns the IP address of the VM.
+func (m *Machine) Address() string {
+	if m.vmiInstance != nil && len(m.vmiInstance.Status.Interfaces) > 0 {
+		return m.vmiInstance.Status.Interfaces[0].IP
+	}
+
+	return ""
+}
+
+// AdditionalAddresses returns the addresses of the VM other than its primary IP address: the other IP addresses of
+// all its interfaces, e.g. the IPv6 address of the pod network or the addresses of the secondary interfaces, and the
+// DNS name of the VMI in the infra cluster when its subdomain is set.
+func (m *Machine) AdditionalAddresses() []clusterv1.MachineAddress {
+	if m.vmiInstance == nil {
+		return nil
+	}
+
+	var addresses []clusterv1.MachineAddress
+	seen := map[string]bool{m.Address(): true}
+	for _, iface := range m.vmiInstance.Status.Interfaces {
+		ips := iface.IPs
+		if len(ips) == 0 && iface.IP != "" {
+			ips = []string{iface.IP}
+		}
+		for _, ip := range ips {
+			if seen[ip] {
+				continue
+			}
+			seen[ip] = true
+			addresses = append(addresses, clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: ip})
+		}
+	}
+
+	if subdomain := m.vmiInstance.Spec.Subdomain; subdomain != "" {
+		hostname := m.vmiInstance.Spec.Hostname
+		if hostname == "" {
+			hostname = m.vmiInstance.Name
+		}
+		addresses = append(addresses, clusterv1.MachineAddress{
+			Type:    clusterv1.MachineInternalDNS,
+			Address: fmt.Sprintf("%s.%s.%s.svc", hostname, subdomain, m.vmiInstance.Namespace),
+		})
+	}
+
+	return addresses
+}
+
+// IsReady checks if the VM is ready
+func (m *Machine) IsReady() bool {
+	return m.hasReadyCondition()
+}
+
+// UnschedulableMessage returns why the VMI can't be scheduled on any node of the infra cluster, e.g. because no node
+// has the host devices it requests, or an empty string if the VMI is not unschedulable.
+func (m *Machine) UnschedulableMessage() string {
+	if m.vmiInstance != nil {
+		for _, cond := range m.vmiInstance.Status.Conditions {
+			if cond.Type == kubevirtv1.VirtualMachineInstanceConditionType(corev1.PodScheduled) &&
+				cond.Status == corev1.ConditionFalse &&
+				cond.Reason == corev1.PodReasonUnschedulable {
+				if cond.Message != "" {
+					return cond.Message
+				}
+				break
+			}
+		}
+	}
+
+	if m.vmInstance != nil && m.vmInstance.Status.PrintableStatus == kubevirtv1.VirtualMachineStatusUnschedulable {
+		return "the VM can't be scheduled on any node of the infra cluster"
+	}
+
+	return ""
+}
+
+// SupportsCheckingIsBootstrapped checks if we have a method of checking
+// that this bootstrapper has completed.
+func (m *Machine) SupportsCheckingIsBootstrapped() bool {
+	// The guest agent checks the bootstrap of any guest, while
+	// the ssh check needs a bootstrapper that allows for us to
+	// inject ssh keys into the guest.
+	if m.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy == "guestAgent" {
+		return true
+	}
+
+	if m.sshKeys != nil {
+		return m.machineContext.HasInjectedCapkSSHKeys(m.sshKeys.PublicKey)
+	}
+	return false
+}
+
+// IsBootstrapped checks if the VM is bootstrapped with Kubernetes.
+func (m *Machine) IsBootstrapped() bool {
+	// CheckStrategy value is already sanitized by apiserver
+	switch m.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy {
+	case "none":
+		// skip bootstrap check and always returns positively
+		return true
+
+	case "":
+		fallthrough // ssh is default check strategy, fallthrough
+	case "ssh":
+		return m.IsBootstrappedWithSSH()
+
+	case "guestAgent":
+		return m.IsBootstrappedWithGuestAgent()
+
+	default:
+		// Since CRD CheckStrategy field is validated by an enum, this case should never be hit
+		return false
+	}
+}
+
+// IsBootstrappedWithSSH checks if the VM is bootstrapped with Kubernetes using SSH strategy.
+func (m *Machine) IsBootstrappedWithSSH() bool {
+	if !m.IsReady() || m.sshKeys == nil {
+		return false
+	}
+
+	executor := m.sshCommandExecutor()
+
+	output, err := executor.ExecuteCommand("cat " + bootstrapSentinelFile)
+	if err != nil || output != "success" {
+		return false
+	}
+	return true
+}
+
+// sshCommandExecutor returns the executor running commands in the guest with SSH, as the user and on the port of the
+// bootstrap check spec.
+func (m *Machine) sshCommandExecutor() ssh.VMCommandExecutor {
+	checkSpec := m.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec
+	return m.getCommandExecutor(m.Address(), checkSpec.Port, checkSpec.User, m.sshKeys)
+}
+
+// IsBootstrappedWithGuestAgent checks if the VM is bootstrapped with Kubernetes using the guest agent strategy, i.e. if
+// the readiness probe checking the CAPI Sentinel file through the guest agent succeeded.
+func (m *Machine) IsBootstrappedWithGuestAgent() bool {
+	if !m.IsReady() || m.vmiInstance.Spec.ReadinessProbe == nil {
+		return false
+	}
+
+	for _, cond := range m.vmiInstance.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceAgentConnected && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateProviderID generates the KubeVirt provider ID to be used for the NodeRef
+func (m *Machine) GenerateProviderID() (string, error) {
+	if m.vmiInstance == nil {
+		return "", errors.New("Underlying Kubevirt VM is NOT running")
+	}
+
+	providerID := fmt.Sprintf("kubevirt://%s", VMName(m.machineContext.KubevirtMachine))
+
+	return providerID, nil
+}
+
+// Delete deletes VM for this machine.
+func (m *Machine) Delete() error {
+	namespacedName := types.NamespacedName{Namespace: m.namespace, Name: VMName(m.machineContext.KubevirtMachine)}
+	vm := &kubevirtv1.VirtualMachine{}
+	if err := m.client.Get(m.machineContext.Context, namespacedName, vm); err != nil {
+		if apierrors.IsNotFound(err) {
+			m.machineContext.Logger.Info("VM does not exist, nothing to do.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve VM to delete")
+	}
+
+	retainDisks := m.machineContext.KubevirtMachine.Spec.DiskRetentionPolicy == infrav1.RetainDiskRetentionPolicy
+	if retainDisks {
+		if err := m.orphanDataVolumes(vm); err != nil {
+			return err
+		}
+	}
+
+	if err := m.client.Delete(gocontext.Background(), vm); err != nil {
+		return errors.Wrapf(err, "failed to delete VM")
+	}
+
+	if !retainDisks {
+		return m.deleteDataVolumes(vm)
+	}
+
+	return nil
+}
+
+// orphanDataVolumes removes the VM from the owners of its DataVolumes, so that they, and their PVCs, are not garbage
+// collected with the VM.
+func (m *Machine) orphanDataVolumes(vm *kubevirtv1.VirtualMachine) error {
+	for _, dataVolumeTemplate := range vm.Spec.DataVolumeTemplates {
+		dataVolume := &cdiv1.DataVolume{}
+		key := client.ObjectKey{Namespace: vm.Namespace, Name: dataVolumeTemplate.Name}
+		if err := m.client.Get(m.machineContext, key, dataVolume); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to retrieve DataVolume %s", dataVolumeTemplate.Name)
+		}
+
+		var ownerReferences []metav1.OwnerReference
+		for _, ownerReference := range dataVolume.OwnerReferences {
+			if ownerReference.UID != vm.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		if len(ownerReferences) == len(dataVolume.OwnerReferences) {
+			continue
+		}
+
+		patch := client.MergeFrom(dataVolume.DeepCopy())
+		dataVolume.OwnerReferences = ownerReferences
+		if err := m.client.Patch(m.machineContext, dataVolume, patch); err != nil {
+			return errors.Wrapf(err, "failed to orphan DataVolume %s", dataVolume.Name)
+		}
+		m.machineContext.Logger.Info("retaining the DataVolume of the VM", "DataVolume", dataVolume.Name)
+	}
+
+	return nil
+}
+
+// deleteDataVolumes deletes the DataVolumes of the VM, and so their PVCs. They are usually garbage collected with the
+// VM, but the DataVolumes created before the VM owned them, e.g. by a failed creation, would leak otherwise.
+func (m *Machine) deleteDataVolumes(vm *kubevirtv1.VirtualMachine) error {
+	for _, dataVolumeTemplate := range vm.Spec.DataVolumeTemplates {
+		dataVolume := &cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{Namespace: vm.Namespace, Name: dataVolumeTemplate.Name},
+		}
+		if err := m.client.Delete(m.machineContext, dataVolume, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete DataVolume %s", dataVolume.Name)
+		}
+	}
+
+	return nil
+}
+
+// DrainOptions holds the controller wide settings for draining the tenant cluster nodes.
+type DrainOptions struct {
+	// Tracker keeps track of the drain attempts across reconciliations.
+	Tracker *NodeDrainTracker
+	// PodExclusionSelector selects the pods that are never evicted when draining a node, in addition to the
+	// pods selected by the KubevirtCluster drainPodExclusionSelector.
+	PodExclusionSelector labels.Selector
+	// Recorder records the drain events on the KubevirtMachine.
+	Recorder record.EventRecorder
+	// SkipWaitForDeleteTimeout is how long the drain of an unreachable tenant cluster node waits for the deleted
+	// pods to be gone, unless overridden in the KubevirtMachine spec. Defaults to DefaultSkipWaitForDeleteTimeout.
+	SkipWaitForDeleteTimeout time.Duration
+	// TenantUnreachableTimeout is how long the drain retries while the API server of the tenant cluster is
+	// unreachable, before deleting the VMI without draining the node, unless overridden in the KubevirtMachine spec
+	// or in the drain policy. Defaults to DefaultTenantUnreachableTimeout.
+	TenantUnreachableTimeout time.Duration
+	// ProactiveDrain drains the tenant cluster node as soon as the infra cluster node running its VMI is cordoned,
+	// without waiting for KubeVirt to evacuate the VMI. A VMI that KubeVirt live migrates is left to the migration.
+	ProactiveDrain bool
+	// DryRun only logs and records events for what the drain would do, without touching the tenant cluster nor
+	// deleting the VMI. It can also be enabled per cluster, with the KubevirtCluster drain-dry-run annotation.
+	DryRun bool
+}
+
+// IsDryRun checks if the drain of the nodes of the tenant cluster is a dry run, either for all the clusters or for
+// the given KubevirtCluster.
+func (o DrainOptions) IsDryRun(kubevirtCluster *infrav1.KubevirtCluster) bool {
+	return o.DryRun || (kubevirtCluster != nil && kubevirtCluster.Annotations[infrav1.DrainDryRunAnnotation] == "true")
+}
+
+func (m *Machine) DrainNodeIfNeeded(wrkldClstr workloadcluster.WorkloadCluster, opts DrainOptions) (time.Duration, error) {
+	if m.vmiInstance == nil || !m.shouldGracefulDeleteVMI() {
+		opts.Tracker.DrainDone(m.nodeDrainKey())
+
+		if err := m.uncordonNodeIfNeeded(wrkldClstr); err != nil {
+			return 0, err
+		}
+
+		if _, anntExists := m.machineContext.KubevirtMachine.Annotations[infrav1.VmiDeletionGraceTime]; anntExists {
+			if err := m.removeGracePeriodAnnotation(); err != nil {
+				return 100 * time.Millisecond, err
+			}
+		}
+		return 0, nil
+	}
+
+	if opts.IsDryRun(m.machineContext.KubevirtCluster) {
+		m.reportDrainDryRun(opts)
+		return 0, nil
+	}
+
+	drained, exceeded, unreachable := false, false, false
+	if m.shouldSkipDrain() {
+		m.machineContext.Logger.Info(fmt.Sprintf("DrainNode: the %s annotation is set; deleting the VirtualMachineInstance without draining the node", infrav1.SkipDrainAnnotation))
+	} else {
+		if wait, err := m.waitForDeletionHooks(clusterv1.PreDrainDeleteHookAnnotationPrefix); err != nil || wait {
+			return deletionHooksRetryInterval, err
+		}
+
+		var err error
+		exceeded, err = m.drainGracePeriodExceeded()
+		if err != nil {
+			return 0, err
+		}
+
+		if !exceeded {
+			retryDuration, err := m.drainNode(wrkldClstr, opts)
+			if errors.Is(err, errTenantUnreachableTimeout) {
+				m.machineContext.Logger.Info("DrainNode: the tenant cluster is unreachable; deleting the VirtualMachineInstance without draining the node")
+				unreachable = true
+			} else if err != nil {
+				return 0, err
+			} else if retryDuration > 0 {
+				return retryDuration, nil
+			} else {
+				drained = true
+			}
+		}
+	}
+
+	if wait, err := m.waitForDeletionHooks(clusterv1.PreTerminateDeleteHookAnnotationPrefix); err != nil || wait {
+		return deletionHooksRetryInterval, err
+	}
+
+	// now, when the node is drained (or the deletion grace period has passed), we can delete the VMI
+	propagationPolicy := metav1.DeletePropagationForeground
+	deleteOptions := &client.DeleteOptions{PropagationPolicy: &propagationPolicy}
+	shutdownSkipped := m.setGuestShutdownGracePeriod(opts, deleteOptions)
+	err := m.client.Delete(m.machineContext, m.vmiInstance, deleteOptions)
+	if err != nil {
+		m.machineContext.Logger.Error(err, "failed to delete VirtualMachineInstance")
+		return 0, err
+	}
+	m.recordEvent(opts, corev1.EventTypeNormal, VMIDeletedEventReason, "Deleted the evacuated VirtualMachineInstance %s", m.vmiInstance.Name)
+	opts.Tracker.DrainDone(m.nodeDrainKey())
+
+	if m.machineContext.KubevirtMachine.Spec.DeleteNodeAfterEvacuation {
+		if err = m.setEvacuatedNodeAnnotation(m.tenantNodeName()); err != nil {
+			return 100 * time.Millisecond, err
+		}
+	}
+
+	if _, anntExists := m.machineContext.KubevirtMachine.Annotations[infrav1.VmiDeletionGraceTime]; anntExists {
+		if err = m.removeGracePeriodAnnotation(); err != nil {
+			return 100 * time.Millisecond, err
+		}
+	}
+
+	// set the condition only after patching the KubevirtMachine, as the patch overrides the in-memory status
+	if drained && shutdownSkipped != "" {
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.GuestShutdownSkippedReason, clusterv1.ConditionSeverityInfo,
+			"the node was drained, but the guest OS was not shut down before deleting the VMI: %s", shutdownSkipped)
+	} else if drained {
+		conditions.MarkTrue(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition)
+	} else if exceeded {
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.DrainGracePeriodExceededReason, clusterv1.ConditionSeverityWarning,
+			"the VMI deletion grace period was exceeded before the node was drained")
+	} else if unreachable {
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.TenantClusterUnreachableReason, clusterv1.ConditionSeverityWarning,
+			"the API server of the tenant cluster was unreachable for more than %s; the VMI was deleted without draining the node", m.tenantUnreachableTimeout(opts))
+	}
+
+	// requeue to force reading the VMI again
+	return time.Second * 10, nil
+}
+
+// deletionHooksRetryInterval is how often the removal of the deletion hooks of the Machine is checked, on top of the
+// updates of the Machine.
+const deletionHooksRetryInterval = 20 * time.Second
+
+// waitForDeletionHooks returns true, and reports it in the DrainingSucceeded condition, if the Machine is being
+// deleted and has deletion hooks with the prefix, e.g. set by backup agents quiescing the node, which pause the drain
+// of the tenant cluster node or the deletion of the evacuated VMI until they are removed, as they pause the deletion
+// of the Machine. The hooks are waited for up to the VMI deletion grace period.
+func (m *Machine) waitForDeletionHooks(prefix string) (bool, error) {
+	machine := m.machineContext.Machine
+	if machine == nil || machine.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+
+	var hooks []string
+	for name := range machine.Annotations {
+		if strings.HasPrefix(name, prefix) {
+			hooks = append(hooks, name)
+		}
+	}
+	if len(hooks) == 0 {
+		return false, nil
+	}
+
+	exceeded, err := m.drainGracePeriodExceeded()
+	if err != nil {
+		return false, err
+	}
+	sort.Strings(hooks)
+	if exceeded {
+		m.machineContext.Logger.Info("DrainNode: the VMI deletion grace period is exceeded; ignoring the deletion hooks of the Machine", "hooks", hooks)
+		return false, nil
+	}
+
+	m.machineContext.Logger.Info("DrainNode: waiting for the deletion hooks of the Machine to be removed", "hooks", hooks)
+	conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.WaitingForDeletionHooksReason, clusterv1.ConditionSeverityInfo,
+		"waiting for the deletion hooks %s of the Machine to be removed", strings.Join(hooks, ", "))
+	return true, nil
+}
+
+const removeGracePeriodAnnotationPatch = `[{"op": "remove", "path": "/metadata/annotations/` + infrav1.VmiDeletionGraceTimeEscape + `"}]`
+
+func (m *Machine) removeGracePeriodAnnotation() error {
+	patch := client.RawPatch(types.JSONPatchType, []byte(removeGracePeriodAnnotationPatch))
+
+	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patch); err != nil {
+		return fmt.Errorf("failed to remove the %s annotation to the KubeVirtMachine %s; %w", infrav1.VmiDeletionGraceTime, m.machineContext.KubevirtMachine.Name, err)
+	}
+
+	return nil
+}
+
+// setGuestShutdownGracePeriod sets the deletion grace period of the VMI to the GuestShutdownGracePeriod set in the
+// KubevirtMachine spec or in the drain policy, so that KubeVirt sends an ACPI shutdown to the guest OS, and waits for
+// it to stop for up to the grace period before killing the VM. It returns why the guest OS is not shut down, if the
+// grace period is set but the VMI doesn't run.
+func (m *Machine) setGuestShutdownGracePeriod(opts DrainOptions, deleteOptions *client.DeleteOptions) string {
+	gracePeriod := firstDuration(m.machineContext.KubevirtMachine.Spec.GuestShutdownGracePeriod, m.drainPolicy().GuestShutdownGracePeriod)
+	if gracePeriod <= 0 {
+		return ""
+	}
+
+	if m.vmiInstance.Status.Phase != kubevirtv1.Running {
+		m.machineContext.Logger.Info("DrainNode: the VirtualMachineInstance doesn't run; deleting it without shutting down the guest OS", "phase", m.vmiInstance.Status.Phase)
+		return fmt.Sprintf("the VMI phase is %q", m.vmiInstance.Status.Phase)
+	}
+
+	gracePeriodSeconds := int64(gracePeriod.Seconds())
+	deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
+	m.machineContext.Logger.Info("DrainNode: shutting down the guest OS", "grace period", gracePeriod)
+	m.recordEvent(opts, corev1.EventTypeNormal, GuestShutdownEventReason, "Shutting down the guest OS of VirtualMachineInstance %s, for up to %s", m.vmiInstance.Name, gracePeriod)
+	return ""
+}
+
+func (m *Machine) setEvacuatedNodeAnnotation(nodeName string) error {
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s": "%s"}}}`, infrav1.EvacuatedNodeAnnotation, nodeName)
+	patchRequest := client.RawPatch(types.MergePatchType, []byte(patch))
+
+	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patchRequest); err != nil {
+		return fmt.Errorf("failed to add the %s annotation to the KubeVirtMachine %s; %w", infrav1.EvacuatedNodeAnnotation, m.machineContext.KubevirtMachine.Name, err)
+	}
+
+	return nil
+}
+
+const removeEvacuatedNodeAnnotationPatch = `[{"op": "remove", "path": "/metadata/annotations/` + infrav1.EvacuatedNodeAnnotationEscape + `"}]`
+
+func (m *Machine) removeEvacuatedNodeAnnotation() error {
+	patch := client.RawPatch(types.JSONPatchType, []byte(removeEvacuatedNodeAnnotationPatch))
+
+	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patch); err != nil {
+		return fmt.Errorf("failed to remove the %s annotation from the KubeVirtMachine %s; %w", infrav1.EvacuatedNodeAnnotation, m.machineContext.KubevirtMachine.Name, err)
+	}
+
+	return nil
+}
+
+// DeleteEvacuatedNode deletes the tenant cluster node left NotReady by the deletion of the evacuated VMI. The node
+// is deleted once the VMI is gone, unless the VM already runs again, and re-registered the node.
+func (m *Machine) DeleteEvacuatedNode(wrkldClstr workloadcluster.WorkloadCluster) error {
+	nodeName, found := m.machineContext.KubevirtMachine.Annotations[infrav1.EvacuatedNodeAnnotation]
+	if !found {
+		return nil
+	}
+
+	if m.vmiInstance != nil {
+		// wait for the evacuated VMI to be deleted
+		if m.vmiInstance.DeletionTimestamp != nil {
+			return nil
+		}
+
+		if m.vmiInstance.Status.Phase == kubevirtv1.Running {
+			return m.removeEvacuatedNodeAnnotation()
+		}
+	}
+
+	kubeClient, err := wrkldClstr.GenerateWorkloadClusterK8sClient(m.machineContext)
+	if err != nil {
+		return fmt.Errorf("failed to get client to remote cluster; %w", err)
+	}
+
+	m.machineContext.Logger.Info("deleting the tenant cluster node of the evacuated VirtualMachineInstance", "node name", nodeName)
+	if err = kubeClient.CoreV1().Nodes().Delete(m.machineContext, nodeName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete node %q: %w", nodeName, err)
+	}
+
+	return m.removeEvacuatedNodeAnnotation()
+}
+
+const removeCordonedNodeAnnotationPatch = `[{"op": "remove", "path": "/metadata/annotations/` + infrav1.CordonedNodeAnnotationEscape + `"}]`
+
+func (m *Machine) removeCordonedNodeAnnotation() error {
+	patch := client.RawPatch(types.JSONPatchType, []byte(removeCordonedNodeAnnotationPatch))
+
+	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patch); err != nil {
+		return fmt.Errorf("failed to remove the %s annotation from the KubeVirtMachine %s; %w", infrav1.CordonedNodeAnnotation, m.machineContext.KubevirtMachine.Name, err)
+	}
+
+	return nil
+}
+
+func (m *Machine) setCordonedNodeAnnotation(nodeName string) error {
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s": "%s"}}}`, infrav1.CordonedNodeAnnotation, nodeName)
+	patchRequest := client.RawPatch(types.MergePatchType, []byte(patch))
+
+	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patchRequest); err != nil {
+		return fmt.Errorf("failed to add the %s annotation to the KubeVirtMachine %s; %w", infrav1.CordonedNodeAnnotation, m.machineContext.KubevirtMachine.Name, err)
+	}
+
+	return nil
+}
+
+// uncordonNodeIfNeeded uncordons the tenant cluster node that was cordoned by a previous drain, if the evacuation of
+// the VMI was cancelled in the meantime.
+func (m *Machine) uncordonNodeIfNeeded(wrkldClstr workloadcluster.WorkloadCluster) error {
+	nodeName, found := m.machineContext.KubevirtMachine.Annotations[infrav1.CordonedNodeAnnotation]
+	if !found {
+		return nil
+	}
+
+	// wait for the VMI to run again, if it is being deleted; the node is cordoned until the VMI is back.
+	if m.vmiInstance == nil || m.vmiInstance.DeletionTimestamp != nil || len(m.evacuationNodeName()) > 0 {
+		return nil
+	}
+
+	kubeClient, err := wrkldClstr.GenerateWorkloadClusterK8sClient(m.machineContext)
+	if err != nil {
+		return fmt.Errorf("failed to get client to remote cluster; %w", err)
+	}
+
+	node, err := kubeClient.CoreV1().Nodes().Get(m.machineContext, nodeName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get node %q: %w", nodeName, err)
+	}
+
+	if err == nil {
+		drainer := &kubedrain.Helper{
+			Client: kubeClient,
+			Ctx:    m.machineContext,
+			Out:    writer{m.machineContext.Logger.Info},
+			ErrOut: writer{func(msg string, keysAndValues ...interface{}) {
+				m.machineContext.Logger.Error(nil, msg, keysAndValues...)
+			}},
+		}
+
+		m.machineContext.Logger.Info("DrainNode: the evacuation was cancelled, uncordoning the node", "node name", nodeName)
+		if err = kubedrain.RunCordonOrUncordon(drainer, node, false); err != nil {
+			return errors.Errorf("unable to uncordon node %s: %v", nodeName, err)
+		}
+	}
+
+	return m.removeCordonedNodeAnnotation()
+}
+
+func (m *Machine) shouldGracefulDeleteVMI() bool {
+	if m.vmiInstance.DeletionTimestamp != nil {
+		m.machineContext.Logger.V(4).Info("DrainNode: the virtualMachineInstance is already in deletion process. Nothing to do here")
+		return false
+	}
+
+	// KubeVirt will set the EvacuationNodeName field in case of guest node eviction. If the field is not set, and the
+	// infra cluster node is not cordoned, there is nothing to do.
+	if len(m.evacuationNodeName()) == 0 {
+		m.machineContext.Logger.V(4).Info("DrainNode: the virtualMachineInstance is not marked for deletion. Nothing to do here")
+		return false
+	}
+
+	switch m.EvacuationPolicy() {
+	case infrav1.DrainAndDeleteEvacuationPolicy:
+		return true
+	case infrav1.DeleteMachineEvacuationPolicy:
+		m.machineContext.Logger.V(4).Info("DrainNode: the owner Machine is deleted instead of the virtualMachineInstance. Nothing to do here")
+		return false
+	case infrav1.AnnotateMachineEvacuationPolicy:
+		m.machineContext.Logger.V(4).Info("DrainNode: the owner Machine is annotated for deletion instead of deleting the virtualMachineInstance. Nothing to do here")
+		return false
+	}
+
+	// The infra cluster node is cordoned, and KubeVirt doesn't live migrate the VMI, see CheckInfraNodeCordoned.
+	if len(m.vmiInstance.Status.EvacuationNodeName) == 0 {
+		return true
+	}
+
+	if m.vmiInstance.Spec.EvictionStrategy != nil && *m.vmiInstance.Spec.EvictionStrategy == kubevirtv1.EvictionStrategyExternal {
+		return true
+	}
+
+	if m.vmiInstance.Status.MigrationState != nil && m.vmiInstance.Status.MigrationState.Failed {
+		m.machineContext.Logger.Info("DrainNode: the virtualMachineInstance migration failed; falling back to drain and delete")
+		return true
+	}
+
+	for _, cond := range m.vmiInstance.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceIsMigratable && cond.Status == corev1.ConditionFalse {
+			m.machineContext.Logger.Info("DrainNode: the virtualMachineInstance is not migratable; falling back to drain and delete", "reason", cond.Reason)
+			return true
+		}
+	}
+
+	m.machineContext.Logger.V(4).Info("DrainNode: the virtualMachineInstance is migrated by KubeVirt. Nothing to do here")
+	return false
+}
+
+// UpdateMACAddresses records the MAC addresses of the VMI interfaces in the KubevirtMachine status, to reuse them when
+// the VM is re-created.
+func (m *Machine) UpdateMACAddresses() {
+	if m.vmiInstance == nil {
+		return
+	}
+
+	var macAddresses []infrav1.InterfaceMACAddress
+	for _, iface := range m.vmiInstance.Status.Interfaces {
+		if iface.Name == "" || iface.MAC == "" {
+			continue
+		}
+		macAddresses = append(macAddresses, infrav1.InterfaceMACAddress{Name: iface.Name, MACAddress: iface.MAC})
+	}
+	if len(macAddresses) > 0 {
+		m.machineContext.KubevirtMachine.Status.MACAddresses = macAddresses
+	}
+}
+
+// ReconcileHotplugVolumes requests KubeVirt to hot-plug the hotplug volumes of the KubevirtMachine missing from the VM,
+// and to unplug the volumes it hot-plugged before that were removed from the KubevirtMachine. Nothing is requested
+// while KubeVirt processes the previous requests.
+func (m *Machine) ReconcileHotplugVolumes() error {
+	if m.vmInstance == nil || len(m.vmInstance.Status.VolumeRequests) > 0 {
+		return nil
+	}
+
+	hotplugVolumes := m.machineContext.KubevirtMachine.Spec.HotplugVolumes
+	wanted := sets.New[string]()
+	for _, hotplugVolume := range hotplugVolumes {
+		wanted.Insert(hotplugVolume.Name)
+	}
+	hotplugged := sets.New[string](m.machineContext.KubevirtMachine.Status.HotplugVolumes...)
+
+	var volumes []kubevirtv1.Volume
+	if m.vmInstance.Spec.Template != nil {
+		volumes = m.vmInstance.Spec.Template.Spec.Volumes
+	}
+
+	var requests []kubevirtv1.VirtualMachineVolumeRequest
+	plugged, added := sets.New[string](), sets.New[string]()
+	for _, volume := range volumes {
+		if !isHotpluggable(volume) {
+			continue
+		}
+		plugged.Insert(volume.Name)
+		if hotplugged.Has(volume.Name) && !wanted.Has(volume.Name) {
+			requests = append(requests, kubevirtv1.VirtualMachineVolumeRequest{
+				RemoveVolumeOptions: &kubevirtv1.RemoveVolumeOptions{Name: volume.Name},
+			})
+		}
+	}
+	for _, hotplugVolume := range hotplugVolumes {
+		if plugged.Has(hotplugVolume.Name) {
+			continue
+		}
+		added.Insert(hotplugVolume.Name)
+		requests = append(requests, kubevirtv1.VirtualMachineVolumeRequest{
+			AddVolumeOptions: &kubevirtv1.AddVolumeOptions{
+				Name: hotplugVolume.Name,
+				Disk: &kubevirtv1.Disk{
+					Name:       hotplugVolume.Name,
+					DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusSCSI}},
+					Serial:     hotplugVolume.Serial,
+				},
+				VolumeSource: hotplugVolumeSource(hotplugVolume),
+			},
+		})
+	}
+
+	// only the wanted volumes plugged to the VM, and the ones just requested, are recorded as hot-plugged
+	hotplugged = plugged.Intersection(wanted).Union(added)
+	if len(requests) > 0 {
+		patch := client.MergeFrom(m.vmInstance.DeepCopy())
+		m.vmInstance.Status.VolumeRequests = requests
+		if err := m.client.Status().Patch(m.machineContext, m.vmInstance, patch); err != nil {
+			return fmt.Errorf("failed to request the hotplug of the volumes of the VM; %w", err)
+		}
+	}
+
+	m.machineContext.KubevirtMachine.Status.HotplugVolumes = sets.List(hotplugged)
+	return nil
+}
+
+// isHotpluggable returns whether the volume of the VM is a hot-plugged PVC or DataVolume.
+func isHotpluggable(volume kubevirtv1.Volume) bool {
+	return volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.Hotpluggable ||
+		volume.DataVolume != nil && volume.DataVolume.Hotpluggable
+}
+
+// hotplugVolumeSource returns the source of the hotplug volume, marked as hotpluggable.
+func hotplugVolumeSource(hotplugVolume infrav1.HotplugVolume) *kubevirtv1.HotplugVolumeSource {
+	source := hotplugVolume.HotplugVolumeSource.DeepCopy()
+	if source.PersistentVolumeClaim != nil {
+		source.PersistentVolumeClaim.Hotpluggable = true
+	}
+	if source.DataVolume != nil {
+		source.DataVolume.Hotpluggable = true
+	}
+	return source
+}
+
+// ReconcileMutableFields updates the fields of the existing VM that can change without replacing the machine from the
+// KubevirtMachine: the labels and the annotations of the VM and of its VMI template, which are added or updated but
+// never removed, the node selector and the run strategy. KubeVirt applies the node selector on the next start of the
+// VMI.
+func (m *Machine) ReconcileMutableFields() error {
+	if m.vmInstance == nil || m.vmInstance.Spec.Template == nil {
+		return nil
+	}
+
+	desired := newVirtualMachineFromKubevirtMachine(m.machineContext, m.namespace)
+	vm := m.vmInstance.DeepCopy()
+	vm.Labels = mergeMaps(vm.Labels, desired.Labels)
+	vm.Annotations = mergeMaps(vm.Annotations, desired.Annotations)
+	vm.Spec.Template.ObjectMeta.Labels = mergeMaps(vm.Spec.Template.ObjectMeta.Labels, desired.Spec.Template.ObjectMeta.Labels)
+	vm.Spec.Template.ObjectMeta.Annotations = mergeMaps(vm.Spec.Template.ObjectMeta.Annotations, desired.Spec.Template.ObjectMeta.Annotations)
+	if len(vm.Spec.Template.Spec.NodeSelector) > 0 || len(desired.Spec.Template.Spec.NodeSelector) > 0 {
+		vm.Spec.Template.Spec.NodeSelector = desired.Spec.Template.Spec.NodeSelector
+	}
+	vm.Spec.Running = desired.Spec.Running
+	vm.Spec.RunStrategy = desired.Spec.RunStrategy
+
+	if equality.Semantic.DeepEqual(vm, m.vmInstance) {
+		return nil
+	}
+
+	if err := m.client.Patch(m.machineContext, vm, client.MergeFrom(m.vmInstance)); err != nil {
+		return fmt.Errorf("failed to update the VM; %w", err)
+	}
+	m.machineContext.Logger.Info("updated the labels, annotations, node selector or run strategy of the VM")
+	m.vmInstance = vm
+
+	return nil
+}
+
+// immutableField is a field of the VM whose changes are only applied by replacing the machine.
+type immutableField struct {
+	name  string
+	value interface{}
+}
+
+// immutableFields returns the fields of the VM whose changes are only applied by replacing the machine.
+func immutableFields(vm *kubevirtv1.VirtualMachine) []immutableField {
+	spec := &vm.Spec.Template.Spec
+	return []immutableField{
+		{"instancetype", instancetypeName(vm)},
+		{"preference", preferenceName(vm)},
+		{"dataVolumeTemplates", vm.Spec.DataVolumeTemplates},
+		{"cpu", spec.Domain.CPU},
+		{"memory", spec.Domain.Memory},
+		{"resources", spec.Domain.Resources},
+		{"firmware", spec.Domain.Firmware},
+		{"features", spec.Domain.Features},
+		{"disks", spec.Domain.Devices.Disks},
+		{"interfaces", spec.Domain.Devices.Interfaces},
+		{"gpus", spec.Domain.Devices.GPUs},
+		{"hostDevices", spec.Domain.Devices.HostDevices},
+		{"volumes", spec.Volumes},
+		{"networks", spec.Networks},
+		{"affinity", spec.Affinity},
+		{"tolerations", spec.Tolerations},
+		{"topologySpreadConstraints", spec.TopologySpreadConstraints},
+		{"evictionStrategy", spec.EvictionStrategy},
+		{"priorityClassName", spec.PriorityClassName},
+		{"dnsConfig", spec.DNSConfig},
+	}
+}
+
+// instancetypeName returns the name of the instancetype of the VM, ignoring the revision KubeVirt pins.
+func instancetypeName(vm *kubevirtv1.VirtualMachine) string {
+	if vm.Spec.Instancetype == nil {
+		return ""
+	}
+	return vm.Spec.Instancetype.Name
+}
+
+// preferenceName returns the name of the preference of the VM, ignoring the revision KubeVirt pins.
+func preferenceName(vm *kubevirtv1.VirtualMachine) string {
+	if vm.Spec.Preference == nil {
+		return ""
+	}
+	return vm.Spec.Preference.Name
+}
+
+// UpdateSpecOutOfDateCondition sets the SpecOutOfDate condition of the KubevirtMachine, listing the fields of the VM
+// that differ from the KubevirtMachine and can't be changed in place. The fields KubeVirt sets on the VM, but the
+// KubevirtMachine doesn't, are ignored.
+func (m *Machine) UpdateSpecOutOfDateCondition(ctx gocontext.Context) {
+	if m.vmInstance == nil || m.vmInstance.Spec.Template == nil {
+		return
+	}
+
+	desired := newVirtualMachineFromKubevirtMachine(m.machineContext, m.namespace)
+	m.preferSmartClone(ctx, desired)
+
+	var drifted []string
+	live := immutableFields(m.vmInstance)
+	for i, field := range immutableFields(desired) {
+		if !equality.Semantic.DeepDerivative(field.value, live[i].value) {
+			drifted = append(drifted, field.name)
+		}
+	}
+
+	if len(drifted) == 0 {
+		conditions.Set(m.machineContext.KubevirtMachine, &clusterv1.Condition{
+			Type:   infrav1.SpecOutOfDateCondition,
+			Status: corev1.ConditionFalse,
+		})
+		return
+	}
+	conditions.Set(m.machineContext.KubevirtMachine, &clusterv1.Condition{
+		Type:     infrav1.SpecOutOfDateCondition,
+		Status:   corev1.ConditionTrue,
+		Severity: clusterv1.ConditionSeverityInfo,
+		Reason:   infrav1.ImmutableFieldsChangedReason,
+		Message:  fmt.Sprintf("the changes of %s require replacing the machine", strings.Join(drifted, ", ")),
+	})
+}
+
+// mergeMaps returns the entries of the base map, added or updated with the entries of the overrides.
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := mapCopy(base)
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ReconcileCPUHotplug hot-plugs the CPU sockets added to the VM template of the KubevirtMachine into the VM, if its
+// CPU hotplug was enabled when it was created. KubeVirt doesn't support unplugging the sockets, nor plugging more than
+// the maximum sockets of the VM.
+func (m *Machine) ReconcileCPUHotplug() error {
+	if !m.machineContext.CPUHotplug || m.vmInstance == nil || m.vmInstance.Spec.Template == nil {
+		return nil
+	}
+	if features := m.vmInstance.Spec.LiveUpdateFeatures; features == nil || features.CPU == nil {
+		return nil
+	}
+
+	vmiTemplate := m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.CPU == nil || vmiTemplate.Spec.Domain.CPU.Sockets == 0 {
+		return nil
+	}
+	wanted := vmiTemplate.Spec.Domain.CPU.Sockets
+
+	cpu := m.vmInstance.Spec.Template.Spec.Domain.CPU
+	current := uint32(1)
+	if cpu != nil && cpu.Sockets > 0 {
+		current = cpu.Sockets
+	}
+
+	switch {
+	case wanted == current:
+		return nil
+	case wanted < current:
+		m.machineContext.Logger.Info("the CPU sockets of the VM can't be unplugged", "sockets", current, "wanted sockets", wanted)
+		return nil
+	case maxSockets(m.vmInstance) > 0 && wanted > maxSockets(m.vmInstance):
+		m.machineContext.Logger.Info("the CPU sockets of the VM can't be hot-plugged beyond its maximum sockets", "max sockets", maxSockets(m.vmInstance), "wanted sockets", wanted)
+		return nil
+	}
+
+	patch := client.MergeFrom(m.vmInstance.DeepCopy())
+	if m.vmInstance.Spec.Template.Spec.Domain.CPU == nil {
+		m.vmInstance.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{}
+	}
+	m.vmInstance.Spec.Template.Spec.Domain.CPU.Sockets = wanted
+	if err := m.client.Patch(m.machineContext, m.vmInstance, patch); err != nil {
+		return fmt.Errorf("failed to hot-plug the CPU sockets of the VM; %w", err)
+	}
+	m.machineContext.Logger.Info("hot-plugging the CPU sockets of the VM", "sockets", current, "wanted sockets", wanted)
+
+	return nil
+}
+
+// maxSockets returns the maximum CPU sockets of the VM, or 0 if KubeVirt picks it.
+func maxSockets(vm *kubevirtv1.VirtualMachine) uint32 {
+	if maxSockets := vm.Spec.LiveUpdateFeatures.CPU.MaxSockets; maxSockets != nil {
+		return *maxSockets
+	}
+	if cpu := vm.Spec.Template.Spec.Domain.CPU; cpu != nil {
+		return cpu.MaxSockets
+	}
+	return 0
+}
+
+// ReconcileMemoryHotplug hot-plugs the guest memory added to the KubevirtMachine, through its memory or its VM template,
+// into the VM, along with the memory request and limit derived from it, if its memory hotplug was enabled when it was
+// created, and reports the desired and applied guest memory in the KubevirtMachine status and MemoryHotplugSucceeded
+// condition. KubeVirt doesn't support decreasing the guest memory, nor increasing it beyond the maximum guest memory of
+// the VM.
+func (m *Machine) ReconcileMemoryHotplug() error {
+	if !m.machineContext.MemoryHotplug || m.vmInstance == nil || m.vmInstance.Spec.Template == nil {
+		return nil
+	}
+	if features := m.vmInstance.Spec.LiveUpdateFeatures; features == nil || features.Memory == nil {
+		return nil
+	}
+
+	vmiTemplate := m.machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil {
+		return nil
+	}
+	// the VM was sized by the memory of the KubevirtMachine on top of its VM template
+	desired := vmiTemplate.Spec.DeepCopy()
+	setMemory(desired, m.machineContext.KubevirtMachine.Spec.Memory)
+	if m.machineContext.KubevirtMachine.Spec.QOSClass == corev1.PodQOSGuaranteed {
+		setGuaranteedQOS(desired)
+	}
+	if desired.Domain.Memory == nil || desired.Domain.Memory.Guest == nil {
+		return nil
+	}
+	wanted := desired.Domain.Memory.Guest.DeepCopy()
+
+	status := &infrav1.GuestMemoryStatus{Desired: &wanted}
+	if m.vmiInstance != nil && m.vmiInstance.Status.Memory != nil && m.vmiInstance.Status.Memory.GuestCurrent != nil {
+		applied := m.vmiInstance.Status.Memory.GuestCurrent.DeepCopy()
+		status.Applied = &applied
+	}
+	m.machineContext.KubevirtMachine.Status.GuestMemory = status
+
+	var current *resource.Quantity
+	if memory := m.vmInstance.Spec.Template.Spec.Domain.Memory; memory != nil {
+		current = memory.Guest
+	}
+
+	switch maxGuest := maxGuestMemory(m.vmInstance); {
+	case current != nil && wanted.Cmp(*current) < 0:
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.MemoryHotplugSucceededCondition, infrav1.MemoryHotplugNotSupportedReason, clusterv1.ConditionSeverityWarning,
+			"the guest memory of the VM can't be decreased from %s to %s without replacing the machine", current.String(), wanted.String())
+		return nil
+	case maxGuest != nil && wanted.Cmp(*maxGuest) > 0:
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.MemoryHotplugSucceededCondition, infrav1.MemoryHotplugNotSupportedReason, clusterv1.ConditionSeverityWarning,
+			"the guest memory of the VM can't be hot-plugged beyond its maximum guest memory %s without replacing the machine", maxGuest.String())
+		return nil
+	case current == nil || wanted.Cmp(*current) > 0:
+		patch := client.MergeFrom(m.vmInstance.DeepCopy())
+		if m.vmInstance.Spec.Template.Spec.Domain.Memory == nil {
+			m.vmInstance.Spec.Template.Spec.Domain.Memory = &kubevirtv1.Memory{}
+		}
+		m.vmInstance.Spec.Template.Spec.Domain.Memory.Guest = &wanted
+		setMemoryResources(&m.vmInstance.Spec.Template.Spec.Domain.Resources, &desired.Domain.Resources)
+		if err := m.client.Patch(m.machineContext, m.vmInstance, patch); err != nil {
+			return fmt.Errorf("failed to hot-plug the guest memory of the VM; %w", err)
+		}
+		m.machineContext.Logger.Info("hot-plugging the guest memory of the VM", "wanted memory", wanted.String())
+	}
+
+	if status.Applied == nil || status.Applied.Cmp(wanted) != 0 {
+		applied := "none"
+		if status.Applied != nil {
+			applied = status.Applied.String()
+		}
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.MemoryHotplugSucceededCondition, infrav1.MemoryHotplugInProgressReason, clusterv1.ConditionSeverityInfo,
+			"the guest memory of the VM is being hot-plugged; applied: %s, desired: %s", applied, wanted.String())
+		return nil
+	}
+	conditions.MarkTrue(m.machineContext.KubevirtMachine, infrav1.MemoryHotplugSucceededCondition)
+
+	return nil
+}
+
+// setMemoryResources sets the memory request and limit of the virt-launcher pod of the VM to the desired ones, as the
+// overcommit of the memory and the Guaranteed QoS class derive them from the guest memory.
+func setMemoryResources(resources, desired *kubevirtv1.ResourceRequirements) {
+	if request, found := desired.Requests[corev1.ResourceMemory]; found {
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[corev1.ResourceMemory] = request.DeepCopy()
+	}
+	if limit, found := desired.Limits[corev1.ResourceMemory]; found {
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+		resources.Limits[corev1.ResourceMemory] = limit.DeepCopy()
+	}
+}
+
+// maxGuestMemory returns the maximum guest memory of the VM, or nil if KubeVirt picks it.
+func maxGuestMemory(vm *kubevirtv1.VirtualMachine) *resource.Quantity {
+	if maxGuest := vm.Spec.LiveUpdateFeatures.Memory.MaxGuest; maxGuest != nil {
+		return maxGuest
+	}
+	if memory := vm.Spec.Template.Spec.Domain.Memory; memory != nil {
+		return memory.MaxGuest
+	}
+	return nil
+}
+
+// UpdateMigrationStatus reports the state of the last live migration of the VMI in the KubevirtMachine status and
+// VMMigrationSucceeded condition.
+func (m *Machine) UpdateMigrationStatus() error {
+	if m.vmiInstance == nil {
+		return nil
+	}
+
+	migrations := &kubevirtv1.VirtualMachineInstanceMigrationList{}
+	if err := m.client.List(m.machineContext, migrations, client.InNamespace(m.vmiInstance.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the VirtualMachineInstanceMigrations; %w", err)
+	}
+
+	var last *kubevirtv1.VirtualMachineInstanceMigration
+	for i := range migrations.Items {
+		migration := &migrations.Items[i]
+		if migration.Spec.VMIName != m.vmiInstance.Name {
+			continue
+		}
+		if last == nil || last.CreationTimestamp.Before(&migration.CreationTimestamp) {
+			last = migration
+		}
+	}
+	if last == nil {
+		return nil
+	}
+
+	status := &infrav1.VirtualMachineMigrationStatus{
+		Name:  last.Name,
+		Phase: string(last.Status.Phase),
+	}
+	if state := m.vmiInstance.Status.MigrationState; state != nil && state.MigrationUID == last.UID {
+		status.SourceNode = state.SourceNode
+		status.TargetNode = state.TargetNode
+		status.StartTime = state.StartTimestamp
+		status.EndTime = state.EndTimestamp
+	}
+	m.machineContext.KubevirtMachine.Status.Migration = status
+
+	switch last.Status.Phase {
+	case kubevirtv1.MigrationSucceeded:
+		conditions.MarkTrue(m.machineContext.KubevirtMachine, infrav1.VMMigrationSucceededCondition)
+	case kubevirtv1.MigrationFailed:
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.VMMigrationSucceededCondition, infrav1.VMMigrationFailedReason, clusterv1.ConditionSeverityWarning,
+			"live migration %s of the VM from infra node %q failed", last.Name, status.SourceNode)
+	default:
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.VMMigrationSucceededCondition, infrav1.VMMigratingReason, clusterv1.ConditionSeverityInfo,
+			"the VM is being live migrated to infra node %q; migration %s phase: %s", status.TargetNode, last.Name, status.Phase)
+	}
+
+	return nil
+}
+
+// EvacuationRequested checks if KubeVirt asked to evacuate the VMI from its infra cluster node, or if the infra cluster
+// node was found cordoned by CheckInfraNodeCordoned.
+func (m *Machine) EvacuationRequested() bool {
+	return m.vmiInstance != nil && m.vmiInstance.DeletionTimestamp == nil && len(m.evacuationNodeName()) > 0
+}
+
+// CheckInfraNodeCordoned checks if the infra cluster node running the VMI is cordoned, e.g. for maintenance. If so, the
+// VMI is evacuated as if KubeVirt asked to, so the tenant cluster node is drained before the infra cluster node is.
+// A VMI that KubeVirt live migrates is left to the migration, under the Migrate evacuation policy.
+func (m *Machine) CheckInfraNodeCordoned() error {
+	if m.vmiInstance == nil || m.vmiInstance.DeletionTimestamp != nil || m.vmiInstance.Status.NodeName == "" {
+		return nil
+	}
+
+	node := &corev1.Node{}
+	if err := m.client.Get(m.machineContext, client.ObjectKey{Name: m.vmiInstance.Status.NodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get the infra cluster node %s; %w", m.vmiInstance.Status.NodeName, err)
+	}
+
+	if !node.Spec.Unschedulable {
+		return nil
+	}
+
+	if m.EvacuationPolicy() == infrav1.MigrateEvacuationPolicy && m.isLiveMigratable() {
+		m.machineContext.Logger.V(4).Info("the infra cluster node is cordoned, leaving the live migration of the virtualMachineInstance to KubeVirt", "infra node", node.Name)
+		return nil
+	}
+
+	m.machineContext.Logger.Info("the infra cluster node is cordoned, evacuating the virtualMachineInstance", "infra node", node.Name)
+	m.proactiveEvacuationNode = node.Name
+	return nil
+}
+
+// isLiveMigratable checks if KubeVirt live migrates the VMI when its infra cluster node is drained, according to the
+// eviction strategy of the VMI, or else of the KubeVirt configuration of the infra cluster.
+func (m *Machine) isLiveMigratable() bool {
+	strategy := m.vmiInstance.Spec.EvictionStrategy
+	if strategy == nil {
+		if kubevirt := m.infraKubeVirt(m.machineContext); kubevirt != nil {
+			strategy = kubevirt.Spec.Configuration.EvictionStrategy
+		}
+	}
+	if strategy == nil || (*strategy != kubevirtv1.EvictionStrategyLiveMigrate && *strategy != kubevirtv1.EvictionStrategyLiveMigrateIfPossible) {
+		return false
+	}
+
+	for _, cond := range m.vmiInstance.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceIsMigratable && cond.Status == corev1.ConditionFalse {
+			return false
+		}
+	}
+	return true
+}
+
+// tenantNodeName returns the name of the tenant cluster node of the VM: the node referenced by the owner Machine, or
+// else the name of the KubevirtMachine, the node is named after.
+func (m *Machine) tenantNodeName() string {
+	if machine := m.machineContext.Machine; machine != nil && machine.Status.NodeRef != nil && machine.Status.NodeRef.Name != "" {
+		return machine.Status.NodeRef.Name
+	}
+	return m.machineContext.KubevirtMachine.Name
+}
+
+// InfraNodeTopology returns the topology zone and region labels of the infra cluster node running the VMI, or nil if
+// the VMI is not scheduled yet.
+func (m *Machine) InfraNodeTopology() (map[string]string, error) {
+	if m.vmiInstance == nil || m.vmiInstance.Status.NodeName == "" {
+		return nil, nil
+	}
+
+	node := &corev1.Node{}
+	if err := m.client.Get(m.machineContext, client.ObjectKey{Name: m.vmiInstance.Status.NodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the infra cluster node %s; %w", m.vmiInstance.Status.NodeName, err)
+	}
+
+	topology := map[string]string{}
+	for _, label := range []string{corev1.LabelTopologyZone, corev1.LabelTopologyRegion} {
+		if value, ok := node.Labels[label]; ok {
+			topology[label] = value
+		}
+	}
+
+	return topology, nil
+}
+
+// evacuationNodeName returns the name of the node the VMI is evacuated from, or an empty string if it is not.
+func (m *Machine) evacuationNodeName() string {
+	if len(m.vmiInstance.Status.EvacuationNodeName) > 0 {
+		return m.vmiInstance.Status.EvacuationNodeName
+	}
+	return m.proactiveEvacuationNode
+}
+
+// EvacuationPolicy returns the evacuation policy set in the KubevirtMachine spec, or in the drain policy: the
+// DeschedulerEvictionPolicy if the VMI is evicted by the descheduler, the EvacuationPolicy otherwise.
+func (m *Machine) EvacuationPolicy() infrav1.EvacuationPolicy {
+	spec, drainPolicy := m.machineContext.KubevirtMachine.Spec, m.drainPolicy()
+	policies := []infrav1.EvacuationPolicy{spec.EvacuationPolicy, drainPolicy.EvacuationPolicy}
+	if m.isDeschedulerEviction() {
+		policies = []infrav1.EvacuationPolicy{spec.DeschedulerEvictionPolicy, drainPolicy.DeschedulerEvictionPolicy}
+	}
+
+	for _, policy := range policies {
+		if policy != "" {
+			return policy
+		}
+	}
+	return infrav1.MigrateEvacuationPolicy
+}
+
+// isDeschedulerEviction checks if KubeVirt asked to evacuate the VMI while its infra cluster node is still
+// schedulable. This is the case when the VMI is evicted by the descheduler, rather than by the drain of the infra
+// cluster node.
+func (m *Machine) isDeschedulerEviction() bool {
+	if m.vmiInstance == nil || len(m.vmiInstance.Status.EvacuationNodeName) == 0 {
+		return false
+	}
+
+	node := &corev1.Node{}
+	if err := m.client.Get(m.machineContext, client.ObjectKey{Name: m.vmiInstance.Status.EvacuationNodeName}, node); err != nil {
+		// the infra cluster node can't be checked, so the evacuation is handled as an infra cluster node drain
+		m.machineContext.Logger.V(4).Info("failed to get the infra cluster node", "infra node", m.vmiInstance.Status.EvacuationNodeName, "error", err.Error())
+		return false
+	}
+
+	return !node.Spec.Unschedulable
+}
+
+// shouldSkipDrain checks if either the KubevirtMachine or the VMI asks to delete the VMI without draining the node.
+func (m *Machine) shouldSkipDrain() bool {
+	if _, found := m.machineContext.KubevirtMachine.Annotations[infrav1.SkipDrainAnnotation]; found {
+		return true
+	}
+	_, found := m.vmiInstance.Annotations[infrav1.SkipDrainAnnotation]
+	return found
+}
+
+// wait vmiDeletionGracePeriod to the node to be drained. If this time had passed, don't wait anymore.
+func (m *Machine) drainGracePeriodExceeded() (bool, error) {
+	if graceTime, found := m.machineContext.KubevirtMachine.Annotations[infrav1.VmiDeletionGraceTime]; found {
+		deletionGraceTime, err := time.Parse(time.RFC3339, graceTime)
+		if err != nil { // wrong format - rewrite
+			if err = m.setVmiDeletionGraceTime(); err != nil {
+				return false, err
+			}
+		} else {
+			return time.Now().UTC().After(deletionGraceTime), nil
+		}
+	} else {
+		if err := m.setVmiDeletionGraceTime(); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func (m *Machine) setVmiDeletionGraceTime() error {
+	m.machineContext.Logger.Info(fmt.Sprintf("setting the %s annotation", infrav1.VmiDeletionGraceTime))
+	graceTime := time.Now().Add(m.vmiDeletionGracePeriod()).UTC().Format(time.RFC3339)
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s": "%s"}}}`, infrav1.VmiDeletionGraceTime, graceTime)
+	patchRequest := client.RawPatch(types.MergePatchType, []byte(patch))
+
+	if err := m.client.Patch(m.machineContext, m.machineContext.KubevirtMachine, patchRequest); err != nil {
+		return fmt.Errorf("failed to add the %s annotation to the KubeVirtMachine %s; %w", infrav1.VmiDeletionGraceTime, m.machineContext.KubevirtMachine.Name, err)
+	}
+
+	return nil
+}
+
+// vmiDeletionGracePeriod returns how long to try draining the node before giving up and deleting the VMI.
+// The owner Machine's NodeDrainTimeout is honored when set, otherwise vmiDeleteGraceTimeoutDurationSeconds is used.
+func (m *Machine) vmiDeletionGracePeriod() time.Duration {
+	if machine := m.machineContext.Machine; machine != nil && machine.Spec.NodeDrainTimeout != nil && machine.Spec.NodeDrainTimeout.Duration > 0 {
+		return machine.Spec.NodeDrainTimeout.Duration
+	}
+	return vmiDeleteGraceTimeoutDurationSeconds * time.Second
+}
+
+// This functions drains a node from a tenant cluster.
+// The function returns 3 values:
+// * drain done - boolean
+// * retry time, or 0 if not needed
+// * error - to be returned if we want to retry
+func (m *Machine) drainNode(wrkldClstr workloadcluster.WorkloadCluster, opts DrainOptions) (time.Duration, error) {
+	tracker := opts.Tracker
+	drainKey := m.nodeDrainKey()
+	if maxDrains := m.maxConcurrentDrains(); !tracker.StartDrain(drainKey, maxDrains) {
+		inProgress := tracker.DrainsInProgress(drainKey.Cluster)
+		m.machineContext.Logger.Info("DrainNode: too many nodes of the cluster are being drained, waiting", "max concurrent drains", maxDrains, "drains in progress", inProgress)
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.WaitingForConcurrentDrainsReason, clusterv1.ConditionSeverityInfo,
+			"waiting for %d other node drain(s) of the cluster to complete", inProgress)
+		return 10 * time.Second, nil
+	}
+
+	kubeClient, err := wrkldClstr.GenerateWorkloadClusterK8sClient(m.machineContext)
+	if err != nil {
+		// the client can't be created either while the API server of the tenant cluster is down, e.g. when the
+		// ClusterCacheTracker can't connect to it, so the tenant unreachable timeout applies as well.
+		timeout := m.tenantUnreachableTimeout(opts)
+		if unreachableFor := tracker.TenantUnreachable(drainKey); unreachableFor >= timeout {
+			return 0, fmt.Errorf("unable to get a client to the tenant cluster for %s: %v: %w", unreachableFor.Round(time.Second), err, errTenantUnreachableTimeout)
+		}
+		// the kubeconfig secret may be rotated; retry with the new credentials, also when the secret changes.
+		retryInterval, failures := tracker.DrainFailed(drainKey, m.drainRetryInterval())
+		m.machineContext.Logger.Error(err, "Error creating a remote client while draining the node, retrying", "retry interval", retryInterval, "failures", failures)
+		conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.TenantClusterClientFailedReason, clusterv1.ConditionSeverityWarning,
+			"failed to get a client to the tenant cluster, retrying in %s; the VMI will be deleted without draining the node after %s: %v", retryInterval, timeout, err)
+		return retryInterval, nil
+	}
+
+	nodeName := m.tenantNodeName()
+	node, err := kubeClient.CoreV1().Nodes().Get(m.machineContext, nodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// If an admin deletes the node directly, we'll end up here.
+			m.machineContext.Logger.Error(err, "Could not find node from noderef, it may have already been deleted")
+			tracker.DrainDone(drainKey)
+			return 0, nil
+		}
+		if isTenantUnreachable(err) {
+			timeout := m.tenantUnreachableTimeout(opts)
+			if unreachableFor := tracker.TenantUnreachable(drainKey); unreachableFor >= timeout {
+				return 0, fmt.Errorf("unable to get node %q for %s: %w", nodeName, unreachableFor.Round(time.Second), errTenantUnreachableTimeout)
+			}
+			conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.TenantClusterUnreachableReason, clusterv1.ConditionSeverityWarning,
+				"the API server of the tenant cluster is unreachable; the VMI will be deleted without draining node %s after %s: %v", nodeName, timeout, err)
+		}
+		return 0, fmt.Errorf("unable to get node %q: %w", nodeName, err)
+	}
+	tracker.TenantReachable(drainKey)
+
+	podFilters, err := m.drainPodFilters(opts.PodExclusionSelector)
+	if err != nil {
+		return 0, err
 	}
 
 	drainer := &kubedrain.Helper{
 		Client:              kubeClient,
 		Ctx:                 m.machineContext,
-		Force:               true,
+		Force:               m.drainForce(),
 		IgnoreAllDaemonSets: true,
 		DeleteEmptyDirData:  true,
-		GracePeriodSeconds:  -1,
-		// If a pod is not evicted in 20 seconds, retry the eviction next time the
+		GracePeriodSeconds:  m.podEvictionGracePeriodSeconds(),
+		// If a pod is not evicted in time, retry the eviction next time the
 		// machine gets reconciled again (to allow other machines to be reconciled).
-		Timeout: 20 * time.Second,
+		Timeout:           m.drainTimeout(),
+		AdditionalFilters: podFilters,
 		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
 			verbStr := "Deleted"
 			if usingEviction {
@@ -444,6 +2118,8 @@ func (m *Machine) drainNode(wrkldClstr workloadcluster.WorkloadCluster) (time.Du
 			}
 			m.machineContext.Logger.Info(fmt.Sprintf("%s pod from Node", verbStr),
 				"pod", fmt.Sprintf("%s/%s", pod.Name, pod.Namespace))
+			m.recordEvent(opts, corev1.EventTypeNormal, PodEvictedEventReason, "%s pod %s/%s from node %s", verbStr, pod.Namespace, pod.Name, nodeName)
+			podsEvictedTotal.With(clusterLabels(drainKey.Cluster)).Inc()
 		},
 		Out: writer{m.machineContext.Logger.Info},
 		ErrOut: writer{func(msg string, keysAndValues ...interface{}) {
@@ -453,7 +2129,15 @@ func (m *Machine) drainNode(wrkldClstr workloadcluster.WorkloadCluster) (time.Du
 
 	if noderefutil.IsNodeUnreachable(node) {
 		// When the node is unreachable and some pods are not evicted for as long as this timeout, we ignore them.
-		drainer.SkipWaitForDeleteTimeoutSeconds = 60 * 5 // 5 minutes
+		drainer.SkipWaitForDeleteTimeoutSeconds = int(m.skipWaitForDeleteTimeout(opts).Seconds())
+	}
+
+	// remember the node was cordoned by the controller, to uncordon it if the evacuation is cancelled. A node that was
+	// already cordoned before the drain is left as is.
+	if _, found := m.machineContext.KubevirtMachine.Annotations[infrav1.CordonedNodeAnnotation]; !found && !node.Spec.Unschedulable {
+		if err = m.setCordonedNodeAnnotation(nodeName); err != nil {
+			return 0, err
+		}
 	}
 
 	if err = kubedrain.RunCordonOrUncordon(drainer, node, true); err != nil {
@@ -462,16 +2146,277 @@ func (m *Machine) drainNode(wrkldClstr workloadcluster.WorkloadCluster) (time.Du
 		return 0, errors.Errorf("unable to cordon node %s: %v", nodeName, err)
 	}
 
+	if m.podDisruptionBudgetTimeoutExceeded(tracker, drainKey) {
+		m.machineContext.Logger.Info("DrainNode: the PodDisruptionBudget timeout is exceeded, deleting the pods instead of evicting them", "node name", nodeName)
+		drainer.DisableEviction = true
+	}
+
+	m.recordEvent(opts, corev1.EventTypeNormal, DrainStartedEventReason, "Draining node %s", nodeName)
 	if err = kubedrain.RunNodeDrain(drainer, node.Name); err != nil {
-		// Machine will be re-reconciled after a drain failure.
-		m.machineContext.Logger.Error(err, "Drain failed, retry in a second", "node name", nodeName)
-		return time.Second, nil
+		// Machine will be re-reconciled after a drain failure. Back off exponentially on repeated failures, to
+		// avoid hammering the tenant cluster with evictions that keep failing.
+		retryInterval, failures := tracker.DrainFailed(drainKey, m.drainRetryInterval())
+		drainsFailedTotal.With(clusterLabels(drainKey.Cluster)).Inc()
+		m.machineContext.Logger.Error(err, "Drain failed, retrying", "node name", nodeName, "retry interval", retryInterval, "failures", failures)
+		m.recordEvent(opts, corev1.EventTypeWarning, DrainFailedEventReason, "Failed to drain node %s, retrying in %s: %v", nodeName, retryInterval, err)
+		if blockingPDBs := m.blockingPodDisruptionBudgets(drainer, nodeName); len(blockingPDBs) > 0 {
+			conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.PodDisruptionBudgetBlockedReason, clusterv1.ConditionSeverityWarning,
+				"the eviction of the pods of node %s is blocked by the PodDisruptionBudgets %s; retrying in %s", nodeName, strings.Join(blockingPDBs, ", "), retryInterval)
+		} else if isDrainTimeout(err) {
+			conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.DrainingReason, clusterv1.ConditionSeverityInfo,
+				"draining node %s, pods are still being evicted; retrying in %s", nodeName, retryInterval)
+		} else {
+			conditions.MarkFalse(m.machineContext.KubevirtMachine, infrav1.DrainingSucceededCondition, infrav1.PodEvictionFailedReason, clusterv1.ConditionSeverityWarning,
+				"failed to drain node %s %d time(s), retrying in %s: %v", nodeName, failures, retryInterval, err)
+		}
+		return retryInterval, nil
 	}
 
 	m.machineContext.Logger.Info("Drain successful", "node name", nodeName)
+	m.recordEvent(opts, corev1.EventTypeNormal, DrainSucceededEventReason, "Drained node %s", nodeName)
+	drainsSucceededTotal.With(clusterLabels(drainKey.Cluster)).Inc()
+	if startTime, found := tracker.DrainStartTime(drainKey); found {
+		drainDurationSeconds.With(clusterLabels(drainKey.Cluster)).Observe(time.Since(startTime).Seconds())
+	}
+	tracker.DrainDone(drainKey)
 	return 0, nil
 }
 
+// podDisruptionBudgetTimeoutExceeded checks if the drain in progress respected the PodDisruptionBudgets for longer
+// than the PodDisruptionBudgetTimeout set in the KubevirtMachine spec or in the drain policy.
+func (m *Machine) podDisruptionBudgetTimeoutExceeded(tracker *NodeDrainTracker, key NodeKey) bool {
+	timeout := firstDuration(m.machineContext.KubevirtMachine.Spec.PodDisruptionBudgetTimeout, m.drainPolicy().PodDisruptionBudgetTimeout)
+	if timeout <= 0 {
+		return false
+	}
+
+	startTime, found := tracker.DrainStartTime(key)
+	return found && time.Since(startTime) > timeout
+}
+
+// blockingPodDisruptionBudgets returns the namespaced names of the PodDisruptionBudgets that don't allow the eviction
+// of the pods left on the node.
+func (m *Machine) blockingPodDisruptionBudgets(drainer *kubedrain.Helper, nodeName string) []string {
+	podList, errs := drainer.GetPodsForDeletion(nodeName)
+	if len(errs) > 0 || podList == nil {
+		return nil
+	}
+	pods := podList.Pods()
+	if len(pods) == 0 {
+		return nil
+	}
+
+	pdbs, err := drainer.Client.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(m.machineContext, metav1.ListOptions{})
+	if err != nil {
+		m.machineContext.Logger.Error(err, "DrainNode: failed to list the PodDisruptionBudgets")
+		return nil
+	}
+
+	var blocking []string
+	for _, pdb := range pdbs.Items {
+		if pdb.Status.DisruptionsAllowed > 0 || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Namespace == pdb.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+				blocking = append(blocking, pdb.Namespace+"/"+pdb.Name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(blocking)
+	return blocking
+}
+
+// drainPodFilters returns the drain filters skipping the pods selected either by the controller wide selector, by the
+// KubevirtCluster drainPodExclusionSelector, or by the drain policy podExclusionSelector.
+func (m *Machine) drainPodFilters(selector labels.Selector) ([]kubedrain.PodFilter, error) {
+	var selectors []labels.Selector
+	if selector != nil && !selector.Empty() {
+		selectors = append(selectors, selector)
+	}
+
+	if kubevirtCluster := m.machineContext.KubevirtCluster; kubevirtCluster != nil && kubevirtCluster.Spec.DrainPodExclusionSelector != nil {
+		clusterSelector, err := metav1.LabelSelectorAsSelector(kubevirtCluster.Spec.DrainPodExclusionSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid drainPodExclusionSelector in KubevirtCluster %s; %w", kubevirtCluster.Name, err)
+		}
+		if !clusterSelector.Empty() {
+			selectors = append(selectors, clusterSelector)
+		}
+	}
+
+	if drainPolicy := m.machineContext.DrainPolicy; drainPolicy != nil && drainPolicy.Spec.PodExclusionSelector != nil {
+		policySelector, err := metav1.LabelSelectorAsSelector(drainPolicy.Spec.PodExclusionSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid podExclusionSelector in KubevirtDrainPolicy %s; %w", drainPolicy.Name, err)
+		}
+		if !policySelector.Empty() {
+			selectors = append(selectors, policySelector)
+		}
+	}
+
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+
+	return []kubedrain.PodFilter{
+		func(pod corev1.Pod) kubedrain.PodDeleteStatus {
+			for _, selector := range selectors {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					return kubedrain.MakePodDeleteStatusSkip()
+				}
+			}
+			return kubedrain.MakePodDeleteStatusOkay()
+		},
+	}, nil
+}
+
+// isTenantUnreachable checks if a request to the tenant cluster failed because its API server could not be reached,
+// rather than because the API server rejected the request.
+func isTenantUnreachable(err error) bool {
+	if apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) {
+		return true
+	}
+
+	var status apierrors.APIStatus
+	return !errors.As(err, &status)
+}
+
+// isDrainTimeout checks if a drain attempt failed only because the pods were not evicted before the drain timeout.
+func isDrainTimeout(err error) bool {
+	// evicted pods not deleted in time fail with a wait timeout; the eviction itself fails with a global timeout.
+	return wait.Interrupted(err) || strings.Contains(err.Error(), "global timeout reached")
+}
+
+// reportDrainDryRun logs and records an event for what the drain of the evacuated VMI would do.
+func (m *Machine) reportDrainDryRun(opts DrainOptions) {
+	msg := fmt.Sprintf("Dry run: would cordon and drain node %s, then delete the evacuated VirtualMachineInstance %s", m.tenantNodeName(), m.vmiInstance.Name)
+	if m.shouldSkipDrain() {
+		msg = fmt.Sprintf("Dry run: would delete the evacuated VirtualMachineInstance %s without draining node %s", m.vmiInstance.Name, m.tenantNodeName())
+	}
+
+	m.machineContext.Logger.Info(msg)
+	m.recordEvent(opts, corev1.EventTypeNormal, DrainDryRunEventReason, msg)
+}
+
+// recordEvent records an event on the KubevirtMachine, if an event recorder is set.
+func (m *Machine) recordEvent(opts DrainOptions, eventType, reason, messageFmt string, args ...interface{}) {
+	if opts.Recorder == nil {
+		return
+	}
+	opts.Recorder.Eventf(m.machineContext.KubevirtMachine, eventType, reason, messageFmt, args...)
+}
+
+// nodeDrainKey returns the key identifying the tenant cluster node in the NodeDrainTracker.
+func (m *Machine) nodeDrainKey() NodeKey {
+	return NodeDrainKey(m.machineContext.KubevirtMachine)
+}
+
+// NodeDrainKey returns the key identifying the tenant cluster node of the KubevirtMachine in the NodeDrainTracker. The
+// tenant cluster node is named after the KubevirtMachine.
+func NodeDrainKey(kubevirtMachine *infrav1.KubevirtMachine) NodeKey {
+	return NodeKey{
+		Cluster: types.NamespacedName{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Labels[clusterv1.ClusterNameLabel]},
+		Node:    kubevirtMachine.Name,
+	}
+}
+
+// drainPolicy returns the spec of the KubevirtDrainPolicy referenced by the KubevirtCluster, or an empty spec if there
+// is none.
+func (m *Machine) drainPolicy() infrav1.KubevirtDrainPolicySpec {
+	if m.machineContext.DrainPolicy == nil {
+		return infrav1.KubevirtDrainPolicySpec{}
+	}
+	return m.machineContext.DrainPolicy.Spec
+}
+
+// maxConcurrentDrains returns the maximum number of nodes of the tenant cluster drained at the same time, as set in
+// the KubevirtCluster spec, or in the drain policy.
+func (m *Machine) maxConcurrentDrains() int {
+	if kubevirtCluster := m.machineContext.KubevirtCluster; kubevirtCluster != nil && kubevirtCluster.Spec.MaxConcurrentDrains != nil && *kubevirtCluster.Spec.MaxConcurrentDrains > 0 {
+		return int(*kubevirtCluster.Spec.MaxConcurrentDrains)
+	}
+	if maxConcurrent := m.drainPolicy().MaxConcurrentDrains; maxConcurrent != nil && *maxConcurrent > 0 {
+		return int(*maxConcurrent)
+	}
+	return defaultMaxConcurrentDrains
+}
+
+// drainForce returns whether the drain deletes the pods not managed by a controller, as set in the drain policy.
+func (m *Machine) drainForce() bool {
+	if force := m.drainPolicy().Force; force != nil {
+		return *force
+	}
+	return true
+}
+
+// drainTimeout returns the timeout of a single drain attempt, as set in the KubevirtMachine spec, or in the drain
+// policy.
+func (m *Machine) drainTimeout() time.Duration {
+	if timeout := firstDuration(m.machineContext.KubevirtMachine.Spec.DrainTimeout, m.drainPolicy().DrainTimeout); timeout > 0 {
+		return timeout
+	}
+	return defaultDrainTimeout
+}
+
+// drainRetryInterval returns the time to wait before retrying a failed drain, as set in the KubevirtMachine spec, or
+// in the drain policy.
+func (m *Machine) drainRetryInterval() time.Duration {
+	if interval := firstDuration(m.machineContext.KubevirtMachine.Spec.DrainRetryInterval, m.drainPolicy().DrainRetryInterval); interval > 0 {
+		return interval
+	}
+	return defaultDrainRetryInterval
+}
+
+// skipWaitForDeleteTimeout returns how long the drain of an unreachable node waits for the deleted pods to be gone,
+// as set in the KubevirtMachine spec, in the drain policy, or in the controller wide drain options.
+func (m *Machine) skipWaitForDeleteTimeout(opts DrainOptions) time.Duration {
+	if timeout := firstDuration(m.machineContext.KubevirtMachine.Spec.SkipWaitForDeleteTimeout, m.drainPolicy().SkipWaitForDeleteTimeout); timeout > 0 {
+		return timeout
+	}
+	if opts.SkipWaitForDeleteTimeout > 0 {
+		return opts.SkipWaitForDeleteTimeout
+	}
+	return DefaultSkipWaitForDeleteTimeout
+}
+
+// podEvictionGracePeriodSeconds returns the termination grace period of the evicted pods, as set in the KubevirtMachine
+// spec, or in the drain policy, or -1 to use the grace period of each pod.
+func (m *Machine) podEvictionGracePeriodSeconds() int {
+	if gracePeriod := firstDuration(m.machineContext.KubevirtMachine.Spec.PodEvictionGracePeriod, m.drainPolicy().PodEvictionGracePeriod); gracePeriod > 0 {
+		return int(gracePeriod.Seconds())
+	}
+	return -1
+}
+
+// tenantUnreachableTimeout returns how long the drain retries while the API server of the tenant cluster is
+// unreachable, as set in the KubevirtMachine spec, in the drain policy, or in the controller wide drain options.
+func (m *Machine) tenantUnreachableTimeout(opts DrainOptions) time.Duration {
+	if timeout := firstDuration(m.machineContext.KubevirtMachine.Spec.TenantUnreachableTimeout, m.drainPolicy().TenantUnreachableTimeout); timeout > 0 {
+		return timeout
+	}
+	if opts.TenantUnreachableTimeout > 0 {
+		return opts.TenantUnreachableTimeout
+	}
+	return DefaultTenantUnreachableTimeout
+}
+
+// firstDuration returns the first of the durations that is set and positive, or 0 if there is none.
+func firstDuration(durations ...*metav1.Duration) time.Duration {
+	for _, duration := range durations {
+		if duration != nil && duration.Duration > 0 {
+			return duration.Duration
+		}
+	}
+	return 0
+}
+
 // writer implements io.Writer interface as a pass-through for klog.
 type writer struct {
 	logFunc func(msg string, keysAndValues ...interface{})
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/ssh"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster"
@@ -19,14 +21,38 @@ import (
 type MachineInterface interface {
 	// Create creates a new VM for this machine.
 	Create(ctx gocontext.Context) error
+	// InsufficientCapacity returns which resources requested by the VM no node of the infra cluster has free.
+	InsufficientCapacity(ctx gocontext.Context) string
 	// Delete deletes VM for this machine.
 	Delete() error
 	// Exists checks if the VM has been provisioned already.
 	Exists() bool
+	// UpdateHealthCondition sets the VMHealthy condition of the KubevirtMachine.
+	UpdateHealthCondition()
+	// DegradedReason returns why the VM is broken, or an empty string if it isn't.
+	DegradedReason() string
+	// Adopt labels the existing VM as belonging to the KubevirtMachine.
+	Adopt() error
 	// IsReady checks if the VM is ready
 	IsReady() bool
+	// UnschedulableMessage returns why the VMI can't be scheduled on any node of the infra cluster, if it can't.
+	UnschedulableMessage() string
 	// Address returns the IP address of the VM.
 	Address() string
+	// AdditionalAddresses returns the addresses of the VM other than its primary IP address.
+	AdditionalAddresses() []clusterv1.MachineAddress
+	// UpdateMACAddresses records the MAC addresses of the VMI interfaces in the KubevirtMachine status.
+	UpdateMACAddresses()
+	// ReconcileHotplugVolumes hot-plugs the hotplug volumes of the KubevirtMachine into the VM, and unplugs the removed ones.
+	ReconcileHotplugVolumes() error
+	// ReconcileCPUHotplug hot-plugs the CPU sockets added to the KubevirtMachine into the running VM.
+	ReconcileCPUHotplug() error
+	// ReconcileMemoryHotplug hot-plugs the guest memory added to the KubevirtMachine into the running VM.
+	ReconcileMemoryHotplug() error
+	// ReconcileMutableFields updates the labels, annotations, node selector and run strategy of the existing VM.
+	ReconcileMutableFields() error
+	// UpdateSpecOutOfDateCondition reports the fields of the KubevirtMachine whose changes require replacing the VM.
+	UpdateSpecOutOfDateCondition(ctx gocontext.Context)
 	// SupportsCheckingIsBootstrapped checks if we have a method of checking
 	// that this bootstrapper has completed.
 	SupportsCheckingIsBootstrapped() bool
@@ -36,8 +62,25 @@ type MachineInterface interface {
 	GenerateProviderID() (string, error)
 	// IsTerminal reports back if a VM is in a permanent terminal state
 	IsTerminal() (bool, string, error)
+	// Preempted checks if the VMI of a preemptible machine failed, e.g. because it was preempted.
+	Preempted() bool
 
-	DrainNodeIfNeeded(workloadcluster.WorkloadCluster) (time.Duration, error)
+	// EvacuationRequested checks if KubeVirt asked to evacuate the VM from its infra cluster node.
+	EvacuationRequested() bool
+	// EvacuationPolicy returns the evacuation policy applying to the evacuation of the VM.
+	EvacuationPolicy() infrav1.EvacuationPolicy
+	// CheckInfraNodeCordoned checks if the infra cluster node running the VM is cordoned, and if so evacuates the VM.
+	CheckInfraNodeCordoned() error
+	// InfraNodeTopology returns the topology zone and region labels of the infra cluster node running the VM.
+	InfraNodeTopology() (map[string]string, error)
+
+	DrainNodeIfNeeded(workloadcluster.WorkloadCluster, DrainOptions) (time.Duration, error)
+	// PinInstancetypeRevisions records the instancetype and preference revisions used by the VM in the KubevirtMachine.
+	PinInstancetypeRevisions()
+	// UpdateMigrationStatus reports the state of the last live migration of the VM in the KubevirtMachine status.
+	UpdateMigrationStatus() error
+	// DeleteEvacuatedNode deletes the tenant cluster node of the VM, once the evacuated VMI is deleted.
+	DeleteEvacuatedNode(workloadcluster.WorkloadCluster) error
 }
 
 // MachineFactory allows creating new instances of kubevirt.machine
@@ -19,6 +19,7 @@ package kubevirt
 import (
 	gocontext "context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang/mock/gomock"
@@ -26,14 +27,25 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	kubedrain "k8s.io/kubectl/pkg/drain"
+	"k8s.io/utils/pointer"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
@@ -90,7 +102,10 @@ var _ = Describe("Without KubeVirt VM running", func() {
 			kubevirtMachine,
 		}
 
-		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(objects...).Build()
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(objects...).
+			WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+				return []string{obj.(*corev1.Pod).Spec.NodeName}
+			}).Build()
 
 		fakeVMCommandExecutor = FakeVMCommandExecutor{false}
 	})
@@ -190,6 +205,359 @@ var _ = Describe("Without KubeVirt VM running", func() {
 		validateVMExist(virtualMachine, fakeClient, machineContext)
 	})
 
+	Context("with GPUs in the VM template", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.GPUs = []kubevirtv1.GPU{
+				{Name: "gpu1", DeviceName: "nvidia.com/gpu_dev1"},
+			}
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.GPUs = nil
+		})
+
+		newKubeVirt := func(resourceName string) *kubevirtv1.KubeVirt {
+			return &kubevirtv1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+				Spec: kubevirtv1.KubeVirtSpec{
+					Configuration: kubevirtv1.KubeVirtConfiguration{
+						PermittedHostDevices: &kubevirtv1.PermittedHostDevices{
+							PciHostDevices: []kubevirtv1.PciHostDevice{{PCIVendorSelector: "10DE:20F1", ResourceName: resourceName}},
+						},
+					},
+				},
+			}
+		}
+
+		It("Create should create VM if the GPU is a permitted host device", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("nvidia.com/gpu_dev1"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+			validateVMExist(virtualMachine, fakeClient, machineContext)
+		})
+
+		It("Create should fail if the GPU is not a permitted host device", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("nvidia.com/gpu_dev2"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(MatchError(ContainSubstring("nvidia.com/gpu_dev1")))
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+		})
+	})
+
+	Context("with host devices in the VM template", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.HostDevices = []kubevirtv1.HostDevice{
+				{Name: "vgpu1", DeviceName: "nvidia.com/GRID_T4-1Q"},
+			}
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.HostDevices = nil
+		})
+
+		newKubeVirt := func(resourceName string) *kubevirtv1.KubeVirt {
+			return &kubevirtv1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+				Spec: kubevirtv1.KubeVirtSpec{
+					Configuration: kubevirtv1.KubeVirtConfiguration{
+						PermittedHostDevices: &kubevirtv1.PermittedHostDevices{
+							MediatedDevices: []kubevirtv1.MediatedHostDevice{{MDEVNameSelector: "GRID T4-1Q", ResourceName: resourceName}},
+						},
+					},
+				},
+			}
+		}
+
+		It("Create should create VM if the host device is permitted", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("nvidia.com/GRID_T4-1Q"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+			validateVMExist(virtualMachine, fakeClient, machineContext)
+		})
+
+		It("Create should fail if the host device is not permitted", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("nvidia.com/GRID_T4-2Q"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(MatchError(ContainSubstring("nvidia.com/GRID_T4-1Q")))
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+		})
+	})
+
+	It("UpdateSpecOutOfDateCondition should not report the VM created from the KubevirtMachine", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+
+		externalMachine, err = defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+		Expect(err).NotTo(HaveOccurred())
+		externalMachine.UpdateSpecOutOfDateCondition(machineContext.Context)
+
+		Expect(conditions.IsFalse(machineContext.KubevirtMachine, v1alpha1.SpecOutOfDateCondition)).To(BeTrue())
+		conditions.Delete(machineContext.KubevirtMachine, v1alpha1.SpecOutOfDateCondition)
+	})
+
+	Context("with the CPU and memory hotplug enabled", func() {
+		BeforeEach(func() {
+			machineContext.CPUHotplug = true
+			machineContext.MemoryHotplug = true
+		})
+
+		newKubeVirt := func(featureGates ...string) *kubevirtv1.KubeVirt {
+			return &kubevirtv1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+				Spec: kubevirtv1.KubeVirtSpec{
+					Configuration: kubevirtv1.KubeVirtConfiguration{
+						DeveloperConfiguration: &kubevirtv1.DeveloperConfiguration{FeatureGates: featureGates},
+					},
+				},
+			}
+		}
+
+		It("Create should enable the CPU and memory hotplug of the VM if KubeVirt supports it", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("VMLiveUpdateFeatures"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.LiveUpdateFeatures).To(Equal(&kubevirtv1.LiveUpdateFeatures{
+				CPU:    &kubevirtv1.LiveUpdateCPU{},
+				Memory: &kubevirtv1.LiveUpdateMemory{},
+			}))
+		})
+
+		It("Create should not enable the CPU and memory hotplug of the VM if KubeVirt doesn't support it", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt())).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.LiveUpdateFeatures).To(BeNil())
+		})
+	})
+
+	Context("with a TPM in the VM template", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.TPM = &kubevirtv1.TPMDevice{}
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.TPM = nil
+		})
+
+		newKubeVirt := func(version string) *kubevirtv1.KubeVirt {
+			return &kubevirtv1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+				Status:     kubevirtv1.KubeVirtStatus{ObservedKubeVirtVersion: version},
+			}
+		}
+
+		It("Create should create VM if KubeVirt supports the TPM", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("v0.59.0"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+			validateVMExist(virtualMachine, fakeClient, machineContext)
+		})
+
+		It("Create should fail if KubeVirt doesn't support the TPM", func() {
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("v0.52.0"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(MatchError(ContainSubstring("doesn't support the TPM")))
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+		})
+
+		It("Create should fail if KubeVirt doesn't support the persistent TPM", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.TPM.Persistent = pointer.Bool(true)
+			Expect(fakeClient.Create(gocontext.Background(), newKubeVirt("v0.59.0"))).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(MatchError(ContainSubstring("requires KubeVirt 1.0.0 or later")))
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+		})
+	})
+
+	Context("with SEV in the VM template", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.LaunchSecurity = &kubevirtv1.LaunchSecurity{
+				SEV: &kubevirtv1.SEV{},
+			}
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.LaunchSecurity = nil
+		})
+
+		It("Create should create VM if an infra node is SEV capable", func() {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "sev-node", Labels: map[string]string{kubevirtv1.SEVLabel: "true"}}}
+			Expect(fakeClient.Create(gocontext.Background(), node)).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+			validateVMExist(virtualMachine, fakeClient, machineContext)
+		})
+
+		It("Create should fail if no infra node is SEV-ES capable", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.LaunchSecurity.SEV.Policy = &kubevirtv1.SEVPolicy{
+				EncryptedState: pointer.Bool(true),
+			}
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "sev-node", Labels: map[string]string{kubevirtv1.SEVLabel: "true"}}}
+			Expect(fakeClient.Create(gocontext.Background(), node)).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(MatchError(ContainSubstring(kubevirtv1.SEVESLabel)))
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+		})
+	})
+
+	Context("with the capacity check enabled", func() {
+		BeforeEach(func() {
+			machineContext.CapacityCheck = true
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			}
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "infra-node"},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("4"),
+						corev1.ResourceMemory: resource.MustParse("8Gi"),
+					},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "infra-pod", Namespace: namespace},
+				Spec: corev1.PodSpec{
+					NodeName: node.Name,
+					Containers: []corev1.Container{{
+						Name: "compute",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("3"),
+							corev1.ResourceMemory: resource.MustParse("4Gi"),
+						}},
+					}},
+				},
+			}
+			Expect(fakeClient.Create(gocontext.Background(), node)).To(Succeed())
+			Expect(fakeClient.Create(gocontext.Background(), pod)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Resources.Requests = nil
+		})
+
+		It("InsufficientCapacity should return an empty string if an infra node has the resources of the VM free", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.InsufficientCapacity(machineContext.Context)).To(BeEmpty())
+		})
+
+		It("InsufficientCapacity should return the resources of the VM no infra node has free", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("2")
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.InsufficientCapacity(machineContext.Context)).To(ContainSubstring("cpu=2, memory=2Gi"))
+		})
+
+		It("InsufficientCapacity should account for the init containers and the overhead of the pods", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-infra-pod", Namespace: namespace},
+				Spec: corev1.PodSpec{
+					NodeName: "infra-node",
+					InitContainers: []corev1.Container{{
+						Name: "init",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("2Gi"),
+						}},
+					}},
+					Containers: []corev1.Container{{
+						Name: "compute",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						}},
+					}},
+					Overhead: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			}
+			Expect(fakeClient.Create(gocontext.Background(), pod)).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.InsufficientCapacity(machineContext.Context)).To(ContainSubstring("memory=2Gi"))
+		})
+	})
+
+	Context("with a root disk smart cloned from a golden image", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.RootDisk = &v1alpha1.RootDisk{
+				SourceRef:            &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: "ubuntu-22.04", Namespace: pointer.String("golden-images")},
+				ProvisioningStrategy: v1alpha1.SmartCloneProvisioningStrategy,
+			}
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Spec.RootDisk = nil
+		})
+
+		It("Create should put the root disk on the storage class of the golden image", func() {
+			goldenImage := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "ubuntu-22.04-pvc", Namespace: "golden-images"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: pointer.String("ceph-block")},
+			}
+			dataSource := &cdiv1.DataSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "ubuntu-22.04", Namespace: "golden-images"},
+				Spec: cdiv1.DataSourceSpec{Source: cdiv1.DataSourceSource{
+					PVC: &cdiv1.DataVolumeSourcePVC{Name: "ubuntu-22.04-pvc", Namespace: "golden-images"},
+				}},
+			}
+			Expect(fakeClient.Create(gocontext.Background(), goldenImage)).To(Succeed())
+			Expect(fakeClient.Create(gocontext.Background(), dataSource)).To(Succeed())
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: namespace, Name: kubevirtMachine.Name}, vm)).To(Succeed())
+			Expect(vm.Spec.DataVolumeTemplates).To(HaveLen(1))
+			Expect(vm.Spec.DataVolumeTemplates[0].Spec.Storage.StorageClassName).To(Equal(pointer.String("ceph-block")))
+		})
+
+		It("Create should create the VM if the golden image can't be read", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Create(machineContext.Context)).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: namespace, Name: kubevirtMachine.Name}, vm)).To(Succeed())
+			Expect(vm.Spec.DataVolumeTemplates[0].Spec.Storage.StorageClassName).To(BeNil())
+		})
+	})
+
 	It("Delete should be lenient if VM doesn't exist", func() {
 		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
 		Expect(err).NotTo(HaveOccurred())
@@ -267,348 +635,3016 @@ var _ = Describe("With KubeVirt VM running", func() {
 		Expect(externalMachine.IsReady()).To(BeTrue())
 	})
 
-	It("default mode: IsBootstrapped should return true", func() {
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-		Expect(err).NotTo(HaveOccurred())
-		Expect(externalMachine.IsBootstrapped()).To(BeTrue())
-	})
+	Context("with additional networks", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+				{Name: "storage", NetworkAttachmentDefinition: "storage-net"},
+			}
+			virtualMachineInstance.Status.Interfaces = append(virtualMachineInstance.Status.Interfaces[:1:1],
+				kubevirtv1.VirtualMachineInstanceNetworkInterface{Name: "storage", MAC: "02:00:00:00:00:05", IP: "10.10.0.5", IPs: []string{"10.10.0.5", "fd00::5"}},
+			)
+		})
 
-	It("ssh mode: IsBootstrapped return true", func() {
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-		externalMachine.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "ssh"
-		Expect(err).NotTo(HaveOccurred())
-		Expect(externalMachine.IsBootstrapped()).To(BeTrue())
-	})
+		AfterEach(func() {
+			kubevirtMachine.Spec.AdditionalNetworks = nil
+			virtualMachineInstance.Status.Interfaces = virtualMachineInstance.Status.Interfaces[:1]
+		})
 
-	It("none mode: IsBootstrapped should be forced to be true", func() {
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-		externalMachine.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "none"
-		Expect(err).NotTo(HaveOccurred())
-		Expect(externalMachine.IsBootstrapped()).To(BeTrue())
-	})
+		It("UpdateMACAddresses should record the MAC addresses of the interfaces", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-	It("invalid mode: IsBootstrapped should return false", func() {
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-		externalMachine.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "impossible-invalid-input"
-		Expect(err).NotTo(HaveOccurred())
-		Expect(externalMachine.IsBootstrapped()).To(BeFalse())
-	})
+			externalMachine.UpdateMACAddresses()
+			Expect(machineContext.KubevirtMachine.Status.MACAddresses).To(Equal([]v1alpha1.InterfaceMACAddress{
+				{Name: "storage", MACAddress: "02:00:00:00:00:05"},
+			}))
+			machineContext.KubevirtMachine.Status.MACAddresses = nil
+		})
 
-	It("SupportsCheckingIsBootstrapped should return true", func() {
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-		Expect(err).NotTo(HaveOccurred())
-		Expect(externalMachine.SupportsCheckingIsBootstrapped()).To(BeTrue())
-	})
+		When("the pod network has several IPs", func() {
+			BeforeEach(func() {
+				virtualMachineInstance.Status.Interfaces[0].IPs = []string{virtualMachineInstance.Status.Interfaces[0].IP, "fd10::1"}
+			})
 
-	It("GenerateProviderID should succeed", func() {
-		expectedProviderId := fmt.Sprintf("kubevirt://%s", kubevirtMachineName)
+			AfterEach(func() {
+				virtualMachineInstance.Status.Interfaces[0].IPs = nil
+			})
 
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-		Expect(err).NotTo(HaveOccurred())
-		providerId, err := externalMachine.GenerateProviderID()
-		Expect(err).ToNot(HaveOccurred())
-		Expect(providerId).To(Equal(expectedProviderId))
-	})
+			It("AdditionalAddresses should return the IPs of all the interfaces but the primary one", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.AdditionalAddresses()).To(Equal([]clusterv1.MachineAddress{
+					{Type: clusterv1.MachineInternalIP, Address: "fd10::1"},
+					{Type: clusterv1.MachineInternalIP, Address: "10.10.0.5"},
+					{Type: clusterv1.MachineInternalIP, Address: "fd00::5"},
+				}))
+				Expect(externalMachine.Address()).To(Equal(virtualMachineInstance.Status.Interfaces[0].IP))
+			})
+		})
 
-	It("Delete should succeed", func() {
-		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
-		Expect(err).NotTo(HaveOccurred())
-		validateVMExist(virtualMachine, fakeClient, machineContext)
+		When("the VMI has a subdomain", func() {
+			BeforeEach(func() {
+				virtualMachineInstance.Spec.Subdomain = "nodes"
+				virtualMachineInstance.Spec.Hostname = "node1"
+			})
 
-		Expect(externalMachine.Delete()).To(Succeed())
-		validateVMNotExist(virtualMachine, fakeClient, machineContext)
+			AfterEach(func() {
+				virtualMachineInstance.Spec.Subdomain = ""
+				virtualMachineInstance.Spec.Hostname = ""
+			})
+
+			It("AdditionalAddresses should return the DNS name of the VMI in the infra cluster", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.AdditionalAddresses()).To(ContainElement(clusterv1.MachineAddress{
+					Type:    clusterv1.MachineInternalDNS,
+					Address: "node1.nodes." + virtualMachineInstance.Namespace + ".svc",
+				}))
+			})
+		})
 	})
 
-	Context("test DrainNodeIfNeeded", func() {
-		const nodeName = "control-plane1"
+	Context("with mutable fields changed in the KubevirtMachine", func() {
+		BeforeEach(func() {
+			virtualMachine.Labels = map[string]string{"kubevirt.io/vm": kubevirtMachineName, "team": "a"}
+			virtualMachine.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+			virtualMachine.Spec.Template.Spec.NodeSelector = map[string]string{"old": "selector"}
+			virtualMachine.Spec.Running = pointer.Bool(true)
+
+			kubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Labels = map[string]string{"team": "b"}
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.NodeSelector = map[string]string{"new": "selector"}
+			runStrategy := kubevirtv1.RunStrategyRerunOnFailure
+			kubevirtMachine.Spec.RunStrategy = &runStrategy
+		})
 
-		var (
-			wlCluster *mock.MockWorkloadCluster
-		)
+		AfterEach(func() {
+			virtualMachine = testing.NewVirtualMachine(virtualMachineInstance)
+			kubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Labels = nil
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.NodeSelector = nil
+			kubevirtMachine.Spec.RunStrategy = nil
+		})
 
-		BeforeEach(func() {
-			virtualMachineInstance = testing.NewVirtualMachineInstance(kubevirtMachine)
-			strategy := kubevirtv1.EvictionStrategyExternal
-			virtualMachineInstance.Spec.EvictionStrategy = &strategy
-			virtualMachineInstance.Status.EvacuationNodeName = nodeName
+		It("UpdateSpecOutOfDateCondition should report the changed fields that can't be updated in place", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{Cores: 4}
+			defer func() { kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = nil }()
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-			if kubevirtMachine.Annotations == nil {
-				kubevirtMachine.Annotations = make(map[string]string)
-			}
+			externalMachine.UpdateSpecOutOfDateCondition(machineContext.Context)
 
-			mockCtrl := gomock.NewController(GinkgoT())
-			wlCluster = mock.NewMockWorkloadCluster(mockCtrl)
+			Expect(conditions.IsTrue(machineContext.KubevirtMachine, v1alpha1.SpecOutOfDateCondition)).To(BeTrue())
+			Expect(conditions.GetMessage(machineContext.KubevirtMachine, v1alpha1.SpecOutOfDateCondition)).To(ContainSubstring("cpu"))
+			Expect(conditions.GetMessage(machineContext.KubevirtMachine, v1alpha1.SpecOutOfDateCondition)).ToNot(ContainSubstring("affinity"))
+			conditions.Delete(machineContext.KubevirtMachine, v1alpha1.SpecOutOfDateCondition)
 		})
 
-		When("VMI is not evicted", func() {
-			BeforeEach(func() {
-				virtualMachineInstance.Spec.EvictionStrategy = nil
-				virtualMachineInstance.Status.EvacuationNodeName = ""
-			})
+		It("ReconcileMutableFields should update the labels, node selector and run strategy of the VM", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-			It("Should do nothing", func() {
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+			Expect(externalMachine.ReconcileMutableFields()).To(Succeed())
 
-				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-				Expect(err).NotTo(HaveOccurred())
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Labels).To(HaveKeyWithValue("team", "b"))
+			Expect(vm.Labels).To(HaveKeyWithValue("cluster.x-k8s.io/cluster-name", clusterName))
+			Expect(vm.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"new": "selector"}))
+			Expect(vm.Spec.Running).To(BeNil())
+			Expect(vm.Spec.RunStrategy).To(HaveValue(Equal(kubevirtv1.RunStrategyRerunOnFailure)))
+		})
+	})
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(requeueDuration).Should(BeZero())
+	Context("with a running VMI", func() {
+		BeforeEach(func() {
+			virtualMachineInstance.Status.Phase = kubevirtv1.Running
+		})
 
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(vmi).ToNot(BeNil())
-			})
+		AfterEach(func() {
+			virtualMachineInstance.Status.Phase = ""
+			virtualMachineInstance.Status.GuestOSInfo = kubevirtv1.VirtualMachineInstanceGuestOSInfo{}
+			conditions.Delete(kubevirtMachine, v1alpha1.VMHealthyCondition)
 		})
 
-		When("VMI is already deleted", func() {
+		It("UpdateHealthCondition should report the ready VMI as healthy", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			externalMachine.UpdateHealthCondition()
+
+			Expect(conditions.IsTrue(machineContext.KubevirtMachine, v1alpha1.VMHealthyCondition)).To(BeTrue())
+		})
+
+		Context("whose guest agent disconnected", func() {
 			BeforeEach(func() {
-				deletionTimeStamp := metav1.NewTime(time.Now().UTC().Add(-5 * time.Second))
-				virtualMachineInstance.DeletionTimestamp = &deletionTimeStamp
-				virtualMachineInstance.Finalizers = []string{"fake/finalizer"}
+				virtualMachineInstance.Status.GuestOSInfo = kubevirtv1.VirtualMachineInstanceGuestOSInfo{Name: "Fedora Linux"}
+				conditions.MarkTrue(kubevirtMachine, v1alpha1.VMHealthyCondition)
 			})
 
-			It("Should do nothing", func() {
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
-
+			It("UpdateHealthCondition should report the VM as unhealthy", func() {
 				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
 				Expect(err).NotTo(HaveOccurred())
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(requeueDuration).Should(BeZero())
+				externalMachine.UpdateHealthCondition()
 
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(vmi).ToNot(BeNil())
+				Expect(conditions.IsFalse(machineContext.KubevirtMachine, v1alpha1.VMHealthyCondition)).To(BeTrue())
+				Expect(conditions.GetReason(machineContext.KubevirtMachine, v1alpha1.VMHealthyCondition)).To(Equal(v1alpha1.GuestAgentDisconnectedReason))
 			})
 		})
+	})
 
-		When("VMI is already deleted, but the grace period annotation is still there", func() {
-			BeforeEach(func() {
-				deletionTimeStamp := metav1.NewTime(time.Now().UTC().Add(-5 * time.Second))
-				virtualMachineInstance.DeletionTimestamp = &deletionTimeStamp
-				virtualMachineInstance.Finalizers = []string{"fake/finalizer"}
+	Context("with a failed VMI", func() {
+		BeforeEach(func() {
+			virtualMachineInstance.Status.Phase = kubevirtv1.Failed
+		})
 
-				graceTime := time.Now().UTC().Add(-5 * time.Minute).Format(time.RFC3339)
-				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
-			})
+		AfterEach(func() {
+			virtualMachineInstance.Status.Phase = ""
+			conditions.Delete(kubevirtMachine, v1alpha1.VMHealthyCondition)
+		})
 
-			It("Should remove the grace period annotation", func() {
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+		It("UpdateHealthCondition should not report the VM before it was healthy", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-				Expect(err).NotTo(HaveOccurred())
+			externalMachine.UpdateHealthCondition()
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(requeueDuration).Should(BeZero())
+			Expect(conditions.Has(machineContext.KubevirtMachine, v1alpha1.VMHealthyCondition)).To(BeFalse())
+		})
 
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(vmi).ToNot(BeNil())
+		It("UpdateHealthCondition should report the VM that was healthy as unhealthy", func() {
+			conditions.MarkTrue(kubevirtMachine, v1alpha1.VMHealthyCondition)
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-				machine := &v1alpha1.KubevirtMachine{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(machine).ToNot(BeNil())
-				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
-			})
+			externalMachine.UpdateHealthCondition()
+
+			Expect(conditions.GetReason(machineContext.KubevirtMachine, v1alpha1.VMHealthyCondition)).To(Equal(v1alpha1.VMINotRunningReason))
+			Expect(conditions.GetMessage(machineContext.KubevirtMachine, v1alpha1.VMHealthyCondition)).To(ContainSubstring("Failed"))
 		})
 
-		When("VMI is missing, but the grace period annotation is still there", func() {
+		It("Preempted should return true for a preemptible machine", func() {
+			kubevirtMachine.Spec.Preemptible = &v1alpha1.PreemptibleSpec{PriorityClassName: "preemptible"}
+			defer func() { kubevirtMachine.Spec.Preemptible = nil }()
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Preempted()).To(BeTrue())
+		})
+
+		It("Preempted should return false for a machine that is not preemptible", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Preempted()).To(BeFalse())
+		})
+	})
+
+	Context("with a VM failing to pull its images", func() {
+		BeforeEach(func() {
+			virtualMachine.Status.PrintableStatus = kubevirtv1.VirtualMachineStatusImagePullBackOff
+			virtualMachine.Status.Conditions = []kubevirtv1.VirtualMachineCondition{{
+				Type:               kubevirtv1.VirtualMachineReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			}}
+		})
+
+		AfterEach(func() {
+			virtualMachine = testing.NewVirtualMachine(virtualMachineInstance)
+			kubevirtMachine.Spec.ProvisioningFailureTimeout = nil
+		})
+
+		It("IsTerminal should return true once the provisioning failure timeout is exceeded", func() {
+			kubevirtMachine.Spec.ProvisioningFailureTimeout = &metav1.Duration{Duration: 30 * time.Minute}
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			isTerminal, reason, err := externalMachine.IsTerminal()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isTerminal).To(BeTrue())
+			Expect(reason).To(ContainSubstring("ImagePullBackOff"))
+		})
+
+		It("IsTerminal should return false before the provisioning failure timeout is exceeded", func() {
+			kubevirtMachine.Spec.ProvisioningFailureTimeout = &metav1.Duration{Duration: 2 * time.Hour}
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			isTerminal, _, err := externalMachine.IsTerminal()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isTerminal).To(BeFalse())
+		})
+
+		It("IsTerminal should return false without a provisioning failure timeout", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			isTerminal, _, err := externalMachine.IsTerminal()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isTerminal).To(BeFalse())
+		})
+	})
+
+	Context("with an existing VM to adopt", func() {
+		BeforeEach(func() {
+			virtualMachine.Labels = map[string]string{"team": "a"}
+			virtualMachine.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+		})
+
+		AfterEach(func() {
+			virtualMachine = testing.NewVirtualMachine(virtualMachineInstance)
+		})
+
+		It("Adopt should label the VM as belonging to the KubevirtMachine", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Adopt()).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Labels).To(HaveKeyWithValue("team", "a"))
+			Expect(vm.Labels).To(HaveKeyWithValue("cluster.x-k8s.io/cluster-name", clusterName))
+			Expect(vm.Labels).To(HaveKeyWithValue(v1alpha1.KubevirtMachineNameLabel, kubevirtMachine.Name))
+			Expect(vm.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue(v1alpha1.KubevirtMachineNamespaceLabel, kubevirtMachine.Namespace))
+		})
+
+		Context("belonging to another KubevirtMachine", func() {
 			BeforeEach(func() {
-				graceTime := time.Now().UTC().Add(-5 * time.Minute).Format(time.RFC3339)
-				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				virtualMachine.Labels[v1alpha1.KubevirtMachineNameLabel] = "other-machine"
+				virtualMachine.Labels[v1alpha1.KubevirtMachineNamespaceLabel] = kubevirtMachine.Namespace
 			})
 
-			It("Should remove the grace period annotation", func() {
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
-
+			It("Adopt should fail", func() {
 				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
 				Expect(err).NotTo(HaveOccurred())
-				externalMachine.vmiInstance = nil
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(requeueDuration).Should(BeZero())
+				Expect(externalMachine.Adopt()).ToNot(Succeed())
+			})
+		})
+	})
 
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(vmi).ToNot(BeNil())
+	Context("with the CPU hotplug enabled", func() {
+		BeforeEach(func() {
+			machineContext.CPUHotplug = true
+			virtualMachine.Spec.LiveUpdateFeatures = &kubevirtv1.LiveUpdateFeatures{CPU: &kubevirtv1.LiveUpdateCPU{MaxSockets: pointer.Uint32(4)}}
+			virtualMachine.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+			virtualMachine.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{Sockets: 2}
+		})
 
-				machine := &v1alpha1.KubevirtMachine{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(machine).ToNot(BeNil())
-				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
-			})
+		AfterEach(func() {
+			virtualMachine.Spec.LiveUpdateFeatures = nil
+			virtualMachine.Spec.Template = nil
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = nil
 		})
 
-		When("grace not expired (wrap for BeforeEach)", func() {
-			BeforeEach(func() {
-				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
-				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
-			})
+		It("ReconcileCPUHotplug should hot-plug the sockets added to the KubevirtMachine", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{Sockets: 3}
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-			It("Should drain the node", func() {
-				node := &corev1.Node{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: nodeName,
-					},
-				}
+			Expect(externalMachine.ReconcileCPUHotplug()).To(Succeed())
 
-				Expect(k8sfake.AddToScheme(setupRemoteScheme())).ToNot(HaveOccurred())
-				cl := k8sfake.NewSimpleClientset(node)
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.CPU.Sockets).To(BeEquivalentTo(3))
+		})
 
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+		It("ReconcileCPUHotplug should not hot-plug the sockets beyond the maximum sockets of the VM", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{Sockets: 8}
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-				Expect(err).NotTo(HaveOccurred())
+			Expect(externalMachine.ReconcileCPUHotplug()).To(Succeed())
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(requeueDuration).To(Equal(time.Duration(10 * time.Second)))
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.CPU.Sockets).To(BeEquivalentTo(2))
+		})
 
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).To(HaveOccurred())
-				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		It("ReconcileCPUHotplug should not unplug the sockets removed from the KubevirtMachine", func() {
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{Sockets: 1}
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-				machine := &v1alpha1.KubevirtMachine{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(machine).ToNot(BeNil())
-				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
-			})
+			Expect(externalMachine.ReconcileCPUHotplug()).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.CPU.Sockets).To(BeEquivalentTo(2))
 		})
+	})
 
-		When("grace not expired, drain fails (wrap for BeforeEach)", func() {
-			BeforeEach(func() {
-				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
-				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
-			})
+	Context("with the memory hotplug enabled", func() {
+		BeforeEach(func() {
+			machineContext.MemoryHotplug = true
+			maxGuest := resource.MustParse("8Gi")
+			virtualMachine.Spec.LiveUpdateFeatures = &kubevirtv1.LiveUpdateFeatures{Memory: &kubevirtv1.LiveUpdateMemory{MaxGuest: &maxGuest}}
+			virtualMachine.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+			guest := resource.MustParse("2Gi")
+			virtualMachine.Spec.Template.Spec.Domain.Memory = &kubevirtv1.Memory{Guest: &guest}
+			current := resource.MustParse("2Gi")
+			virtualMachineInstance.Status.Memory = &kubevirtv1.MemoryStatus{GuestCurrent: &current}
+		})
 
-			It("Should not drain the node", func() {
-				node := &corev1.Node{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: nodeName,
-					},
-				}
+		AfterEach(func() {
+			virtualMachine.Spec.LiveUpdateFeatures = nil
+			virtualMachine.Spec.Template = nil
+			virtualMachineInstance.Status.Memory = nil
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Memory = nil
+			kubevirtMachine.Spec.Memory = nil
+			kubevirtMachine.Status.GuestMemory = nil
+			conditions.Delete(kubevirtMachine, v1alpha1.MemoryHotplugSucceededCondition)
+		})
 
-				Expect(k8sfake.AddToScheme(setupRemoteScheme())).ToNot(HaveOccurred())
-				cl := k8sfake.NewSimpleClientset(node)
+		setGuestMemory := func(quantity string) {
+			guest := resource.MustParse(quantity)
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Memory = &kubevirtv1.Memory{Guest: &guest}
+		}
 
-				fakeErr := errors.New("fake error: can't get node")
-				cl.PrependReactor("get", "nodes", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
-					return true, nil, fakeErr
-				})
+		It("ReconcileMemoryHotplug should hot-plug the guest memory added to the KubevirtMachine", func() {
+			setGuestMemory("4Gi")
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
 
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+			Expect(externalMachine.ReconcileMemoryHotplug()).To(Succeed())
 
-				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
-				Expect(err).ToNot(HaveOccurred())
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.Memory.Guest.String()).To(Equal("4Gi"))
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster)
-				Expect(err).To(HaveOccurred())
-				Expect(err).To(MatchError(fakeErr))
-				Expect(requeueDuration).To(Equal(time.Duration(0)))
+			Expect(kubevirtMachine.Status.GuestMemory.Desired.String()).To(Equal("4Gi"))
+			Expect(kubevirtMachine.Status.GuestMemory.Applied.String()).To(Equal("2Gi"))
+			cond := conditions.Get(kubevirtMachine, v1alpha1.MemoryHotplugSucceededCondition)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(v1alpha1.MemoryHotplugInProgressReason))
+		})
 
-				By("VMI should not be deleted")
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(vmi).ToNot(BeNil())
+		It("ReconcileMemoryHotplug should hot-plug the guest memory of the KubevirtMachine with its overcommitted memory request", func() {
+			setGuestMemory("2Gi")
+			guest := resource.MustParse("4Gi")
+			kubevirtMachine.Spec.Memory = &v1alpha1.MemorySpec{Guest: &guest, OvercommitPercent: pointer.Int32(200)}
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.ReconcileMemoryHotplug()).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.Memory.Guest.String()).To(Equal("4Gi"))
+			request := vm.Spec.Template.Spec.Domain.Resources.Requests[corev1.ResourceMemory]
+			Expect(request.String()).To(Equal("2Gi"))
+			Expect(kubevirtMachine.Status.GuestMemory.Desired.String()).To(Equal("4Gi"))
+		})
 
-				By("the grace period annotation still exists")
-				machine := &v1alpha1.KubevirtMachine{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(machine).ToNot(BeNil())
-				Expect(machine.Annotations).To(HaveKey(v1alpha1.VmiDeletionGraceTime))
+		It("ReconcileMemoryHotplug should report the guest memory once it is applied", func() {
+			setGuestMemory("2Gi")
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.ReconcileMemoryHotplug()).To(Succeed())
+
+			Expect(kubevirtMachine.Status.GuestMemory.Desired.String()).To(Equal("2Gi"))
+			Expect(kubevirtMachine.Status.GuestMemory.Applied.String()).To(Equal("2Gi"))
+			Expect(conditions.IsTrue(kubevirtMachine, v1alpha1.MemoryHotplugSucceededCondition)).To(BeTrue())
+		})
+
+		It("ReconcileMemoryHotplug should not hot-plug the guest memory beyond the maximum guest memory of the VM", func() {
+			setGuestMemory("16Gi")
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.ReconcileMemoryHotplug()).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.Memory.Guest.String()).To(Equal("2Gi"))
+			Expect(conditions.GetReason(kubevirtMachine, v1alpha1.MemoryHotplugSucceededCondition)).To(Equal(v1alpha1.MemoryHotplugNotSupportedReason))
+		})
+
+		It("ReconcileMemoryHotplug should not decrease the guest memory of the VM", func() {
+			setGuestMemory("1Gi")
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.ReconcileMemoryHotplug()).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Spec.Template.Spec.Domain.Memory.Guest.String()).To(Equal("2Gi"))
+			Expect(conditions.GetReason(kubevirtMachine, v1alpha1.MemoryHotplugSucceededCondition)).To(Equal(v1alpha1.MemoryHotplugNotSupportedReason))
+		})
+	})
+
+	Context("with hotplug volumes", func() {
+		BeforeEach(func() {
+			virtualMachine.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+			virtualMachine.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{
+				{Name: "old", VolumeSource: kubevirtv1.VolumeSource{PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: "old-pvc"},
+					Hotpluggable:                      true,
+				}}},
+				{Name: "manual", VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{
+					Name:         "manual-dv",
+					Hotpluggable: true,
+				}}},
+			}
+			kubevirtMachine.Spec.HotplugVolumes = []v1alpha1.HotplugVolume{
+				{Name: "data", HotplugVolumeSource: kubevirtv1.HotplugVolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: "data-dv"}}, Serial: "data0"},
+			}
+			kubevirtMachine.Status.HotplugVolumes = []string{"old"}
+		})
+
+		JustBeforeEach(func() {
+			fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).
+				WithObjects(virtualMachineInstance, virtualMachine).
+				WithStatusSubresource(virtualMachine).
+				Build()
+		})
+
+		AfterEach(func() {
+			virtualMachine.Spec.Template = nil
+			kubevirtMachine.Spec.HotplugVolumes = nil
+			kubevirtMachine.Status.HotplugVolumes = nil
+		})
+
+		It("ReconcileHotplugVolumes should hot-plug the new volumes and unplug the removed ones", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.ReconcileHotplugVolumes()).To(Succeed())
+
+			vm := &kubevirtv1.VirtualMachine{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(virtualMachine), vm)).To(Succeed())
+			Expect(vm.Status.VolumeRequests).To(Equal([]kubevirtv1.VirtualMachineVolumeRequest{
+				{RemoveVolumeOptions: &kubevirtv1.RemoveVolumeOptions{Name: "old"}},
+				{AddVolumeOptions: &kubevirtv1.AddVolumeOptions{
+					Name: "data",
+					Disk: &kubevirtv1.Disk{
+						Name:       "data",
+						DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusSCSI}},
+						Serial:     "data0",
+					},
+					VolumeSource: &kubevirtv1.HotplugVolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: "data-dv", Hotpluggable: true}},
+				}},
+			}))
+			Expect(machineContext.KubevirtMachine.Status.HotplugVolumes).To(Equal([]string{"data"}))
+		})
+
+		It("ReconcileHotplugVolumes should not update the hot-plugged volumes if the volume requests can't be patched", func() {
+			failingClient := interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				SubResourcePatch: func(ctx gocontext.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					return errors.New("fake error")
+				},
 			})
+			externalMachine, err := defaultTestMachine(machineContext, namespace, failingClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.ReconcileHotplugVolumes()).ToNot(Succeed())
+			Expect(machineContext.KubevirtMachine.Status.HotplugVolumes).To(Equal([]string{"old"}))
 		})
 
-		When("grace period expired (wrap for BeforeEach)", func() {
+		Context("with pending volume requests", func() {
 			BeforeEach(func() {
-				graceTime := time.Now().UTC().Format(time.RFC3339)
-				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				virtualMachine.Status.VolumeRequests = []kubevirtv1.VirtualMachineVolumeRequest{
+					{RemoveVolumeOptions: &kubevirtv1.RemoveVolumeOptions{Name: "other"}},
+				}
 			})
 
-			It("Should delete the VMI after grace period", func() {
-				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+			AfterEach(func() {
+				virtualMachine.Status.VolumeRequests = nil
+			})
 
+			It("ReconcileHotplugVolumes should wait for KubeVirt to process them", func() {
 				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
 				Expect(err).NotTo(HaveOccurred())
 
-				requeueDuration, err := externalMachine.DrainNodeIfNeeded(nil)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(requeueDuration).Should(Equal(10 * time.Second))
-
-				vmi := &kubevirtv1.VirtualMachineInstance{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
-				Expect(err).To(HaveOccurred())
-				Expect(apierrors.IsNotFound(err)).To(BeTrue())
-
-				machine := &v1alpha1.KubevirtMachine{}
-				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(machine).ToNot(BeNil())
-				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
+				Expect(externalMachine.ReconcileHotplugVolumes()).To(Succeed())
+				Expect(externalMachine.vmInstance.Status.VolumeRequests).To(HaveLen(1))
+				Expect(machineContext.KubevirtMachine.Status.HotplugVolumes).To(Equal([]string{"old"}))
 			})
 		})
 	})
-})
 
-var _ = Describe("util functions", func() {
-	var machineContext *context.MachineContext
+	It("UnschedulableMessage should return an empty string", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.UnschedulableMessage()).To(BeEmpty())
+	})
 
-	BeforeEach(func() {
-		machineContext = &context.MachineContext{
-			Context:             gocontext.TODO(),
-			Cluster:             cluster,
-			KubevirtCluster:     kubevirtCluster,
-			Machine:             machine,
-			KubevirtMachine:     kubevirtMachine.DeepCopy(),
-			BootstrapDataSecret: bootstrapDataSecret,
-			Logger:              logger,
-		}
+	Context("with an unschedulable VMI", func() {
+		BeforeEach(func() {
+			virtualMachineInstance.Status.Conditions = []kubevirtv1.VirtualMachineInstanceCondition{
+				{
+					Type:    kubevirtv1.VirtualMachineInstanceConditionType(corev1.PodScheduled),
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu_dev1.",
+				},
+			}
+		})
+
+		It("IsReady should return false", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(externalMachine.IsReady()).To(BeFalse())
+		})
+
+		It("UnschedulableMessage should return the scheduling failure", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(externalMachine.UnschedulableMessage()).To(ContainSubstring("Insufficient nvidia.com/gpu_dev1"))
+		})
 	})
 
-	It("GenerateProviderID should succeed", func() {
-		dataVolumeTemplates := []kubevirtv1.DataVolumeTemplateSpec{
-			{
+	Context("with a VMI running on an infra cluster node", func() {
+		const infraNodeName = "infra-node1"
+
+		BeforeEach(func() {
+			virtualMachineInstance.Status.NodeName = infraNodeName
+		})
+
+		It("InfraNodeTopology should return the topology labels of the infra cluster node", func() {
+			infraNode := &corev1.Node{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      map[string]string{"my": "label"},
-					Annotations: map[string]string{"my": "annotation"},
-					Name:        "dv1",
-				},
-			},
-		}
-		volumes := []kubevirtv1.Volume{
-			{
-				Name: "test1",
-				VolumeSource: kubevirtv1.VolumeSource{
-					DataVolume: &kubevirtv1.DataVolumeSource{
-						Name: "dv1",
+					Name: infraNodeName,
+					Labels: map[string]string{
+						corev1.LabelTopologyZone:   "zone-a",
+						corev1.LabelTopologyRegion: "region-1",
+						corev1.LabelHostname:       infraNodeName,
 					},
 				},
-			},
-		}
+			}
+			Expect(fakeClient.Create(gocontext.Background(), infraNode)).To(Succeed())
+
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(externalMachine.InfraNodeTopology()).To(Equal(map[string]string{
+				corev1.LabelTopologyZone:   "zone-a",
+				corev1.LabelTopologyRegion: "region-1",
+			}))
+		})
 
-		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.DataVolumeTemplates = dataVolumeTemplates
-		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Volumes = volumes
+		It("InfraNodeTopology should return nil if the infra cluster node is not found", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(externalMachine.InfraNodeTopology()).To(BeNil())
+		})
+	})
 
-		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+	It("default mode: IsBootstrapped should return true", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.IsBootstrapped()).To(BeTrue())
+	})
 
-		Expect(newVM.Spec.DataVolumeTemplates[0].ObjectMeta.Name).To(Equal(kubevirtMachineName + "-dv1"))
+	It("ssh mode: IsBootstrapped return true", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		externalMachine.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "ssh"
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.IsBootstrapped()).To(BeTrue())
+	})
+
+	It("none mode: IsBootstrapped should be forced to be true", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		externalMachine.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "none"
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.IsBootstrapped()).To(BeTrue())
+	})
+
+	It("invalid mode: IsBootstrapped should return false", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		externalMachine.machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "impossible-invalid-input"
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.IsBootstrapped()).To(BeFalse())
+	})
+
+	When("the bootstrap is checked by the guest agent", func() {
+		BeforeEach(func() {
+			kubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "guestAgent"
+			virtualMachineInstance.Spec.ReadinessProbe = bootstrapCheckProbe()
+			virtualMachineInstance.Status.Conditions = append(virtualMachineInstance.Status.Conditions, kubevirtv1.VirtualMachineInstanceCondition{
+				Type:   kubevirtv1.VirtualMachineInstanceAgentConnected,
+				Status: corev1.ConditionTrue,
+			})
+		})
+
+		AfterEach(func() {
+			virtualMachineInstance.Spec.ReadinessProbe = nil
+		})
+
+		It("guestAgent mode: IsBootstrapped should return true without ssh", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, FakeVMCommandExecutor{false}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(externalMachine.SupportsCheckingIsBootstrapped()).To(BeTrue())
+			Expect(externalMachine.IsBootstrapped()).To(BeTrue())
+		})
+
+		When("the guest agent is not connected", func() {
+			BeforeEach(func() {
+				virtualMachineInstance.Status.Conditions = virtualMachineInstance.Status.Conditions[:1]
+			})
+
+			It("guestAgent mode: IsBootstrapped should return false", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.IsBootstrapped()).To(BeFalse())
+			})
+		})
+	})
+
+	It("SupportsCheckingIsBootstrapped should return true", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalMachine.SupportsCheckingIsBootstrapped()).To(BeTrue())
+	})
+
+	It("GenerateProviderID should succeed", func() {
+		expectedProviderId := fmt.Sprintf("kubevirt://%s", kubevirtMachineName)
+
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+		Expect(err).NotTo(HaveOccurred())
+		providerId, err := externalMachine.GenerateProviderID()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(providerId).To(Equal(expectedProviderId))
+	})
+
+	It("Delete should succeed", func() {
+		externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+		Expect(err).NotTo(HaveOccurred())
+		validateVMExist(virtualMachine, fakeClient, machineContext)
+
+		Expect(externalMachine.Delete()).To(Succeed())
+		validateVMNotExist(virtualMachine, fakeClient, machineContext)
+	})
+
+	Context("with DataVolumes", func() {
+		var dataVolume *cdiv1.DataVolume
+
+		BeforeEach(func() {
+			virtualMachine.UID = "vm-uid"
+			virtualMachine.Spec.DataVolumeTemplates = []kubevirtv1.DataVolumeTemplateSpec{
+				{ObjectMeta: metav1.ObjectMeta{Name: kubevirtMachineName + "-rootdisk"}},
+			}
+			dataVolume = &cdiv1.DataVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubevirtMachineName + "-rootdisk",
+					Namespace: namespace,
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: kubevirtv1.GroupVersion.String(), Kind: "VirtualMachine", Name: virtualMachine.Name, UID: virtualMachine.UID},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			Expect(fakeClient.Create(gocontext.TODO(), dataVolume)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			virtualMachine.UID = ""
+			virtualMachine.Spec.DataVolumeTemplates = nil
+			kubevirtMachine.Spec.DiskRetentionPolicy = ""
+		})
+
+		It("Delete should delete the DataVolumes of the VM", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Delete()).To(Succeed())
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+
+			err = fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(dataVolume), &cdiv1.DataVolume{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Delete should orphan the DataVolumes of the VM with the Retain disk retention policy", func() {
+			kubevirtMachine.Spec.DiskRetentionPolicy = v1alpha1.RetainDiskRetentionPolicy
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.Delete()).To(Succeed())
+			validateVMNotExist(virtualMachine, fakeClient, machineContext)
+
+			retained := &cdiv1.DataVolume{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKeyFromObject(dataVolume), retained)).To(Succeed())
+			Expect(retained.OwnerReferences).To(BeEmpty())
+		})
+	})
+
+	Context("test PinInstancetypeRevisions", func() {
+		BeforeEach(func() {
+			virtualMachine = testing.NewVirtualMachine(virtualMachineInstance)
+			virtualMachine.Spec.Instancetype = &kubevirtv1.InstancetypeMatcher{Name: "u1.medium", RevisionName: "vm-u1.medium-1"}
+			virtualMachine.Spec.Preference = &kubevirtv1.PreferenceMatcher{Name: "fedora", RevisionName: "vm-fedora-1"}
+
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Instancetype = &kubevirtv1.InstancetypeMatcher{Name: "u1.medium"}
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Preference = &kubevirtv1.PreferenceMatcher{Name: "fedora", RevisionName: "vm-fedora-0"}
+		})
+
+		AfterEach(func() {
+			virtualMachine = testing.NewVirtualMachine(virtualMachineInstance)
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Instancetype = nil
+			kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Preference = nil
+		})
+
+		It("should only pin the revisions that are not pinned yet", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			externalMachine.PinInstancetypeRevisions()
+			Expect(kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Instancetype.RevisionName).To(Equal("vm-u1.medium-1"))
+			Expect(kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Preference.RevisionName).To(Equal("vm-fedora-0"))
+		})
+	})
+
+	Context("test UpdateMigrationStatus", func() {
+		const migrationUID = "migration-uid"
+
+		newMigration := func(name string, uid types.UID, created time.Time, phase kubevirtv1.VirtualMachineInstanceMigrationPhase) *kubevirtv1.VirtualMachineInstanceMigration {
+			return &kubevirtv1.VirtualMachineInstanceMigration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         virtualMachineInstance.Namespace,
+					UID:               uid,
+					CreationTimestamp: metav1.NewTime(created),
+				},
+				Spec:   kubevirtv1.VirtualMachineInstanceMigrationSpec{VMIName: virtualMachineInstance.Name},
+				Status: kubevirtv1.VirtualMachineInstanceMigrationStatus{Phase: phase},
+			}
+		}
+
+		BeforeEach(func() {
+			virtualMachineInstance = testing.NewVirtualMachineInstance(kubevirtMachine)
+			virtualMachineInstance.Status.MigrationState = &kubevirtv1.VirtualMachineInstanceMigrationState{
+				MigrationUID: migrationUID,
+				SourceNode:   "infra-node1",
+				TargetNode:   "infra-node2",
+			}
+		})
+
+		AfterEach(func() {
+			kubevirtMachine.Status.Migration = nil
+			conditions.Delete(kubevirtMachine, v1alpha1.VMMigrationSucceededCondition)
+		})
+
+		It("should not report anything if the VMI was never migrated", func() {
+			externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(externalMachine.UpdateMigrationStatus()).To(Succeed())
+			Expect(kubevirtMachine.Status.Migration).To(BeNil())
+			Expect(conditions.Get(kubevirtMachine, v1alpha1.VMMigrationSucceededCondition)).To(BeNil())
+		})
+
+		DescribeTable("should report the last migration of the VMI",
+			func(phase kubevirtv1.VirtualMachineInstanceMigrationPhase, status corev1.ConditionStatus, reason string) {
+				now := time.Now()
+				Expect(fakeClient.Create(gocontext.Background(), newMigration("old-migration", "old-uid", now.Add(-time.Hour), kubevirtv1.MigrationFailed))).To(Succeed())
+				Expect(fakeClient.Create(gocontext.Background(), newMigration("migration", migrationUID, now, phase))).To(Succeed())
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(externalMachine.UpdateMigrationStatus()).To(Succeed())
+				Expect(kubevirtMachine.Status.Migration).To(Equal(&v1alpha1.VirtualMachineMigrationStatus{
+					Name:       "migration",
+					Phase:      string(phase),
+					SourceNode: "infra-node1",
+					TargetNode: "infra-node2",
+				}))
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.VMMigrationSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(status))
+				Expect(cond.Reason).To(Equal(reason))
+			},
+			Entry("running", kubevirtv1.MigrationRunning, corev1.ConditionFalse, v1alpha1.VMMigratingReason),
+			Entry("succeeded", kubevirtv1.MigrationSucceeded, corev1.ConditionTrue, ""),
+			Entry("failed", kubevirtv1.MigrationFailed, corev1.ConditionFalse, v1alpha1.VMMigrationFailedReason),
+		)
+	})
+
+	Context("test DrainNodeIfNeeded", func() {
+		const (
+			nodeName      = "control-plane1"
+			infraNodeName = "infra-node1"
+		)
+
+		var (
+			wlCluster *mock.MockWorkloadCluster
+			tracker   *NodeDrainTracker
+		)
+
+		BeforeEach(func() {
+			virtualMachineInstance = testing.NewVirtualMachineInstance(kubevirtMachine)
+			strategy := kubevirtv1.EvictionStrategyExternal
+			virtualMachineInstance.Spec.EvictionStrategy = &strategy
+			virtualMachineInstance.Status.EvacuationNodeName = infraNodeName
+			machine.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: nodeName}
+
+			if kubevirtMachine.Annotations == nil {
+				kubevirtMachine.Annotations = make(map[string]string)
+			}
+
+			mockCtrl := gomock.NewController(GinkgoT())
+			wlCluster = mock.NewMockWorkloadCluster(mockCtrl)
+			tracker = NewNodeDrainTracker()
+		})
+
+		AfterEach(func() {
+			machine.Status.NodeRef = nil
+		})
+
+		When("VMI is not evicted", func() {
+			BeforeEach(func() {
+				virtualMachineInstance.Spec.EvictionStrategy = nil
+				virtualMachineInstance.Status.EvacuationNodeName = ""
+			})
+
+			It("Should do nothing", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vmi).ToNot(BeNil())
+			})
+		})
+
+		When("the evacuation was cancelled after the node was cordoned", func() {
+			BeforeEach(func() {
+				virtualMachineInstance.Status.EvacuationNodeName = ""
+				kubevirtMachine.Annotations[v1alpha1.CordonedNodeAnnotation] = nodeName
+			})
+
+			AfterEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.CordonedNodeAnnotation)
+			})
+
+			It("Should uncordon the node", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+					Spec: corev1.NodeSpec{
+						Unschedulable: true,
+					},
+				}
+				cl := k8sfake.NewSimpleClientset(node)
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				updatedNode, err := cl.CoreV1().Nodes().Get(gocontext.Background(), nodeName, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedNode.Spec.Unschedulable).To(BeFalse())
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.CordonedNodeAnnotation))
+			})
+		})
+
+		When("VMI is already deleted", func() {
+			BeforeEach(func() {
+				deletionTimeStamp := metav1.NewTime(time.Now().UTC().Add(-5 * time.Second))
+				virtualMachineInstance.DeletionTimestamp = &deletionTimeStamp
+				virtualMachineInstance.Finalizers = []string{"fake/finalizer"}
+			})
+
+			It("Should do nothing", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vmi).ToNot(BeNil())
+			})
+		})
+
+		When("VMI is already deleted, but the grace period annotation is still there", func() {
+			BeforeEach(func() {
+				deletionTimeStamp := metav1.NewTime(time.Now().UTC().Add(-5 * time.Second))
+				virtualMachineInstance.DeletionTimestamp = &deletionTimeStamp
+				virtualMachineInstance.Finalizers = []string{"fake/finalizer"}
+
+				graceTime := time.Now().UTC().Add(-5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+			})
+
+			It("Should remove the grace period annotation", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vmi).ToNot(BeNil())
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine).ToNot(BeNil())
+				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
+			})
+		})
+
+		When("VMI is missing, but the grace period annotation is still there", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(-5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+			})
+
+			It("Should remove the grace period annotation", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				externalMachine.vmiInstance = nil
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vmi).ToNot(BeNil())
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine).ToNot(BeNil())
+				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
+			})
+		})
+
+		When("grace not expired (wrap for BeforeEach)", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+			})
+
+			It("Should drain the node", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+
+				Expect(k8sfake.AddToScheme(setupRemoteScheme())).ToNot(HaveOccurred())
+				cl := k8sfake.NewSimpleClientset(node)
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				recorder := record.NewFakeRecorder(10)
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, Recorder: recorder})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).To(Equal(time.Duration(10 * time.Second)))
+				Expect(conditions.IsTrue(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(BeTrue())
+
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainStartedEventReason)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainSucceededEventReason)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(VMIDeletedEventReason)))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).To(HaveOccurred())
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine).ToNot(BeNil())
+				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
+				Expect(machine.Annotations).To(HaveKeyWithValue(v1alpha1.CordonedNodeAnnotation, nodeName))
+
+				updatedNode, err := cl.CoreV1().Nodes().Get(gocontext.Background(), nodeName, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedNode.Spec.Unschedulable).To(BeTrue())
+			})
+
+			AfterEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.CordonedNodeAnnotation)
+			})
+		})
+
+		When("grace not expired, and the guest OS shutdown grace period is set", func() {
+			var deleteOptions *client.DeleteOptions
+
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Spec.GuestShutdownGracePeriod = &metav1.Duration{Duration: time.Minute}
+				kubevirtMachine.Status.Conditions = nil
+				virtualMachineInstance.Status.Phase = kubevirtv1.Running
+				deleteOptions = nil
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.GuestShutdownGracePeriod = nil
+				kubevirtMachine.Status.Conditions = nil
+				virtualMachineInstance.Status.Phase = ""
+				delete(kubevirtMachine.Annotations, v1alpha1.CordonedNodeAnnotation)
+			})
+
+			drainNode := func(recorder record.EventRecorder) {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				cl := k8sfake.NewSimpleClientset(node)
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				recordingClient := interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+					Delete: func(ctx gocontext.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+						if _, ok := obj.(*kubevirtv1.VirtualMachineInstance); ok {
+							deleteOptions = &client.DeleteOptions{}
+							deleteOptions.ApplyOptions(opts)
+						}
+						return c.Delete(ctx, obj, opts...)
+					},
+				})
+				externalMachine, err := defaultTestMachine(machineContext, namespace, recordingClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, Recorder: recorder})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(virtualMachineInstance), vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				Expect(deleteOptions).ToNot(BeNil())
+			}
+
+			It("Should delete the VMI with the grace period, for KubeVirt to shut down the guest OS", func() {
+				recorder := record.NewFakeRecorder(10)
+				drainNode(recorder)
+
+				Expect(deleteOptions.GracePeriodSeconds).To(Equal(pointer.Int64(60)))
+				Expect(conditions.IsTrue(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(BeTrue())
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainStartedEventReason)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainSucceededEventReason)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(GuestShutdownEventReason)))
+			})
+
+			When("the VMI doesn't run", func() {
+				BeforeEach(func() {
+					virtualMachineInstance.Status.Phase = kubevirtv1.Scheduled
+				})
+
+				It("Should report that the guest OS was not shut down", func() {
+					drainNode(nil)
+
+					Expect(deleteOptions.GracePeriodSeconds).To(BeNil())
+					cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+					Expect(cond).ToNot(BeNil())
+					Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(v1alpha1.GuestShutdownSkippedReason))
+					Expect(cond.Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+				})
+			})
+		})
+
+		When("grace not expired, and the node is deleted after the evacuation", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Spec.DeleteNodeAfterEvacuation = true
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.DeleteNodeAfterEvacuation = false
+				delete(kubevirtMachine.Annotations, v1alpha1.CordonedNodeAnnotation)
+				delete(kubevirtMachine.Annotations, v1alpha1.EvacuatedNodeAnnotation)
+			})
+
+			It("Should delete the tenant cluster node once the VMI is deleted", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				// a tenant cluster node named after the infra cluster node the VMI is evacuated from
+				otherNode := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: infraNodeName,
+					},
+				}
+				cl := k8sfake.NewSimpleClientset(node, otherNode)
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(2)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+				Expect(kubevirtMachine.Annotations).To(HaveKeyWithValue(v1alpha1.EvacuatedNodeAnnotation, nodeName))
+
+				By("deleting the node when the VMI is gone")
+				externalMachine, err = defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.vmiInstance).To(BeNil())
+				Expect(externalMachine.DeleteEvacuatedNode(wlCluster)).To(Succeed())
+
+				_, err = cl.CoreV1().Nodes().Get(gocontext.Background(), nodeName, metav1.GetOptions{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				_, err = cl.CoreV1().Nodes().Get(gocontext.Background(), infraNodeName, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.EvacuatedNodeAnnotation))
+			})
+
+			When("the VM runs again", func() {
+				BeforeEach(func() {
+					kubevirtMachine.Annotations[v1alpha1.EvacuatedNodeAnnotation] = nodeName
+					virtualMachineInstance.Status.Phase = kubevirtv1.Running
+				})
+
+				It("Should not delete the node", func() {
+					wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(externalMachine.DeleteEvacuatedNode(wlCluster)).To(Succeed())
+					Expect(kubevirtMachine.Annotations).ToNot(HaveKey(v1alpha1.EvacuatedNodeAnnotation))
+				})
+			})
+		})
+
+		When("grace not expired, drain fails (wrap for BeforeEach)", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+			})
+
+			It("Should not drain the node", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+
+				Expect(k8sfake.AddToScheme(setupRemoteScheme())).ToNot(HaveOccurred())
+				cl := k8sfake.NewSimpleClientset(node)
+
+				fakeErr := errors.New("fake error: can't get node")
+				cl.PrependReactor("get", "nodes", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, fakeErr
+				})
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(MatchError(fakeErr))
+				Expect(requeueDuration).To(Equal(time.Duration(0)))
+
+				By("VMI should not be deleted")
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vmi).ToNot(BeNil())
+
+				By("the grace period annotation still exists")
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine).ToNot(BeNil())
+				Expect(machine.Annotations).To(HaveKey(v1alpha1.VmiDeletionGraceTime))
+			})
+		})
+
+		When("grace not expired, the tenant cluster kubeconfig can't be read", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should retry the drain", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(nil, errors.New("failed to fetch kubeconfig for workload cluster")).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(BeNumerically(">", 0))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).To(Equal(v1alpha1.TenantClusterClientFailedReason))
+			})
+
+			It("Should delete the VMI without draining the node once the client keeps failing for the tenant unreachable timeout", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(nil, errors.New("failed to get REST config for workload cluster")).Times(2)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, TenantUnreachableTimeout: time.Hour})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(BeNumerically(">", 0))
+				Expect(fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(virtualMachineInstance), &kubevirtv1.VirtualMachineInstance{})).To(Succeed())
+
+				// the client still fails after the tenant unreachable timeout
+				key := NodeKey{Cluster: types.NamespacedName{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Labels[clusterv1.ClusterNameLabel]}, Node: kubevirtMachine.Name}
+				Expect(tracker.unreachable).To(HaveKey(key))
+				tracker.unreachable[key] = time.Now().Add(-2 * time.Hour)
+
+				requeueDuration, err = externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, TenantUnreachableTimeout: time.Hour})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(virtualMachineInstance), &kubevirtv1.VirtualMachineInstance{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("grace not expired, the tenant cluster API server is unreachable", func() {
+			var cl *k8sfake.Clientset
+
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Status.Conditions = nil
+
+				cl = k8sfake.NewSimpleClientset()
+				cl.PrependReactor("get", "nodes", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, errors.New("dial tcp 10.0.0.1:6443: connect: connection refused")
+				})
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+			})
+
+			It("Should retry until the tenant unreachable timeout", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).To(HaveOccurred())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).To(Equal(v1alpha1.TenantClusterUnreachableReason))
+			})
+
+			It("Should delete the VMI without draining the node after the tenant unreachable timeout", func() {
+				tracker.unreachable[NodeKey{Cluster: types.NamespacedName{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Labels[clusterv1.ClusterNameLabel]}, Node: kubevirtMachine.Name}] = time.Now().Add(-time.Hour)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).To(Equal(v1alpha1.TenantClusterUnreachableReason))
+			})
+		})
+
+		When("grace not expired, pod eviction fails (wrap for BeforeEach)", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Spec.DrainTimeout = &metav1.Duration{Duration: time.Second}
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.DrainTimeout = nil
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should back off exponentially and report it in the DrainingSucceeded condition", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-pod",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: nodeName,
+					},
+				}
+
+				cl := k8sfake.NewSimpleClientset(node, pod)
+				// no eviction API, so the pods are deleted
+				cl.Resources = []*metav1.APIResourceList{{GroupVersion: "v1"}}
+				cl.PrependReactor("delete", "pods", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, errors.New("fake error: can't delete pod")
+				})
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(2)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				By("first failure waits the retry interval")
+				recorder := record.NewFakeRecorder(10)
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, Recorder: recorder})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(BeNumerically(">=", defaultDrainRetryInterval))
+				Expect(requeueDuration).To(BeNumerically("<=", time.Duration(float64(defaultDrainRetryInterval)*(1+drainBackoffJitterFactor))))
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(v1alpha1.PodEvictionFailedReason))
+				Expect(cond.Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainStartedEventReason)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainFailedEventReason)))
+
+				By("second failure doubles the wait")
+				requeueDuration, err = externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(BeNumerically(">=", 2*defaultDrainRetryInterval))
+				Expect(requeueDuration).To(BeNumerically("<=", time.Duration(float64(2*defaultDrainRetryInterval)*(1+drainBackoffJitterFactor))))
+
+				By("VMI should not be deleted")
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("grace not expired, pods are not evicted before the drain timeout", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Spec.DrainTimeout = &metav1.Duration{Duration: time.Second}
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.DrainTimeout = nil
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should report the drain is in progress in the DrainingSucceeded condition", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-pod",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: nodeName,
+					},
+				}
+
+				cl := k8sfake.NewSimpleClientset(node, pod)
+				// no eviction API, so the pods are deleted
+				cl.Resources = []*metav1.APIResourceList{{GroupVersion: "v1"}}
+				// the pod deletion is accepted, but the pod is never removed
+				cl.PrependReactor("delete", "pods", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, nil
+				})
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(BeNumerically(">", 0))
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(v1alpha1.DrainingReason))
+				Expect(cond.Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+			})
+		})
+
+		When("grace not expired, the pod eviction is blocked by a PodDisruptionBudget", func() {
+			var cl *k8sfake.Clientset
+
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Spec.DrainTimeout = &metav1.Duration{Duration: time.Second}
+				kubevirtMachine.Status.Conditions = nil
+
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "db",
+						Namespace: "default",
+						Labels:    map[string]string{"app": "db"},
+					},
+					Spec: corev1.PodSpec{
+						NodeName: nodeName,
+					},
+				}
+				pdb := &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "db-pdb",
+						Namespace: "default",
+					},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					},
+					Status: policyv1.PodDisruptionBudgetStatus{
+						DisruptionsAllowed: 0,
+					},
+				}
+
+				cl = k8sfake.NewSimpleClientset(node, pod, pdb)
+				cl.Resources = []*metav1.APIResourceList{
+					{
+						GroupVersion: "v1",
+						APIResources: []metav1.APIResource{{Name: kubedrain.EvictionSubresource, Kind: kubedrain.EvictionKind, Group: "policy", Version: "v1"}},
+					},
+				}
+				cl.PrependReactor("create", "pods", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					if action.GetSubresource() != "eviction" {
+						return false, nil, nil
+					}
+					return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+				})
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.DrainTimeout = nil
+				kubevirtMachine.Spec.PodDisruptionBudgetTimeout = nil
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should report the blocking PodDisruptionBudget in the DrainingSucceeded condition", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(BeNumerically(">", 0))
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(v1alpha1.PodDisruptionBudgetBlockedReason))
+				Expect(cond.Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+				Expect(cond.Message).To(ContainSubstring("default/db-pdb"))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			When("the PodDisruptionBudget timeout is exceeded", func() {
+				BeforeEach(func() {
+					kubevirtMachine.Spec.PodDisruptionBudgetTimeout = &metav1.Duration{Duration: time.Nanosecond}
+				})
+
+				It("Should delete the pods and drain the node", func() {
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).ToNot(HaveOccurred())
+
+					requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(requeueDuration).To(Equal(10 * time.Second))
+					Expect(conditions.IsTrue(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(BeTrue())
+
+					_, err = cl.CoreV1().Pods("default").Get(gocontext.Background(), "db", metav1.GetOptions{})
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		When("grace not expired, the pods on the node are excluded from the drain", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtCluster.Spec.DrainPodExclusionSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "storage"},
+				}
+			})
+
+			AfterEach(func() {
+				kubevirtCluster.Spec.DrainPodExclusionSelector = nil
+			})
+
+			It("Should drain the node without evicting the excluded pods", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				newPod := func(name string, podLabels map[string]string) *corev1.Pod {
+					return &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: "default",
+							Labels:    podLabels,
+						},
+						Spec: corev1.PodSpec{
+							NodeName: nodeName,
+						},
+					}
+				}
+
+				cl := k8sfake.NewSimpleClientset(node, newPod("storage", map[string]string{"app": "storage"}), newPod("monitoring", map[string]string{"app": "monitoring"}))
+				cl.PrependReactor("delete", "pods", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, errors.New("fake error: can't delete pod")
+				})
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{
+					Tracker:              tracker,
+					PodExclusionSelector: labels.SelectorFromSet(labels.Set{"app": "monitoring"}),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("grace not expired, other nodes of the cluster are being drained", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Add(5 * time.Minute).Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should wait for the other drains to complete", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				// the drains started before the max concurrent drains was lowered
+				for _, name := range []string{"other-node1", "other-node2"} {
+					otherNode := externalMachine.nodeDrainKey()
+					otherNode.Node = name
+					Expect(tracker.StartDrain(otherNode, 2)).To(BeTrue())
+				}
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).To(Equal(v1alpha1.WaitingForConcurrentDrainsReason))
+				Expect(cond.Message).To(Equal("waiting for 2 other node drain(s) of the cluster to complete"))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("the owner Machine sets a NodeDrainTimeout", func() {
+			BeforeEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.VmiDeletionGraceTime)
+				machineContext.Machine = machine.DeepCopy()
+				machineContext.Machine.Spec.NodeDrainTimeout = &metav1.Duration{Duration: time.Minute}
+			})
+
+			It("Should use the NodeDrainTimeout as the deletion grace period", func() {
+				cl := k8sfake.NewSimpleClientset()
+				fakeErr := errors.New("fake error: can't get node")
+				cl.PrependReactor("get", "nodes", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, fakeErr
+				})
+
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).To(MatchError(fakeErr))
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine.Annotations).To(HaveKey(v1alpha1.VmiDeletionGraceTime))
+
+				graceTime, err := time.Parse(time.RFC3339, machine.Annotations[v1alpha1.VmiDeletionGraceTime])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(graceTime).To(BeTemporally("~", time.Now().Add(time.Minute), 10*time.Second))
+			})
+		})
+
+		When("the KubevirtMachine has the skip-drain annotation", func() {
+			BeforeEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.VmiDeletionGraceTime)
+				kubevirtMachine.Annotations[v1alpha1.SkipDrainAnnotation] = "true"
+			})
+
+			AfterEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.SkipDrainAnnotation)
+			})
+
+			It("Should delete the VMI without draining the node", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("the VMI has the skip-drain annotation", func() {
+			BeforeEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.VmiDeletionGraceTime)
+				virtualMachineInstance.Annotations = map[string]string{v1alpha1.SkipDrainAnnotation: "true"}
+			})
+
+			It("Should delete the VMI without draining the node", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("the owner Machine has a pre-drain deletion hook", func() {
+			BeforeEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.VmiDeletionGraceTime)
+				kubevirtMachine.Status.Conditions = nil
+				machineContext.Machine = machine.DeepCopy()
+				machineContext.Machine.Annotations = map[string]string{clusterv1.PreDrainDeleteHookAnnotationPrefix + "/backup": "backup-agent"}
+				deletionTimestamp := metav1.Now()
+				machineContext.Machine.DeletionTimestamp = &deletionTimestamp
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Status.Conditions = nil
+				delete(kubevirtMachine.Annotations, v1alpha1.CordonedNodeAnnotation)
+			})
+
+			It("Should wait for the hook to be removed before draining the node", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(Equal(deletionHooksRetryInterval))
+				Expect(conditions.GetReason(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(Equal(v1alpha1.WaitingForDeletionHooksReason))
+				Expect(kubevirtMachine.Annotations).To(HaveKey(v1alpha1.VmiDeletionGraceTime))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("the VMI deletion grace period is exceeded", func() {
+				BeforeEach(func() {
+					kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+				})
+
+				It("Should stop waiting for the hook, and delete the VMI", func() {
+					wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).NotTo(HaveOccurred())
+
+					requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(requeueDuration).Should(Equal(10 * time.Second))
+					Expect(conditions.GetReason(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(Equal(v1alpha1.DrainGracePeriodExceededReason))
+
+					vmi := &kubevirtv1.VirtualMachineInstance{}
+					err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+
+			When("the owner Machine is not being deleted", func() {
+				BeforeEach(func() {
+					machineContext.Machine.DeletionTimestamp = nil
+				})
+
+				It("Should drain the node without waiting for the hook", func() {
+					node := &corev1.Node{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: nodeName,
+						},
+					}
+					cl := k8sfake.NewSimpleClientset(node)
+					wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).NotTo(HaveOccurred())
+
+					requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(requeueDuration).Should(Equal(10 * time.Second))
+					Expect(conditions.IsTrue(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(BeTrue())
+
+					vmi := &kubevirtv1.VirtualMachineInstance{}
+					err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		When("the owner Machine has a pre-terminate deletion hook", func() {
+			BeforeEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.VmiDeletionGraceTime)
+				kubevirtMachine.Annotations[v1alpha1.SkipDrainAnnotation] = "true"
+				machineContext.Machine = machine.DeepCopy()
+				machineContext.Machine.Annotations = map[string]string{clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/backup": "backup-agent"}
+				deletionTimestamp := metav1.Now()
+				machineContext.Machine.DeletionTimestamp = &deletionTimestamp
+			})
+
+			AfterEach(func() {
+				delete(kubevirtMachine.Annotations, v1alpha1.SkipDrainAnnotation)
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should wait for the hook to be removed before deleting the VMI", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(Equal(deletionHooksRetryInterval))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the drain is a dry run", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+				kubevirtMachine.Status.Conditions = nil
+			})
+
+			It("Should only record what it would do", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+				recorder := record.NewFakeRecorder(10)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, Recorder: recorder, DryRun: true})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+				Expect(recorder.Events).To(Receive(ContainSubstring(DrainDryRunEventReason)))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)).To(BeNil())
+			})
+		})
+
+		When("the VMI is live migrated by KubeVirt", func() {
+			BeforeEach(func() {
+				strategy := kubevirtv1.EvictionStrategyLiveMigrate
+				virtualMachineInstance.Spec.EvictionStrategy = &strategy
+				graceTime := time.Now().UTC().Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.EvacuationPolicy = ""
+			})
+
+			It("Should not delete the VMI", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.EvacuationRequested()).To(BeTrue())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			When("the migration failed", func() {
+				BeforeEach(func() {
+					virtualMachineInstance.Status.MigrationState = &kubevirtv1.VirtualMachineInstanceMigrationState{Failed: true}
+				})
+
+				It("Should delete the VMI", func() {
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).NotTo(HaveOccurred())
+
+					requeueDuration, err := externalMachine.DrainNodeIfNeeded(nil, DrainOptions{Tracker: tracker})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(requeueDuration).Should(Equal(10 * time.Second))
+
+					vmi := &kubevirtv1.VirtualMachineInstance{}
+					err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+
+			When("the VMI is not migratable", func() {
+				BeforeEach(func() {
+					virtualMachineInstance.Status.Conditions = []kubevirtv1.VirtualMachineInstanceCondition{
+						{
+							Type:   kubevirtv1.VirtualMachineInstanceIsMigratable,
+							Status: corev1.ConditionFalse,
+						},
+					}
+				})
+
+				It("Should delete the VMI", func() {
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).NotTo(HaveOccurred())
+
+					requeueDuration, err := externalMachine.DrainNodeIfNeeded(nil, DrainOptions{Tracker: tracker})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(requeueDuration).Should(Equal(10 * time.Second))
+
+					vmi := &kubevirtv1.VirtualMachineInstance{}
+					err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+
+			It("Should delete the VMI if the evacuation policy is DrainAndDelete", func() {
+				kubevirtMachine.Spec.EvacuationPolicy = v1alpha1.DrainAndDeleteEvacuationPolicy
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(nil, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("the VMI is evicted while its infra cluster node is schedulable", func() {
+			BeforeEach(func() {
+				kubevirtMachine.Spec.EvacuationPolicy = v1alpha1.DrainAndDeleteEvacuationPolicy
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.EvacuationPolicy = ""
+				kubevirtMachine.Spec.DeschedulerEvictionPolicy = ""
+			})
+
+			It("Should apply the descheduler eviction policy", func() {
+				infraNode := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: infraNodeName,
+					},
+				}
+				Expect(fakeClient.Create(gocontext.Background(), infraNode)).To(Succeed())
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.EvacuationPolicy()).To(Equal(v1alpha1.MigrateEvacuationPolicy))
+
+				kubevirtMachine.Spec.DeschedulerEvictionPolicy = v1alpha1.DeleteMachineEvacuationPolicy
+				Expect(externalMachine.EvacuationPolicy()).To(Equal(v1alpha1.DeleteMachineEvacuationPolicy))
+
+				By("applying the evacuation policy once the infra cluster node is cordoned")
+				infraNode.Spec.Unschedulable = true
+				Expect(fakeClient.Update(gocontext.Background(), infraNode)).To(Succeed())
+				Expect(externalMachine.EvacuationPolicy()).To(Equal(v1alpha1.DrainAndDeleteEvacuationPolicy))
+			})
+
+			It("Should apply the evacuation policy if the infra cluster node is not found", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.EvacuationPolicy()).To(Equal(v1alpha1.DrainAndDeleteEvacuationPolicy))
+			})
+		})
+
+		When("the infra cluster node is cordoned before KubeVirt evacuates the VMI", func() {
+			var infraNode *corev1.Node
+
+			BeforeEach(func() {
+				virtualMachineInstance.Spec.EvictionStrategy = nil
+				virtualMachineInstance.Status.EvacuationNodeName = ""
+				virtualMachineInstance.Status.NodeName = infraNodeName
+				infraNode = &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: infraNodeName,
+					},
+				}
+			})
+
+			AfterEach(func() {
+				virtualMachineInstance.Status.NodeName = ""
+				delete(kubevirtMachine.Annotations, v1alpha1.CordonedNodeAnnotation)
+			})
+
+			It("Should do nothing until the infra cluster node is cordoned", func() {
+				Expect(fakeClient.Create(gocontext.Background(), infraNode)).To(Succeed())
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.CheckInfraNodeCordoned()).To(Succeed())
+				Expect(externalMachine.EvacuationRequested()).To(BeFalse())
+			})
+
+			It("Should drain the tenant cluster node, then delete the VMI", func() {
+				infraNode.Spec.Unschedulable = true
+				Expect(fakeClient.Create(gocontext.Background(), infraNode)).To(Succeed())
+
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "workload",
+						Namespace: "default",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: nodeName,
+					},
+				}
+				cl := k8sfake.NewSimpleClientset(node, pod)
+				// no eviction API, so the pods are deleted
+				cl.Resources = []*metav1.APIResourceList{{GroupVersion: "v1"}}
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Return(cl, nil).Times(1)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.CheckInfraNodeCordoned()).To(Succeed())
+				Expect(externalMachine.EvacuationRequested()).To(BeTrue())
+
+				recorder := record.NewFakeRecorder(10)
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, Recorder: recorder, ProactiveDrain: true})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).To(Equal(10 * time.Second))
+				Expect(recorder.Events).To(Receive(ContainSubstring("Draining node " + nodeName)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(PodEvictedEventReason)))
+				Expect(recorder.Events).To(Receive(ContainSubstring("Drained node " + nodeName)))
+
+				updatedNode, err := cl.CoreV1().Nodes().Get(gocontext.Background(), nodeName, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedNode.Spec.Unschedulable).To(BeTrue())
+				_, err = cl.CoreV1().Pods(pod.Namespace).Get(gocontext.Background(), pod.Name, metav1.GetOptions{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(virtualMachineInstance), vmi)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			When("the VMI is live migratable", func() {
+				BeforeEach(func() {
+					strategy := kubevirtv1.EvictionStrategyLiveMigrate
+					virtualMachineInstance.Spec.EvictionStrategy = &strategy
+				})
+
+				It("Should leave the VMI to the live migration by KubeVirt", func() {
+					infraNode.Spec.Unschedulable = true
+					Expect(fakeClient.Create(gocontext.Background(), infraNode)).To(Succeed())
+					wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+					externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(externalMachine.CheckInfraNodeCordoned()).To(Succeed())
+					Expect(externalMachine.EvacuationRequested()).To(BeFalse())
+
+					requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker, ProactiveDrain: true})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(requeueDuration).To(BeZero())
+
+					vmi := &kubevirtv1.VirtualMachineInstance{}
+					Expect(fakeClient.Get(gocontext.Background(), client.ObjectKeyFromObject(virtualMachineInstance), vmi)).To(Succeed())
+				})
+			})
+
+			It("Should do nothing if the infra cluster node is not found", func() {
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(externalMachine.CheckInfraNodeCordoned()).To(Succeed())
+				Expect(externalMachine.EvacuationRequested()).To(BeFalse())
+			})
+		})
+
+		When("the evacuation policy is DeleteMachine", func() {
+			BeforeEach(func() {
+				kubevirtMachine.Spec.EvacuationPolicy = v1alpha1.DeleteMachineEvacuationPolicy
+			})
+
+			AfterEach(func() {
+				kubevirtMachine.Spec.EvacuationPolicy = ""
+			})
+
+			It("Should leave the VMI to the owner Machine deletion", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(wlCluster, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(BeZero())
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		When("grace period expired (wrap for BeforeEach)", func() {
+			BeforeEach(func() {
+				graceTime := time.Now().UTC().Format(time.RFC3339)
+				kubevirtMachine.Annotations[v1alpha1.VmiDeletionGraceTime] = graceTime
+			})
+
+			It("Should delete the VMI after grace period", func() {
+				wlCluster.EXPECT().GenerateWorkloadClusterK8sClient(gomock.Any()).Times(0)
+
+				externalMachine, err := defaultTestMachine(machineContext, namespace, fakeClient, fakeVMCommandExecutor, []byte(sshKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				requeueDuration, err := externalMachine.DrainNodeIfNeeded(nil, DrainOptions{Tracker: tracker})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requeueDuration).Should(Equal(10 * time.Second))
+
+				vmi := &kubevirtv1.VirtualMachineInstance{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: virtualMachineInstance.Namespace, Name: virtualMachineInstance.Name}, vmi)
+				Expect(err).To(HaveOccurred())
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				cond := conditions.Get(kubevirtMachine, v1alpha1.DrainingSucceededCondition)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Reason).To(Equal(v1alpha1.DrainGracePeriodExceededReason))
+
+				machine := &v1alpha1.KubevirtMachine{}
+				err = fakeClient.Get(gocontext.Background(), client.ObjectKey{Namespace: kubevirtMachine.Namespace, Name: kubevirtMachine.Name}, machine)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(machine).ToNot(BeNil())
+				Expect(machine.Annotations).ToNot(HaveKey(v1alpha1.VmiDeletionGraceTime))
+			})
+		})
+	})
+})
+
+var _ = Describe("util functions", func() {
+	var machineContext *context.MachineContext
+
+	BeforeEach(func() {
+		machineContext = &context.MachineContext{
+			Context:             gocontext.TODO(),
+			Cluster:             cluster,
+			KubevirtCluster:     kubevirtCluster,
+			Machine:             machine,
+			KubevirtMachine:     kubevirtMachine.DeepCopy(),
+			BootstrapDataSecret: bootstrapDataSecret,
+			Logger:              logger,
+		}
+	})
+
+	It("GenerateProviderID should succeed", func() {
+		dataVolumeTemplates := []kubevirtv1.DataVolumeTemplateSpec{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"my": "label"},
+					Annotations: map[string]string{"my": "annotation"},
+					Name:        "dv1",
+				},
+			},
+		}
+		volumes := []kubevirtv1.Volume{
+			{
+				Name: "test1",
+				VolumeSource: kubevirtv1.VolumeSource{
+					DataVolume: &kubevirtv1.DataVolumeSource{
+						Name: "dv1",
+					},
+				},
+			},
+		}
+
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.DataVolumeTemplates = dataVolumeTemplates
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Volumes = volumes
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.DataVolumeTemplates[0].ObjectMeta.Name).To(Equal(kubevirtMachineName + "-dv1"))
 		Expect(newVM.Spec.Template.Spec.Volumes[0].VolumeSource.DataVolume.Name).To(Equal(kubevirtMachineName + "-dv1"))
 	})
+
+	It("newVirtualMachineFromKubevirtMachine should keep the CPU model of KubeVirt when no default is set", func() {
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.CPU).To(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should set the default CPU model", func() {
+		machineContext.DefaultCPUModel = kubevirtv1.CPUModeHostModel
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.CPU).ToNot(BeNil())
+		Expect(newVM.Spec.Template.Spec.Domain.CPU.Model).To(Equal(kubevirtv1.CPUModeHostModel))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should not override the CPU model of the template", func() {
+		machineContext.DefaultCPUModel = kubevirtv1.CPUModeHostModel
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{
+			Model:    kubevirtv1.CPUModeHostPassthrough,
+			Features: []kubevirtv1.CPUFeature{{Name: "pcid", Policy: "require"}},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.CPU.Model).To(Equal(kubevirtv1.CPUModeHostPassthrough))
+		Expect(newVM.Spec.Template.Spec.Domain.CPU.Features).To(HaveLen(1))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should not set the default CPU model with an instancetype", func() {
+		machineContext.DefaultCPUModel = kubevirtv1.CPUModeHostModel
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Instancetype = &kubevirtv1.InstancetypeMatcher{Name: "u1.medium"}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.CPU).To(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should enable SMM for the EFI SecureBoot", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+			Bootloader: &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{}},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.Features).ToNot(BeNil())
+		Expect(newVM.Spec.Template.Spec.Domain.Features.SMM).ToNot(BeNil())
+		Expect(newVM.Spec.Template.Spec.Domain.Features.SMM.Enabled).To(HaveValue(BeTrue()))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should not enable SMM without the EFI SecureBoot", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+			Bootloader: &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{SecureBoot: pointer.Bool(false)}},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.Features).To(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should attach the pod network and the additional networks", func() {
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "infra/storage-net"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Networks).To(HaveLen(2))
+		Expect(newVM.Spec.Template.Spec.Networks[0].Pod).ToNot(BeNil())
+		Expect(newVM.Spec.Template.Spec.Networks[1].Name).To(Equal("storage"))
+		Expect(newVM.Spec.Template.Spec.Networks[1].Multus).To(Equal(&kubevirtv1.MultusNetwork{NetworkName: "infra/storage-net"}))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces).To(HaveLen(2))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces[1].Name).To(Equal("storage"))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces[1].Bridge).ToNot(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should attach the SR-IOV networks and bring them up in the guest", func() {
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "data", NetworkAttachmentDefinition: "sriov-data", Binding: v1alpha1.SRIOVNetworkBinding, PCIAddress: "0000:81:01.0"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		interfaces := newVM.Spec.Template.Spec.Domain.Devices.Interfaces
+		Expect(interfaces).To(HaveLen(2))
+		Expect(interfaces[1].SRIOV).ToNot(BeNil())
+		Expect(interfaces[1].Bridge).To(BeNil())
+		Expect(interfaces[1].PciAddress).To(Equal("0000:81:01.0"))
+
+		var cloudInitVolume *kubevirtv1.Volume
+		for i, volume := range newVM.Spec.Template.Spec.Volumes {
+			if volume.CloudInitConfigDrive != nil {
+				cloudInitVolume = &newVM.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		Expect(cloudInitVolume).ToNot(BeNil())
+		Expect(cloudInitVolume.CloudInitConfigDrive.NetworkData).To(Equal(sriovNetworkData))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should configure the static IP addresses claimed from the IPAM pools", func() {
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "infra/storage-net", MACAddress: "02:00:00:00:00:0A"},
+		}
+		machineContext.IPAddresses = map[string][]string{"storage": {"10.10.0.5/24"}}
+		defer func() { machineContext.IPAddresses = nil }()
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		interfaces := newVM.Spec.Template.Spec.Domain.Devices.Interfaces
+		Expect(interfaces).To(HaveLen(2))
+		Expect(interfaces[0].MacAddress).ToNot(BeEmpty())
+
+		var cloudInitVolume *kubevirtv1.Volume
+		for i, volume := range newVM.Spec.Template.Spec.Volumes {
+			if volume.CloudInitConfigDrive != nil {
+				cloudInitVolume = &newVM.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		Expect(cloudInitVolume).ToNot(BeNil())
+		networkData := cloudInitVolume.CloudInitConfigDrive.NetworkData
+		Expect(networkData).To(ContainSubstring(fmt.Sprintf("macaddress: %q\n    dhcp4: true", interfaces[0].MacAddress)))
+		Expect(networkData).To(ContainSubstring("macaddress: \"02:00:00:00:00:0a\"\n    addresses:\n    - 10.10.0.5/24"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should configure the secondary interfaces and the default gateway", func() {
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "public", NetworkAttachmentDefinition: "public-net", MACAddress: "02:00:00:00:00:0B", Gateway: "192.168.1.1"},
+			{Name: "backup", NetworkAttachmentDefinition: "backup-net", MACAddress: "02:00:00:00:00:0C"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		var networkData string
+		for _, volume := range newVM.Spec.Template.Spec.Volumes {
+			if volume.CloudInitConfigDrive != nil {
+				networkData = volume.CloudInitConfigDrive.NetworkData
+			}
+		}
+		Expect(networkData).To(ContainSubstring("macaddress: \"02:00:00:00:00:0b\"\n    dhcp4: true\n    routes:\n    - to: default\n      via: 192.168.1.1\n"))
+		Expect(networkData).To(ContainSubstring("macaddress: \"02:00:00:00:00:0c\"\n    dhcp4: true\n"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should configure the DNS resolvers of the cluster", func() {
+		machineContext.KubevirtCluster = kubevirtCluster.DeepCopy()
+		machineContext.KubevirtCluster.Spec.DNS = &v1alpha1.DNSConfig{
+			Nameservers: []string{"10.0.0.53", "10.0.1.53"},
+			Searches:    []string{"corp.example.com"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSNone))
+		Expect(newVM.Spec.Template.Spec.DNSConfig).To(Equal(&corev1.PodDNSConfig{
+			Nameservers: []string{"10.0.0.53", "10.0.1.53"},
+			Searches:    []string{"corp.example.com"},
+		}))
+
+		var cloudInitVolume *kubevirtv1.Volume
+		for i, volume := range newVM.Spec.Template.Spec.Volumes {
+			if volume.CloudInitConfigDrive != nil {
+				cloudInitVolume = &newVM.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		Expect(cloudInitVolume).ToNot(BeNil())
+		Expect(cloudInitVolume.CloudInitConfigDrive.NetworkData).To(ContainSubstring(
+			"    dhcp4: true\n    nameservers:\n      addresses: [10.0.0.53, 10.0.1.53]\n      search: [corp.example.com]\n"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should boot from the root disk cloned from the golden image", func() {
+		size := resource.MustParse("20Gi")
+		machineContext.KubevirtMachine.Spec.RootDisk = &v1alpha1.RootDisk{
+			SourceRef:        &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: "ubuntu-22.04"},
+			Size:             &size,
+			StorageClassName: pointer.String("ceph-block"),
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		rootDiskDataVolumeName := machineContext.KubevirtMachine.Name + "-rootdisk"
+		Expect(newVM.Spec.DataVolumeTemplates).To(HaveLen(1))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Name).To(Equal(rootDiskDataVolumeName))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Spec.SourceRef.Name).To(Equal("ubuntu-22.04"))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Spec.Storage.Resources.Requests.Storage().Equal(size)).To(BeTrue())
+		Expect(*newVM.Spec.DataVolumeTemplates[0].Spec.Storage.StorageClassName).To(Equal("ceph-block"))
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElement(kubevirtv1.Volume{
+			Name:         "rootdisk",
+			VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: rootDiskDataVolumeName}},
+		}))
+		bootOrder := uint(1)
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Disks).To(ContainElement(kubevirtv1.Disk{
+			Name:       "rootdisk",
+			DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "virtio"}},
+			BootOrder:  &bootOrder,
+		}))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should replace the root disk of the VM template with the container disk", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{
+			{Name: "rootdisk", VolumeSource: kubevirtv1.VolumeSource{ContainerDisk: &kubevirtv1.ContainerDiskSource{Image: "old-image"}}},
+		}
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{
+			{Name: "rootdisk", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio}}},
+		}
+		machineContext.KubevirtMachine.Spec.RootDisk = &v1alpha1.RootDisk{Image: "new-image", Bus: kubevirtv1.DiskBusSATA}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.DataVolumeTemplates).To(BeEmpty())
+		Expect(newVM.Spec.Template.Spec.Volumes[0]).To(Equal(kubevirtv1.Volume{
+			Name:         "rootdisk",
+			VolumeSource: kubevirtv1.VolumeSource{ContainerDisk: &kubevirtv1.ContainerDiskSource{Image: "new-image"}},
+		}))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Disks[0].Disk.Bus).To(Equal(kubevirtv1.DiskBusSATA))
+		Expect(*newVM.Spec.Template.Spec.Domain.Devices.Disks[0].BootOrder).To(BeEquivalentTo(1))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should attach the blank data disks", func() {
+		blockVolumeMode := corev1.PersistentVolumeBlock
+		machineContext.KubevirtMachine.Spec.DataDisks = []v1alpha1.DataDisk{
+			{Name: "etcd", Size: resource.MustParse("10Gi"), StorageClassName: pointer.String("local-nvme"), Serial: "etcd0",
+				AccessMode: corev1.ReadWriteMany, VolumeMode: &blockVolumeMode},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		dataVolumeName := machineContext.KubevirtMachine.Name + "-etcd"
+		Expect(newVM.Spec.DataVolumeTemplates).To(HaveLen(1))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Name).To(Equal(dataVolumeName))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Spec.Source.Blank).ToNot(BeNil())
+		Expect(newVM.Spec.DataVolumeTemplates[0].Spec.Storage.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+		Expect(*newVM.Spec.DataVolumeTemplates[0].Spec.Storage.StorageClassName).To(Equal("local-nvme"))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Spec.Storage.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+		Expect(*newVM.Spec.DataVolumeTemplates[0].Spec.Storage.VolumeMode).To(Equal(corev1.PersistentVolumeBlock))
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElement(kubevirtv1.Volume{
+			Name:         "etcd",
+			VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: dataVolumeName}},
+		}))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Disks).To(ContainElement(kubevirtv1.Disk{
+			Name:       "etcd",
+			DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio}},
+			Serial:     "etcd0",
+		}))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should attach the ConfigMaps and Secrets as disks", func() {
+		machineContext.KubevirtMachine.Spec.ConfigDisks = []v1alpha1.ConfigDisk{
+			{Name: "registry", ConfigMap: &corev1.LocalObjectReference{Name: "registry-config"}},
+			{Name: "certs", Secret: &corev1.LocalObjectReference{Name: "node-certs"}, Serial: "certs"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElements(
+			kubevirtv1.Volume{Name: "registry", VolumeSource: kubevirtv1.VolumeSource{
+				ConfigMap: &kubevirtv1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "registry-config"}},
+			}},
+			kubevirtv1.Volume{Name: "certs", VolumeSource: kubevirtv1.VolumeSource{
+				Secret: &kubevirtv1.SecretVolumeSource{SecretName: "node-certs"},
+			}},
+		))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Disks).To(ContainElement(kubevirtv1.Disk{
+			Name:       "certs",
+			DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio}},
+			Serial:     "certs",
+		}))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should attach the ISO images as CD-ROMs", func() {
+		machineContext.KubevirtMachine.Spec.CDROMs = []v1alpha1.CDROM{
+			{Name: "virtio", Image: "quay.io/kubevirt/virtio-container-disk"},
+			{Name: "tools", PVC: &corev1.LocalObjectReference{Name: "tools-iso"}, Bus: kubevirtv1.DiskBusSCSI},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElements(
+			kubevirtv1.Volume{Name: "virtio", VolumeSource: kubevirtv1.VolumeSource{
+				ContainerDisk: &kubevirtv1.ContainerDiskSource{Image: "quay.io/kubevirt/virtio-container-disk"},
+			}},
+			kubevirtv1.Volume{Name: "tools", VolumeSource: kubevirtv1.VolumeSource{
+				PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: "tools-iso", ReadOnly: true},
+				},
+			}},
+		))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Disks).To(ContainElements(
+			kubevirtv1.Disk{Name: "virtio", DiskDevice: kubevirtv1.DiskDevice{CDRom: &kubevirtv1.CDRomTarget{Bus: kubevirtv1.DiskBusSATA}}},
+			kubevirtv1.Disk{Name: "tools", DiskDevice: kubevirtv1.DiskDevice{CDRom: &kubevirtv1.CDRomTarget{Bus: kubevirtv1.DiskBusSCSI}}},
+		))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should keep booting from the root disk of the VM template first", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{
+			{Name: "containervolume", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio}}},
+		}
+		machineContext.KubevirtMachine.Spec.CDROMs = []v1alpha1.CDROM{
+			{Name: "virtio", Image: "quay.io/kubevirt/virtio-container-disk", BootOrder: pointer.Uint(2)},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		disks := newVM.Spec.Template.Spec.Domain.Devices.Disks
+		Expect(disks[0].Name).To(Equal("containervolume"))
+		Expect(*disks[0].BootOrder).To(BeEquivalentTo(1))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should boot from the root disk after the devices with a boot order", func() {
+		machineContext.KubevirtMachine.Spec.RootDisk = &v1alpha1.RootDisk{Image: "quay.io/capk/ubuntu-2204-container-disk:v1.27.6", BootOrder: pointer.Uint(2)}
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "provisioning", NetworkAttachmentDefinition: "pxe-net", BootOrder: pointer.Uint(1)},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(*newVM.Spec.Template.Spec.Domain.Devices.Interfaces[1].BootOrder).To(BeEquivalentTo(1))
+		for _, disk := range newVM.Spec.Template.Spec.Domain.Devices.Disks {
+			if disk.Name == "rootdisk" {
+				Expect(*disk.BootOrder).To(BeEquivalentTo(2))
+			}
+		}
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should prefer the DNS configuration of the machine", func() {
+		machineContext.KubevirtCluster = kubevirtCluster.DeepCopy()
+		machineContext.KubevirtCluster.Spec.DNS = &v1alpha1.DNSConfig{Nameservers: []string{"10.0.0.53"}}
+		machineContext.KubevirtMachine.Spec.DNS = &v1alpha1.DNSConfig{Searches: []string{"corp.example.com"}}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.DNSPolicy).To(BeEmpty())
+		Expect(newVM.Spec.Template.Spec.DNSConfig).To(Equal(&corev1.PodDNSConfig{Searches: []string{"corp.example.com"}}))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should override the running field of the template with the run strategy", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Running = pointer.Bool(true)
+		runStrategy := kubevirtv1.RunStrategyRerunOnFailure
+		machineContext.KubevirtMachine.Spec.RunStrategy = &runStrategy
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Running).To(BeNil())
+		Expect(newVM.Spec.RunStrategy).To(HaveValue(Equal(kubevirtv1.RunStrategyRerunOnFailure)))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should override the eviction strategy of the template", func() {
+		liveMigrate := kubevirtv1.EvictionStrategyLiveMigrate
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.EvictionStrategy = &liveMigrate
+		external := kubevirtv1.EvictionStrategyExternal
+		machineContext.KubevirtMachine.Spec.EvictionStrategy = &external
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.EvictionStrategy).To(HaveValue(Equal(kubevirtv1.EvictionStrategyExternal)))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should spread the VMs of the same pool across the infra nodes", func() {
+		machineContext.Machine = machine.DeepCopy()
+		machineContext.Machine.Labels = map[string]string{clusterv1.MachineDeploymentNameLabel: "md-0"}
+		poolSelector := &metav1.LabelSelector{MatchLabels: map[string]string{clusterv1.MachineDeploymentNameLabel: "md-0"}}
+		vmiSpec := &machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec
+		vmiSpec.NodeSelector = map[string]string{"node-role.kubernetes.io/tenant": ""}
+		vmiSpec.Tolerations = []corev1.Toleration{{Key: "tenant", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+		vmiSpec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: poolSelector, TopologyKey: corev1.LabelHostname},
+			},
+		}}
+		vmiSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: poolSelector},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue(clusterv1.MachineDeploymentNameLabel, "md-0"))
+		Expect(newVM.Spec.Template.Spec.NodeSelector).To(Equal(vmiSpec.NodeSelector))
+		Expect(newVM.Spec.Template.Spec.Tolerations).To(Equal(vmiSpec.Tolerations))
+		Expect(newVM.Spec.Template.Spec.Affinity).To(Equal(vmiSpec.Affinity))
+		Expect(newVM.Spec.Template.Spec.TopologySpreadConstraints).To(Equal(vmiSpec.TopologySpreadConstraints))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should schedule the VM in the zone of the failure domain", func() {
+		machineContext.Machine = machine.DeepCopy()
+		machineContext.Machine.Spec.FailureDomain = pointer.String("zone-a")
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/tenant": ""}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{
+			"node-role.kubernetes.io/tenant": "",
+			corev1.LabelTopologyZone:         "zone-a",
+		}))
+		Expect(machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.NodeSelector).To(HaveLen(1))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should apply the overrides of the failure domain of the cluster", func() {
+		machineContext.Machine = machine.DeepCopy()
+		machineContext.Machine.Spec.FailureDomain = pointer.String("edge")
+		machineContext.KubevirtCluster = kubevirtCluster.DeepCopy()
+		machineContext.KubevirtCluster.Spec.FailureDomains = []v1alpha1.FailureDomain{
+			{
+				Name:                         "edge",
+				NodeSelector:                 map[string]string{"example.com/site": "edge"},
+				StorageClassName:             pointer.String("edge-local"),
+				NetworkAttachmentDefinitions: map[string]string{"storage": "edge-storage-net"},
+			},
+		}
+		machineContext.KubevirtMachine.Spec.RootDisk = &v1alpha1.RootDisk{
+			SourceRef:        &cdiv1.DataVolumeSourceRef{Kind: "DataSource", Name: "ubuntu-22.04"},
+			Size:             resource.NewQuantity(20<<30, resource.BinarySI),
+			StorageClassName: pointer.String("ceph-block"),
+		}
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "storage-net"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"example.com/site": "edge"}))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Spec.Storage.StorageClassName).To(HaveValue(Equal("edge-local")))
+		Expect(newVM.Spec.Template.Spec.Networks[1].Multus.NetworkName).To(Equal("edge-storage-net"))
+		Expect(machineContext.KubevirtMachine.Spec.AdditionalNetworks[0].NetworkAttachmentDefinition).To(Equal("storage-net"))
+		Expect(*machineContext.KubevirtMachine.Spec.RootDisk.StorageClassName).To(Equal("ceph-block"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should run the control plane VMs on distinct infra nodes", func() {
+		machineContext.Machine = machine.DeepCopy()
+		machineContext.Machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.PodAffinityTerm{
+			{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+					"cluster.x-k8s.io/cluster-name": clusterName,
+					"cluster.x-k8s.io/role":         "control-plane",
+				}},
+				TopologyKey: corev1.LabelHostname,
+			},
+		}))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should prefer to run the control plane VMs on distinct infra nodes", func() {
+		machineContext.Machine = machine.DeepCopy()
+		machineContext.Machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+		machineContext.KubevirtMachine.Spec.ControlPlaneAntiAffinity = v1alpha1.PreferredAntiAffinityPolicy
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		antiAffinity := newVM.Spec.Template.Spec.Affinity.PodAntiAffinity
+		Expect(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+		Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey).To(Equal(corev1.LabelHostname))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should not add the anti-affinity to the worker VMs", func() {
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Affinity).To(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should override the priority class of the template", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.PriorityClassName = "tenant"
+		machineContext.KubevirtMachine.Spec.PriorityClassName = "tenant-control-plane"
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.PriorityClassName).To(Equal("tenant-control-plane"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should neither restart nor migrate the preemptible VMs", func() {
+		machineContext.KubevirtMachine.Spec.PriorityClassName = "tenant"
+		machineContext.KubevirtMachine.Spec.Preemptible = &v1alpha1.PreemptibleSpec{PriorityClassName: "preemptible"}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.PriorityClassName).To(Equal("preemptible"))
+		Expect(newVM.Spec.Template.Spec.EvictionStrategy).To(HaveValue(Equal(kubevirtv1.EvictionStrategyNone)))
+		Expect(newVM.Spec.Running).To(BeNil())
+		Expect(newVM.Spec.RunStrategy).To(HaveValue(Equal(kubevirtv1.RunStrategyOnce)))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should set the guest memory and the memory request", func() {
+		machineContext.KubevirtMachine.Spec.Memory = &v1alpha1.MemorySpec{
+			Guest:   resource.NewQuantity(8<<30, resource.BinarySI),
+			Request: resource.NewQuantity(6<<30, resource.BinarySI),
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		domain := newVM.Spec.Template.Spec.Domain
+		Expect(domain.Memory.Guest.Value()).To(BeEquivalentTo(8 << 30))
+		Expect(domain.Resources.Requests.Memory().Value()).To(BeEquivalentTo(6 << 30))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should overcommit the memory request of the template", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("6Gi"),
+		}
+		machineContext.KubevirtMachine.Spec.Memory = &v1alpha1.MemorySpec{OvercommitPercent: pointer.Int32(150)}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		domain := newVM.Spec.Template.Spec.Domain
+		Expect(domain.Memory.Guest.Value()).To(BeEquivalentTo(6 << 30))
+		Expect(domain.Resources.Requests.Memory().Value()).To(BeEquivalentTo(4 << 30))
+		Expect(machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Resources.Requests.Memory().String()).To(Equal("6Gi"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should set the limits of the VM of the Guaranteed QoS class to its requests", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU = &kubevirtv1.CPU{Cores: 2, Threads: 2}
+		machineContext.KubevirtMachine.Spec.Memory = &v1alpha1.MemorySpec{Guest: resource.NewQuantity(8<<30, resource.BinarySI)}
+		machineContext.KubevirtMachine.Spec.QOSClass = corev1.PodQOSGuaranteed
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		resources := newVM.Spec.Template.Spec.Domain.Resources
+		Expect(resources.Requests.Cpu().Value()).To(BeEquivalentTo(4))
+		Expect(resources.Requests.Memory().Value()).To(BeEquivalentTo(8 << 30))
+		Expect(resources.Limits).To(Equal(resources.Requests))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should override the kernelBoot of the template", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+			KernelBoot: &kubevirtv1.KernelBoot{
+				Container: &kubevirtv1.KernelBootContainer{Image: "quay.io/example/kernel:old", KernelPath: "/vmlinuz"},
+			},
+		}
+		kernelBoot := &kubevirtv1.KernelBoot{
+			KernelArgs: "console=ttyS0",
+			Container: &kubevirtv1.KernelBootContainer{
+				Image:      "quay.io/example/kernel:new",
+				KernelPath: "/boot/vmlinuz",
+				InitrdPath: "/boot/initrd",
+			},
+		}
+		machineContext.KubevirtMachine.Spec.KernelBoot = kernelBoot
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.Firmware.KernelBoot).To(Equal(kernelBoot))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should log the serial console of the VM when it is captured", func() {
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.LogSerialConsole).To(BeNil())
+
+		machineContext.KubevirtMachine.Spec.SerialConsoleLog = &v1alpha1.SerialConsoleLogSpec{}
+
+		newVM = newVirtualMachineFromKubevirtMachine(machineContext, "default")
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.LogSerialConsole).To(Equal(pointer.Bool(true)))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should name the VM and its DataVolumes after the VM naming", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.DataVolumeTemplates = []kubevirtv1.DataVolumeTemplateSpec{
+			{ObjectMeta: metav1.ObjectMeta{Name: "dv1"}},
+		}
+		machineContext.KubevirtMachine.Spec.VMNaming = &v1alpha1.VMNamingSpec{Prefix: "capk-", Suffix: "-vm", HashLength: 6}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Name).To(MatchRegexp(`^capk-` + kubevirtMachineName + `-vm-[0-9a-f]{6}$`))
+		Expect(newVM.Labels).To(HaveKeyWithValue("kubevirt.io/vm", newVM.Name))
+		Expect(newVM.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("kubevirt.io/vm", newVM.Name))
+		Expect(newVM.Spec.DataVolumeTemplates[0].Name).To(Equal(newVM.Name + "-dv1"))
+		Expect(newVM.Spec.Template.Spec.Hostname).To(Equal(kubevirtMachineName))
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("CloudInitConfigDrive.UserDataSecretRef.Name",
+			UserDataSecretName(machineContext.KubevirtMachine, *machineContext.Machine.Spec.Bootstrap.DataSecretName))))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should deliver the bootstrap data with the NoCloud datasource", func() {
+		machineContext.KubevirtMachine.Spec.CloudInitDatasource = v1alpha1.NoCloudCloudInitDatasource
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("CloudInitNoCloud.UserDataSecretRef.Name",
+			UserDataSecretName(machineContext.KubevirtMachine, *machineContext.Machine.Spec.Bootstrap.DataSecretName))))
+		Expect(newVM.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("CloudInitConfigDrive", Not(BeNil()))))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should deliver the Ignition bootstrap data with the ConfigDrive datasource", func() {
+		machineContext.KubevirtMachine.Spec.CloudInitDatasource = v1alpha1.NoCloudCloudInitDatasource
+		machineContext.BootstrapDataSecret = &corev1.Secret{Data: map[string][]byte{
+			"userdata": []byte(`{"ignition":{"version":"3.3.0"}}`),
+			"format":   []byte("ignition"),
+		}}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("CloudInitConfigDrive", Not(BeNil()))))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should propagate the allowed metadata of the KubevirtMachine", func() {
+		machineContext.KubevirtMachine.Labels = map[string]string{
+			"cost-center":                   "team-a",
+			"example.com/tier":              "gold",
+			"kubevirt.io/vm":                "ignored",
+			"not-allowed":                   "true",
+			"cluster.x-k8s.io/cluster-name": "ignored",
+		}
+		machineContext.KubevirtMachine.Annotations = map[string]string{"example.com/owner": "alice", "not-allowed": "true"}
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Labels = map[string]string{"cost-center": "template"}
+		machineContext.KubevirtMachine.Spec.MetadataPropagation = &v1alpha1.MetadataPropagationSpec{
+			Labels:      []string{"cost-center", "example.com/*", "kubevirt.io/vm", "cluster.x-k8s.io/cluster-name"},
+			Annotations: []string{"example.com/*"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		for _, meta := range []metav1.ObjectMeta{newVM.ObjectMeta, newVM.Spec.Template.ObjectMeta} {
+			Expect(meta.Labels).To(HaveKeyWithValue("example.com/tier", "gold"))
+			Expect(meta.Labels).To(HaveKeyWithValue("kubevirt.io/vm", newVM.Name))
+			Expect(meta.Labels).To(HaveKeyWithValue("cluster.x-k8s.io/cluster-name", machineContext.Cluster.Name))
+			Expect(meta.Labels).ToNot(HaveKey("not-allowed"))
+			Expect(meta.Annotations).To(HaveKeyWithValue("example.com/owner", "alice"))
+			Expect(meta.Annotations).ToNot(HaveKey("not-allowed"))
+		}
+		Expect(newVM.Labels).To(HaveKeyWithValue("cost-center", "template"))
+		Expect(newVM.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("cost-center", "team-a"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should add the bootstrap check probe with the guest agent strategy", func() {
+		machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "guestAgent"
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.ReadinessProbe).ToNot(BeNil())
+		Expect(newVM.Spec.Template.Spec.ReadinessProbe.Exec.Command).To(ContainElement(bootstrapSentinelFile))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should not add the bootstrap check probe with the ssh strategy", func() {
+		machineContext.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy = "ssh"
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.ReadinessProbe).To(BeNil())
+	})
+
+	It("VMName should be the name of the KubevirtMachine without VM naming", func() {
+		Expect(VMName(machineContext.KubevirtMachine)).To(Equal(kubevirtMachineName))
+		Expect(UserDataSecretName(machineContext.KubevirtMachine, "bootstrap")).To(Equal("bootstrap-userdata"))
+	})
+
+	It("VMName should be unique across the namespaces, and at most 63 characters long", func() {
+		machineContext.KubevirtMachine.Name = strings.Repeat("a", 70)
+		machineContext.KubevirtMachine.Spec.VMNaming = &v1alpha1.VMNamingSpec{Prefix: "capk-", HashLength: 8}
+		otherKubevirtMachine := machineContext.KubevirtMachine.DeepCopy()
+		otherKubevirtMachine.Namespace = "other"
+
+		name := VMName(machineContext.KubevirtMachine)
+		Expect(name).To(HaveLen(63))
+		Expect(name).To(HavePrefix("capk-aaa"))
+		Expect(name).ToNot(Equal(VMName(otherKubevirtMachine)))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should attach the pod network with the pod network binding", func() {
+		machineContext.KubevirtMachine.Spec.PodNetworkBinding = v1alpha1.PasstPodNetworkBinding
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Networks).To(Equal([]kubevirtv1.Network{*kubevirtv1.DefaultPodNetwork()}))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces).To(HaveLen(1))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces[0].Passt).ToNot(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should override the pod network binding of the template", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Networks = []kubevirtv1.Network{
+			*kubevirtv1.DefaultPodNetwork(),
+		}
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Interfaces = []kubevirtv1.Interface{
+			*kubevirtv1.DefaultBridgeNetworkInterface(),
+		}
+		machineContext.KubevirtMachine.Spec.PodNetworkBinding = v1alpha1.MasqueradePodNetworkBinding
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces[0].Masquerade).ToNot(BeNil())
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces[0].Bridge).To(BeNil())
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should set the MAC addresses", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Networks = []kubevirtv1.Network{
+			*kubevirtv1.DefaultPodNetwork(),
+		}
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Interfaces = []kubevirtv1.Interface{
+			*kubevirtv1.DefaultMasqueradeNetworkInterface(),
+		}
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "storage-net", MACAddress: "02:00:00:00:00:01"},
+		}
+		machineContext.KubevirtMachine.Status.MACAddresses = []v1alpha1.InterfaceMACAddress{
+			{Name: "default", MACAddress: "02:00:00:00:00:02"},
+			{Name: "storage", MACAddress: "02:00:00:00:00:03"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		interfaces := newVM.Spec.Template.Spec.Domain.Devices.Interfaces
+		Expect(interfaces).To(HaveLen(2))
+		Expect(interfaces[0].MacAddress).To(Equal("02:00:00:00:00:02"))
+		Expect(interfaces[1].MacAddress).To(Equal("02:00:00:00:00:01"))
+	})
+
+	It("newVirtualMachineFromKubevirtMachine should keep the networks of the template", func() {
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Networks = []kubevirtv1.Network{
+			*kubevirtv1.DefaultPodNetwork(),
+		}
+		machineContext.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Interfaces = []kubevirtv1.Interface{
+			*kubevirtv1.DefaultMasqueradeNetworkInterface(),
+		}
+		machineContext.KubevirtMachine.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "storage-net"},
+		}
+
+		newVM := newVirtualMachineFromKubevirtMachine(machineContext, "default")
+
+		Expect(newVM.Spec.Template.Spec.Networks).To(HaveLen(2))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces).To(HaveLen(2))
+		Expect(newVM.Spec.Template.Spec.Domain.Devices.Interfaces[0].Masquerade).ToNot(BeNil())
+	})
+
+	It("drainTimeout and drainRetryInterval should use defaults when not set", func() {
+		m := &Machine{machineContext: machineContext}
+		Expect(m.drainTimeout()).To(Equal(defaultDrainTimeout))
+		Expect(m.drainRetryInterval()).To(Equal(defaultDrainRetryInterval))
+	})
+
+	It("drainTimeout and drainRetryInterval should use the KubevirtMachine spec values", func() {
+		machineContext.KubevirtMachine.Spec.DrainTimeout = &metav1.Duration{Duration: 2 * time.Minute}
+		machineContext.KubevirtMachine.Spec.DrainRetryInterval = &metav1.Duration{Duration: 30 * time.Second}
+
+		m := &Machine{machineContext: machineContext}
+		Expect(m.drainTimeout()).To(Equal(2 * time.Minute))
+		Expect(m.drainRetryInterval()).To(Equal(30 * time.Second))
+	})
+
+	It("skipWaitForDeleteTimeout should use the KubevirtMachine spec value over the drain options", func() {
+		m := &Machine{machineContext: machineContext}
+		Expect(m.skipWaitForDeleteTimeout(DrainOptions{})).To(Equal(DefaultSkipWaitForDeleteTimeout))
+		Expect(m.skipWaitForDeleteTimeout(DrainOptions{SkipWaitForDeleteTimeout: time.Minute})).To(Equal(time.Minute))
+
+		machineContext.KubevirtMachine.Spec.SkipWaitForDeleteTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+		defer func() { machineContext.KubevirtMachine.Spec.SkipWaitForDeleteTimeout = nil }()
+		Expect(m.skipWaitForDeleteTimeout(DrainOptions{SkipWaitForDeleteTimeout: time.Minute})).To(Equal(10 * time.Minute))
+	})
+
+	It("drain settings should fall back to the KubevirtDrainPolicy values", func() {
+		force, maxConcurrent := false, int32(3)
+		machineContext.DrainPolicy = &v1alpha1.KubevirtDrainPolicy{
+			Spec: v1alpha1.KubevirtDrainPolicySpec{
+				DrainTimeout:             &metav1.Duration{Duration: time.Minute},
+				DrainRetryInterval:       &metav1.Duration{Duration: 5 * time.Second},
+				SkipWaitForDeleteTimeout: &metav1.Duration{Duration: 2 * time.Minute},
+				MaxConcurrentDrains:      &maxConcurrent,
+				Force:                    &force,
+				EvacuationPolicy:         v1alpha1.DrainAndDeleteEvacuationPolicy,
+			},
+		}
+		machineContext.KubevirtMachine.Spec.DrainTimeout = &metav1.Duration{Duration: 2 * time.Minute}
+
+		m := &Machine{machineContext: machineContext}
+		Expect(m.drainTimeout()).To(Equal(2 * time.Minute))
+		Expect(m.drainRetryInterval()).To(Equal(5 * time.Second))
+		Expect(m.skipWaitForDeleteTimeout(DrainOptions{SkipWaitForDeleteTimeout: time.Minute})).To(Equal(2 * time.Minute))
+		Expect(m.maxConcurrentDrains()).To(Equal(3))
+		Expect(m.drainForce()).To(BeFalse())
+		Expect(m.podEvictionGracePeriodSeconds()).To(Equal(-1))
+
+		machineContext.DrainPolicy.Spec.PodEvictionGracePeriod = &metav1.Duration{Duration: time.Minute}
+		Expect(m.podEvictionGracePeriodSeconds()).To(Equal(60))
+		machineContext.KubevirtMachine.Spec.PodEvictionGracePeriod = &metav1.Duration{Duration: 30 * time.Second}
+		Expect(m.podEvictionGracePeriodSeconds()).To(Equal(30))
+		Expect(m.EvacuationPolicy()).To(Equal(v1alpha1.DrainAndDeleteEvacuationPolicy))
+	})
 })
 
 var _ = Describe("With KubeVirt VM running externally", func() {
@@ -810,6 +3846,8 @@ func (e FakeVMCommandExecutor) ExecuteCommand(command string) (string, error) {
 		return kubevirtMachineName, nil
 	case "cat /run/cluster-api/bootstrap-success.complete":
 		return "success", nil
+	case "sudo shutdown -h now":
+		return "", nil
 	default:
 		return "", errors.New("unexpected input argument")
 	}
@@ -819,7 +3857,7 @@ func defaultTestMachine(ctx *context.MachineContext, namespace string, client cl
 
 	machine, err := NewMachine(ctx, client, namespace, &ssh.ClusterNodeSshKeys{PublicKey: sshPubKey})
 
-	machine.getCommandExecutor = func(fake string, fakeKeys *ssh.ClusterNodeSshKeys) ssh.VMCommandExecutor {
+	machine.getCommandExecutor = func(fake string, fakePort int32, fakeUser string, fakeKeys *ssh.ClusterNodeSshKeys) ssh.VMCommandExecutor {
 		return vmExecutor
 	}
 
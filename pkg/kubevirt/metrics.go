@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsSubsystem = "capk_node_drain"
+
+	metricsNamespaceLabel = "namespace"
+	metricsClusterLabel   = "cluster"
+)
+
+var (
+	drainsStartedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricsSubsystem,
+		Name:      "started_total",
+		Help:      "Number of tenant cluster node drains started, when their VMs are evacuated from the infra nodes.",
+	}, []string{metricsNamespaceLabel, metricsClusterLabel})
+
+	drainsSucceededTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricsSubsystem,
+		Name:      "succeeded_total",
+		Help:      "Number of tenant cluster node drains that succeeded.",
+	}, []string{metricsNamespaceLabel, metricsClusterLabel})
+
+	drainsFailedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricsSubsystem,
+		Name:      "failed_total",
+		Help:      "Number of failed tenant cluster node drain attempts.",
+	}, []string{metricsNamespaceLabel, metricsClusterLabel})
+
+	drainDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: metricsSubsystem,
+		Name:      "duration_seconds",
+		Help:      "Time from the start of a tenant cluster node drain until it succeeded.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}, []string{metricsNamespaceLabel, metricsClusterLabel})
+
+	podsEvictedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricsSubsystem,
+		Name:      "pods_evicted_total",
+		Help:      "Number of pods evicted or deleted when draining tenant cluster nodes.",
+	}, []string{metricsNamespaceLabel, metricsClusterLabel})
+
+	cordonedNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsSubsystem,
+		Name:      "cordoned_nodes",
+		Help:      "Number of tenant cluster nodes currently cordoned and being drained.",
+	}, []string{metricsNamespaceLabel, metricsClusterLabel})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		drainsStartedTotal,
+		drainsSucceededTotal,
+		drainsFailedTotal,
+		drainDurationSeconds,
+		podsEvictedTotal,
+		cordonedNodes,
+	)
+}
+
+// clusterLabels returns the metrics labels of the tenant cluster.
+func clusterLabels(cluster types.NamespacedName) prometheus.Labels {
+	return prometheus.Labels{
+		metricsNamespaceLabel: cluster.Namespace,
+		metricsClusterLabel:   cluster.Name,
+	}
+}
@@ -10,10 +10,12 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	v1alpha1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	context0 "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 	kubevirt "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/kubevirt"
 	ssh "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/ssh"
 	workloadcluster "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/workloadcluster"
+	v1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -40,6 +42,20 @@ func (m *MockMachineInterface) EXPECT() *MockMachineInterfaceMockRecorder {
 	return m.recorder
 }
 
+// AdditionalAddresses mocks base method.
+func (m *MockMachineInterface) AdditionalAddresses() []v1beta1.MachineAddress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalAddresses")
+	ret0, _ := ret[0].([]v1beta1.MachineAddress)
+	return ret0
+}
+
+// AdditionalAddresses indicates an expected call of AdditionalAddresses.
+func (mr *MockMachineInterfaceMockRecorder) AdditionalAddresses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalAddresses", reflect.TypeOf((*MockMachineInterface)(nil).AdditionalAddresses))
+}
+
 // Address mocks base method.
 func (m *MockMachineInterface) Address() string {
 	m.ctrl.T.Helper()
@@ -54,6 +70,34 @@ func (mr *MockMachineInterfaceMockRecorder) Address() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Address", reflect.TypeOf((*MockMachineInterface)(nil).Address))
 }
 
+// Adopt mocks base method.
+func (m *MockMachineInterface) Adopt() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adopt")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adopt indicates an expected call of Adopt.
+func (mr *MockMachineInterfaceMockRecorder) Adopt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockMachineInterface)(nil).Adopt))
+}
+
+// CheckInfraNodeCordoned mocks base method.
+func (m *MockMachineInterface) CheckInfraNodeCordoned() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInfraNodeCordoned")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckInfraNodeCordoned indicates an expected call of CheckInfraNodeCordoned.
+func (mr *MockMachineInterfaceMockRecorder) CheckInfraNodeCordoned() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInfraNodeCordoned", reflect.TypeOf((*MockMachineInterface)(nil).CheckInfraNodeCordoned))
+}
+
 // Create mocks base method.
 func (m *MockMachineInterface) Create(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -68,6 +112,20 @@ func (mr *MockMachineInterfaceMockRecorder) Create(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMachineInterface)(nil).Create), ctx)
 }
 
+// DegradedReason mocks base method.
+func (m *MockMachineInterface) DegradedReason() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DegradedReason")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DegradedReason indicates an expected call of DegradedReason.
+func (mr *MockMachineInterfaceMockRecorder) DegradedReason() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DegradedReason", reflect.TypeOf((*MockMachineInterface)(nil).DegradedReason))
+}
+
 // Delete mocks base method.
 func (m *MockMachineInterface) Delete() error {
 	m.ctrl.T.Helper()
@@ -82,19 +140,61 @@ func (mr *MockMachineInterfaceMockRecorder) Delete() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMachineInterface)(nil).Delete))
 }
 
+// DeleteEvacuatedNode mocks base method.
+func (m *MockMachineInterface) DeleteEvacuatedNode(arg0 workloadcluster.WorkloadCluster) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvacuatedNode", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvacuatedNode indicates an expected call of DeleteEvacuatedNode.
+func (mr *MockMachineInterfaceMockRecorder) DeleteEvacuatedNode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvacuatedNode", reflect.TypeOf((*MockMachineInterface)(nil).DeleteEvacuatedNode), arg0)
+}
+
 // DrainNodeIfNeeded mocks base method.
-func (m *MockMachineInterface) DrainNodeIfNeeded(arg0 workloadcluster.WorkloadCluster) (time.Duration, error) {
+func (m *MockMachineInterface) DrainNodeIfNeeded(arg0 workloadcluster.WorkloadCluster, arg1 kubevirt.DrainOptions) (time.Duration, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DrainNodeIfNeeded", arg0)
+	ret := m.ctrl.Call(m, "DrainNodeIfNeeded", arg0, arg1)
 	ret0, _ := ret[0].(time.Duration)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DrainNodeIfNeeded indicates an expected call of DrainNodeIfNeeded.
-func (mr *MockMachineInterfaceMockRecorder) DrainNodeIfNeeded(arg0 interface{}) *gomock.Call {
+func (mr *MockMachineInterfaceMockRecorder) DrainNodeIfNeeded(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainNodeIfNeeded", reflect.TypeOf((*MockMachineInterface)(nil).DrainNodeIfNeeded), arg0, arg1)
+}
+
+// EvacuationPolicy mocks base method.
+func (m *MockMachineInterface) EvacuationPolicy() v1alpha1.EvacuationPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvacuationPolicy")
+	ret0, _ := ret[0].(v1alpha1.EvacuationPolicy)
+	return ret0
+}
+
+// EvacuationPolicy indicates an expected call of EvacuationPolicy.
+func (mr *MockMachineInterfaceMockRecorder) EvacuationPolicy() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainNodeIfNeeded", reflect.TypeOf((*MockMachineInterface)(nil).DrainNodeIfNeeded), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvacuationPolicy", reflect.TypeOf((*MockMachineInterface)(nil).EvacuationPolicy))
+}
+
+// EvacuationRequested mocks base method.
+func (m *MockMachineInterface) EvacuationRequested() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvacuationRequested")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// EvacuationRequested indicates an expected call of EvacuationRequested.
+func (mr *MockMachineInterfaceMockRecorder) EvacuationRequested() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvacuationRequested", reflect.TypeOf((*MockMachineInterface)(nil).EvacuationRequested))
 }
 
 // Exists mocks base method.
@@ -126,6 +226,35 @@ func (mr *MockMachineInterfaceMockRecorder) GenerateProviderID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateProviderID", reflect.TypeOf((*MockMachineInterface)(nil).GenerateProviderID))
 }
 
+// InfraNodeTopology mocks base method.
+func (m *MockMachineInterface) InfraNodeTopology() (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InfraNodeTopology")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InfraNodeTopology indicates an expected call of InfraNodeTopology.
+func (mr *MockMachineInterfaceMockRecorder) InfraNodeTopology() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfraNodeTopology", reflect.TypeOf((*MockMachineInterface)(nil).InfraNodeTopology))
+}
+
+// InsufficientCapacity mocks base method.
+func (m *MockMachineInterface) InsufficientCapacity(ctx context.Context) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsufficientCapacity", ctx)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// InsufficientCapacity indicates an expected call of InsufficientCapacity.
+func (mr *MockMachineInterfaceMockRecorder) InsufficientCapacity(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsufficientCapacity", reflect.TypeOf((*MockMachineInterface)(nil).InsufficientCapacity), ctx)
+}
+
 // IsBootstrapped mocks base method.
 func (m *MockMachineInterface) IsBootstrapped() bool {
 	m.ctrl.T.Helper()
@@ -170,6 +299,88 @@ func (mr *MockMachineInterfaceMockRecorder) IsTerminal() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTerminal", reflect.TypeOf((*MockMachineInterface)(nil).IsTerminal))
 }
 
+// PinInstancetypeRevisions mocks base method.
+func (m *MockMachineInterface) PinInstancetypeRevisions() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PinInstancetypeRevisions")
+}
+
+// PinInstancetypeRevisions indicates an expected call of PinInstancetypeRevisions.
+func (mr *MockMachineInterfaceMockRecorder) PinInstancetypeRevisions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinInstancetypeRevisions", reflect.TypeOf((*MockMachineInterface)(nil).PinInstancetypeRevisions))
+}
+
+// Preempted mocks base method.
+func (m *MockMachineInterface) Preempted() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preempted")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Preempted indicates an expected call of Preempted.
+func (mr *MockMachineInterfaceMockRecorder) Preempted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preempted", reflect.TypeOf((*MockMachineInterface)(nil).Preempted))
+}
+
+// ReconcileCPUHotplug mocks base method.
+func (m *MockMachineInterface) ReconcileCPUHotplug() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileCPUHotplug")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileCPUHotplug indicates an expected call of ReconcileCPUHotplug.
+func (mr *MockMachineInterfaceMockRecorder) ReconcileCPUHotplug() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileCPUHotplug", reflect.TypeOf((*MockMachineInterface)(nil).ReconcileCPUHotplug))
+}
+
+// ReconcileHotplugVolumes mocks base method.
+func (m *MockMachineInterface) ReconcileHotplugVolumes() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileHotplugVolumes")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileHotplugVolumes indicates an expected call of ReconcileHotplugVolumes.
+func (mr *MockMachineInterfaceMockRecorder) ReconcileHotplugVolumes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileHotplugVolumes", reflect.TypeOf((*MockMachineInterface)(nil).ReconcileHotplugVolumes))
+}
+
+// ReconcileMemoryHotplug mocks base method.
+func (m *MockMachineInterface) ReconcileMemoryHotplug() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileMemoryHotplug")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileMemoryHotplug indicates an expected call of ReconcileMemoryHotplug.
+func (mr *MockMachineInterfaceMockRecorder) ReconcileMemoryHotplug() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileMemoryHotplug", reflect.TypeOf((*MockMachineInterface)(nil).ReconcileMemoryHotplug))
+}
+
+// ReconcileMutableFields mocks base method.
+func (m *MockMachineInterface) ReconcileMutableFields() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileMutableFields")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileMutableFields indicates an expected call of ReconcileMutableFields.
+func (mr *MockMachineInterfaceMockRecorder) ReconcileMutableFields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileMutableFields", reflect.TypeOf((*MockMachineInterface)(nil).ReconcileMutableFields))
+}
+
 // SupportsCheckingIsBootstrapped mocks base method.
 func (m *MockMachineInterface) SupportsCheckingIsBootstrapped() bool {
 	m.ctrl.T.Helper()
@@ -184,6 +395,70 @@ func (mr *MockMachineInterfaceMockRecorder) SupportsCheckingIsBootstrapped() *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportsCheckingIsBootstrapped", reflect.TypeOf((*MockMachineInterface)(nil).SupportsCheckingIsBootstrapped))
 }
 
+// UnschedulableMessage mocks base method.
+func (m *MockMachineInterface) UnschedulableMessage() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnschedulableMessage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// UnschedulableMessage indicates an expected call of UnschedulableMessage.
+func (mr *MockMachineInterfaceMockRecorder) UnschedulableMessage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnschedulableMessage", reflect.TypeOf((*MockMachineInterface)(nil).UnschedulableMessage))
+}
+
+// UpdateHealthCondition mocks base method.
+func (m *MockMachineInterface) UpdateHealthCondition() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateHealthCondition")
+}
+
+// UpdateHealthCondition indicates an expected call of UpdateHealthCondition.
+func (mr *MockMachineInterfaceMockRecorder) UpdateHealthCondition() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHealthCondition", reflect.TypeOf((*MockMachineInterface)(nil).UpdateHealthCondition))
+}
+
+// UpdateMACAddresses mocks base method.
+func (m *MockMachineInterface) UpdateMACAddresses() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateMACAddresses")
+}
+
+// UpdateMACAddresses indicates an expected call of UpdateMACAddresses.
+func (mr *MockMachineInterfaceMockRecorder) UpdateMACAddresses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMACAddresses", reflect.TypeOf((*MockMachineInterface)(nil).UpdateMACAddresses))
+}
+
+// UpdateMigrationStatus mocks base method.
+func (m *MockMachineInterface) UpdateMigrationStatus() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMigrationStatus")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMigrationStatus indicates an expected call of UpdateMigrationStatus.
+func (mr *MockMachineInterfaceMockRecorder) UpdateMigrationStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMigrationStatus", reflect.TypeOf((*MockMachineInterface)(nil).UpdateMigrationStatus))
+}
+
+// UpdateSpecOutOfDateCondition mocks base method.
+func (m *MockMachineInterface) UpdateSpecOutOfDateCondition(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateSpecOutOfDateCondition", ctx)
+}
+
+// UpdateSpecOutOfDateCondition indicates an expected call of UpdateSpecOutOfDateCondition.
+func (mr *MockMachineInterfaceMockRecorder) UpdateSpecOutOfDateCondition(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpecOutOfDateCondition", reflect.TypeOf((*MockMachineInterface)(nil).UpdateSpecOutOfDateCondition), ctx)
+}
+
 // MockMachineFactory is a mock of MachineFactory interface.
 type MockMachineFactory struct {
 	ctrl     *gomock.Controller
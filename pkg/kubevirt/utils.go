@@ -17,17 +17,84 @@ limitations under the License.
 package kubevirt
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 )
 
+// sriovNetworkData is the cloud-init network configuration of the VMs with SR-IOV interfaces, that brings up all their
+// ethernet interfaces with DHCP.
+const sriovNetworkData = `version: 2
+ethernets:
+  all:
+    match:
+      name: "e*"
+    dhcp4: true
+`
+
+// machinePoolLabels are the labels of the Machines identifying their pool, copied to the VMIs so that the affinity
+// and the topology spread constraints of the VM template can select the VMs of the same pool.
+var machinePoolLabels = []string{
+	clusterv1.MachineControlPlaneNameLabel,
+	clusterv1.MachineDeploymentNameLabel,
+	clusterv1.MachineSetNameLabel,
+	clusterv1.MachinePoolNameLabel,
+}
+
+// rootDiskName is the name of the volume and of the DataVolume template of the root disk of the VMs.
+const rootDiskName = "rootdisk"
+
+// GPUResourceName is the resource of the GPUs in the capacity of the nodes, as read by the cluster autoscaler.
+const GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+// maxInfraNameLength is the maximum length of the names of the VMs, which are used as label values.
+const maxInfraNameLength = 63
+
+// VMName returns the name of the VM of the KubevirtMachine in the infra cluster.
+func VMName(kubevirtMachine *infrav1.KubevirtMachine) string {
+	return infraName(kubevirtMachine, kubevirtMachine.Name)
+}
+
+// UserDataSecretName returns the name of the secret of the infra cluster holding the user data of the VM of the
+// KubevirtMachine, built from its bootstrap data secret.
+func UserDataSecretName(kubevirtMachine *infrav1.KubevirtMachine, dataSecretName string) string {
+	return infraName(kubevirtMachine, dataSecretName+"-userdata")
+}
+
+// infraName returns the name of a resource of the KubevirtMachine in the infra cluster, following its VM naming.
+func infraName(kubevirtMachine *infrav1.KubevirtMachine, name string) string {
+	naming := kubevirtMachine.Spec.VMNaming
+	if naming == nil {
+		return name
+	}
+
+	hash := ""
+	if naming.HashLength > 0 {
+		sum := sha256.Sum256([]byte(kubevirtMachine.Namespace + "/" + kubevirtMachine.Name))
+		hash = "-" + fmt.Sprintf("%x", sum)[:naming.HashLength]
+	}
+
+	if maxLength := maxInfraNameLength - len(naming.Prefix) - len(naming.Suffix) - len(hash); len(name) > maxLength {
+		name = strings.TrimRight(name[:maxLength], "-.")
+	}
+
+	return naming.Prefix + name + naming.Suffix + hash
+}
+
 type CommandExecutor interface {
 	ExecuteCommand(command string) (string, error)
 }
@@ -77,11 +144,23 @@ func newVirtualMachineFromKubevirtMachine(ctx *context.MachineContext, namespace
 
 	virtualMachine.Spec.Template = vmiTemplate
 
+	runStrategy := ctx.KubevirtMachine.Spec.RunStrategy
+	if runStrategy == nil && ctx.KubevirtMachine.Spec.Preemptible != nil {
+		// the preempted VMs are replaced, rather than restarted
+		once := kubevirtv1.RunStrategyOnce
+		runStrategy = &once
+	}
+	if runStrategy != nil {
+		// running and runStrategy are mutually exclusive
+		virtualMachine.Spec.Running = nil
+		virtualMachine.Spec.RunStrategy = runStrategy
+	}
+
 	virtualMachine.APIVersion = "kubevirt.io/v1"
 	virtualMachine.Kind = "VirtualMachine"
 
 	virtualMachine.ObjectMeta = metav1.ObjectMeta{
-		Name:      ctx.KubevirtMachine.Name,
+		Name:      VMName(ctx.KubevirtMachine),
 		Namespace: namespace,
 		Labels:    map[string]string{},
 	}
@@ -94,17 +173,218 @@ func newVirtualMachineFromKubevirtMachine(ctx *context.MachineContext, namespace
 		virtualMachine.ObjectMeta.Annotations = mapCopy(ctx.KubevirtMachine.Spec.VirtualMachineTemplate.ObjectMeta.Annotations)
 	}
 
-	virtualMachine.ObjectMeta.Labels["kubevirt.io/vm"] = ctx.KubevirtMachine.Name
-	virtualMachine.ObjectMeta.Labels["name"] = ctx.KubevirtMachine.Name
+	propagateMetadata(&virtualMachine.ObjectMeta, ctx.KubevirtMachine)
+
+	virtualMachine.ObjectMeta.Labels["kubevirt.io/vm"] = virtualMachine.Name
+	virtualMachine.ObjectMeta.Labels["name"] = virtualMachine.Name
 	virtualMachine.ObjectMeta.Labels["cluster.x-k8s.io/role"] = nodeRole(ctx)
 	virtualMachine.ObjectMeta.Labels["cluster.x-k8s.io/cluster-name"] = ctx.Cluster.Name
 
+	addRootDisk(virtualMachine, ctx.KubevirtMachine.Spec.RootDisk)
+	addDataDisks(virtualMachine, ctx.KubevirtMachine.Spec.DataDisks)
+	setRootDiskBootOrder(&virtualMachine.Spec.Template.Spec, bootDiskName(ctx.KubevirtMachine))
+	setFailureDomainStorageClass(virtualMachine, clusterFailureDomain(ctx))
+
 	// make each datavolume unique by appending machine name as a prefix
-	virtualMachine = prefixDataVolumeTemplates(virtualMachine, ctx.KubevirtMachine.Name)
+	virtualMachine = prefixDataVolumeTemplates(virtualMachine, virtualMachine.Name)
 
 	return virtualMachine
 }
 
+// addRootDisk adds the root disk to the VM, and boots the VM from it. The root disk replaces the volume of the VM
+// template with the same name, if any. A persistent root disk is cloned from the golden image by a DataVolume template.
+func addRootDisk(vm *kubevirtv1.VirtualMachine, rootDisk *infrav1.RootDisk) {
+	if rootDisk == nil {
+		return
+	}
+
+	var volumeSource kubevirtv1.VolumeSource
+	if rootDisk.Image != "" {
+		volumeSource.ContainerDisk = &kubevirtv1.ContainerDiskSource{Image: rootDisk.Image}
+	} else {
+		dataVolumeTemplate := kubevirtv1.DataVolumeTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Name: rootDiskName},
+			Spec: cdiv1.DataVolumeSpec{
+				SourceRef: rootDisk.SourceRef,
+				Storage:   storageSpec(rootDisk.Size, rootDisk.StorageClassName, rootDisk.AccessMode, rootDisk.VolumeMode),
+			},
+		}
+		if rootDisk.PVC != nil {
+			dataVolumeTemplate.Spec.Source = &cdiv1.DataVolumeSource{PVC: rootDisk.PVC}
+		}
+		vm.Spec.DataVolumeTemplates = append(vm.Spec.DataVolumeTemplates, dataVolumeTemplate)
+		volumeSource.DataVolume = &kubevirtv1.DataVolumeSource{Name: rootDiskName}
+	}
+
+	spec := &vm.Spec.Template.Spec
+	replaced := false
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == rootDiskName {
+			spec.Volumes[i].VolumeSource = volumeSource
+			replaced = true
+		}
+	}
+	if !replaced {
+		spec.Volumes = append(spec.Volumes, kubevirtv1.Volume{Name: rootDiskName, VolumeSource: volumeSource})
+	}
+
+	bus := rootDisk.Bus
+	if bus == "" {
+		bus = kubevirtv1.DiskBusVirtio
+	}
+	// the disks without a boot order are not bootable once a disk has one
+	bootOrder := uint(1)
+	if rootDisk.BootOrder != nil {
+		bootOrder = *rootDisk.BootOrder
+	}
+	for i := range spec.Domain.Devices.Disks {
+		if spec.Domain.Devices.Disks[i].Name == rootDiskName {
+			spec.Domain.Devices.Disks[i].DiskDevice = kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: bus}}
+			spec.Domain.Devices.Disks[i].BootOrder = &bootOrder
+			return
+		}
+	}
+	spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, kubevirtv1.Disk{
+		Name:       rootDiskName,
+		DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: bus}},
+		BootOrder:  &bootOrder,
+	})
+}
+
+// storageSpec returns the storage of a DataVolume. CDI picks the storage settings not set from the storage profile of
+// the storage class.
+func storageSpec(size *resource.Quantity, storageClassName *string, accessMode corev1.PersistentVolumeAccessMode, volumeMode *corev1.PersistentVolumeMode) *cdiv1.StorageSpec {
+	storage := &cdiv1.StorageSpec{
+		StorageClassName: storageClassName,
+		VolumeMode:       volumeMode,
+	}
+	if size != nil {
+		storage.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: *size}
+	}
+	if accessMode != "" {
+		storage.AccessModes = []corev1.PersistentVolumeAccessMode{accessMode}
+	}
+	return storage
+}
+
+// bootDiskName returns the name of the root disk of the VM: the root disk of the KubevirtMachine, or else the first disk
+// of the VM template, if any.
+func bootDiskName(kubevirtMachine *infrav1.KubevirtMachine) string {
+	if kubevirtMachine.Spec.RootDisk != nil {
+		return rootDiskName
+	}
+	if vmiTemplate := kubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template; vmiTemplate != nil && len(vmiTemplate.Spec.Domain.Devices.Disks) > 0 {
+		return vmiTemplate.Spec.Domain.Devices.Disks[0].Name
+	}
+	return ""
+}
+
+// setRootDiskBootOrder boots the VM from the root disk first, when other devices of the VM have a boot order but the
+// root disk doesn't, so that the root disk stays bootable. The root disk gets the boot order 1 if no other device has
+// it, or else the boot order after the last one.
+func setRootDiskBootOrder(spec *kubevirtv1.VirtualMachineInstanceSpec, bootDiskName string) {
+	var bootDisk *kubevirtv1.Disk
+	bootOrders := sets.New[uint]()
+	for i := range spec.Domain.Devices.Disks {
+		disk := &spec.Domain.Devices.Disks[i]
+		if disk.Name == bootDiskName {
+			bootDisk = disk
+		}
+		if disk.BootOrder != nil {
+			bootOrders.Insert(*disk.BootOrder)
+		}
+	}
+	for _, iface := range spec.Domain.Devices.Interfaces {
+		if iface.BootOrder != nil {
+			bootOrders.Insert(*iface.BootOrder)
+		}
+	}
+	if bootDisk == nil || bootDisk.BootOrder != nil || bootOrders.Len() == 0 {
+		return
+	}
+
+	bootOrder := uint(1)
+	if bootOrders.Has(bootOrder) {
+		sortedBootOrders := sets.List(bootOrders)
+		bootOrder = sortedBootOrders[len(sortedBootOrders)-1] + 1
+	}
+	bootDisk.BootOrder = &bootOrder
+}
+
+// addDataDisks adds the data disks to the VM, each with the DataVolume template of a blank volume.
+func addDataDisks(vm *kubevirtv1.VirtualMachine, dataDisks []infrav1.DataDisk) {
+	spec := &vm.Spec.Template.Spec
+	for _, dataDisk := range dataDisks {
+		vm.Spec.DataVolumeTemplates = append(vm.Spec.DataVolumeTemplates, kubevirtv1.DataVolumeTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Name: dataDisk.Name},
+			Spec: cdiv1.DataVolumeSpec{
+				Source:  &cdiv1.DataVolumeSource{Blank: &cdiv1.DataVolumeBlankImage{}},
+				Storage: storageSpec(&dataDisk.Size, dataDisk.StorageClassName, dataDisk.AccessMode, dataDisk.VolumeMode),
+			},
+		})
+
+		spec.Volumes = append(spec.Volumes, kubevirtv1.Volume{
+			Name:         dataDisk.Name,
+			VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: dataDisk.Name}},
+		})
+
+		bus := dataDisk.Bus
+		if bus == "" {
+			bus = kubevirtv1.DiskBusVirtio
+		}
+		spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, kubevirtv1.Disk{
+			Name:       dataDisk.Name,
+			DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: bus}},
+			Serial:     dataDisk.Serial,
+			BootOrder:  dataDisk.BootOrder,
+		})
+	}
+}
+
+// addConfigDisks attaches the ConfigMaps and Secrets of the config disks to the VM.
+func addConfigDisks(spec *kubevirtv1.VirtualMachineInstanceSpec, configDisks []infrav1.ConfigDisk) {
+	for _, configDisk := range configDisks {
+		volume := kubevirtv1.Volume{Name: configDisk.Name}
+		if configDisk.ConfigMap != nil {
+			volume.ConfigMap = &kubevirtv1.ConfigMapVolumeSource{LocalObjectReference: *configDisk.ConfigMap}
+		} else if configDisk.Secret != nil {
+			volume.Secret = &kubevirtv1.SecretVolumeSource{SecretName: configDisk.Secret.Name}
+		}
+		spec.Volumes = append(spec.Volumes, volume)
+
+		spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, kubevirtv1.Disk{
+			Name:       configDisk.Name,
+			DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio}},
+			Serial:     configDisk.Serial,
+		})
+	}
+}
+
+// addCDROMs attaches the ISO images of the CD-ROMs to the VM.
+func addCDROMs(spec *kubevirtv1.VirtualMachineInstanceSpec, cdroms []infrav1.CDROM) {
+	for _, cdrom := range cdroms {
+		volume := kubevirtv1.Volume{Name: cdrom.Name}
+		if cdrom.Image != "" {
+			volume.ContainerDisk = &kubevirtv1.ContainerDiskSource{Image: cdrom.Image}
+		} else if cdrom.PVC != nil {
+			volume.PersistentVolumeClaim = &kubevirtv1.PersistentVolumeClaimVolumeSource{
+				PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: cdrom.PVC.Name, ReadOnly: true},
+			}
+		}
+		spec.Volumes = append(spec.Volumes, volume)
+
+		bus := cdrom.Bus
+		if bus == "" {
+			bus = kubevirtv1.DiskBusSATA
+		}
+		spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, kubevirtv1.Disk{
+			Name:       cdrom.Name,
+			DiskDevice: kubevirtv1.DiskDevice{CDRom: &kubevirtv1.CDRomTarget{Bus: bus}},
+			BootOrder:  cdrom.BootOrder,
+		})
+	}
+}
+
 func mapCopy(src map[string]string) map[string]string {
 	dst := map[string]string{}
 	for k, v := range src {
@@ -114,6 +394,47 @@ func mapCopy(src map[string]string) map[string]string {
 	return dst
 }
 
+// propagateMetadata copies the labels and annotations of the KubevirtMachine selected by its metadata propagation
+// allowlist to the metadata of its VM or VMI template, without overriding the ones already set.
+func propagateMetadata(meta *metav1.ObjectMeta, kubevirtMachine *infrav1.KubevirtMachine) {
+	propagation := kubevirtMachine.Spec.MetadataPropagation
+	if propagation == nil {
+		return
+	}
+
+	meta.Labels = propagateAllowedKeys(meta.Labels, kubevirtMachine.Labels, propagation.Labels)
+	meta.Annotations = propagateAllowedKeys(meta.Annotations, kubevirtMachine.Annotations, propagation.Annotations)
+}
+
+// propagateAllowedKeys copies the entries of src whose key is matched by the allowlist to dst, unless dst already
+// has them, and returns dst.
+func propagateAllowedKeys(dst map[string]string, src map[string]string, allowlist []string) map[string]string {
+	for key, value := range src {
+		if _, ok := dst[key]; ok || !keyAllowed(key, allowlist) {
+			continue
+		}
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		dst[key] = value
+	}
+	return dst
+}
+
+// keyAllowed returns true if the key is in the allowlist, or starts with the prefix of an entry ending with "*".
+func keyAllowed(key string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // buildVirtualMachineInstanceTemplate creates VirtualMachineInstanceTemplateSpec.
 func buildVirtualMachineInstanceTemplate(ctx *context.MachineContext) *kubevirtv1.VirtualMachineInstanceTemplateSpec {
 	template := &kubevirtv1.VirtualMachineInstanceTemplateSpec{
@@ -131,23 +452,104 @@ func buildVirtualMachineInstanceTemplate(ctx *context.MachineContext) *kubevirtv
 		template.ObjectMeta.Annotations = mapCopy(ctx.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.ObjectMeta.Annotations)
 	}
 
-	template.ObjectMeta.Labels["kubevirt.io/vm"] = ctx.KubevirtMachine.Name
-	template.ObjectMeta.Labels["name"] = ctx.KubevirtMachine.Name
+	propagateMetadata(&template.ObjectMeta, ctx.KubevirtMachine)
+
+	vmName := VMName(ctx.KubevirtMachine)
+	template.ObjectMeta.Labels["kubevirt.io/vm"] = vmName
+	template.ObjectMeta.Labels["name"] = vmName
 	template.ObjectMeta.Labels["cluster.x-k8s.io/role"] = nodeRole(ctx)
 	template.ObjectMeta.Labels["cluster.x-k8s.io/cluster-name"] = ctx.Cluster.Name
+	for _, label := range machinePoolLabels {
+		if value, ok := ctx.Machine.Labels[label]; ok {
+			template.ObjectMeta.Labels[label] = value
+		}
+	}
 
 	template.Spec = *ctx.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Template.Spec.DeepCopy()
 
+	// the guest agent checks the bootstrap of the VM, which is ready once the bootstrap succeeded
+	if ctx.KubevirtMachine.Spec.BootstrapCheckSpec.CheckStrategy == "guestAgent" && template.Spec.ReadinessProbe == nil {
+		template.Spec.ReadinessProbe = bootstrapCheckProbe()
+	}
+
+	// the guest hostname defaults to the VM name, while the tenant cluster node must be named after the KubevirtMachine
+	if vmName != ctx.KubevirtMachine.Name && template.Spec.Hostname == "" {
+		template.Spec.Hostname = ctx.KubevirtMachine.Name
+	}
+
+	// the instancetype, if any, sets the CPU model of the VM
+	if ctx.DefaultCPUModel != "" && ctx.KubevirtMachine.Spec.VirtualMachineTemplate.Spec.Instancetype == nil {
+		if template.Spec.Domain.CPU == nil {
+			template.Spec.Domain.CPU = &kubevirtv1.CPU{}
+		}
+		if template.Spec.Domain.CPU.Model == "" {
+			template.Spec.Domain.CPU.Model = ctx.DefaultCPUModel
+		}
+	}
+
+	if evictionStrategy := ctx.KubevirtMachine.Spec.EvictionStrategy; evictionStrategy != nil {
+		template.Spec.EvictionStrategy = evictionStrategy
+	}
+
+	if priorityClassName := ctx.KubevirtMachine.Spec.PriorityClassName; priorityClassName != "" {
+		template.Spec.PriorityClassName = priorityClassName
+	}
+
+	// the preempted VMs are replaced, rather than migrated or drained
+	if preemptible := ctx.KubevirtMachine.Spec.Preemptible; preemptible != nil {
+		template.Spec.PriorityClassName = preemptible.PriorityClassName
+		if ctx.KubevirtMachine.Spec.EvictionStrategy == nil {
+			none := kubevirtv1.EvictionStrategyNone
+			template.Spec.EvictionStrategy = &none
+		}
+	}
+
+	failureDomain := clusterFailureDomain(ctx)
+	setFailureDomain(&template.Spec, ctx.Machine.Spec.FailureDomain, failureDomain)
+	setMemory(&template.Spec, ctx.KubevirtMachine.Spec.Memory)
+	if ctx.KubevirtMachine.Spec.QOSClass == corev1.PodQOSGuaranteed {
+		setGuaranteedQOS(&template.Spec)
+	}
+	if util.IsControlPlaneMachine(ctx.Machine) {
+		addControlPlaneAntiAffinity(&template.Spec, ctx.Cluster.Name, ctx.KubevirtMachine.Spec.ControlPlaneAntiAffinity)
+	}
+	setKernelBoot(&template.Spec, ctx.KubevirtMachine.Spec.KernelBoot)
+	if ctx.KubevirtMachine.Spec.SerialConsoleLog != nil {
+		template.Spec.Domain.Devices.LogSerialConsole = pointer.Bool(true)
+	}
+	enableSMMForSecureBoot(&template.Spec)
+	setPodNetworkBinding(&template.Spec, ctx.KubevirtMachine.Spec.PodNetworkBinding)
+	addAdditionalNetworks(&template.Spec, failureDomainNetworks(ctx.KubevirtMachine.Spec.AdditionalNetworks, failureDomain))
+	addConfigDisks(&template.Spec, ctx.KubevirtMachine.Spec.ConfigDisks)
+	addCDROMs(&template.Spec, ctx.KubevirtMachine.Spec.CDROMs)
+	reuseMACAddresses(&template.Spec, ctx.KubevirtMachine.Status.MACAddresses)
+	dns := dnsConfig(ctx)
+	setDNSConfig(&template.Spec, dns)
+
 	cloudInitVolumeName := "cloudinitvolume"
+	userDataSecretRef := &corev1.LocalObjectReference{
+		Name: UserDataSecretName(ctx.KubevirtMachine, *ctx.Machine.Spec.Bootstrap.DataSecretName),
+	}
+	var networkData string
+	switch {
+	case len(ctx.IPAddresses) > 0 || dns != nil:
+		// the interfaces with static IP addresses don't get the DNS configuration through DHCP
+		networkData = staticNetworkData(&template.Spec, ctx, dns)
+	case hasSRIOVNetwork(ctx.KubevirtMachine.Spec.AdditionalNetworks):
+		// KubeVirt doesn't configure the IP address of SR-IOV interfaces in the guest, so the guest must bring them up.
+		networkData = sriovNetworkData
+	case len(ctx.KubevirtMachine.Spec.AdditionalNetworks) > 0:
+		// cloud-init only configures the first interface with DHCP by default
+		networkData = staticNetworkData(&template.Spec, ctx, dns)
+	}
+	datasource := ctx.KubevirtMachine.Spec.CloudInitDatasource
+	if ctx.BootstrapDataFormat() == infrav1.IgnitionBootstrapDataFormat {
+		// Ignition doesn't read the NoCloud datasource
+		datasource = infrav1.ConfigDriveCloudInitDatasource
+	}
 	cloudInitVolume := kubevirtv1.Volume{
-		Name: cloudInitVolumeName,
-		VolumeSource: kubevirtv1.VolumeSource{
-			CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{
-				UserDataSecretRef: &corev1.LocalObjectReference{
-					Name: *ctx.Machine.Spec.Bootstrap.DataSecretName + "-userdata",
-				},
-			},
-		},
+		Name:         cloudInitVolumeName,
+		VolumeSource: cloudInitVolumeSource(datasource, userDataSecretRef, networkData),
 	}
 	template.Spec.Volumes = append(template.Spec.Volumes, cloudInitVolume)
 
@@ -164,6 +566,447 @@ func buildVirtualMachineInstanceTemplate(ctx *context.MachineContext) *kubevirtv
 	return template
 }
 
+// cloudInitVolumeSource returns the source of the volume delivering the user data and network data to the guest with
+// the cloud-init datasource.
+func cloudInitVolumeSource(datasource infrav1.CloudInitDatasource, userDataSecretRef *corev1.LocalObjectReference, networkData string) kubevirtv1.VolumeSource {
+	if datasource == infrav1.NoCloudCloudInitDatasource {
+		return kubevirtv1.VolumeSource{
+			CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+				UserDataSecretRef: userDataSecretRef,
+				NetworkData:       networkData,
+			},
+		}
+	}
+
+	return kubevirtv1.VolumeSource{
+		CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{
+			UserDataSecretRef: userDataSecretRef,
+			NetworkData:       networkData,
+		},
+	}
+}
+
+// enableSMMForSecureBoot enables the System Management Mode required by the EFI SecureBoot, which KubeVirt enables by
+// default with the EFI bootloader, unless it is explicitly disabled.
+func enableSMMForSecureBoot(spec *kubevirtv1.VirtualMachineInstanceSpec) {
+	firmware := spec.Domain.Firmware
+	if firmware == nil || firmware.Bootloader == nil || firmware.Bootloader.EFI == nil {
+		return
+	}
+	if secureBoot := firmware.Bootloader.EFI.SecureBoot; secureBoot != nil && !*secureBoot {
+		return
+	}
+
+	if spec.Domain.Features == nil {
+		spec.Domain.Features = &kubevirtv1.Features{}
+	}
+	if spec.Domain.Features.SMM == nil {
+		spec.Domain.Features.SMM = &kubevirtv1.FeatureState{}
+	}
+	if spec.Domain.Features.SMM.Enabled == nil {
+		spec.Domain.Features.SMM.Enabled = pointer.Bool(true)
+	}
+}
+
+// setKernelBoot boots the VM directly from the kernel and initrd of the given container, overriding the kernelBoot
+// of the VM template.
+func setKernelBoot(spec *kubevirtv1.VirtualMachineInstanceSpec, kernelBoot *kubevirtv1.KernelBoot) {
+	if kernelBoot == nil {
+		return
+	}
+
+	if spec.Domain.Firmware == nil {
+		spec.Domain.Firmware = &kubevirtv1.Firmware{}
+	}
+	spec.Domain.Firmware.KernelBoot = kernelBoot.DeepCopy()
+}
+
+// setPodNetworkBinding sets the binding of the VM interface attached to the pod network, attaching the VM to the pod
+// network first if the VM template has no network.
+func setPodNetworkBinding(spec *kubevirtv1.VirtualMachineInstanceSpec, binding infrav1.PodNetworkBinding) {
+	if binding == "" {
+		return
+	}
+
+	if len(spec.Networks) == 0 && len(spec.Domain.Devices.Interfaces) == 0 {
+		spec.Networks = append(spec.Networks, *kubevirtv1.DefaultPodNetwork())
+		spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, kubevirtv1.Interface{
+			Name: kubevirtv1.DefaultPodNetwork().Name,
+		})
+	}
+
+	for _, network := range spec.Networks {
+		if network.Pod == nil {
+			continue
+		}
+		for i := range spec.Domain.Devices.Interfaces {
+			if spec.Domain.Devices.Interfaces[i].Name == network.Name {
+				spec.Domain.Devices.Interfaces[i].InterfaceBindingMethod = podInterfaceBindingMethod(binding)
+			}
+		}
+	}
+}
+
+func podInterfaceBindingMethod(binding infrav1.PodNetworkBinding) kubevirtv1.InterfaceBindingMethod {
+	switch binding {
+	case infrav1.MasqueradePodNetworkBinding:
+		return kubevirtv1.InterfaceBindingMethod{Masquerade: &kubevirtv1.InterfaceMasquerade{}}
+	case infrav1.PasstPodNetworkBinding:
+		return kubevirtv1.InterfaceBindingMethod{Passt: &kubevirtv1.InterfacePasst{}}
+	default:
+		return kubevirtv1.InterfaceBindingMethod{Bridge: &kubevirtv1.InterfaceBridge{}}
+	}
+}
+
+// addAdditionalNetworks attaches the VM to the additional Multus networks, with a bridge or SR-IOV interface. KubeVirt only
+// attaches the pod network to the VMs without networks, so it is attached first in that case.
+func addAdditionalNetworks(spec *kubevirtv1.VirtualMachineInstanceSpec, additionalNetworks []infrav1.AdditionalNetwork) {
+	if len(additionalNetworks) == 0 {
+		return
+	}
+
+	if len(spec.Networks) == 0 && len(spec.Domain.Devices.Interfaces) == 0 {
+		spec.Networks = append(spec.Networks, *kubevirtv1.DefaultPodNetwork())
+		spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, *kubevirtv1.DefaultBridgeNetworkInterface())
+	}
+
+	for _, additionalNetwork := range additionalNetworks {
+		if hasNetwork(spec, additionalNetwork.Name) {
+			continue
+		}
+		spec.Networks = append(spec.Networks, kubevirtv1.Network{
+			Name: additionalNetwork.Name,
+			NetworkSource: kubevirtv1.NetworkSource{
+				Multus: &kubevirtv1.MultusNetwork{NetworkName: additionalNetwork.NetworkAttachmentDefinition},
+			},
+		})
+		iface := kubevirtv1.Interface{
+			Name:       additionalNetwork.Name,
+			PciAddress: additionalNetwork.PCIAddress,
+			MacAddress: additionalNetwork.MACAddress,
+			BootOrder:  additionalNetwork.BootOrder,
+		}
+		if additionalNetwork.Binding == infrav1.SRIOVNetworkBinding {
+			iface.SRIOV = &kubevirtv1.InterfaceSRIOV{}
+		} else {
+			iface.Bridge = &kubevirtv1.InterfaceBridge{}
+		}
+		spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, iface)
+	}
+}
+
+// reuseMACAddresses sets the MAC addresses recorded in the KubevirtMachine status to the VM interfaces without a MAC
+// address.
+func reuseMACAddresses(spec *kubevirtv1.VirtualMachineInstanceSpec, macAddresses []infrav1.InterfaceMACAddress) {
+	for i := range spec.Domain.Devices.Interfaces {
+		iface := &spec.Domain.Devices.Interfaces[i]
+		if iface.MacAddress != "" {
+			continue
+		}
+		for _, macAddress := range macAddresses {
+			if macAddress.Name == iface.Name {
+				iface.MacAddress = macAddress.MACAddress
+				break
+			}
+		}
+	}
+}
+
+// staticNetworkData returns the cloud-init network configuration of the VMs with static IP addresses or a DNS
+// configuration. The guest interfaces are matched by MAC address, so a stable MAC address is generated for the VM
+// interfaces without one. The interfaces without static IP addresses are configured with DHCP.
+func staticNetworkData(spec *kubevirtv1.VirtualMachineInstanceSpec, ctx *context.MachineContext, dns *infrav1.DNSConfig) string {
+	networkData := &strings.Builder{}
+	networkData.WriteString("version: 2\nethernets:\n")
+
+	// without interfaces in the VM template, KubeVirt attaches the VM to the pod network with its default interface
+	if len(spec.Domain.Devices.Interfaces) == 0 {
+		networkData.WriteString("  all:\n    match:\n      name: \"e*\"\n    dhcp4: true\n")
+		writeNameservers(networkData, dns)
+	}
+
+	for i := range spec.Domain.Devices.Interfaces {
+		iface := &spec.Domain.Devices.Interfaces[i]
+		if iface.MacAddress == "" {
+			iface.MacAddress = generateMACAddress(string(ctx.KubevirtMachine.UID) + "/" + iface.Name)
+		}
+
+		fmt.Fprintf(networkData, "  %s:\n    match:\n      macaddress: %q\n", iface.Name, strings.ToLower(iface.MacAddress))
+		if addresses := ctx.IPAddresses[iface.Name]; len(addresses) > 0 {
+			networkData.WriteString("    addresses:\n")
+			for _, address := range addresses {
+				fmt.Fprintf(networkData, "    - %s\n", address)
+			}
+		} else {
+			networkData.WriteString("    dhcp4: true\n")
+		}
+		if gateway := networkGateway(ctx.KubevirtMachine.Spec.AdditionalNetworks, iface.Name); gateway != "" {
+			fmt.Fprintf(networkData, "    routes:\n    - to: default\n      via: %s\n", gateway)
+		}
+		writeNameservers(networkData, dns)
+	}
+
+	return networkData.String()
+}
+
+// writeNameservers writes the DNS configuration, if any, of an interface of the cloud-init network configuration.
+func writeNameservers(networkData *strings.Builder, dns *infrav1.DNSConfig) {
+	if dns == nil {
+		return
+	}
+
+	networkData.WriteString("    nameservers:\n")
+	if len(dns.Nameservers) > 0 {
+		fmt.Fprintf(networkData, "      addresses: [%s]\n", strings.Join(dns.Nameservers, ", "))
+	}
+	if len(dns.Searches) > 0 {
+		fmt.Fprintf(networkData, "      search: [%s]\n", strings.Join(dns.Searches, ", "))
+	}
+}
+
+// generateMACAddress returns a locally administered unicast MAC address derived from the seed.
+func generateMACAddress(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return fmt.Sprintf("02:%02x:%02x:%02x:%02x:%02x", sum[0], sum[1], sum[2], sum[3], sum[4])
+}
+
+// dnsConfig returns the DNS configuration of the VM: the one of the KubevirtMachine, or the default of the
+// KubevirtCluster.
+func dnsConfig(ctx *context.MachineContext) *infrav1.DNSConfig {
+	if ctx.KubevirtMachine.Spec.DNS != nil {
+		return ctx.KubevirtMachine.Spec.DNS
+	}
+	if ctx.KubevirtCluster != nil {
+		return ctx.KubevirtCluster.Spec.DNS
+	}
+	return nil
+}
+
+// setDNSConfig sets the DNS configuration of the virt-launcher pod, which KubeVirt passes to the guest through DHCP.
+func setDNSConfig(spec *kubevirtv1.VirtualMachineInstanceSpec, dns *infrav1.DNSConfig) {
+	if dns == nil {
+		return
+	}
+
+	if spec.DNSConfig == nil {
+		spec.DNSConfig = &corev1.PodDNSConfig{}
+	}
+	if len(dns.Nameservers) > 0 {
+		spec.DNSPolicy = corev1.DNSNone
+		spec.DNSConfig.Nameservers = dns.Nameservers
+	}
+	if len(dns.Searches) > 0 {
+		spec.DNSConfig.Searches = dns.Searches
+	}
+}
+
+// networkGateway returns the gateway of the default route through the additional network, if any.
+func networkGateway(additionalNetworks []infrav1.AdditionalNetwork, name string) string {
+	for _, network := range additionalNetworks {
+		if network.Name == name {
+			return network.Gateway
+		}
+	}
+	return ""
+}
+
+// hasSRIOVNetwork returns whether the VM is attached to an additional network with an SR-IOV virtual function.
+func hasSRIOVNetwork(additionalNetworks []infrav1.AdditionalNetwork) bool {
+	for _, additionalNetwork := range additionalNetworks {
+		if additionalNetwork.Binding == infrav1.SRIOVNetworkBinding {
+			return true
+		}
+	}
+	return false
+}
+
+func hasNetwork(spec *kubevirtv1.VirtualMachineInstanceSpec, name string) bool {
+	for _, network := range spec.Networks {
+		if network.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setMemory sets the guest memory of the VM, and the memory request of its virt-launcher pod.
+func setMemory(spec *kubevirtv1.VirtualMachineInstanceSpec, memory *infrav1.MemorySpec) {
+	if memory == nil {
+		return
+	}
+
+	if memory.Guest != nil {
+		if spec.Domain.Memory == nil {
+			spec.Domain.Memory = &kubevirtv1.Memory{}
+		}
+		guest := memory.Guest.DeepCopy()
+		spec.Domain.Memory.Guest = &guest
+	}
+
+	request := memory.Request
+	if request == nil && memory.OvercommitPercent != nil {
+		guest, found := spec.Domain.Resources.Requests[corev1.ResourceMemory]
+		if spec.Domain.Memory != nil && spec.Domain.Memory.Guest != nil {
+			guest, found = *spec.Domain.Memory.Guest, true
+		}
+		if !found {
+			return
+		}
+		// KubeVirt sets the guest memory to the memory request when not set
+		if spec.Domain.Memory == nil {
+			spec.Domain.Memory = &kubevirtv1.Memory{}
+		}
+		spec.Domain.Memory.Guest = &guest
+		request = resource.NewQuantity(guest.Value()*100/int64(*memory.OvercommitPercent), resource.BinarySI)
+	}
+	if request == nil {
+		return
+	}
+
+	if spec.Domain.Resources.Requests == nil {
+		spec.Domain.Resources.Requests = corev1.ResourceList{}
+	}
+	spec.Domain.Resources.Requests[corev1.ResourceMemory] = request.DeepCopy()
+}
+
+// setGuaranteedQOS sets the CPU and memory limits of the VM to its requests, so that its virt-launcher pod has the
+// Guaranteed QoS class. The CPU request defaults to the number of vCPUs, and the memory request to the guest memory.
+func setGuaranteedQOS(spec *kubevirtv1.VirtualMachineInstanceSpec) {
+	resources := &spec.Domain.Resources
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	if _, found := resources.Requests[corev1.ResourceCPU]; !found {
+		resources.Requests[corev1.ResourceCPU] = *resource.NewQuantity(vCPUs(spec.Domain.CPU), resource.DecimalSI)
+	}
+	if _, found := resources.Requests[corev1.ResourceMemory]; !found && spec.Domain.Memory != nil && spec.Domain.Memory.Guest != nil {
+		resources.Requests[corev1.ResourceMemory] = spec.Domain.Memory.Guest.DeepCopy()
+	}
+
+	if resources.Limits == nil {
+		resources.Limits = corev1.ResourceList{}
+	}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if request, found := resources.Requests[name]; found {
+			resources.Limits[name] = request.DeepCopy()
+		}
+	}
+}
+
+// vCPUs returns the number of vCPUs of the VM.
+func vCPUs(cpu *kubevirtv1.CPU) int64 {
+	if cpu == nil {
+		return 1
+	}
+	count := int64(1)
+	for _, topology := range []uint32{cpu.Sockets, cpu.Cores, cpu.Threads} {
+		if topology > 0 {
+			count *= int64(topology)
+		}
+	}
+	return count
+}
+
+// clusterFailureDomain returns the failure domain of the KubevirtCluster the Machine runs in, if any.
+func clusterFailureDomain(ctx *context.MachineContext) *infrav1.FailureDomain {
+	if ctx.KubevirtCluster == nil || ctx.Machine.Spec.FailureDomain == nil {
+		return nil
+	}
+	for i, failureDomain := range ctx.KubevirtCluster.Spec.FailureDomains {
+		if failureDomain.Name == *ctx.Machine.Spec.FailureDomain {
+			return &ctx.KubevirtCluster.Spec.FailureDomains[i]
+		}
+	}
+	return nil
+}
+
+// setFailureDomain schedules the VM on the infra nodes of the failure domain of the Machine, if any: the infra nodes
+// selected by the failure domain of the KubevirtCluster, or else the infra nodes of the zone of the same name.
+func setFailureDomain(spec *kubevirtv1.VirtualMachineInstanceSpec, failureDomainName *string, failureDomain *infrav1.FailureDomain) {
+	if failureDomainName == nil || *failureDomainName == "" {
+		return
+	}
+
+	nodeSelector := map[string]string{corev1.LabelTopologyZone: *failureDomainName}
+	if failureDomain != nil && len(failureDomain.NodeSelector) > 0 {
+		nodeSelector = failureDomain.NodeSelector
+	}
+
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range nodeSelector {
+		spec.NodeSelector[key] = value
+	}
+}
+
+// failureDomainNetworks returns the additional networks with the NetworkAttachmentDefinitions of the failure domain.
+func failureDomainNetworks(additionalNetworks []infrav1.AdditionalNetwork, failureDomain *infrav1.FailureDomain) []infrav1.AdditionalNetwork {
+	if failureDomain == nil || len(failureDomain.NetworkAttachmentDefinitions) == 0 {
+		return additionalNetworks
+	}
+
+	networks := make([]infrav1.AdditionalNetwork, len(additionalNetworks))
+	for i, additionalNetwork := range additionalNetworks {
+		networks[i] = *additionalNetwork.DeepCopy()
+		if nad, ok := failureDomain.NetworkAttachmentDefinitions[additionalNetwork.Name]; ok {
+			networks[i].NetworkAttachmentDefinition = nad
+		}
+	}
+	return networks
+}
+
+// setFailureDomainStorageClass sets the storage class of the failure domain, if any, to the DataVolume templates of the
+// VM.
+func setFailureDomainStorageClass(vm *kubevirtv1.VirtualMachine, failureDomain *infrav1.FailureDomain) {
+	if failureDomain == nil || failureDomain.StorageClassName == nil {
+		return
+	}
+
+	for i := range vm.Spec.DataVolumeTemplates {
+		dataVolumeSpec := &vm.Spec.DataVolumeTemplates[i].Spec
+		switch {
+		case dataVolumeSpec.Storage != nil:
+			dataVolumeSpec.Storage.StorageClassName = pointer.String(*failureDomain.StorageClassName)
+		case dataVolumeSpec.PVC != nil:
+			dataVolumeSpec.PVC.StorageClassName = pointer.String(*failureDomain.StorageClassName)
+		default:
+			dataVolumeSpec.Storage = &cdiv1.StorageSpec{StorageClassName: pointer.String(*failureDomain.StorageClassName)}
+		}
+	}
+}
+
+// addControlPlaneAntiAffinity adds the pod anti-affinity of the control plane VMs of the cluster, so that they run on
+// distinct infra nodes, to the anti-affinity of the VM template.
+func addControlPlaneAntiAffinity(spec *kubevirtv1.VirtualMachineInstanceSpec, clusterName string, policy infrav1.AntiAffinityPolicy) {
+	if policy == infrav1.NoneAntiAffinityPolicy {
+		return
+	}
+
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+			"cluster.x-k8s.io/cluster-name": clusterName,
+			"cluster.x-k8s.io/role":         constants.ControlPlaneNodeRoleValue,
+		}},
+		TopologyKey: corev1.LabelHostname,
+	}
+
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.PodAntiAffinity == nil {
+		spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	antiAffinity := spec.Affinity.PodAntiAffinity
+
+	if policy == infrav1.PreferredAntiAffinityPolicy {
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+		return
+	}
+	antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+}
+
 // nodeRole returns the role of this node ("control-plane" or "worker").
 func nodeRole(ctx *context.MachineContext) string {
 	if util.IsControlPlaneMachine(ctx.Machine) {
@@ -171,3 +1014,47 @@ func nodeRole(ctx *context.MachineContext) string {
 	}
 	return constants.WorkerNodeRoleValue
 }
+
+// bootstrapCheckProbe returns the readiness probe of the VMI checking the CAPI Sentinel file through the guest agent.
+// The Sentinel file doesn't survive a reboot of the guest, so the probe also succeeds once the kubelet has its
+// kubeconfig.
+func bootstrapCheckProbe() *kubevirtv1.Probe {
+	return &kubevirtv1.Probe{
+		Handler: kubevirtv1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"test", "-f", bootstrapSentinelFile, "-o", "-f", "/etc/kubernetes/kubelet.conf"},
+			},
+		},
+		PeriodSeconds:  10,
+		TimeoutSeconds: 5,
+	}
+}
+
+// NodeCapacity returns the capacity of the node of a machine with the given spec, as seen from the guest: the number
+// of vCPUs, the guest memory and the number of GPUs of its VM. The CPU and memory of a VM with an instancetype are
+// left out, as they are only known to the infra cluster.
+func NodeCapacity(spec *infrav1.KubevirtMachineSpec) corev1.ResourceList {
+	capacity := corev1.ResourceList{}
+	vmiSpec := &kubevirtv1.VirtualMachineInstanceSpec{}
+	if template := spec.VirtualMachineTemplate.Spec.Template; template != nil {
+		vmiSpec = template.Spec.DeepCopy()
+	}
+
+	if spec.VirtualMachineTemplate.Spec.Instancetype == nil {
+		capacity[corev1.ResourceCPU] = *resource.NewQuantity(vCPUs(vmiSpec.Domain.CPU), resource.DecimalSI)
+
+		// KubeVirt sets the guest memory to the memory request when not set
+		setMemory(vmiSpec, spec.Memory)
+		if vmiSpec.Domain.Memory != nil && vmiSpec.Domain.Memory.Guest != nil {
+			capacity[corev1.ResourceMemory] = vmiSpec.Domain.Memory.Guest.DeepCopy()
+		} else if memory, found := vmiSpec.Domain.Resources.Requests[corev1.ResourceMemory]; found {
+			capacity[corev1.ResourceMemory] = memory.DeepCopy()
+		}
+	}
+
+	if gpus := len(vmiSpec.Domain.Devices.GPUs); gpus > 0 {
+		capacity[GPUResourceName] = *resource.NewQuantity(int64(gpus), resource.DecimalSI)
+	}
+
+	return capacity
+}
@@ -18,13 +18,17 @@ package loadbalancer
 
 import (
 	"fmt"
+	"net"
+	"sort"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/kind/pkg/cluster/constants"
@@ -102,16 +106,21 @@ func (l *LoadBalancer) Create(ctx *context.ClusterContext) error {
 		},
 	}
 
-	lbService.Labels = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta.Labels
-	lbService.Annotations = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta.Annotations
+	setMetadata(lbService, ctx)
 	lbService.Spec.Type = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type
+	lbService.Spec.IPFamilies = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.IPFamilies
+	lbService.Spec.IPFamilyPolicy = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.IPFamilyPolicy
+	if lbService.Spec.IPFamilyPolicy == nil && len(lbService.Spec.IPFamilies) > 1 {
+		policy := corev1.IPFamilyPolicyPreferDualStack
+		lbService.Spec.IPFamilyPolicy = &policy
+	}
+	if lbService.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		lbService.Spec.LoadBalancerIP = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.LoadBalancerIP
+		lbService.Spec.LoadBalancerClass = ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.LoadBalancerClass
+	}
 
 	mutateFn := func() (err error) {
-		if lbService.Labels == nil {
-			lbService.Labels = map[string]string{}
-		}
-		lbService.Labels[clusterv1.ClusterNameLabel] = ctx.Cluster.Name
-
+		setMetadata(lbService, ctx)
 		return nil
 	}
 	if _, err := ctrlutil.CreateOrUpdate(ctx.Context, l.infraClient, lbService, mutateFn); err != nil {
@@ -121,6 +130,59 @@ func (l *LoadBalancer) Create(ctx *context.ClusterContext) error {
 	return nil
 }
 
+// UpdateMetadata sets the labels and annotations of the control plane service template on the existing load
+// balancer, e.g. for the load balancer implementation or external-dns to pick their changes up. The labels and
+// annotations removed from the template are left on the service, as they may have been set by someone else.
+func (l *LoadBalancer) UpdateMetadata(ctx *context.ClusterContext) error {
+	if !l.IsFound() {
+		return nil
+	}
+
+	patchHelper := runtimeclient.MergeFrom(l.service.DeepCopy())
+	if !setMetadata(l.service, ctx) {
+		return nil
+	}
+	if err := l.infraClient.Patch(ctx.Context, l.service, patchHelper); err != nil {
+		return errors.Wrapf(err, "failed to update the metadata of the load balancer service")
+	}
+
+	return nil
+}
+
+// setMetadata sets the labels and annotations of the control plane service template, and the cluster name label, on
+// the load balancer service. It returns whether the service changed.
+func setMetadata(lbService *corev1.Service, ctx *context.ClusterContext) bool {
+	template := ctx.KubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta
+	changed := false
+
+	labels := map[string]string{}
+	for key, value := range template.Labels {
+		labels[key] = value
+	}
+	labels[clusterv1.ClusterNameLabel] = ctx.Cluster.Name
+	for key, value := range labels {
+		if current, ok := lbService.Labels[key]; !ok || current != value {
+			if lbService.Labels == nil {
+				lbService.Labels = map[string]string{}
+			}
+			lbService.Labels[key] = value
+			changed = true
+		}
+	}
+
+	for key, value := range template.Annotations {
+		if current, ok := lbService.Annotations[key]; !ok || current != value {
+			if lbService.Annotations == nil {
+				lbService.Annotations = map[string]string{}
+			}
+			lbService.Annotations[key] = value
+			changed = true
+		}
+	}
+
+	return changed
+}
+
 // IP returns ip address of the load balancer
 func (l *LoadBalancer) IP(ctx *context.ClusterContext) (string, error) {
 	loadBalancer := &corev1.Service{}
@@ -154,7 +216,90 @@ func (l *LoadBalancer) ExternalIP(ctx *context.ClusterContext) (string, error) {
 		return "", fmt.Errorf("the load balancer external IP is not ready yet")
 	}
 
-	return loadBalancer.Status.LoadBalancer.Ingress[0].IP, nil
+	// some load balancer implementations only expose a hostname
+	ingress := loadBalancer.Status.LoadBalancer.Ingress[0]
+	if ingress.IP == "" {
+		return ingress.Hostname, nil
+	}
+	// the external IP of a dual-stack load balancer is the one of the primary IP family of the service
+	if len(loadBalancer.Spec.IPFamilies) > 0 {
+		for _, candidate := range loadBalancer.Status.LoadBalancer.Ingress {
+			if ipFamily(candidate.IP) == loadBalancer.Spec.IPFamilies[0] {
+				return candidate.IP, nil
+			}
+		}
+	}
+	return ingress.IP, nil
+}
+
+func ipFamily(address string) corev1.IPFamily {
+	switch ip := net.ParseIP(address); {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return corev1.IPv4Protocol
+	default:
+		return corev1.IPv6Protocol
+	}
+}
+
+// NodePort returns the port the load balancer is exposed on, on every node of the infra cluster.
+func (l *LoadBalancer) NodePort(ctx *context.ClusterContext) (int32, error) {
+	loadBalancer := &corev1.Service{}
+	loadBalancerKey := runtimeclient.ObjectKey{
+		Namespace: l.infraNamespace,
+		Name:      l.name,
+	}
+	if err := l.infraClient.Get(ctx.Context, loadBalancerKey, loadBalancer); err != nil {
+		return 0, err
+	}
+
+	if len(loadBalancer.Spec.Ports) == 0 || loadBalancer.Spec.Ports[0].NodePort == 0 {
+		return 0, fmt.Errorf("the load balancer node port is not allocated yet")
+	}
+
+	return loadBalancer.Spec.Ports[0].NodePort, nil
+}
+
+// NodeAddresses returns the sorted addresses of the ready infra nodes matching the selector, which the node port of
+// the load balancer is reachable on: their external IP, or their internal IP when they have none.
+func (l *LoadBalancer) NodeAddresses(ctx *context.ClusterContext, nodeSelector *metav1.LabelSelector) ([]string, error) {
+	selector := labels.Everything()
+	if nodeSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(nodeSelector); err != nil {
+			return nil, errors.Wrap(err, "invalid node selector")
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := l.infraClient.List(ctx.Context, nodes, runtimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the infra nodes")
+	}
+
+	var addresses []string
+	for _, node := range nodes.Items {
+		if !node.DeletionTimestamp.IsZero() || !noderefutil.IsNodeReady(&node) {
+			continue
+		}
+		if address := nodeAddress(&node, corev1.NodeExternalIP); address != "" {
+			addresses = append(addresses, address)
+		} else if address := nodeAddress(&node, corev1.NodeInternalIP); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	return addresses, nil
+}
+
+func nodeAddress(node *corev1.Node, addressType corev1.NodeAddressType) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == addressType {
+			return address.Address
+		}
+	}
+	return ""
 }
 
 // Delete deletes load-balancer service.
@@ -39,6 +39,7 @@ var (
 	kubevirtCluster     = testing.NewKubevirtCluster(clusterName, kubevirtClusterName)
 	cluster             = testing.NewCluster(clusterName, kubevirtCluster)
 	loadBalancerService = newLoadBalancerService(clusterContext, kubevirtCluster)
+	loadBalancerClass   = "example.com/lb"
 
 	clusterContext = &context.ClusterContext{
 		Logger:          ctrl.LoggerFrom(gocontext.TODO()).WithName("test"),
@@ -111,6 +112,157 @@ var _ = Describe("Load Balancer", func() {
 			err = lb.Create(clusterContext)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should return error for NodePort()", func() {
+			_, err := lb.NodePort(clusterContext)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when listing the addresses of the infra nodes", func() {
+		newNode := func(name string, nodeLabels map[string]string, ready corev1.ConditionStatus, addresses ...corev1.NodeAddress) *corev1.Node {
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+				Status: corev1.NodeStatus{
+					Addresses:  addresses,
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			edge := map[string]string{"edge": "true"}
+			fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(
+				newNode("node-c", edge, corev1.ConditionTrue,
+					corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.3"},
+					corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.3"}),
+				newNode("node-a", edge, corev1.ConditionTrue, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}),
+				newNode("node-b", edge, corev1.ConditionFalse, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.2"}),
+				newNode("node-d", nil, corev1.ConditionTrue, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.4"}),
+			).Build()
+			lb, err = loadbalancer.NewLoadBalancer(clusterContext, fakeClient, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the sorted addresses of all the ready nodes", func() {
+			Expect(lb.NodeAddresses(clusterContext, nil)).To(Equal([]string{"10.0.0.1", "203.0.113.3", "203.0.113.4"}))
+		})
+
+		It("should only return the addresses of the selected nodes", func() {
+			selector := &metav1.LabelSelector{MatchLabels: map[string]string{"edge": "true"}}
+			Expect(lb.NodeAddresses(clusterContext, selector)).To(Equal([]string{"10.0.0.1", "203.0.113.3"}))
+		})
+	})
+
+	Context("when the control plane service template sets the service type", func() {
+		var lbClusterContext *context.ClusterContext
+
+		BeforeEach(func() {
+			lbKubevirtCluster := kubevirtCluster.DeepCopy()
+			lbKubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec = infrav1.ServiceSpecTemplate{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerIP:    "192.168.1.10",
+				LoadBalancerClass: &loadBalancerClass,
+			}
+			lbClusterContext = &context.ClusterContext{
+				Logger:          clusterContext.Logger,
+				Context:         clusterContext.Context,
+				Cluster:         cluster,
+				KubevirtCluster: lbKubevirtCluster,
+			}
+			fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(cluster, lbKubevirtCluster).Build()
+			lb, err = loadbalancer.NewLoadBalancer(lbClusterContext, fakeClient, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		getService := func() *corev1.Service {
+			service := &corev1.Service{}
+			Expect(fakeClient.Get(gocontext.TODO(), client.ObjectKey{Name: clusterName + "-lb"}, service)).To(Succeed())
+			return service
+		}
+
+		It("should create a load balancer with the requested IP and class", func() {
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+
+			service := getService()
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+			Expect(service.Spec.LoadBalancerIP).To(Equal("192.168.1.10"))
+			Expect(service.Spec.LoadBalancerClass).To(Equal(&loadBalancerClass))
+		})
+
+		It("should ignore the load balancer fields for a service of type NodePort", func() {
+			lbClusterContext.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type = corev1.ServiceTypeNodePort
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+
+			service := getService()
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			Expect(service.Spec.LoadBalancerIP).To(BeEmpty())
+			Expect(service.Spec.LoadBalancerClass).To(BeNil())
+		})
+
+		It("should return the hostname of a load balancer without external IP", func() {
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+			service := getService()
+			service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+			Expect(fakeClient.Status().Update(gocontext.TODO(), service)).To(Succeed())
+
+			Expect(lb.ExternalIP(lbClusterContext)).To(Equal("lb.example.com"))
+		})
+
+		It("should create a load balancer with the labels and annotations of the template", func() {
+			lbClusterContext.KubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta = metav1.ObjectMeta{
+				Labels:      map[string]string{"pool": "tenants"},
+				Annotations: map[string]string{"metallb.universe.tf/address-pool": "tenants"},
+			}
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+
+			service := getService()
+			Expect(service.Labels).To(Equal(map[string]string{"pool": "tenants", "cluster.x-k8s.io/cluster-name": clusterName}))
+			Expect(service.Annotations).To(Equal(map[string]string{"metallb.universe.tf/address-pool": "tenants"}))
+			Expect(lbClusterContext.KubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta.Labels).To(HaveLen(1))
+		})
+
+		It("should update the labels and annotations of an existing load balancer", func() {
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+			service := getService()
+			service.Annotations = map[string]string{"other": "value", "external-dns.alpha.kubernetes.io/hostname": "old.example.com"}
+			Expect(fakeClient.Update(gocontext.TODO(), service)).To(Succeed())
+
+			lbClusterContext.KubevirtCluster.Spec.ControlPlaneServiceTemplate.ObjectMeta.Annotations = map[string]string{
+				"external-dns.alpha.kubernetes.io/hostname": "api.example.com",
+			}
+			lb, err = loadbalancer.NewLoadBalancer(lbClusterContext, fakeClient, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lb.UpdateMetadata(lbClusterContext)).To(Succeed())
+
+			Expect(getService().Annotations).To(Equal(map[string]string{
+				"other": "value", "external-dns.alpha.kubernetes.io/hostname": "api.example.com",
+			}))
+		})
+
+		It("should create a dual-stack load balancer", func() {
+			lbClusterContext.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+
+			service := getService()
+			Expect(service.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}))
+			Expect(service.Spec.IPFamilyPolicy).ToNot(BeNil())
+			Expect(*service.Spec.IPFamilyPolicy).To(Equal(corev1.IPFamilyPolicyPreferDualStack))
+
+			service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.168.1.10"}, {IP: "fd00::10"}}
+			Expect(fakeClient.Status().Update(gocontext.TODO(), service)).To(Succeed())
+			Expect(lb.ExternalIP(lbClusterContext)).To(Equal("fd00::10"))
+		})
+
+		It("should return the node port of the load balancer", func() {
+			lbClusterContext.KubevirtCluster.Spec.ControlPlaneServiceTemplate.Spec.Type = corev1.ServiceTypeNodePort
+			Expect(lb.Create(lbClusterContext)).To(Succeed())
+			service := getService()
+			service.Spec.Ports[0].NodePort = 30443
+			Expect(fakeClient.Update(gocontext.TODO(), service)).To(Succeed())
+
+			Expect(lb.NodePort(lbClusterContext)).To(Equal(int32(30443)))
+		})
 	})
 })
 
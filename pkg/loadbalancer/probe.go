@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	gocontext "context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// probeTimeout is how long the TLS connection to a control plane endpoint may take to be established.
+const probeTimeout = 5 * time.Second
+
+// ProbeEndpoint checks that the control plane endpoint at the given address accepts TLS connections. The certificate
+// of the API server is not verified, as only the reachability of the endpoint is checked.
+func ProbeEndpoint(ctx gocontext.Context, address string) error {
+	ctx, cancel := gocontext.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: probeTimeout},
+		Config:    &tls.Config{InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
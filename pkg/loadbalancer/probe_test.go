@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer_test
+
+import (
+	gocontext "context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/loadbalancer"
+)
+
+var _ = Describe("ProbeEndpoint", func() {
+	It("should succeed for an endpoint accepting TLS connections", func() {
+		server := httptest.NewTLSServer(http.NotFoundHandler())
+		defer server.Close()
+
+		Expect(loadbalancer.ProbeEndpoint(gocontext.TODO(), server.Listener.Addr().String())).To(Succeed())
+	})
+
+	It("should fail for an endpoint not accepting TLS connections", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		Expect(loadbalancer.ProbeEndpoint(gocontext.TODO(), server.Listener.Addr().String())).ToNot(Succeed())
+	})
+
+	It("should fail for an endpoint refusing the connections", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		address := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		Expect(loadbalancer.ProbeEndpoint(gocontext.TODO(), address)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
+)
+
+const (
+	// DefaultRoutePort is the port of the control plane endpoint of a ControlPlaneRoute which doesn't set one.
+	DefaultRoutePort = 443
+
+	// sslPassthroughAnnotation makes ingress-nginx pass the TLS connections through to the API server.
+	sslPassthroughAnnotation = "nginx.ingress.kubernetes.io/ssl-passthrough"
+)
+
+// TLSRouteGVK is the kind of the Gateway API route of the control plane, unstructured for the Gateway API not to be
+// required in the infra cluster when no Gateway is used.
+var TLSRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TLSRoute"}
+
+// ReconcileRoute creates or updates the TLSRoute or the Ingress routing the TLS connections for the hostname of the
+// ControlPlaneRoute to the load balancer, and deletes the other one. The route objects are left in place when the
+// ControlPlaneRoute is removed, until the cluster is deleted.
+func (l *LoadBalancer) ReconcileRoute(ctx *context.ClusterContext) error {
+	route := ctx.KubevirtCluster.Spec.ControlPlaneRoute
+	if route == nil {
+		return nil
+	}
+
+	if route.Gateway != nil {
+		if err := l.deleteRouteObject(ctx, &networkingv1.Ingress{}); err != nil {
+			return err
+		}
+		return l.reconcileTLSRoute(ctx, route)
+	}
+
+	if err := l.deleteRouteObject(ctx, l.newTLSRoute()); err != nil {
+		return err
+	}
+	return l.reconcileIngress(ctx, route)
+}
+
+// DeleteRoute deletes the TLSRoute and the Ingress of the load balancer, if existing.
+func (l *LoadBalancer) DeleteRoute(ctx *context.ClusterContext) error {
+	if err := l.deleteRouteObject(ctx, l.newTLSRoute()); err != nil {
+		return err
+	}
+	return l.deleteRouteObject(ctx, &networkingv1.Ingress{})
+}
+
+func (l *LoadBalancer) reconcileTLSRoute(ctx *context.ClusterContext, route *infrav1.ControlPlaneRoute) error {
+	tlsRoute := l.newTLSRoute()
+	tlsRoute.SetNamespace(l.infraNamespace)
+	tlsRoute.SetName(l.name)
+
+	parentRef := map[string]interface{}{"name": route.Gateway.Name}
+	if route.Gateway.Namespace != "" {
+		parentRef["namespace"] = route.Gateway.Namespace
+	}
+	if route.Gateway.SectionName != "" {
+		parentRef["sectionName"] = route.Gateway.SectionName
+	}
+
+	mutateFn := func() error {
+		setRouteMetadata(tlsRoute, route, ctx.Cluster.Name)
+		tlsRoute.Object["spec"] = map[string]interface{}{
+			"parentRefs": []interface{}{parentRef},
+			"hostnames":  []interface{}{route.Hostname},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": l.name, "port": int64(6443)},
+					},
+				},
+			},
+		}
+		return nil
+	}
+	if _, err := ctrlutil.CreateOrUpdate(ctx.Context, l.infraClient, tlsRoute, mutateFn); err != nil {
+		return errors.Wrapf(err, "failed to create or update the TLSRoute of the load balancer")
+	}
+
+	return nil
+}
+
+func (l *LoadBalancer) reconcileIngress(ctx *context.ClusterContext, route *infrav1.ControlPlaneRoute) error {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: l.infraNamespace,
+			Name:      l.name,
+		},
+	}
+
+	pathType := networkingv1.PathTypeImplementationSpecific
+	mutateFn := func() error {
+		setRouteMetadata(ingress, route, ctx.Cluster.Name)
+		if _, ok := route.Annotations[sslPassthroughAnnotation]; !ok {
+			ingress.Annotations[sslPassthroughAnnotation] = "true"
+		}
+		ingress.Spec = networkingv1.IngressSpec{
+			IngressClassName: route.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: route.Hostname,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: l.name,
+							Port: networkingv1.ServiceBackendPort{Number: 6443},
+						}},
+					}},
+				}},
+			}},
+		}
+		return nil
+	}
+	if _, err := ctrlutil.CreateOrUpdate(ctx.Context, l.infraClient, ingress, mutateFn); err != nil {
+		return errors.Wrapf(err, "failed to create or update the Ingress of the load balancer")
+	}
+
+	return nil
+}
+
+// deleteRouteObject deletes the route object of the load balancer of the given kind, ignoring the kinds not served by
+// the infra cluster.
+func (l *LoadBalancer) deleteRouteObject(ctx *context.ClusterContext, obj runtimeclient.Object) error {
+	obj.SetNamespace(l.infraNamespace)
+	obj.SetName(l.name)
+	if err := l.infraClient.Delete(ctx.Context, obj); err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return errors.Wrapf(err, "failed to delete the route %s of the load balancer", l.name)
+	}
+	return nil
+}
+
+func (l *LoadBalancer) newTLSRoute() *unstructured.Unstructured {
+	tlsRoute := &unstructured.Unstructured{}
+	tlsRoute.SetGroupVersionKind(TLSRouteGVK)
+	return tlsRoute
+}
+
+// setRouteMetadata sets the annotations of the ControlPlaneRoute and the cluster name label on the route object.
+func setRouteMetadata(obj runtimeclient.Object, route *infrav1.ControlPlaneRoute, clusterName string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[clusterv1.ClusterNameLabel] = clusterName
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for key, value := range route.Annotations {
+		annotations[key] = value
+	}
+	obj.SetAnnotations(annotations)
+}
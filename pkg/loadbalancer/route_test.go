@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer_test
+
+import (
+	gocontext "context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/loadbalancer"
+	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/testing"
+)
+
+var _ = Describe("Load Balancer route", func() {
+	var (
+		fakeClient          client.Client
+		lb                  *loadbalancer.LoadBalancer
+		routeClusterContext *context.ClusterContext
+		routeKey            = client.ObjectKey{Name: clusterName + "-lb"}
+	)
+
+	BeforeEach(func() {
+		routeKubevirtCluster := kubevirtCluster.DeepCopy()
+		routeKubevirtCluster.Spec.ControlPlaneRoute = &infrav1.ControlPlaneRoute{
+			Hostname:    "tenant.example.com",
+			Annotations: map[string]string{"example.com/team": "tenants"},
+		}
+		routeClusterContext = &context.ClusterContext{
+			Logger:          clusterContext.Logger,
+			Context:         clusterContext.Context,
+			Cluster:         cluster,
+			KubevirtCluster: routeKubevirtCluster,
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(testing.SetupScheme()).WithObjects(cluster, routeKubevirtCluster).Build()
+		var err error
+		lb, err = loadbalancer.NewLoadBalancer(routeClusterContext, fakeClient, "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	getTLSRoute := func() (*unstructured.Unstructured, error) {
+		tlsRoute := &unstructured.Unstructured{}
+		tlsRoute.SetGroupVersionKind(loadbalancer.TLSRouteGVK)
+		return tlsRoute, fakeClient.Get(gocontext.TODO(), routeKey, tlsRoute)
+	}
+
+	It("should route the hostname to the load balancer with an SSL passthrough Ingress", func() {
+		className := "nginx"
+		routeClusterContext.KubevirtCluster.Spec.ControlPlaneRoute.IngressClassName = &className
+		Expect(lb.ReconcileRoute(routeClusterContext)).To(Succeed())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(fakeClient.Get(gocontext.TODO(), routeKey, ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(Equal(map[string]string{
+			"example.com/team": "tenants",
+			"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+		}))
+		Expect(ingress.Labels).To(HaveKeyWithValue("cluster.x-k8s.io/cluster-name", clusterName))
+		Expect(ingress.Spec.IngressClassName).To(Equal(&className))
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("tenant.example.com"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal(clusterName + "-lb"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number).To(Equal(int32(6443)))
+	})
+
+	It("should route the hostname to the load balancer with a TLSRoute of the Gateway", func() {
+		routeClusterContext.KubevirtCluster.Spec.ControlPlaneRoute.Gateway = &infrav1.GatewayReference{
+			Name:        "shared",
+			Namespace:   "gateways",
+			SectionName: "tls",
+		}
+		Expect(lb.ReconcileRoute(routeClusterContext)).To(Succeed())
+
+		tlsRoute, err := getTLSRoute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsRoute.GetLabels()).To(HaveKeyWithValue("cluster.x-k8s.io/cluster-name", clusterName))
+		Expect(tlsRoute.Object["spec"]).To(Equal(map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "shared", "namespace": "gateways", "sectionName": "tls"}},
+			"hostnames":  []interface{}{"tenant.example.com"},
+			"rules": []interface{}{map[string]interface{}{
+				"backendRefs": []interface{}{map[string]interface{}{"name": clusterName + "-lb", "port": int64(6443)}},
+			}},
+		}))
+
+		err = fakeClient.Get(gocontext.TODO(), routeKey, &networkingv1.Ingress{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should replace the Ingress with a TLSRoute when a Gateway is set", func() {
+		Expect(lb.ReconcileRoute(routeClusterContext)).To(Succeed())
+		Expect(fakeClient.Get(gocontext.TODO(), routeKey, &networkingv1.Ingress{})).To(Succeed())
+
+		routeClusterContext.KubevirtCluster.Spec.ControlPlaneRoute.Gateway = &infrav1.GatewayReference{Name: "shared"}
+		Expect(lb.ReconcileRoute(routeClusterContext)).To(Succeed())
+
+		err := fakeClient.Get(gocontext.TODO(), routeKey, &networkingv1.Ingress{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		_, err = getTLSRoute()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should delete the route objects", func() {
+		Expect(lb.ReconcileRoute(routeClusterContext)).To(Succeed())
+
+		Expect(lb.DeleteRoute(routeClusterContext)).To(Succeed())
+
+		err := fakeClient.Get(gocontext.TODO(), routeKey, &networkingv1.Ingress{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
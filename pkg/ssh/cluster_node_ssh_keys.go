@@ -25,6 +25,7 @@ import (
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 	clustercontext "sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
 )
 
@@ -38,6 +39,7 @@ type ClusterNodeSshKeys struct {
 	Client         runtimeclient.Client
 	PublicKey      []byte // in the format "ssh-rsa ...", base64 encoded
 	PrivateKey     []byte // in PEM format
+	KeyType        infrav1.SSHKeyType
 }
 
 // NewClusterNodeSshKeys creates a new struct for cluster nodes ssh keys
@@ -50,7 +52,7 @@ func NewClusterNodeSshKeys(clusterContext *clustercontext.ClusterContext, client
 
 // GenerateNewKeys generates a new pair of ssh keys
 func (c *ClusterNodeSshKeys) GenerateNewKeys() error {
-	if pub, key, err := generateKeys(); err != nil {
+	if pub, key, err := generateKeys(c.KeyType); err != nil {
 		return errors.Wrap(err, "failed to generate new ssh keys")
 	} else {
 		c.PublicKey = pub
@@ -83,10 +85,11 @@ func (c *ClusterNodeSshKeys) PersistKeysToSecret() (*corev1.Secret, error) {
 			newSecret.Data = map[string][]byte{}
 		}
 
-		_, exists := newSecret.Data["key"]
+		pubDataKey, keyDataKey := c.secretDataKeys()
+		_, exists := newSecret.Data[keyDataKey]
 		if !exists {
-			newSecret.Data["pub"] = c.PublicKey
-			newSecret.Data["key"] = c.PrivateKey
+			newSecret.Data[pubDataKey] = c.PublicKey
+			newSecret.Data[keyDataKey] = c.PrivateKey
 		}
 
 		newSecret.SetOwnerReferences(clusterutil.EnsureOwnerRef(
@@ -124,7 +127,8 @@ func (c *ClusterNodeSshKeys) GetKeysDataSecret() (*corev1.Secret, error) {
 // IsPersistedToSecret checks if a secret with ssh keys already exists, and contains key values
 func (c *ClusterNodeSshKeys) IsPersistedToSecret() bool {
 	if sshKeysSecret, err := c.GetKeysDataSecret(); err == nil {
-		return sshKeysSecret.Data != nil && sshKeysSecret.Data["pub"] != nil && sshKeysSecret.Data["key"] != nil
+		pubDataKey, keyDataKey := c.secretDataKeys()
+		return sshKeysSecret.Data != nil && sshKeysSecret.Data[pubDataKey] != nil && sshKeysSecret.Data[keyDataKey] != nil
 	}
 
 	return false
@@ -139,12 +143,13 @@ func (c *ClusterNodeSshKeys) FetchPersistedKeysFromSecret() error {
 	}
 
 	sshKeysSecret, _ := c.GetKeysDataSecret()
-	if pub, ok := sshKeysSecret.Data["pub"]; !ok {
+	pubDataKey, keyDataKey := c.secretDataKeys()
+	if pub, ok := sshKeysSecret.Data[pubDataKey]; !ok {
 		return errors.New("error retrieving secret data: pub value is missing")
 	} else {
 		c.PublicKey = pub
 	}
-	if key, ok := sshKeysSecret.Data["key"]; !ok {
+	if key, ok := sshKeysSecret.Data[keyDataKey]; !ok {
 		return errors.New("error retrieving secret data: key value is missing")
 	} else {
 		c.PrivateKey = key
@@ -162,3 +167,13 @@ func (c *ClusterNodeSshKeys) sshKeysSecretName() string {
 
 	return *c.ClusterContext.KubevirtCluster.Spec.SshKeys.DataSecretName
 }
+
+// secretDataKeys returns the keys of the public and private keys in the ssh keys secret: "pub" and "key" for the
+// default ECDSA keys, and suffixed with the key type for the other types, e.g. "pub-ed25519" and "key-ed25519".
+func (c *ClusterNodeSshKeys) secretDataKeys() (string, string) {
+	if c.KeyType == "" || c.KeyType == infrav1.ECDSASSHKeyType {
+		return "pub", "key"
+	}
+
+	return "pub-" + string(c.KeyType), "key-" + string(c.KeyType)
+}
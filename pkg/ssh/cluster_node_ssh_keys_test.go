@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	cryptossh "golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -100,5 +101,39 @@ var _ = Describe("ClusterNodeSshKeys", func() {
 			Expect(result).To(BeTrue())
 			_ = clusterNodeSshKeys.FetchPersistedKeysFromSecret()
 		})
+
+		DescribeTable("should persist the keys of another type next to the default keys", func(keyType infrav1.SSHKeyType, dataKey, publicKeyType string) {
+			Expect(clusterNodeSshKeys.GenerateNewKeys()).To(Succeed())
+			sshKeysDataSecret, err := clusterNodeSshKeys.PersistKeysToSecret()
+			Expect(err).NotTo(HaveOccurred())
+			clusterContext.KubevirtCluster.Spec.SshKeys = infrav1.SSHKeys{DataSecretName: &sshKeysDataSecret.Name}
+
+			typedKeys := ssh.ClusterNodeSshKeys{
+				Client:         fakeClient,
+				ClusterContext: clusterContext,
+				KeyType:        keyType,
+			}
+			Expect(typedKeys.IsPersistedToSecret()).To(BeFalse())
+			Expect(typedKeys.GenerateNewKeys()).To(Succeed())
+			sshKeysDataSecret, err = typedKeys.PersistKeysToSecret()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sshKeysDataSecret.Data).To(HaveKeyWithValue("key", clusterNodeSshKeys.PrivateKey))
+			Expect(sshKeysDataSecret.Data).To(HaveKey("pub-" + dataKey))
+			Expect(sshKeysDataSecret.Data).To(HaveKey("key-" + dataKey))
+
+			fetchedKeys := ssh.ClusterNodeSshKeys{
+				Client:         fakeClient,
+				ClusterContext: clusterContext,
+				KeyType:        keyType,
+			}
+			Expect(fetchedKeys.FetchPersistedKeysFromSecret()).To(Succeed())
+			Expect(fetchedKeys.PublicKey).To(HavePrefix(publicKeyType + " "))
+			signer, err := cryptossh.ParsePrivateKey(fetchedKeys.PrivateKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signer.PublicKey().Type()).To(Equal(publicKeyType))
+		},
+			Entry("ed25519", infrav1.ED25519SSHKeyType, "ed25519", "ssh-ed25519"),
+			Entry("rsa", infrav1.RSASSHKeyType, "rsa", "ssh-rsa"),
+		)
 	})
 })
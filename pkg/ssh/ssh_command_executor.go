@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -29,15 +30,36 @@ type VMCommandExecutor interface {
 	ExecuteCommand(string) (string, error)
 }
 
+const (
+	// DefaultUser is the user the commands are run as, when no user is set.
+	DefaultUser = "capk"
+
+	// DefaultPort is the port of the SSH server of the VM, when no port is set.
+	DefaultPort = 22
+)
+
 type vmCommandExecutor struct {
 	IPAddress  string
+	Port       int32
+	User       string
 	PublicKey  []byte
 	PrivateKey []byte
 }
 
-func NewVMCommandExecutor(address string, keys *ClusterNodeSshKeys) VMCommandExecutor {
+// NewVMCommandExecutor returns a VMCommandExecutor running the commands as the given user, with the SSH server
+// listening on the given port of the address; the user and port default to DefaultUser and DefaultPort.
+func NewVMCommandExecutor(address string, port int32, user string, keys *ClusterNodeSshKeys) VMCommandExecutor {
+	if port == 0 {
+		port = DefaultPort
+	}
+	if user == "" {
+		user = DefaultUser
+	}
+
 	return vmCommandExecutor{
 		IPAddress:  address,
+		Port:       port,
+		User:       user,
 		PublicKey:  keys.PublicKey,
 		PrivateKey: keys.PrivateKey,
 	}
@@ -52,7 +74,7 @@ func (e vmCommandExecutor) ExecuteCommand(command string) (string, error) {
 	}
 
 	sshConfig := &ssh.ClientConfig{
-		User: "capk",
+		User: e.User,
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(signer),
 		},
@@ -61,7 +83,7 @@ func (e vmCommandExecutor) ExecuteCommand(command string) (string, error) {
 		},
 	}
 
-	hostAddress := strings.Join([]string{e.IPAddress, "22"}, ":")
+	hostAddress := net.JoinHostPort(e.IPAddress, strconv.Itoa(int(e.Port)))
 
 	connection, err := ssh.Dial("tcp", hostAddress, sshConfig)
 	if err != nil {
@@ -18,18 +18,39 @@ package ssh
 
 import (
 	ecdsa "crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 )
 
-// generateKeys generates a pair of public and private keys
-func generateKeys() (pub, key []byte, err error) {
+const rsaKeyBits = 3072
+
+// generateKeys generates a pair of public and private keys of the given type, ECDSA by default
+func generateKeys(keyType infrav1.SSHKeyType) (pub, key []byte, err error) {
+	switch keyType {
+	case infrav1.ED25519SSHKeyType:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		return encodeKeys(publicKey, privateKey)
+	case infrav1.RSASSHKeyType:
+		privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, nil, err
+		}
+		return encodeKeys(&privateKey.PublicKey, privateKey)
+	}
+
 	ec := elliptic.P384()
 
 	privateKey, err := generatePrivateKey(ec)
@@ -49,6 +70,21 @@ func generateKeys() (pub, key []byte, err error) {
 	return publicKeyBytes, privateKeyBytes, nil
 }
 
+// encodeKeys encodes the public key to the authorized_keys format, and the private key to the OpenSSH PEM format
+func encodeKeys(publicKey, privateKey interface{}) (pub, key []byte, err error) {
+	publicKeyBytes, err := generatePublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privBlock, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return publicKeyBytes, pem.EncodeToMemory(privBlock), nil
+}
+
 // generatePrivateKey creates an ECDSA Private Key of specified byte size
 func generatePrivateKey(c elliptic.Curve) (*ecdsa.PrivateKey, error) {
 	// create key
@@ -81,9 +117,9 @@ func encodePrivateKeyToPEM(privateKey *ecdsa.PrivateKey) ([]byte, error) {
 	return privatePEM, nil
 }
 
-// generatePublicKey takes a ecdsa.PublicKey and returns bytes suitable for writing to .pub file
-// returns in the format "ssh-ecdsa ..."
-func generatePublicKey(privateKey *ecdsa.PublicKey) ([]byte, error) {
+// generatePublicKey takes a public key and returns bytes suitable for writing to .pub file
+// returns in the format "ecdsa-sha2-nistp384 ...", "ssh-ed25519 ..." or "ssh-rsa ..."
+func generatePublicKey(privateKey interface{}) ([]byte, error) {
 	publicECKey, err := ssh.NewPublicKey(privateKey)
 	if err != nil {
 		return nil, err
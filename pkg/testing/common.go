@@ -3,11 +3,14 @@ package testing
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
 )
@@ -197,6 +200,12 @@ func SetupScheme() *runtime.Scheme {
 	if err := kubevirtv1.AddToScheme(s); err != nil {
 		panic(err)
 	}
+	if err := cdiv1.AddToScheme(s); err != nil {
+		panic(err)
+	}
+	if err := ipamv1.AddToScheme(s); err != nil {
+		panic(err)
+	}
 	if err := corev1.AddToScheme(s); err != nil {
 		panic(err)
 	}
@@ -206,5 +215,8 @@ func SetupScheme() *runtime.Scheme {
 	if err := rbacv1.AddToScheme(s); err != nil {
 		panic(err)
 	}
+	if err := networkingv1.AddToScheme(s); err != nil {
+		panic(err)
+	}
 	return s
 }
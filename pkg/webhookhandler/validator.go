@@ -20,11 +20,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -34,13 +39,52 @@ import (
 const (
 	webhookValidationPath = "/validate-infrastructure-cluster-x-k8s-io-v1alpha1-kubevirtmachinetemplate"
 	immutableWarning      = "KubevirtMachineTemplateSpec is immutable"
+
+	isolateEmulatorThreadWarning = "isolateEmulatorThread requires dedicatedCpuPlacement"
+	cpuManagerWarning            = "dedicatedCpuPlacement requires the CPUManager feature gate in the KubeVirt configuration of the infra cluster"
+
+	secureBootWarning = "the EFI secureBoot requires the SMM feature, which can't be disabled"
+
+	kernelBootImageWarning = "the kernelBoot requires a container image"
+	kernelBootPathWarning  = "the kernelBoot requires the kernelPath or the initrdPath of its container"
+	kernelArgsWarning      = "the kernelArgs of the kernelBoot require the kernelPath of its container"
+
+	rootDiskSourceWarning = "the rootDisk requires exactly one of image, sourceRef or pvc"
+	rootDiskImageWarning  = "the size, storageClassName, accessMode and volumeMode of the rootDisk are not supported with an image"
+
+	liveMigrationAccessModeWarning = "the disk %q of the live migratable VM requires the ReadWriteMany access mode"
+
+	memoryRequestWarning    = "the memory request and overcommitPercent are mutually exclusive"
+	memoryOvercommitWarning = "memory overcommitPercent requires the guest memory or the memory request of the VM"
+	guaranteedQOSWarning    = "the Guaranteed qosClass doesn't support overcommitting the memory of the VM"
+
+	passtWarning = "the Passt binding requires the Passt feature gate in the KubeVirt configuration of the infra cluster"
+
+	numaDedicatedCPUWarning = "numa.guestMappingPassthrough requires dedicatedCpuPlacement"
+	numaHugepagesWarning    = "numa.guestMappingPassthrough requires hugepages"
+	numaWarning             = "numa.guestMappingPassthrough requires the NUMA feature gate in the KubeVirt configuration of the infra cluster"
+
+	cpuManagerFeatureGate = "CPUManager"
+	numaFeatureGate       = "NUMA"
+	passtFeatureGate      = "Passt"
 )
 
+// supportedCPUFeaturePolicies are the policies of the CPU features KubeVirt supports. An empty policy means require.
+var supportedCPUFeaturePolicies = []string{"", "force", "require", "optional", "disable", "forbid"}
+
+// reservedVolumeNames are the names of the volumes the controller adds to the VMs: the root disk and the cloud-init
+// volume.
+var reservedVolumeNames = []string{"rootdisk", "cloudinitvolume"}
+
+// supportedHugepageSizes are the hugepage sizes KubeVirt can back the memory of the VMs with.
+var supportedHugepageSizes = []string{"2Mi", "1Gi"}
+
 func SetupWebhookWithManager(mgr ctrl.Manager) error {
 	decoder := admission.NewDecoder(mgr.GetScheme())
 
 	whHandler := &kubevirtMachineTemplateHandler{
 		decoder: decoder,
+		client:  mgr.GetAPIReader(),
 	}
 
 	srv := mgr.GetWebhookServer()
@@ -52,9 +96,10 @@ func SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 type kubevirtMachineTemplateHandler struct {
 	decoder *admission.Decoder
+	client  client.Reader
 }
 
-func (wh *kubevirtMachineTemplateHandler) Handle(_ context.Context, req admission.Request) admission.Response {
+func (wh *kubevirtMachineTemplateHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
 	// Get the object in the request
 	kvTmplt := &v1alpha1.KubevirtMachineTemplate{}
 
@@ -65,6 +110,7 @@ func (wh *kubevirtMachineTemplateHandler) Handle(_ context.Context, req admissio
 			return admission.Errored(http.StatusBadRequest, err)
 		}
 
+		err = wh.validateCreate(ctx, kvTmplt)
 	case admissionv1.Update:
 		oldKVTmplt := &v1alpha1.KubevirtMachineTemplate{}
 		if err := wh.decoder.DecodeRaw(req.Object, kvTmplt); err != nil {
@@ -101,3 +147,468 @@ func (wh *kubevirtMachineTemplateHandler) validateUpdate(old *v1alpha1.KubevirtM
 
 	return nil
 }
+
+func (wh *kubevirtMachineTemplateHandler) validateCreate(ctx context.Context, requested *v1alpha1.KubevirtMachineTemplate) error {
+	if err := validateCPUFeatures(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateSecureBoot(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateKernelBoot(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateHugepages(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateRootDisk(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateDiskNames(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateConfigDisks(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateBootOrders(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateStaticAddresses(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateAccessModes(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := validateMemory(&requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := wh.validateDedicatedCPUPlacement(ctx, &requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := wh.validateNUMA(ctx, &requested.Spec.Template.Spec); err != nil {
+		return err
+	}
+	return wh.validateNetworkBindings(ctx, &requested.Spec.Template.Spec)
+}
+
+// validateCPUFeatures checks that the CPU features of the VMs have a policy KubeVirt supports.
+func validateCPUFeatures(spec *v1alpha1.KubevirtMachineSpec) error {
+	vmiTemplate := spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.CPU == nil {
+		return nil
+	}
+
+	for _, feature := range vmiTemplate.Spec.Domain.CPU.Features {
+		if !contains(supportedCPUFeaturePolicies, feature.Policy) {
+			return fmt.Errorf("the policy %q of CPU feature %q is not supported, it must be one of %v", feature.Policy, feature.Name, supportedCPUFeaturePolicies[1:])
+		}
+	}
+
+	return nil
+}
+
+// validateSecureBoot checks that the SMM feature is not disabled for the VMs booting with the EFI SecureBoot; the
+// controller enables it otherwise.
+func validateSecureBoot(spec *v1alpha1.KubevirtMachineSpec) error {
+	vmiTemplate := spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil {
+		return nil
+	}
+
+	domain := vmiTemplate.Spec.Domain
+	if domain.Firmware == nil || domain.Firmware.Bootloader == nil || domain.Firmware.Bootloader.EFI == nil {
+		return nil
+	}
+	if secureBoot := domain.Firmware.Bootloader.EFI.SecureBoot; secureBoot != nil && !*secureBoot {
+		return nil
+	}
+
+	if domain.Features != nil && domain.Features.SMM != nil && domain.Features.SMM.Enabled != nil && !*domain.Features.SMM.Enabled {
+		return errors.New(secureBootWarning)
+	}
+
+	return nil
+}
+
+// validateKernelBoot checks that the VMs booting directly from a kernel have a container image providing their kernel
+// or initrd.
+func validateKernelBoot(spec *v1alpha1.KubevirtMachineSpec) error {
+	kernelBoot := spec.KernelBoot
+	if vmiTemplate := spec.VirtualMachineTemplate.Spec.Template; kernelBoot == nil && vmiTemplate != nil && vmiTemplate.Spec.Domain.Firmware != nil {
+		kernelBoot = vmiTemplate.Spec.Domain.Firmware.KernelBoot
+	}
+	if kernelBoot == nil {
+		return nil
+	}
+
+	container := kernelBoot.Container
+	if container == nil || container.Image == "" {
+		return errors.New(kernelBootImageWarning)
+	}
+	if container.KernelPath == "" && container.InitrdPath == "" {
+		return errors.New(kernelBootPathWarning)
+	}
+	if kernelBoot.KernelArgs != "" && container.KernelPath == "" {
+		return errors.New(kernelArgsWarning)
+	}
+
+	return nil
+}
+
+// validateHugepages checks that the memory of the VMs is backed by hugepages of a size KubeVirt supports.
+func validateHugepages(spec *v1alpha1.KubevirtMachineSpec) error {
+	vmiTemplate := spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.Memory == nil || vmiTemplate.Spec.Domain.Memory.Hugepages == nil {
+		return nil
+	}
+
+	pageSize := vmiTemplate.Spec.Domain.Memory.Hugepages.PageSize
+	if contains(supportedHugepageSizes, pageSize) {
+		return nil
+	}
+
+	return fmt.Errorf("hugepages pageSize %q is not supported, it must be one of %v", pageSize, supportedHugepageSizes)
+}
+
+// validateRootDisk checks that the root disk of the VMs is either a container disk or cloned from a single golden
+// image, and that the golden image is referenced by a kind of source CDI supports.
+func validateRootDisk(spec *v1alpha1.KubevirtMachineSpec) error {
+	rootDisk := spec.RootDisk
+	if rootDisk == nil {
+		return nil
+	}
+
+	sources := 0
+	if rootDisk.Image != "" {
+		sources++
+	}
+	if rootDisk.SourceRef != nil {
+		sources++
+	}
+	if rootDisk.PVC != nil {
+		sources++
+	}
+	if sources != 1 {
+		return errors.New(rootDiskSourceWarning)
+	}
+	if rootDisk.Image != "" && (rootDisk.Size != nil || rootDisk.StorageClassName != nil || rootDisk.AccessMode != "" || rootDisk.VolumeMode != nil) {
+		return errors.New(rootDiskImageWarning)
+	}
+	if rootDisk.SourceRef != nil && rootDisk.SourceRef.Kind != cdiv1.DataVolumeDataSource {
+		return fmt.Errorf("the kind %q of the rootDisk sourceRef is not supported, it must be %q", rootDisk.SourceRef.Kind, cdiv1.DataVolumeDataSource)
+	}
+
+	return nil
+}
+
+// validateDiskNames checks that the names of the data disks, config disks, CD-ROMs and hotplug volumes of the VMs are unique,
+// and don't collide with the names of the volumes of the VM template or of the volumes the controller adds.
+func validateDiskNames(spec *v1alpha1.KubevirtMachineSpec) error {
+	volumeNames := append([]string{}, reservedVolumeNames...)
+	if vmiTemplate := spec.VirtualMachineTemplate.Spec.Template; vmiTemplate != nil {
+		for _, volume := range vmiTemplate.Spec.Volumes {
+			volumeNames = append(volumeNames, volume.Name)
+		}
+	}
+
+	checkName := func(kind, name string) error {
+		if contains(volumeNames, name) {
+			return fmt.Errorf("the name %q of the %s is already used by another volume of the VM", name, kind)
+		}
+		volumeNames = append(volumeNames, name)
+		return nil
+	}
+	for _, dataDisk := range spec.DataDisks {
+		if err := checkName("data disk", dataDisk.Name); err != nil {
+			return err
+		}
+	}
+	for _, configDisk := range spec.ConfigDisks {
+		if err := checkName("config disk", configDisk.Name); err != nil {
+			return err
+		}
+	}
+	for _, cdrom := range spec.CDROMs {
+		if err := checkName("CD-ROM", cdrom.Name); err != nil {
+			return err
+		}
+	}
+	for _, hotplugVolume := range spec.HotplugVolumes {
+		if err := checkName("hotplug volume", hotplugVolume.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateConfigDisks checks that each config disk and CD-ROM of the VMs has a single source.
+func validateConfigDisks(spec *v1alpha1.KubevirtMachineSpec) error {
+	for _, configDisk := range spec.ConfigDisks {
+		if (configDisk.ConfigMap == nil) == (configDisk.Secret == nil) {
+			return fmt.Errorf("the config disk %q requires exactly one of configMap or secret", configDisk.Name)
+		}
+	}
+	for _, cdrom := range spec.CDROMs {
+		if (cdrom.Image == "") == (cdrom.PVC == nil) {
+			return fmt.Errorf("the CD-ROM %q requires exactly one of image or pvc", cdrom.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateStaticAddresses checks that the static IP addresses and gateways of the additional networks are valid.
+func validateStaticAddresses(spec *v1alpha1.KubevirtMachineSpec) error {
+	for _, network := range spec.AdditionalNetworks {
+		for _, address := range network.Addresses {
+			if _, _, err := net.ParseCIDR(address); err != nil {
+				return fmt.Errorf("the address %q of the network %q is not an IP address in the CIDR notation", address, network.Name)
+			}
+		}
+		if network.Gateway != "" && net.ParseIP(network.Gateway) == nil {
+			return fmt.Errorf("the gateway %q of the network %q is not an IP address", network.Gateway, network.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateBootOrders checks that the disks and interfaces of the VMs have unique boot orders.
+func validateBootOrders(spec *v1alpha1.KubevirtMachineSpec) error {
+	devices := map[uint]string{}
+	checkBootOrder := func(name string, bootOrder *uint) error {
+		if bootOrder == nil {
+			return nil
+		}
+		if device, found := devices[*bootOrder]; found {
+			return fmt.Errorf("the boot order %d of %q is already used by %q", *bootOrder, name, device)
+		}
+		devices[*bootOrder] = name
+		return nil
+	}
+
+	if vmiTemplate := spec.VirtualMachineTemplate.Spec.Template; vmiTemplate != nil {
+		for _, disk := range vmiTemplate.Spec.Domain.Devices.Disks {
+			// the root disk replaces the disk of the VM template with the same name
+			if spec.RootDisk != nil && disk.Name == "rootdisk" {
+				continue
+			}
+			if err := checkBootOrder(disk.Name, disk.BootOrder); err != nil {
+				return err
+			}
+		}
+		for _, iface := range vmiTemplate.Spec.Domain.Devices.Interfaces {
+			if err := checkBootOrder(iface.Name, iface.BootOrder); err != nil {
+				return err
+			}
+		}
+	}
+	if spec.RootDisk != nil {
+		bootOrder := uint(1)
+		if spec.RootDisk.BootOrder != nil {
+			bootOrder = *spec.RootDisk.BootOrder
+		}
+		if err := checkBootOrder("rootdisk", &bootOrder); err != nil {
+			return err
+		}
+	}
+	for _, dataDisk := range spec.DataDisks {
+		if err := checkBootOrder(dataDisk.Name, dataDisk.BootOrder); err != nil {
+			return err
+		}
+	}
+	for _, cdrom := range spec.CDROMs {
+		if err := checkBootOrder(cdrom.Name, cdrom.BootOrder); err != nil {
+			return err
+		}
+	}
+	for _, additionalNetwork := range spec.AdditionalNetworks {
+		if err := checkBootOrder(additionalNetwork.Name, additionalNetwork.BootOrder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateAccessModes checks that the persistent disks of the live migratable VMs can be shared by the source and the
+// target infra nodes of the live migration.
+func validateAccessModes(spec *v1alpha1.KubevirtMachineSpec) error {
+	evictionStrategy := spec.EvictionStrategy
+	if vmiTemplate := spec.VirtualMachineTemplate.Spec.Template; evictionStrategy == nil && vmiTemplate != nil {
+		evictionStrategy = vmiTemplate.Spec.EvictionStrategy
+	}
+	if evictionStrategy == nil || *evictionStrategy != kubevirtv1.EvictionStrategyLiveMigrate {
+		return nil
+	}
+
+	if spec.RootDisk != nil && spec.RootDisk.AccessMode == corev1.ReadWriteOnce {
+		return fmt.Errorf(liveMigrationAccessModeWarning, "rootdisk")
+	}
+	for _, dataDisk := range spec.DataDisks {
+		if dataDisk.AccessMode == corev1.ReadWriteOnce {
+			return fmt.Errorf(liveMigrationAccessModeWarning, dataDisk.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateMemory checks that the memory request of the VMs is either set or derived from their guest memory by the
+// overcommit percentage, and that the memory of the VMs of the Guaranteed QoS class is not overcommitted.
+func validateMemory(spec *v1alpha1.KubevirtMachineSpec) error {
+	memory := spec.Memory
+	if memory == nil {
+		return nil
+	}
+
+	if spec.QOSClass == corev1.PodQOSGuaranteed {
+		overcommitted := memory.OvercommitPercent != nil && *memory.OvercommitPercent > 100
+		if memory.Guest != nil && memory.Request != nil && memory.Request.Cmp(*memory.Guest) < 0 {
+			overcommitted = true
+		}
+		if overcommitted {
+			return errors.New(guaranteedQOSWarning)
+		}
+	}
+
+	if memory.OvercommitPercent == nil {
+		return nil
+	}
+
+	if memory.Request != nil {
+		return errors.New(memoryRequestWarning)
+	}
+
+	if memory.Guest != nil {
+		return nil
+	}
+	if vmiTemplate := spec.VirtualMachineTemplate.Spec.Template; vmiTemplate != nil {
+		if vmiTemplate.Spec.Domain.Memory != nil && vmiTemplate.Spec.Domain.Memory.Guest != nil {
+			return nil
+		}
+		if _, found := vmiTemplate.Spec.Domain.Resources.Requests[corev1.ResourceMemory]; found {
+			return nil
+		}
+	}
+
+	return errors.New(memoryOvercommitWarning)
+}
+
+// validateDedicatedCPUPlacement checks that the VMs requesting dedicated CPUs can be scheduled in the infra cluster,
+// which requires the CPUManager feature gate of KubeVirt.
+func (wh *kubevirtMachineTemplateHandler) validateDedicatedCPUPlacement(ctx context.Context, spec *v1alpha1.KubevirtMachineSpec) error {
+	vmiTemplate := spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.CPU == nil {
+		return nil
+	}
+
+	cpu := vmiTemplate.Spec.Domain.CPU
+	if cpu.IsolateEmulatorThread && !cpu.DedicatedCPUPlacement {
+		return errors.New(isolateEmulatorThreadWarning)
+	}
+	if !cpu.DedicatedCPUPlacement {
+		return nil
+	}
+
+	if kubevirt := wh.infraKubeVirt(ctx, spec); kubevirt != nil && !featureGateEnabled(kubevirt, cpuManagerFeatureGate) {
+		return errors.New(cpuManagerWarning)
+	}
+
+	return nil
+}
+
+// validateNUMA checks that the VMs passing their NUMA topology through to the guest also request dedicated CPUs and
+// hugepages, and that the NUMA feature gate of KubeVirt is enabled in the infra cluster.
+func (wh *kubevirtMachineTemplateHandler) validateNUMA(ctx context.Context, spec *v1alpha1.KubevirtMachineSpec) error {
+	vmiTemplate := spec.VirtualMachineTemplate.Spec.Template
+	if vmiTemplate == nil || vmiTemplate.Spec.Domain.CPU == nil || vmiTemplate.Spec.Domain.CPU.NUMA == nil ||
+		vmiTemplate.Spec.Domain.CPU.NUMA.GuestMappingPassthrough == nil {
+		return nil
+	}
+
+	if !vmiTemplate.Spec.Domain.CPU.DedicatedCPUPlacement {
+		return errors.New(numaDedicatedCPUWarning)
+	}
+	if vmiTemplate.Spec.Domain.Memory == nil || vmiTemplate.Spec.Domain.Memory.Hugepages == nil {
+		return errors.New(numaHugepagesWarning)
+	}
+
+	if kubevirt := wh.infraKubeVirt(ctx, spec); kubevirt != nil && !featureGateEnabled(kubevirt, numaFeatureGate) {
+		return errors.New(numaWarning)
+	}
+
+	return nil
+}
+
+// validateNetworkBindings checks that the interfaces of the VMs are bound to their networks in a way KubeVirt supports:
+// the Masquerade and Passt bindings only on the pod network, and the SR-IOV binding only on Multus networks. The Passt
+// binding also requires the Passt feature gate of KubeVirt in the infra cluster.
+func (wh *kubevirtMachineTemplateHandler) validateNetworkBindings(ctx context.Context, spec *v1alpha1.KubevirtMachineSpec) error {
+	usesPasst := spec.PodNetworkBinding == v1alpha1.PasstPodNetworkBinding
+
+	if vmiTemplate := spec.VirtualMachineTemplate.Spec.Template; vmiTemplate != nil {
+		podNetworks := map[string]bool{}
+		for _, network := range vmiTemplate.Spec.Networks {
+			podNetworks[network.Name] = network.Pod != nil
+		}
+
+		for _, iface := range vmiTemplate.Spec.Domain.Devices.Interfaces {
+			onPodNetwork, found := podNetworks[iface.Name]
+			if !found {
+				continue
+			}
+			switch {
+			case !onPodNetwork && (iface.Masquerade != nil || iface.Passt != nil):
+				return fmt.Errorf("the interface %q can't use the masquerade or passt binding, which are only supported on the pod network", iface.Name)
+			case onPodNetwork && iface.SRIOV != nil:
+				return fmt.Errorf("the interface %q can't use the SR-IOV binding on the pod network", iface.Name)
+			case onPodNetwork && iface.Passt != nil && spec.PodNetworkBinding == "":
+				usesPasst = true
+			}
+		}
+	}
+
+	if !usesPasst {
+		return nil
+	}
+	if kubevirt := wh.infraKubeVirt(ctx, spec); kubevirt != nil && !featureGateEnabled(kubevirt, passtFeatureGate) {
+		return errors.New(passtWarning)
+	}
+
+	return nil
+}
+
+// infraKubeVirt returns the KubeVirt configuration of the infra cluster. It returns nil when the infra cluster is not
+// the management cluster, or if the KubeVirt configuration can't be read, in which case it is not validated.
+func (wh *kubevirtMachineTemplateHandler) infraKubeVirt(ctx context.Context, spec *v1alpha1.KubevirtMachineSpec) *kubevirtv1.KubeVirt {
+	if spec.InfraClusterSecretRef != nil || wh.client == nil {
+		return nil
+	}
+
+	kubevirts := &kubevirtv1.KubeVirtList{}
+	if err := wh.client.List(ctx, kubevirts); err != nil || len(kubevirts.Items) == 0 {
+		return nil
+	}
+
+	return &kubevirts.Items[0]
+}
+
+func featureGateEnabled(kubevirt *kubevirtv1.KubeVirt, featureGate string) bool {
+	devConfig := kubevirt.Spec.Configuration.DeveloperConfiguration
+	if devConfig == nil {
+		return false
+	}
+
+	return contains(devConfig.FeatureGates, featureGate)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
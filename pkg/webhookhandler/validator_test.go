@@ -24,10 +24,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"sigs.k8s.io/cluster-api-provider-kubevirt/api/v1alpha1"
@@ -160,6 +165,585 @@ var _ = Describe("Template Validation - ensure immutability in update request",
 	})
 })
 
+var _ = Describe("Template Validation - check the VM resources in create request", func() {
+	var (
+		v1alpha1Codec runtime.Codec
+		wh            *kubevirtMachineTemplateHandler
+		ctx           context.Context
+	)
+
+	newKubeVirt := func(featureGates ...string) *kubevirtv1.KubeVirt {
+		return &kubevirtv1.KubeVirt{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+			Spec: kubevirtv1.KubeVirtSpec{
+				Configuration: kubevirtv1.KubeVirtConfiguration{
+					DeveloperConfiguration: &kubevirtv1.DeveloperConfiguration{FeatureGates: featureGates},
+				},
+			},
+		}
+	}
+
+	newTemplate := func(cpu *kubevirtv1.CPU) *v1alpha1.KubevirtMachineTemplate {
+		return &v1alpha1.KubevirtMachineTemplate{
+			Spec: v1alpha1.KubevirtMachineTemplateSpec{
+				Template: v1alpha1.KubevirtMachineTemplateResource{
+					Spec: v1alpha1.KubevirtMachineSpec{
+						VirtualMachineTemplate: v1alpha1.VirtualMachineTemplateSpec{
+							Spec: kubevirtv1.VirtualMachineSpec{
+								Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+									Spec: kubevirtv1.VirtualMachineInstanceSpec{
+										Domain: kubevirtv1.DomainSpec{CPU: cpu},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	setupHandler := func(objects ...runtime.Object) {
+		s := scheme.Scheme
+		Expect(v1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(kubevirtv1.AddToScheme(s)).To(Succeed())
+		codecFactory := serializer.NewCodecFactory(s)
+		v1alpha1Codec = codecFactory.LegacyCodec(v1alpha1.GroupVersion)
+		wh = &kubevirtMachineTemplateHandler{
+			decoder: admission.NewDecoder(s),
+			client:  fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build(),
+		}
+		ctx = context.Background()
+	}
+
+	It("should return OK if the CPUManager feature gate is enabled", func() {
+		setupHandler(newKubeVirt("CPUManager"))
+		req := newRequest(admissionv1.Create, newTemplate(&kubevirtv1.CPU{DedicatedCPUPlacement: true, IsolateEmulatorThread: true}), nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if the CPUManager feature gate is not enabled", func() {
+		setupHandler(newKubeVirt("HostDevices"))
+		req := newRequest(admissionv1.Create, newTemplate(&kubevirtv1.CPU{DedicatedCPUPlacement: true}), nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Code).To(Equal(int32(http.StatusForbidden)))
+		Expect(res.Result.Message).To(Equal(cpuManagerWarning))
+	})
+
+	It("should return OK if the KubeVirt configuration is not found", func() {
+		setupHandler()
+		req := newRequest(admissionv1.Create, newTemplate(&kubevirtv1.CPU{DedicatedCPUPlacement: true}), nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return OK if the CPU features policies are supported", func() {
+		setupHandler()
+		req := newRequest(admissionv1.Create, newTemplate(&kubevirtv1.CPU{
+			Model:    "Skylake-Server",
+			Features: []kubevirtv1.CPUFeature{{Name: "pcid"}, {Name: "vmx", Policy: "forbid"}},
+		}), nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if a CPU feature policy is not supported", func() {
+		setupHandler()
+		req := newRequest(admissionv1.Create, newTemplate(&kubevirtv1.CPU{
+			Features: []kubevirtv1.CPUFeature{{Name: "pcid", Policy: "always"}},
+		}), nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(ContainSubstring(`the policy "always" of CPU feature "pcid" is not supported`))
+	})
+
+	It("should return OK for the EFI SecureBoot", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+			Bootloader: &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{SecureBoot: pointer.Bool(true)}},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if SMM is disabled for the EFI SecureBoot", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+			Bootloader: &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{}},
+		}
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Features = &kubevirtv1.Features{
+			SMM: &kubevirtv1.FeatureState{Enabled: pointer.Bool(false)},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(secureBootWarning))
+	})
+
+	It("should return OK for the kernelBoot of a container image", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.KernelBoot = &kubevirtv1.KernelBoot{
+			KernelArgs: "console=ttyS0",
+			Container: &kubevirtv1.KernelBootContainer{
+				Image:      "quay.io/example/kernel:latest",
+				KernelPath: "/boot/vmlinuz",
+				InitrdPath: "/boot/initrd",
+			},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if the kernelBoot has no container image", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.KernelBoot = &kubevirtv1.KernelBoot{KernelArgs: "console=ttyS0"}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(kernelBootImageWarning))
+	})
+
+	It("should return error if the kernelArgs of the VM template kernelBoot are set without the kernelPath", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+			KernelBoot: &kubevirtv1.KernelBoot{
+				KernelArgs: "console=ttyS0",
+				Container: &kubevirtv1.KernelBootContainer{
+					Image:      "quay.io/example/kernel:latest",
+					InitrdPath: "/boot/initrd",
+				},
+			},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(kernelArgsWarning))
+	})
+
+	It("should return OK if the hugepages size is supported", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Memory = &kubevirtv1.Memory{
+			Hugepages: &kubevirtv1.Hugepages{PageSize: "1Gi"},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if the hugepages size is not supported", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Memory = &kubevirtv1.Memory{
+			Hugepages: &kubevirtv1.Hugepages{PageSize: "4Ki"},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(ContainSubstring(`hugepages pageSize "4Ki" is not supported`))
+	})
+
+	Context("with the guest NUMA topology passthrough", func() {
+		var template *v1alpha1.KubevirtMachineTemplate
+
+		BeforeEach(func() {
+			template = newTemplate(&kubevirtv1.CPU{
+				DedicatedCPUPlacement: true,
+				NUMA:                  &kubevirtv1.NUMA{GuestMappingPassthrough: &kubevirtv1.NUMAGuestMappingPassthrough{}},
+			})
+			template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Memory = &kubevirtv1.Memory{
+				Hugepages: &kubevirtv1.Hugepages{PageSize: "1Gi"},
+			}
+		})
+
+		It("should return OK if the NUMA feature gate is enabled", func() {
+			setupHandler(newKubeVirt("CPUManager", "NUMA"))
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeTrue())
+		})
+
+		It("should return error if the NUMA feature gate is not enabled", func() {
+			setupHandler(newKubeVirt("CPUManager"))
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(Equal(numaWarning))
+		})
+
+		It("should return error without dedicatedCpuPlacement", func() {
+			setupHandler(newKubeVirt("CPUManager", "NUMA"))
+			template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.CPU.DedicatedCPUPlacement = false
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(Equal(numaDedicatedCPUWarning))
+		})
+
+		It("should return error without hugepages", func() {
+			setupHandler(newKubeVirt("CPUManager", "NUMA"))
+			template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Memory = nil
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(Equal(numaHugepagesWarning))
+		})
+	})
+
+	Context("with network bindings", func() {
+		var template *v1alpha1.KubevirtMachineTemplate
+
+		BeforeEach(func() {
+			template = newTemplate(nil)
+			template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Networks = []kubevirtv1.Network{
+				*kubevirtv1.DefaultPodNetwork(),
+				{Name: "data", NetworkSource: kubevirtv1.NetworkSource{Multus: &kubevirtv1.MultusNetwork{NetworkName: "data-net"}}},
+			}
+		})
+
+		It("should return OK for the Passt pod network binding if the Passt feature gate is enabled", func() {
+			setupHandler(newKubeVirt("Passt"))
+			template.Spec.Template.Spec.PodNetworkBinding = v1alpha1.PasstPodNetworkBinding
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeTrue())
+		})
+
+		It("should return error for the Passt pod network binding if the Passt feature gate is not enabled", func() {
+			setupHandler(newKubeVirt())
+			template.Spec.Template.Spec.PodNetworkBinding = v1alpha1.PasstPodNetworkBinding
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(Equal(passtWarning))
+		})
+
+		It("should return error for the masquerade binding on a Multus network", func() {
+			setupHandler()
+			template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Interfaces = []kubevirtv1.Interface{
+				*kubevirtv1.DefaultMasqueradeNetworkInterface(),
+				{Name: "data", InterfaceBindingMethod: kubevirtv1.InterfaceBindingMethod{Masquerade: &kubevirtv1.InterfaceMasquerade{}}},
+			}
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(ContainSubstring(`the interface "data" can't use the masquerade or passt binding`))
+		})
+
+		It("should return error for the SR-IOV binding on the pod network", func() {
+			setupHandler()
+			template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Devices.Interfaces = []kubevirtv1.Interface{
+				{Name: "default", InterfaceBindingMethod: kubevirtv1.InterfaceBindingMethod{SRIOV: &kubevirtv1.InterfaceSRIOV{}}},
+			}
+			req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+			res := wh.Handle(ctx, req)
+			Expect(res.Allowed).To(BeFalse())
+			Expect(res.Result.Message).To(ContainSubstring(`the interface "default" can't use the SR-IOV binding on the pod network`))
+		})
+	})
+
+	It("should return OK for a root disk cloned from a DataSource", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.RootDisk = &v1alpha1.RootDisk{
+			SourceRef: &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: "ubuntu-22.04"},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error for a root disk with both a sourceRef and a pvc", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.RootDisk = &v1alpha1.RootDisk{
+			SourceRef: &cdiv1.DataVolumeSourceRef{Kind: cdiv1.DataVolumeDataSource, Name: "ubuntu-22.04"},
+			PVC:       &cdiv1.DataVolumeSourcePVC{Namespace: "golden-images", Name: "ubuntu-22.04"},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(rootDiskSourceWarning))
+	})
+
+	It("should return error for a container disk root disk with a size", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		size := resource.MustParse("20Gi")
+		template.Spec.Template.Spec.RootDisk = &v1alpha1.RootDisk{Image: "quay.io/capk/ubuntu-2204-container-disk:v1.27.6", Size: &size}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(rootDiskImageWarning))
+	})
+
+	It("should return error for a root disk cloned from an unsupported kind of source", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.RootDisk = &v1alpha1.RootDisk{
+			SourceRef: &cdiv1.DataVolumeSourceRef{Kind: "VolumeSnapshot", Name: "ubuntu-22.04"},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(ContainSubstring(`the kind "VolumeSnapshot" of the rootDisk sourceRef is not supported`))
+	})
+
+	It("should return OK for data disks with unique names", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.DataDisks = []v1alpha1.DataDisk{
+			{Name: "etcd", Size: resource.MustParse("10Gi")},
+			{Name: "containerd", Size: resource.MustParse("50Gi")},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error for a data disk with the name of another volume", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.DataDisks = []v1alpha1.DataDisk{
+			{Name: "etcd", Size: resource.MustParse("10Gi")},
+			{Name: "etcd", Size: resource.MustParse("50Gi")},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(ContainSubstring(`the name "etcd" of the data disk is already used by another volume of the VM`))
+	})
+
+	It("should return error for a config disk without a source", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.ConfigDisks = []v1alpha1.ConfigDisk{{Name: "certs"}}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(`the config disk "certs" requires exactly one of configMap or secret`))
+	})
+
+	It("should return error for a config disk with the name of a volume of the controller", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.ConfigDisks = []v1alpha1.ConfigDisk{
+			{Name: "cloudinitvolume", Secret: &corev1.LocalObjectReference{Name: "node-certs"}},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(ContainSubstring(`the name "cloudinitvolume" of the config disk is already used by another volume of the VM`))
+	})
+
+	It("should return error for a CD-ROM with both an image and a pvc", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.CDROMs = []v1alpha1.CDROM{
+			{Name: "virtio", Image: "quay.io/kubevirt/virtio-container-disk", PVC: &corev1.LocalObjectReference{Name: "virtio-iso"}},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(`the CD-ROM "virtio" requires exactly one of image or pvc`))
+	})
+
+	It("should return error for a static address without a prefix length", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "storage-net", Addresses: []string{"10.10.0.5"}},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(`the address "10.10.0.5" of the network "storage" is not an IP address in the CIDR notation`))
+	})
+
+	It("should return OK for static addresses with a gateway", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{
+			{Name: "storage", NetworkAttachmentDefinition: "storage-net", Addresses: []string{"10.10.0.5/24", "fd00::5/64"}, Gateway: "10.10.0.1"},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return OK for unique boot orders", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.RootDisk = &v1alpha1.RootDisk{Image: "quay.io/capk/ubuntu-2204-container-disk:v1.27.6"}
+		template.Spec.Template.Spec.CDROMs = []v1alpha1.CDROM{{Name: "virtio", Image: "quay.io/kubevirt/virtio-container-disk", BootOrder: pointer.Uint(2)}}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error for a boot order used twice", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.RootDisk = &v1alpha1.RootDisk{Image: "quay.io/capk/ubuntu-2204-container-disk:v1.27.6"}
+		template.Spec.Template.Spec.AdditionalNetworks = []v1alpha1.AdditionalNetwork{{Name: "provisioning", NetworkAttachmentDefinition: "pxe-net", BootOrder: pointer.Uint(1)}}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(`the boot order 1 of "provisioning" is already used by "rootdisk"`))
+	})
+
+	It("should return error for a ReadWriteOnce data disk of a live migratable VM", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		evictionStrategy := kubevirtv1.EvictionStrategyLiveMigrate
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.EvictionStrategy = &evictionStrategy
+		template.Spec.Template.Spec.DataDisks = []v1alpha1.DataDisk{
+			{Name: "etcd", Size: resource.MustParse("10Gi"), AccessMode: corev1.ReadWriteOnce},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(`the disk "etcd" of the live migratable VM requires the ReadWriteMany access mode`))
+	})
+
+	It("should return error for a ReadWriteOnce data disk of a VM live migrated by the eviction strategy of the machine", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		evictionStrategy := kubevirtv1.EvictionStrategyLiveMigrate
+		template.Spec.Template.Spec.EvictionStrategy = &evictionStrategy
+		template.Spec.Template.Spec.DataDisks = []v1alpha1.DataDisk{
+			{Name: "etcd", Size: resource.MustParse("10Gi"), AccessMode: corev1.ReadWriteOnce},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(`the disk "etcd" of the live migratable VM requires the ReadWriteMany access mode`))
+	})
+
+	It("should allow a ReadWriteOnce data disk when the eviction strategy of the machine overrides the live migration", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		liveMigrate := kubevirtv1.EvictionStrategyLiveMigrate
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.EvictionStrategy = &liveMigrate
+		external := kubevirtv1.EvictionStrategyExternal
+		template.Spec.Template.Spec.EvictionStrategy = &external
+		template.Spec.Template.Spec.DataDisks = []v1alpha1.DataDisk{
+			{Name: "etcd", Size: resource.MustParse("10Gi"), AccessMode: corev1.ReadWriteOnce},
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if the memory request is set with overcommitPercent", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.Memory = &v1alpha1.MemorySpec{
+			Guest:             resource.NewQuantity(8<<30, resource.BinarySI),
+			Request:           resource.NewQuantity(4<<30, resource.BinarySI),
+			OvercommitPercent: pointer.Int32(150),
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(memoryRequestWarning))
+	})
+
+	It("should return error if overcommitPercent is set without the memory of the VM", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.Memory = &v1alpha1.MemorySpec{OvercommitPercent: pointer.Int32(150)}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(memoryOvercommitWarning))
+	})
+
+	It("should allow overcommitPercent with the memory request of the VM template", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.VirtualMachineTemplate.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}
+		template.Spec.Template.Spec.Memory = &v1alpha1.MemorySpec{OvercommitPercent: pointer.Int32(150)}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeTrue())
+	})
+
+	It("should return error if the memory of a VM of the Guaranteed QoS class is overcommitted", func() {
+		setupHandler()
+		template := newTemplate(nil)
+		template.Spec.Template.Spec.QOSClass = corev1.PodQOSGuaranteed
+		template.Spec.Template.Spec.Memory = &v1alpha1.MemorySpec{
+			Guest:             resource.NewQuantity(8<<30, resource.BinarySI),
+			OvercommitPercent: pointer.Int32(150),
+		}
+		req := newRequest(admissionv1.Create, template, nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(guaranteedQOSWarning))
+	})
+
+	It("should return error if isolateEmulatorThread is set without dedicatedCpuPlacement", func() {
+		setupHandler(newKubeVirt("CPUManager"))
+		req := newRequest(admissionv1.Create, newTemplate(&kubevirtv1.CPU{IsolateEmulatorThread: true}), nil, v1alpha1Codec)
+
+		res := wh.Handle(ctx, req)
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.Result.Message).To(Equal(isolateEmulatorThreadWarning))
+	})
+})
+
 func newRequest(operation admissionv1.Operation, oldObj, newObj *v1alpha1.KubevirtMachineTemplate, encoder runtime.Encoder) admission.Request {
 	req := admission.Request{
 		AdmissionRequest: admissionv1.AdmissionRequest{
@@ -1,10 +1,14 @@
 package workloadcluster
 
 import (
+	"sync"
+
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/cluster-api-provider-kubevirt/pkg/context"
@@ -22,13 +26,43 @@ func New(client client.Client) WorkloadCluster {
 	}
 }
 
+// NewWithTracker returns a WorkloadCluster getting the workload cluster clients from the ClusterCacheTracker, so the
+// clients are cached, health-checked and shared by the controllers, instead of being created on every call.
+func NewWithTracker(client client.Client, tracker *remote.ClusterCacheTracker) WorkloadCluster {
+	return &workloadCluster{
+		Client:  client,
+		tracker: tracker,
+	}
+}
+
 // KubevirtMachineReconciler is struct provides workloadCluster access info
 type workloadCluster struct {
 	client.Client
+	tracker *remote.ClusterCacheTracker
+
+	// k8sClients are the kubernetes clients of the workload clusters, by cluster, shared by the drains of their nodes.
+	k8sClientsLock sync.Mutex
+	k8sClients     map[client.ObjectKey]*k8sClient
+}
+
+// k8sClient is a kubernetes client of a workload cluster, with the REST config of the ClusterCacheTracker or the
+// kubeconfig it was created from, to be recreated when they change.
+type k8sClient struct {
+	restConfig *rest.Config
+	kubeConfig string
+	client     k8sclient.Interface
 }
 
 // GenerateWorkloadClusterClient creates a client for workload cluster.
 func (w *workloadCluster) GenerateWorkloadClusterClient(ctx *context.MachineContext) (client.Client, error) {
+	if w.tracker != nil {
+		workloadClusterClient, err := w.tracker.GetClient(ctx, client.ObjectKeyFromObject(ctx.Cluster))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get workload cluster client")
+		}
+		return workloadClusterClient, nil
+	}
+
 	// get workload cluster kubeconfig
 	kubeConfig, err := w.getKubeconfigForWorkloadCluster(ctx)
 	if err != nil {
@@ -50,18 +84,39 @@ func (w *workloadCluster) GenerateWorkloadClusterClient(ctx *context.MachineCont
 	return workloadClusterClient, nil
 }
 
-// GenerateWorkloadClusterK8sClient creates a kubernetes client for workload cluster.
+// GenerateWorkloadClusterK8sClient returns a kubernetes client for workload cluster, created once per cluster.
 func (w *workloadCluster) GenerateWorkloadClusterK8sClient(ctx *context.MachineContext) (k8sclient.Interface, error) {
-	// get workload cluster kubeconfig
-	kubeConfig, err := w.getKubeconfigForWorkloadCluster(ctx)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get kubeconfig for workload cluster")
+	var trackerConfig *rest.Config
+	var kubeConfig string
+	var err error
+	if w.tracker != nil {
+		// the ClusterCacheTracker returns the same REST config until it reconnects to the workload cluster
+		trackerConfig, err = w.tracker.GetRESTConfig(ctx, client.ObjectKeyFromObject(ctx.Cluster))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get REST config for workload cluster")
+		}
+	} else {
+		// get workload cluster kubeconfig
+		kubeConfig, err = w.getKubeconfigForWorkloadCluster(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get kubeconfig for workload cluster")
+		}
 	}
 
-	// generate REST config
-	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create REST config")
+	w.k8sClientsLock.Lock()
+	defer w.k8sClientsLock.Unlock()
+
+	key := client.ObjectKeyFromObject(ctx.Cluster)
+	if cached, ok := w.k8sClients[key]; ok && cached.restConfig == trackerConfig && cached.kubeConfig == kubeConfig {
+		return cached.client, nil
+	}
+
+	restConfig := trackerConfig
+	if restConfig == nil {
+		// generate REST config
+		if restConfig, err = clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig)); err != nil {
+			return nil, errors.Wrap(err, "failed to create REST config")
+		}
 	}
 
 	// create the client
@@ -70,6 +125,11 @@ func (w *workloadCluster) GenerateWorkloadClusterK8sClient(ctx *context.MachineC
 		return nil, errors.Wrap(err, "failed to create workload cluster client")
 	}
 
+	if w.k8sClients == nil {
+		w.k8sClients = map[client.ObjectKey]*k8sClient{}
+	}
+	w.k8sClients[key] = &k8sClient{restConfig: trackerConfig, kubeConfig: kubeConfig, client: workloadClusterClient}
+
 	return workloadClusterClient, nil
 }
 